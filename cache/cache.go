@@ -4,6 +4,8 @@ import (
 	"time"
 
 	goCache "github.com/patrickmn/go-cache"
+
+	"github.com/go-sonic/sonic/metrics"
 )
 
 type Cache interface {
@@ -38,7 +40,9 @@ func (c *cacheImpl) Set(key string, value interface{}, expiration time.Duration)
 
 // Get key's value
 func (c *cacheImpl) Get(key string) (interface{}, bool) {
-	return c.goCache.Get(key)
+	value, ok := c.goCache.Get(key)
+	metrics.ObserveCacheLookup(ok)
+	return value, ok
 }
 
 func (c *cacheImpl) Delete(key string) {
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/util/xerr"
@@ -28,6 +29,38 @@ func BuildCodeCacheKey(userID int32) string {
 	return consts.CodePrefix + strconv.Itoa(int(userID))
 }
 
+func BuildThemePreviewKey(token string) string {
+	return consts.ThemePreviewPrefix + token
+}
+
+func BuildThemePreviewAdminKey(secret string) string {
+	return consts.ThemePreviewAdminPrefix + secret
+}
+
+func BuildOIDCStateKey(state string) string {
+	return consts.OIDCStatePrefix + state
+}
+
+func BuildOIDCMFAKey(state string) string {
+	return consts.OIDCMFAPrefix + state
+}
+
+func BuildLoginFailedIPKey(ip string) string {
+	return consts.LoginFailedIPPrefix + ip
+}
+
+func BuildLoginFailedUserKey(username string) string {
+	return consts.LoginFailedUserPrefix + strings.ToLower(username)
+}
+
+func BuildLoginCaptchaKey(captchaID string) string {
+	return consts.LoginCaptchaPrefix + captchaID
+}
+
+func BuildMicropubTokenKey(token string) string {
+	return consts.MicropubTokenPrefix + token
+}
+
 func BuildAccessPermissionKey(ctx context.Context) (string, error) {
 	sessionID := ctx.Value(consts.SessionID)
 	if sessionID == nil {
@@ -40,13 +40,18 @@ func main() {
 	// apply basic crud api on structs or table models which is specified by table name with function
 	// GenerateModel/GenerateModelAs. And generator will generate table models' code when calling Excute.
 	g.ApplyBasic(g.GenerateModel("attachment", gen.FieldType("type", "consts.AttachmentType")),
+		g.GenerateModel("audit_log"),
 		g.GenerateModel("category", gen.FieldType("type", "consts.CategoryType")),
 		g.GenerateModel("comment", gen.FieldType("type", "consts.CommentType"), gen.FieldType("status", "consts.CommentStatus")),
 		g.GenerateModel("comment_black"),
+		g.GenerateModel("custom_field", gen.FieldType("post_type", "consts.PostType"), gen.FieldType("type", "consts.CustomFieldType")),
 		g.GenerateModel("flyway_schema_history"),
+		g.GenerateModel("follower"),
+		g.GenerateModel("git_sync_file"),
 		g.GenerateModel("journal", gen.FieldType("type", "consts.JournalType")),
 		g.GenerateModel("link"),
 		g.GenerateModel("log", gen.FieldType("type", "consts.LogType")),
+		g.GenerateModel("mention", gen.FieldType("protocol", "consts.MentionProtocol"), gen.FieldType("type", "consts.MentionType"), gen.FieldType("status", "consts.CommentStatus")),
 		g.GenerateModel("menu"),
 		g.GenerateModelAs("meta", "Meta", gen.FieldType("type", "consts.MetaType")),
 		g.GenerateModel("option", gen.FieldType("type", "consts.OptionType")),
@@ -54,8 +59,13 @@ func main() {
 		g.GenerateModel("post", gen.FieldType("type", "consts.PostType"), gen.FieldType("status", "consts.PostStatus"), gen.FieldType("editor_type", "consts.EditorType")),
 		g.GenerateModel("post_category"),
 		g.GenerateModel("post_tag"),
+		g.GenerateModel("redirect"),
+		g.GenerateModel("series"),
+		g.GenerateModel("series_post"),
 		g.GenerateModel("tag"),
 		g.GenerateModel("theme_setting"),
+		g.GenerateModel("theme_setting_preset"),
+		g.GenerateModel("translation", gen.FieldType("type", "consts.TranslationType")),
 		g.GenerateModel("user", gen.FieldType("mfa_type", "consts.MFAType")),
 	)
 
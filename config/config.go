@@ -31,6 +31,7 @@ func NewConfig() *Config {
 	}
 
 	viper.SetDefault("sonic.admin_url_path", "admin")
+	viper.SetDefault("server.shutdown_timeout", "10s")
 
 	conf := &Config{}
 	if err := viper.ReadInConfig(); err != nil {
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Config struct {
 	Server     Server      `mapstructure:"server"`
 	Log        Log         `mapstructure:"logging"`
@@ -7,6 +9,9 @@ type Config struct {
 	MySQL      *MySQL      `mapstructure:"mysql"`
 	SQLite3    *SQLite3    `mapstructure:"sqlite3"`
 	Sonic      Sonic       `mapstructure:"sonic"`
+	OIDC       OIDC        `mapstructure:"oidc"`
+	Metrics    Metrics     `mapstructure:"metrics"`
+	Tracing    Tracing     `mapstructure:"tracing"`
 }
 
 type PostgreSQL struct {
@@ -28,6 +33,8 @@ type SQLite3 struct {
 type Server struct {
 	Host string `mapstructure:"host"`
 	Port string `mapstructure:"port"`
+	// ShutdownTimeout bounds the graceful shutdown, requests and events still running after it are dropped
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 type Log struct {
 	FileName string `mapstructure:"filename"`
@@ -59,3 +66,50 @@ type Sonic struct {
 	AdminResourcesDir string
 	AdminURLPath      string `mapstructure:"admin_url_path"`
 }
+
+type OIDC struct {
+	Providers []OIDCProvider `mapstructure:"providers"`
+}
+
+type OIDCProvider struct {
+	// Name identifies the provider in the login and callback url
+	Name         string   `mapstructure:"name"`
+	DisplayName  string   `mapstructure:"display_name"`
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	// AllowedDomains limits login to identities whose email belongs to one of the domains
+	AllowedDomains []string `mapstructure:"allowed_domains"`
+	// AllowedClaims requires every claim to be present in the id token with the given value
+	AllowedClaims map[string]string `mapstructure:"allowed_claims"`
+	// Subjects links the sub claim of the provider to sonic users, other identities are linked by their verified email
+	Subjects []OIDCSubject `mapstructure:"subjects"`
+	// SkipMFA trusts the provider to check the second factor, otherwise users with MFA must also enter their TOTP code
+	SkipMFA bool `mapstructure:"skip_mfa"`
+}
+
+type OIDCSubject struct {
+	Subject  string `mapstructure:"subject"`
+	Username string `mapstructure:"username"`
+}
+
+type Metrics struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Token must be sent as a bearer token by the scraper when it is not empty
+	Token string `mapstructure:"token"`
+	// AllowedIPs is a list of ip addresses or CIDRs allowed to scrape, only loopback is allowed when both Token and AllowedIPs are empty
+	AllowedIPs []string `mapstructure:"allowed_ips"`
+}
+
+type Tracing struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the url of the OTLP/HTTP collector, e.g. http://localhost:4318, the OTEL_EXPORTER_OTLP_* env is used when empty
+	Endpoint    string            `mapstructure:"endpoint"`
+	Insecure    bool              `mapstructure:"insecure"`
+	Headers     map[string]string `mapstructure:"headers"`
+	ServiceName string            `mapstructure:"service_name"`
+	// SampleRatio is the ratio of traces to sample, between 0 and 1, every trace is sampled when it is not set
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
@@ -16,6 +16,145 @@ const (
 	OneTimeTokenQueryName     = "ott"
 	SessionID                 = "session_id"
 	AccessPermissionKeyPrefix = "access_permission_"
+	OIDCStatePrefix           = "oidc_state_"
+	OIDCStateValidDuration    = time.Minute * 10
+	OIDCMFAPrefix             = "oidc_mfa_"
+	AuditRecorder             = "audit_recorder"
+	LoginFailedIPPrefix       = "login_failed_ip_"
+	LoginFailedUserPrefix     = "login_failed_user_"
+	LoginCaptchaPrefix        = "login_captcha_"
+	LoginCaptchaValidDuration = time.Minute * 5
+	MicropubTokenPrefix       = "micropub_token_"
+	// MicropubTokenValidDuration is how long a token verified by the token endpoint is trusted without verifying again
+	MicropubTokenValidDuration = time.Minute * 5
+	// LoginCaptchaThreshold is the number of failed attempts after which a captcha is required
+	LoginCaptchaThreshold = 2
+	// LoginDelayThreshold is the number of failed attempts after which each attempt has to wait for a doubling delay
+	LoginDelayThreshold  = 3
+	LoginMaxDelay        = time.Minute
+	ThemePreviewPrefix   = "theme_preview_"
+	ThemePreview         = "theme_preview"
+	ThemePreviewTemplate = "theme_preview_template"
+	// ThemePreviewQueryName carries the preview token in the link given to the admin, it is kept in a cookie afterwards
+	ThemePreviewQueryName     = "preview_theme"
+	ThemePreviewCookieName    = "sonic_theme_preview"
+	ThemePreviewValidDuration = time.Hour
+	// ThemePreviewAdminCookieName carries a random secret given to the admin creating the preview, the preview is only rendered for the admin
+	ThemePreviewAdminCookieName = "sonic_theme_preview_admin"
+	ThemePreviewAdminPrefix     = "theme_preview_admin_"
+	// RequestLanguage is the language of the content the request asks for, given by the path prefix such as /en
+	RequestLanguage = "request_language"
+	// RequestPathPrefix is the language prefix taken off the path of the request before routing
+	RequestPathPrefix = "request_path_prefix"
+	// ContentImport marks the context of the importers and the git sync, the required custom fields are not enforced on the posts they write
+	ContentImport = "content_import"
+)
+
+const (
+	JSONFeedVersion     = "https://jsonfeed.org/version/1.1"
+	JSONFeedContentType = "application/feed+json; charset=utf-8"
+	RssContentTypeFull  = "full"
+)
+
+const (
+	// SitemapPageSize is the number of urls in a child sitemap, a sitemap holds at most 50,000 urls
+	SitemapPageSize = 10000
+	// SitemapImageLimit is the number of images a url of a sitemap holds at most
+	SitemapImageLimit    = 1000
+	IndexNowKeyPath      = "/indexnow.txt"
+	IndexNowTimeout      = time.Second * 10
+	SitemapPingURLHolder = "{sitemap}"
+)
+
+const (
+	RelatedPostsCacheKey = "related_posts"
+	// RelatedPostsValidDuration is how long the related posts are kept before being computed again,
+	// which takes in the changes without a PostUpdateEvent such as deletions
+	RelatedPostsValidDuration = time.Hour
+	// RelatedPostsRefreshDelay is how long the related posts wait after the last change before being computed again
+	RelatedPostsRefreshDelay = time.Second * 10
+	// RelatedPostsMaxCount is the number of related posts computed for each post
+	RelatedPostsMaxCount = 10
+)
+
+type SitemapType string
+
+const (
+	SitemapTypePages      SitemapType = "pages"
+	SitemapTypePosts      SitemapType = "posts"
+	SitemapTypeSheets     SitemapType = "sheets"
+	SitemapTypeCategories SitemapType = "categories"
+	SitemapTypeTags       SitemapType = "tags"
+	SitemapTypeJournals   SitemapType = "journals"
+	SitemapTypePhotos     SitemapType = "photos"
+)
+
+var SitemapTypes = []SitemapType{
+	SitemapTypePages, SitemapTypePosts, SitemapTypeSheets, SitemapTypeCategories,
+	SitemapTypeTags, SitemapTypeJournals, SitemapTypePhotos,
+}
+
+const (
+	WebmentionPath = "/webmention"
+	// XMLRPCPath receives the XML-RPC calls like pingback.ping and the MetaWeblog methods
+	XMLRPCPath = "/xmlrpc"
+	// MentionTimeout bounds fetching a source or a target of mentions
+	MentionTimeout = time.Second * 10
+	// MentionFetchLimit is the max size of a page fetched for mentions
+	MentionFetchLimit = 1 << 20
+	// MentionContentLimit is the max length of the content excerpt of mentions
+	MentionContentLimit = 300
+	// MentionSendLimit is the max number of links of a post to send mentions to
+	MentionSendLimit = 50
+	// MentionQueueSize is the max number of received mentions waiting for verification
+	MentionQueueSize = 100
+	// MentionWorkers is the number of received mentions verified at the same time
+	MentionWorkers = 4
+)
+
+const (
+	// XMLRPCRequestLimit is the max size of an XML-RPC call, the media are uploaded base64 encoded in it
+	XMLRPCRequestLimit = 32 << 20
+	// MetaWeblogBlogID is the id of the only blog reported to the XML-RPC editors
+	MetaWeblogBlogID = "1"
+	// MetaWeblogTextFilterMarkdown is the text filter of the posts written in Markdown
+	MetaWeblogTextFilterMarkdown = "markdown"
+	// MetaWeblogRecentPostsLimit is the max number of posts returned by getRecentPosts
+	MetaWeblogRecentPostsLimit = 100
+)
+
+const (
+	MicropubPath      = "/micropub"
+	MicropubMediaPath = "/micropub/media"
+	// MicropubTimeout bounds verifying a token by the token endpoint
+	MicropubTimeout = time.Second * 10
+	// MicropubRequestLimit is the max size of a Micropub request, the photos may be uploaded in it
+	MicropubRequestLimit = 32 << 20
+)
+
+const (
+	// GitSyncDirName is the directory of the working copy of git sync in the work dir
+	GitSyncDirName = "git-sync"
+	// GitSyncScheduleTick is how often the interval of pulling the repository on schedule is checked
+	GitSyncScheduleTick = time.Minute
+	// GitSyncWebhookLimit is the max size of the payload of the push webhook
+	GitSyncWebhookLimit = 25 << 20
+)
+
+const (
+	// ImportMediaTimeout bounds downloading a media file referenced by the imported content
+	ImportMediaTimeout = time.Second * 30
+	// ImportMediaLimit is the max size of a media file downloaded or copied while importing
+	ImportMediaLimit = 32 << 20
+)
+
+// post metas overriding the seo metadata of a post or sheet
+const (
+	SeoMetaTitle       = "seo_title"
+	SeoMetaDescription = "seo_description"
+	SeoMetaImage       = "seo_image"
+	SeoMetaCanonical   = "seo_canonical"
+	SeoMetaRobots      = "seo_robots"
 )
 
 const (
@@ -30,6 +169,36 @@ const (
 var (
 	ThemePropertyFilenames = [2]string{"theme.yaml", "theme.yml"}
 	ThemeSettingFilenames  = [2]string{"settings.yaml", "settings.yml"}
+	// ThemeRequiredTemplates must be defined by every theme as "<theme id>/<name>"
+	ThemeRequiredTemplates = []string{"index", "post", "sheet", "archives", "404"}
+)
+
+const (
+	HealthStatusUp   = "UP"
+	HealthStatusDown = "DOWN"
+	// HealthCheckTimeout bounds every readiness check so that a hanging database does not hang the probe
+	HealthCheckTimeout = time.Second * 3
+)
+
+const (
+	// LiveReloadPath streams the live reload events to the browser in development mode
+	LiveReloadPath = "/__sonic/livereload"
+	// LiveReloadHeartbeat keeps the live reload stream open through proxies
+	LiveReloadHeartbeat = time.Second * 30
+)
+
+const (
+	ActivityPubPath        = "/activitypub"
+	ActivityPubContentType = "application/activity+json; charset=utf-8"
+	ActivityPubAccept      = `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+	ActivityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	ActivityStreamsPublic  = "https://www.w3.org/ns/activitystreams#Public"
+	WebFingerPath          = "/.well-known/webfinger"
+	ActivityPubPageSize    = 20
+	ActivityPubTimeout     = time.Second * 10
+	ActivityPubFetchLimit  = 1 << 20
+	// ActivityPubSignatureMaxAge bounds the clock skew between the servers when verifying the signatures
+	ActivityPubSignatureMaxAge = time.Hour
 )
 
 const (
@@ -37,6 +206,8 @@ const (
 	ThemeScreenshotsName   = "screenshot"
 	ThemeCustomSheetPrefix = "sheet_"
 	ThemeCustomPostPrefix  = "post_"
+	// ThemeChangelogLimit bounds the commits or tags listed when checking a theme for updates
+	ThemeChangelogLimit = 50
 )
 
 var (
@@ -681,10 +681,57 @@ func (m MetaType) Value() (driver.Value, error) {
 type ThemeUpdateStrategy int32
 
 const (
-	ThemeUpdateStrategyBranch = iota
+	// ThemeUpdateStrategyBranch follows the head of the theme branch
+	ThemeUpdateStrategyBranch ThemeUpdateStrategy = iota
+
+	// ThemeUpdateStrategyRelease follows the latest semver tag of the theme repo
 	ThemeUpdateStrategyRelease
 )
 
+func (t ThemeUpdateStrategy) MarshalJSON() ([]byte, error) {
+	switch t {
+	case ThemeUpdateStrategyBranch:
+		return []byte(`"BRANCH"`), nil
+	case ThemeUpdateStrategyRelease:
+		return []byte(`"RELEASE"`), nil
+	default:
+		return nil, xerr.BadParam.New("").WithMsg("unknown ThemeUpdateStrategy")
+	}
+}
+
+func (t *ThemeUpdateStrategy) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	switch str {
+	case `"BRANCH"`:
+		*t = ThemeUpdateStrategyBranch
+		return nil
+	case `"RELEASE"`:
+		*t = ThemeUpdateStrategyRelease
+		return nil
+	default:
+		return xerr.BadParam.New("").WithMsg("unknown ThemeUpdateStrategy")
+	}
+}
+
+func (t *ThemeUpdateStrategy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	strType := ""
+	err := unmarshal(&strType)
+	if err != nil {
+		return xerr.BadParam.New("").WithMsg("ThemeUpdateStrategy yaml unmarshal err")
+	}
+	strType = strings.ToUpper(strType)
+	switch strType {
+	case "", "BRANCH":
+		*t = ThemeUpdateStrategyBranch
+		return nil
+	case "RELEASE":
+		*t = ThemeUpdateStrategyRelease
+		return nil
+	default:
+		return xerr.BadParam.New("").WithMsg("unknown ThemeUpdateStrategy")
+	}
+}
+
 type ThemeConfigInputType int32
 
 const (
@@ -1016,3 +1063,271 @@ func (c CategoryType) Value() (driver.Value, error) {
 func (c CategoryType) Ptr() *CategoryType {
 	return &c
 }
+
+type MentionType int32
+
+const (
+	MentionTypeMention MentionType = iota
+	MentionTypeReply
+	MentionTypeLike
+	MentionTypeRepost
+	MentionTypeBookmark
+)
+
+func (m MentionType) MarshalJSON() ([]byte, error) {
+	switch m {
+	case MentionTypeMention:
+		return []byte(`"MENTION"`), nil
+	case MentionTypeReply:
+		return []byte(`"REPLY"`), nil
+	case MentionTypeLike:
+		return []byte(`"LIKE"`), nil
+	case MentionTypeRepost:
+		return []byte(`"REPOST"`), nil
+	case MentionTypeBookmark:
+		return []byte(`"BOOKMARK"`), nil
+	}
+	return nil, nil
+}
+
+func (m *MentionType) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"MENTION"`:
+		*m = MentionTypeMention
+	case `"REPLY"`:
+		*m = MentionTypeReply
+	case `"LIKE"`:
+		*m = MentionTypeLike
+	case `"REPOST"`:
+		*m = MentionTypeRepost
+	case `"BOOKMARK"`:
+		*m = MentionTypeBookmark
+	default:
+		return xerr.BadParam.New("").WithMsg("unknown MentionType")
+	}
+	return nil
+}
+
+func (m *MentionType) Scan(src interface{}) error {
+	if src == nil {
+		return xerr.BadParam.New("").WithMsg("field nil")
+	}
+	switch data := src.(type) {
+	case int64:
+		*m = MentionType(data)
+	case int32:
+		*m = MentionType(data)
+	case int:
+		*m = MentionType(data)
+	default:
+		return xerr.BadParam.New("").WithMsg("bad type")
+	}
+	return nil
+}
+
+func (m MentionType) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+type MentionProtocol int32
+
+const (
+	MentionProtocolWebmention MentionProtocol = iota
+	MentionProtocolPingback
+)
+
+func (m MentionProtocol) MarshalJSON() ([]byte, error) {
+	switch m {
+	case MentionProtocolWebmention:
+		return []byte(`"WEBMENTION"`), nil
+	case MentionProtocolPingback:
+		return []byte(`"PINGBACK"`), nil
+	}
+	return nil, nil
+}
+
+func (m *MentionProtocol) Scan(src interface{}) error {
+	if src == nil {
+		return xerr.BadParam.New("").WithMsg("field nil")
+	}
+	switch data := src.(type) {
+	case int64:
+		*m = MentionProtocol(data)
+	case int32:
+		*m = MentionProtocol(data)
+	case int:
+		*m = MentionProtocol(data)
+	default:
+		return xerr.BadParam.New("").WithMsg("bad type")
+	}
+	return nil
+}
+
+func (m MentionProtocol) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+type CustomFieldType int32
+
+const (
+	CustomFieldTypeText CustomFieldType = iota
+	CustomFieldTypeNumber
+	CustomFieldTypeDate
+	CustomFieldTypeSelect
+	CustomFieldTypeAttachment
+	CustomFieldTypePost
+)
+
+func (c CustomFieldType) String() string {
+	switch c {
+	case CustomFieldTypeText:
+		return "TEXT"
+	case CustomFieldTypeNumber:
+		return "NUMBER"
+	case CustomFieldTypeDate:
+		return "DATE"
+	case CustomFieldTypeSelect:
+		return "SELECT"
+	case CustomFieldTypeAttachment:
+		return "ATTACHMENT"
+	case CustomFieldTypePost:
+		return "POST"
+	}
+	return ""
+}
+
+func CustomFieldTypeFromString(str string) (CustomFieldType, error) {
+	switch strings.ToUpper(str) {
+	case "TEXT":
+		return CustomFieldTypeText, nil
+	case "NUMBER":
+		return CustomFieldTypeNumber, nil
+	case "DATE":
+		return CustomFieldTypeDate, nil
+	case "SELECT":
+		return CustomFieldTypeSelect, nil
+	case "ATTACHMENT":
+		return CustomFieldTypeAttachment, nil
+	case "POST":
+		return CustomFieldTypePost, nil
+	}
+	return CustomFieldTypeText, xerr.BadParam.New("").WithMsg("unknown CustomFieldType")
+}
+
+func (c CustomFieldType) MarshalJSON() ([]byte, error) {
+	str := c.String()
+	if str == "" {
+		return nil, xerr.BadParam.New("").WithMsg("unknown CustomFieldType")
+	}
+	return []byte(`"` + str + `"`), nil
+}
+
+func (c *CustomFieldType) UnmarshalJSON(data []byte) error {
+	str, err := strconv.Unquote(string(data))
+	if err != nil {
+		return xerr.BadParam.New("").WithMsg("unknown CustomFieldType")
+	}
+	*c, err = CustomFieldTypeFromString(str)
+	return err
+}
+
+func (c *CustomFieldType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	str := ""
+	if err := unmarshal(&str); err != nil {
+		return xerr.BadParam.New("").WithMsg("CustomFieldType yaml unmarshal err")
+	}
+	var err error
+	*c, err = CustomFieldTypeFromString(str)
+	return err
+}
+
+func (c *CustomFieldType) Scan(src interface{}) error {
+	if src == nil {
+		return xerr.BadParam.New("").WithMsg("field nil")
+	}
+	switch data := src.(type) {
+	case int64:
+		*c = CustomFieldType(data)
+	case int32:
+		*c = CustomFieldType(data)
+	case int:
+		*c = CustomFieldType(data)
+	default:
+		return xerr.BadParam.New("").WithMsg("bad type")
+	}
+	return nil
+}
+
+func (c CustomFieldType) Value() (driver.Value, error) {
+	return int64(c), nil
+}
+
+type TranslationType int32
+
+const (
+	TranslationTypePost TranslationType = iota
+	TranslationTypeCategory
+	TranslationTypeMenu
+)
+
+func (t TranslationType) String() string {
+	switch t {
+	case TranslationTypePost:
+		return "POST"
+	case TranslationTypeCategory:
+		return "CATEGORY"
+	case TranslationTypeMenu:
+		return "MENU"
+	}
+	return ""
+}
+
+func TranslationTypeFromString(str string) (TranslationType, error) {
+	switch strings.ToUpper(str) {
+	case "POST", "SHEET":
+		return TranslationTypePost, nil
+	case "CATEGORY":
+		return TranslationTypeCategory, nil
+	case "MENU":
+		return TranslationTypeMenu, nil
+	}
+	return TranslationTypePost, xerr.BadParam.New("").WithMsg("unknown TranslationType")
+}
+
+func (t TranslationType) MarshalJSON() ([]byte, error) {
+	str := t.String()
+	if str == "" {
+		return nil, xerr.BadParam.New("").WithMsg("unknown TranslationType")
+	}
+	return []byte(`"` + str + `"`), nil
+}
+
+func (t *TranslationType) UnmarshalJSON(data []byte) error {
+	str, err := strconv.Unquote(string(data))
+	if err != nil {
+		return xerr.BadParam.New("").WithMsg("unknown TranslationType")
+	}
+	*t, err = TranslationTypeFromString(str)
+	return err
+}
+
+func (t *TranslationType) Scan(src interface{}) error {
+	if src == nil {
+		return xerr.BadParam.New("").WithMsg("field nil")
+	}
+	switch data := src.(type) {
+	case int64:
+		*t = TranslationType(data)
+	case int32:
+		*t = TranslationType(data)
+	case int:
+		*t = TranslationType(data)
+	default:
+		return xerr.BadParam.New("").WithMsg("bad type")
+	}
+	return nil
+}
+
+func (t TranslationType) Value() (driver.Value, error) {
+	return int64(t), nil
+}
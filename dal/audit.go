@@ -0,0 +1,262 @@
+package dal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/entity"
+)
+
+const (
+	auditSnapshotLimit = 20
+	auditSummaryLimit  = 16 * 1024
+	auditRedacted      = "******"
+)
+
+// auditIgnoredTables are written as a side effect of every request and would only add noise
+var auditIgnoredTables = map[string]struct{}{
+	entity.TableNameLog:      {},
+	entity.TableNameAuditLog: {},
+}
+
+var auditSensitiveKeys = []string{"password", "secret", "mfa_key", "access_key", "token"}
+
+type AuditChange struct {
+	Table  string
+	Action string
+	IDs    []string
+	Before []interface{}
+	After  []interface{}
+}
+
+// AuditRecorder collects the rows changed by the statements executed within one admin request
+type AuditRecorder struct {
+	mu      sync.Mutex
+	Changes []*AuditChange
+}
+
+func NewAuditRecorder() *AuditRecorder {
+	return &AuditRecorder{}
+}
+
+func GetAuditRecorder(ctx context.Context) *AuditRecorder {
+	if ctx == nil {
+		return nil
+	}
+	recorder, _ := ctx.Value(consts.AuditRecorder).(*AuditRecorder)
+	return recorder
+}
+
+func (r *AuditRecorder) add(change *AuditChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Changes = append(r.Changes, change)
+}
+
+// Summary returns the changed tables, the primary keys and the json summaries of the rows before and after the request
+func (r *AuditRecorder) Summary() (entityType, entityID, before, after string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tables := make([]string, 0)
+	ids := make([]string, 0)
+	beforeMap := make(map[string][]interface{})
+	afterMap := make(map[string][]interface{})
+	for _, change := range r.Changes {
+		if !slices.Contains(tables, change.Table) {
+			tables = append(tables, change.Table)
+		}
+		for _, id := range change.IDs {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		key := change.Table + ":" + change.Action
+		beforeMap[key] = append(beforeMap[key], change.Before...)
+		afterMap[key] = append(afterMap[key], change.After...)
+	}
+	return strings.Join(tables, ","), strings.Join(ids, ","), marshalSummary(beforeMap), marshalSummary(afterMap)
+}
+
+func registerAuditCallbacks(db *gorm.DB) {
+	callback := db.Callback()
+	_ = callback.Create().After("gorm:create").Register("sonic:audit_create", auditAfter("create"))
+	_ = callback.Update().Before("gorm:update").Register("sonic:audit_before_update", auditBefore("update"))
+	_ = callback.Update().After("gorm:update").Register("sonic:audit_update", auditAfter("update"))
+	_ = callback.Delete().Before("gorm:delete").Register("sonic:audit_delete", auditBefore("delete"))
+}
+
+func auditBefore(action string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		recorder := auditRecorderOf(db)
+		if recorder == nil {
+			return
+		}
+		change := &AuditChange{Table: db.Statement.Table, Action: action}
+		rows := snapshot(db)
+		for _, row := range rows {
+			change.Before = append(change.Before, redact(row))
+			if id, ok := row["id"]; ok {
+				change.IDs = append(change.IDs, fmt.Sprint(id))
+			}
+		}
+		db.InstanceSet("sonic:audit_change", change)
+		if action == "delete" {
+			recorder.add(change)
+		}
+	}
+}
+
+func auditAfter(action string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		recorder := auditRecorderOf(db)
+		if recorder == nil || db.Error != nil {
+			return
+		}
+		change := &AuditChange{Table: db.Statement.Table, Action: action}
+		if v, ok := db.InstanceGet("sonic:audit_change"); ok {
+			change = v.(*AuditChange)
+		}
+		if action == "create" {
+			change.IDs = append(change.IDs, primaryKeys(db)...)
+		}
+		if db.Statement.Dest != nil {
+			change.After = append(change.After, redact(toPlain(db.Statement.Dest)))
+		}
+		recorder.add(change)
+	}
+}
+
+func auditRecorderOf(db *gorm.DB) *AuditRecorder {
+	if db.Statement == nil || db.Statement.Table == "" {
+		return nil
+	}
+	if _, ok := auditIgnoredTables[db.Statement.Table]; ok {
+		return nil
+	}
+	return GetAuditRecorder(db.Statement.Context)
+}
+
+// snapshot loads the rows matched by the conditions of the statement before they are changed
+func snapshot(db *gorm.DB) []map[string]interface{} {
+	stmt := db.Statement
+	exprs := make([]clause.Expression, 0)
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			exprs = append(exprs, where.Exprs...)
+		}
+	}
+	if stmt.Schema != nil && stmt.ReflectValue.IsValid() && stmt.ReflectValue.Kind() == reflect.Struct {
+		for _, field := range stmt.Schema.PrimaryFields {
+			if value, isZero := field.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+				exprs = append(exprs, clause.Eq{Column: clause.Column{Name: field.DBName}, Value: value})
+			}
+		}
+	}
+	if len(exprs) == 0 {
+		return nil
+	}
+	rows := make([]map[string]interface{}, 0)
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table)
+	tx.Statement.AddClause(clause.Where{Exprs: exprs})
+	if err := tx.Limit(auditSnapshotLimit).Find(&rows).Error; err != nil {
+		return nil
+	}
+	return rows
+}
+
+func primaryKeys(db *gorm.DB) []string {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil || !stmt.ReflectValue.IsValid() {
+		return nil
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	ids := make([]string, 0)
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			if value, isZero := field.ValueOf(stmt.Context, stmt.ReflectValue.Index(i)); !isZero {
+				ids = append(ids, fmt.Sprint(value))
+			}
+		}
+	case reflect.Struct:
+		if value, isZero := field.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+			ids = append(ids, fmt.Sprint(value))
+		}
+	}
+	return ids
+}
+
+func toPlain(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var plain interface{}
+	if err = json.Unmarshal(b, &plain); err != nil {
+		return string(b)
+	}
+	return plain
+}
+
+func redact(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		// options keep their secrets in option_value, so look at option_key as well
+		optionKey, _ := value["option_key"].(string)
+		for key, item := range value {
+			if isSensitive(key) || (key == "option_value" && isSensitive(optionKey)) {
+				value[key] = auditRedacted
+				continue
+			}
+			value[key] = redact(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redact(item)
+		}
+		return value
+	case []byte:
+		return string(value)
+	}
+	return v
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range auditSensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func marshalSummary(summary map[string][]interface{}) string {
+	for key, rows := range summary {
+		if len(rows) == 0 {
+			delete(summary, key)
+		}
+	}
+	if len(summary) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(summary)
+	if err != nil {
+		return ""
+	}
+	if len(b) > auditSummaryLimit {
+		return string(b[:auditSummaryLimit]) + "..."
+	}
+	return string(b)
+}
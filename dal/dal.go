@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/sqlite"
@@ -13,7 +14,9 @@ import (
 	"github.com/go-sonic/sonic/config"
 	"github.com/go-sonic/sonic/consts"
 	sonicLog "github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/metrics"
 	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/tracing"
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
@@ -22,7 +25,7 @@ var (
 	DBType consts.DBType
 )
 
-func NewGormDB(conf *config.Config, gormLogger logger.Interface) *gorm.DB {
+func NewGormDB(conf *config.Config, gormLogger logger.Interface, lifecycle fx.Lifecycle) *gorm.DB {
 	var err error
 
 	//nolint:gocritic
@@ -52,8 +55,25 @@ func NewGormDB(conf *config.Config, gormLogger logger.Interface) *gorm.DB {
 	sqlDB.SetMaxIdleConns(200)
 	sqlDB.SetMaxOpenConns(300)
 	sqlDB.SetConnMaxIdleTime(time.Hour)
+	// the hook is appended before those of the services, so the database is closed after them
+	lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return sqlDB.Close()
+		},
+	})
 	SetDefault(DB)
 	dbMigrate()
+	registerAuditCallbacks(DB)
+	if conf.Metrics.Enabled {
+		if err = DB.Use(metrics.NewGormPlugin()); err != nil {
+			sonicLog.Fatal("register metrics plugin error", zap.Error(err))
+		}
+	}
+	if conf.Tracing.Enabled {
+		if err = DB.Use(tracing.NewGormPlugin(string(DBType))); err != nil {
+			sonicLog.Fatal("register tracing plugin error", zap.Error(err))
+		}
+	}
 	return DB
 }
 
@@ -93,9 +113,12 @@ func dbMigrate() {
 	db := DB.Session(&gorm.Session{
 		Logger: DB.Logger.LogMode(logger.Warn),
 	})
-	err := db.AutoMigrate(&entity.Attachment{}, &entity.Category{}, &entity.Comment{}, &entity.CommentBlack{}, &entity.Journal{},
-		&entity.Link{}, &entity.Log{}, &entity.Menu{}, &entity.Meta{}, &entity.Option{}, &entity.Photo{}, &entity.Post{},
-		&entity.PostCategory{}, &entity.PostTag{}, &entity.Tag{}, &entity.ThemeSetting{}, &entity.User{})
+	err := db.AutoMigrate(&entity.Attachment{}, &entity.AuditLog{}, &entity.Category{}, &entity.Comment{}, &entity.CommentBlack{}, &entity.CustomField{}, &entity.Follower{},
+		&entity.GitSyncFile{}, &entity.Journal{}, &entity.Link{}, &entity.Log{}, &entity.Mention{}, &entity.Menu{}, &entity.Meta{}, &entity.Option{},
+		&entity.Photo{},
+		&entity.Post{}, &entity.PostCategory{}, &entity.PostTag{}, &entity.Redirect{}, &entity.Series{}, &entity.SeriesPost{}, &entity.Tag{}, &entity.ThemeSetting{}, &entity.ThemeSettingPreset{},
+		&entity.Translation{},
+		&entity.User{})
 	if err != nil {
 		sonicLog.Fatal("failed auto migrate db", zap.Error(err))
 	}
@@ -16,13 +16,18 @@ import (
 var (
 	Q                   = new(Query)
 	Attachment          *attachment
+	AuditLog            *auditLog
 	Category            *category
 	Comment             *comment
 	CommentBlack        *commentBlack
+	CustomField         *customField
 	FlywaySchemaHistory *flywaySchemaHistory
+	Follower            *follower
+	GitSyncFile         *gitSyncFile
 	Journal             *journal
 	Link                *link
 	Log                 *log
+	Mention             *mention
 	Menu                *menu
 	Meta                *meta
 	Option              *option
@@ -30,21 +35,31 @@ var (
 	Post                *post
 	PostCategory        *postCategory
 	PostTag             *postTag
+	Redirect            *redirect
+	Series              *series
+	SeriesPost          *seriesPost
 	Tag                 *tag
 	ThemeSetting        *themeSetting
+	ThemeSettingPreset  *themeSettingPreset
+	Translation         *translation
 	User                *user
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
 	Attachment = &Q.Attachment
+	AuditLog = &Q.AuditLog
 	Category = &Q.Category
 	Comment = &Q.Comment
 	CommentBlack = &Q.CommentBlack
+	CustomField = &Q.CustomField
 	FlywaySchemaHistory = &Q.FlywaySchemaHistory
+	Follower = &Q.Follower
+	GitSyncFile = &Q.GitSyncFile
 	Journal = &Q.Journal
 	Link = &Q.Link
 	Log = &Q.Log
+	Mention = &Q.Mention
 	Menu = &Q.Menu
 	Meta = &Q.Meta
 	Option = &Q.Option
@@ -52,8 +67,13 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	Post = &Q.Post
 	PostCategory = &Q.PostCategory
 	PostTag = &Q.PostTag
+	Redirect = &Q.Redirect
+	Series = &Q.Series
+	SeriesPost = &Q.SeriesPost
 	Tag = &Q.Tag
 	ThemeSetting = &Q.ThemeSetting
+	ThemeSettingPreset = &Q.ThemeSettingPreset
+	Translation = &Q.Translation
 	User = &Q.User
 }
 
@@ -61,13 +81,18 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
 		db:                  db,
 		Attachment:          newAttachment(db, opts...),
+		AuditLog:            newAuditLog(db, opts...),
 		Category:            newCategory(db, opts...),
 		Comment:             newComment(db, opts...),
 		CommentBlack:        newCommentBlack(db, opts...),
+		CustomField:         newCustomField(db, opts...),
 		FlywaySchemaHistory: newFlywaySchemaHistory(db, opts...),
+		Follower:            newFollower(db, opts...),
+		GitSyncFile:         newGitSyncFile(db, opts...),
 		Journal:             newJournal(db, opts...),
 		Link:                newLink(db, opts...),
 		Log:                 newLog(db, opts...),
+		Mention:             newMention(db, opts...),
 		Menu:                newMenu(db, opts...),
 		Meta:                newMeta(db, opts...),
 		Option:              newOption(db, opts...),
@@ -75,8 +100,13 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 		Post:                newPost(db, opts...),
 		PostCategory:        newPostCategory(db, opts...),
 		PostTag:             newPostTag(db, opts...),
+		Redirect:            newRedirect(db, opts...),
+		Series:              newSeries(db, opts...),
+		SeriesPost:          newSeriesPost(db, opts...),
 		Tag:                 newTag(db, opts...),
 		ThemeSetting:        newThemeSetting(db, opts...),
+		ThemeSettingPreset:  newThemeSettingPreset(db, opts...),
+		Translation:         newTranslation(db, opts...),
 		User:                newUser(db, opts...),
 	}
 }
@@ -85,13 +115,18 @@ type Query struct {
 	db *gorm.DB
 
 	Attachment          attachment
+	AuditLog            auditLog
 	Category            category
 	Comment             comment
 	CommentBlack        commentBlack
+	CustomField         customField
 	FlywaySchemaHistory flywaySchemaHistory
+	Follower            follower
+	GitSyncFile         gitSyncFile
 	Journal             journal
 	Link                link
 	Log                 log
+	Mention             mention
 	Menu                menu
 	Meta                meta
 	Option              option
@@ -99,8 +134,13 @@ type Query struct {
 	Post                post
 	PostCategory        postCategory
 	PostTag             postTag
+	Redirect            redirect
+	Series              series
+	SeriesPost          seriesPost
 	Tag                 tag
 	ThemeSetting        themeSetting
+	ThemeSettingPreset  themeSettingPreset
+	Translation         translation
 	User                user
 }
 
@@ -110,13 +150,18 @@ func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
 		db:                  db,
 		Attachment:          q.Attachment.clone(db),
+		AuditLog:            q.AuditLog.clone(db),
 		Category:            q.Category.clone(db),
 		Comment:             q.Comment.clone(db),
 		CommentBlack:        q.CommentBlack.clone(db),
+		CustomField:         q.CustomField.clone(db),
 		FlywaySchemaHistory: q.FlywaySchemaHistory.clone(db),
+		Follower:            q.Follower.clone(db),
+		GitSyncFile:         q.GitSyncFile.clone(db),
 		Journal:             q.Journal.clone(db),
 		Link:                q.Link.clone(db),
 		Log:                 q.Log.clone(db),
+		Mention:             q.Mention.clone(db),
 		Menu:                q.Menu.clone(db),
 		Meta:                q.Meta.clone(db),
 		Option:              q.Option.clone(db),
@@ -124,8 +169,13 @@ func (q *Query) clone(db *gorm.DB) *Query {
 		Post:                q.Post.clone(db),
 		PostCategory:        q.PostCategory.clone(db),
 		PostTag:             q.PostTag.clone(db),
+		Redirect:            q.Redirect.clone(db),
+		Series:              q.Series.clone(db),
+		SeriesPost:          q.SeriesPost.clone(db),
 		Tag:                 q.Tag.clone(db),
 		ThemeSetting:        q.ThemeSetting.clone(db),
+		ThemeSettingPreset:  q.ThemeSettingPreset.clone(db),
+		Translation:         q.Translation.clone(db),
 		User:                q.User.clone(db),
 	}
 }
@@ -142,13 +192,18 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
 		db:                  db,
 		Attachment:          q.Attachment.replaceDB(db),
+		AuditLog:            q.AuditLog.replaceDB(db),
 		Category:            q.Category.replaceDB(db),
 		Comment:             q.Comment.replaceDB(db),
 		CommentBlack:        q.CommentBlack.replaceDB(db),
+		CustomField:         q.CustomField.replaceDB(db),
 		FlywaySchemaHistory: q.FlywaySchemaHistory.replaceDB(db),
+		Follower:            q.Follower.replaceDB(db),
+		GitSyncFile:         q.GitSyncFile.replaceDB(db),
 		Journal:             q.Journal.replaceDB(db),
 		Link:                q.Link.replaceDB(db),
 		Log:                 q.Log.replaceDB(db),
+		Mention:             q.Mention.replaceDB(db),
 		Menu:                q.Menu.replaceDB(db),
 		Meta:                q.Meta.replaceDB(db),
 		Option:              q.Option.replaceDB(db),
@@ -156,21 +211,31 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 		Post:                q.Post.replaceDB(db),
 		PostCategory:        q.PostCategory.replaceDB(db),
 		PostTag:             q.PostTag.replaceDB(db),
+		Redirect:            q.Redirect.replaceDB(db),
+		Series:              q.Series.replaceDB(db),
+		SeriesPost:          q.SeriesPost.replaceDB(db),
 		Tag:                 q.Tag.replaceDB(db),
 		ThemeSetting:        q.ThemeSetting.replaceDB(db),
+		ThemeSettingPreset:  q.ThemeSettingPreset.replaceDB(db),
+		Translation:         q.Translation.replaceDB(db),
 		User:                q.User.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
 	Attachment          *attachmentDo
+	AuditLog            *auditLogDo
 	Category            *categoryDo
 	Comment             *commentDo
 	CommentBlack        *commentBlackDo
+	CustomField         *customFieldDo
 	FlywaySchemaHistory *flywaySchemaHistoryDo
+	Follower            *followerDo
+	GitSyncFile         *gitSyncFileDo
 	Journal             *journalDo
 	Link                *linkDo
 	Log                 *logDo
+	Mention             *mentionDo
 	Menu                *menuDo
 	Meta                *metaDo
 	Option              *optionDo
@@ -178,21 +243,31 @@ type queryCtx struct {
 	Post                *postDo
 	PostCategory        *postCategoryDo
 	PostTag             *postTagDo
+	Redirect            *redirectDo
+	Series              *seriesDo
+	SeriesPost          *seriesPostDo
 	Tag                 *tagDo
 	ThemeSetting        *themeSettingDo
+	ThemeSettingPreset  *themeSettingPresetDo
+	Translation         *translationDo
 	User                *userDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
 		Attachment:          q.Attachment.WithContext(ctx),
+		AuditLog:            q.AuditLog.WithContext(ctx),
 		Category:            q.Category.WithContext(ctx),
 		Comment:             q.Comment.WithContext(ctx),
 		CommentBlack:        q.CommentBlack.WithContext(ctx),
+		CustomField:         q.CustomField.WithContext(ctx),
 		FlywaySchemaHistory: q.FlywaySchemaHistory.WithContext(ctx),
+		Follower:            q.Follower.WithContext(ctx),
+		GitSyncFile:         q.GitSyncFile.WithContext(ctx),
 		Journal:             q.Journal.WithContext(ctx),
 		Link:                q.Link.WithContext(ctx),
 		Log:                 q.Log.WithContext(ctx),
+		Mention:             q.Mention.WithContext(ctx),
 		Menu:                q.Menu.WithContext(ctx),
 		Meta:                q.Meta.WithContext(ctx),
 		Option:              q.Option.WithContext(ctx),
@@ -200,8 +275,13 @@ func (q *Query) WithContext(ctx context.Context) *queryCtx {
 		Post:                q.Post.WithContext(ctx),
 		PostCategory:        q.PostCategory.WithContext(ctx),
 		PostTag:             q.PostTag.WithContext(ctx),
+		Redirect:            q.Redirect.WithContext(ctx),
+		Series:              q.Series.WithContext(ctx),
+		SeriesPost:          q.SeriesPost.WithContext(ctx),
 		Tag:                 q.Tag.WithContext(ctx),
 		ThemeSetting:        q.ThemeSetting.WithContext(ctx),
+		ThemeSettingPreset:  q.ThemeSettingPreset.WithContext(ctx),
+		Translation:         q.Translation.WithContext(ctx),
 		User:                q.User.WithContext(ctx),
 	}
 }
@@ -3,11 +3,16 @@ package event
 import (
 	"context"
 	"reflect"
+	"runtime"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
 
 	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/metrics"
+	"github.com/go-sonic/sonic/tracing"
 )
 
 type Listener func(ctx context.Context, event Event) error
@@ -21,30 +26,67 @@ type Bus interface {
 type syncLocalBus struct {
 	listeners sync.Map
 	logger    *zap.Logger
+	// inFlight counts the events being handled, shutdown waits for them before the database is closed
+	inFlight sync.WaitGroup
 }
 
-func NewSyncEventBus(logger *zap.Logger) Bus {
-	return &syncLocalBus{
+func NewSyncEventBus(logger *zap.Logger, lifecycle fx.Lifecycle) Bus {
+	e := &syncLocalBus{
 		logger: logger,
 	}
+	lifecycle.Append(fx.Hook{
+		OnStop: e.drain,
+	})
+	return e
 }
 
 func (e *syncLocalBus) Publish(ctx context.Context, event Event) {
+	e.inFlight.Add(1)
+	defer e.inFlight.Done()
 	defer func() {
 		if err := recover(); err != nil {
+			metrics.IncEventListenerError(event.EventType())
 			log.CtxError(ctx, "event panic", zap.String("event", event.EventType()), zap.Stack("stack"), zap.Any("err", err))
 		}
 	}()
 	if listeners, ok := e.listeners.Load(event.EventType()); ok {
 		for _, listener := range listeners.([]Listener) {
-			err := listener(ctx, event)
+			err := e.invoke(ctx, event, listener)
 			if err != nil {
+				metrics.IncEventListenerError(event.EventType())
 				e.logger.Error("error in event listener", zap.Any("event", event.EventType()), zap.Error(err))
 			}
 		}
 	}
 }
 
+func (e *syncLocalBus) invoke(ctx context.Context, event Event, listener Listener) (err error) {
+	listenerName := runtime.FuncForPC(reflect.ValueOf(listener).Pointer()).Name()
+	spanCtx, span := tracing.Start(ctx, "event "+event.EventType(),
+		attribute.String("event.type", event.EventType()),
+		attribute.String("event.listener", listenerName))
+	defer func() {
+		tracing.End(span, err)
+	}()
+	// spanCtx is derived from ctx, so the listeners still read the request values of *gin.Context through it
+	return listener(spanCtx, event)
+}
+
+func (e *syncLocalBus) drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		e.logger.Warn("events are still being handled when shutdown times out")
+		return ctx.Err()
+	}
+}
+
 func (e *syncLocalBus) Subscribe(eventType string, listener Listener) {
 	if listeners, ok := e.listeners.Load(eventType); ok {
 		listeners = append(listeners.([]Listener), listener)
@@ -0,0 +1,45 @@
+package listener
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/service"
+)
+
+type ActivityPubListener struct {
+	PostService        service.PostService
+	ActivityPubService service.ActivityPubService
+}
+
+func NewActivityPubListener(bus event.Bus, postService service.PostService, activityPubService service.ActivityPubService) {
+	a := &ActivityPubListener{
+		PostService:        postService,
+		ActivityPubService: activityPubService,
+	}
+	bus.Subscribe(event.PostUpdateEventName, a.HandlePostUpdateEvent)
+}
+
+// HandlePostUpdateEvent delivers the published post to the followers in the background
+func (a *ActivityPubListener) HandlePostUpdateEvent(ctx context.Context, postUpdateEvent event.Event) error {
+	postID := postUpdateEvent.(*event.PostUpdateEvent).PostID
+	post, err := a.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.Status != consts.PostStatusPublished {
+		return nil
+	}
+	go func() {
+		// every request to the inboxes is bounded by the client timeout, the followers may be many
+		ctx := context.Background()
+		if err := a.ActivityPubService.Deliver(ctx, post); err != nil {
+			log.CtxWarn(ctx, "deliver post err", zap.Int32("postID", postID), zap.Error(err))
+		}
+	}()
+	return nil
+}
@@ -133,7 +133,7 @@ func (c *CommentListener) HandleCommentNew(ctx context.Context, ce event.Event)
 		}
 	}
 	content := bytes.Buffer{}
-	err = c.Template.ExecuteTemplate(&content, template, data)
+	err = c.Template.ExecuteTemplate(ctx, &content, template, data)
 	if err != nil {
 		return err
 	}
@@ -247,7 +247,7 @@ func (c *CommentListener) HandleCommentReply(ctx context.Context, ce event.Event
 		}
 	}
 	content := bytes.Buffer{}
-	err = c.Template.ExecuteTemplate(&content, template, data)
+	err = c.Template.ExecuteTemplate(ctx, &content, template, data)
 	if err != nil {
 		return err
 	}
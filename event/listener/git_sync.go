@@ -0,0 +1,35 @@
+package listener
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/service"
+)
+
+type GitSyncListener struct {
+	GitSyncService service.GitSyncService
+}
+
+func NewGitSyncListener(bus event.Bus, gitSyncService service.GitSyncService) {
+	g := &GitSyncListener{
+		GitSyncService: gitSyncService,
+	}
+	bus.Subscribe(event.PostUpdateEventName, g.HandlePostUpdateEvent)
+}
+
+// HandlePostUpdateEvent commits the published post to the repository in the background,
+// the post updated by pulling the repository is pushed after the pull and found unchanged
+func (g *GitSyncListener) HandlePostUpdateEvent(ctx context.Context, postUpdateEvent event.Event) error {
+	postID := postUpdateEvent.(*event.PostUpdateEvent).PostID
+	go func() {
+		ctx := context.Background()
+		if err := g.GitSyncService.PushPost(ctx, postID); err != nil {
+			log.CtxWarn(ctx, "git sync push post err", zap.Int32("postID", postID), zap.Error(err))
+		}
+	}()
+	return nil
+}
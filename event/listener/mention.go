@@ -0,0 +1,46 @@
+package listener
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/service"
+)
+
+type MentionListener struct {
+	PostService    service.PostService
+	MentionService service.MentionService
+}
+
+func NewMentionListener(bus event.Bus, postService service.PostService, mentionService service.MentionService) {
+	m := &MentionListener{
+		PostService:    postService,
+		MentionService: mentionService,
+	}
+	bus.Subscribe(event.PostUpdateEventName, m.HandlePostUpdateEvent)
+}
+
+// HandlePostUpdateEvent sends webmentions and pingbacks to the links of the published post in the background
+func (m *MentionListener) HandlePostUpdateEvent(ctx context.Context, postUpdateEvent event.Event) error {
+	postID := postUpdateEvent.(*event.PostUpdateEvent).PostID
+	post, err := m.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.Status != consts.PostStatusPublished {
+		return nil
+	}
+	go func() {
+		// every link is requested at most twice, for discovering the endpoint and sending
+		ctx, cancel := context.WithTimeout(context.Background(), consts.MentionTimeout*2*consts.MentionSendLimit)
+		defer cancel()
+		if err := m.MentionService.Send(ctx, post); err != nil {
+			log.CtxWarn(ctx, "send mentions err", zap.Int32("postID", postID), zap.Error(err))
+		}
+	}()
+	return nil
+}
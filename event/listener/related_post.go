@@ -0,0 +1,25 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/service"
+)
+
+type RelatedPostListener struct {
+	RelatedPostService service.RelatedPostService
+}
+
+func NewRelatedPostListener(bus event.Bus, relatedPostService service.RelatedPostService) {
+	r := &RelatedPostListener{
+		RelatedPostService: relatedPostService,
+	}
+	bus.Subscribe(event.PostUpdateEventName, r.HandlePostUpdateEvent)
+}
+
+// HandlePostUpdateEvent marks the related posts stale, a burst of updates computes them only once
+func (r *RelatedPostListener) HandlePostUpdateEvent(ctx context.Context, postUpdateEvent event.Event) error {
+	r.RelatedPostService.Invalidate(ctx)
+	return nil
+}
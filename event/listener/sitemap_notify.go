@@ -0,0 +1,62 @@
+package listener
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/service"
+)
+
+type SitemapNotifyListener struct {
+	PostService    service.PostService
+	OptionService  service.OptionService
+	SitemapService service.SitemapService
+}
+
+func NewSitemapNotifyListener(bus event.Bus,
+	postService service.PostService,
+	optionService service.OptionService,
+	sitemapService service.SitemapService,
+) {
+	s := &SitemapNotifyListener{
+		PostService:    postService,
+		OptionService:  optionService,
+		SitemapService: sitemapService,
+	}
+	bus.Subscribe(event.PostUpdateEventName, s.HandlePostUpdateEvent)
+}
+
+// HandlePostUpdateEvent notifies the search engines of the published post without blocking the request
+func (s *SitemapNotifyListener) HandlePostUpdateEvent(ctx context.Context, postUpdateEvent event.Event) error {
+	postID := postUpdateEvent.(*event.PostUpdateEvent).PostID
+	post, err := s.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.Status != consts.PostStatusPublished {
+		return nil
+	}
+	fullPath, err := s.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return err
+	}
+	blogURL, err := s.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return err
+	}
+	if len(fullPath) > 0 && fullPath[0] == '/' {
+		fullPath = blogURL + fullPath
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), consts.IndexNowTimeout*2)
+		defer cancel()
+		if err := s.SitemapService.Notify(ctx, []string{fullPath}); err != nil {
+			log.CtxWarn(ctx, "notify search engines err", zap.Int32("postID", postID), zap.Error(err))
+		}
+	}()
+	return nil
+}
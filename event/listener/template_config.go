@@ -2,7 +2,6 @@ package listener
 
 import (
 	"context"
-	"path/filepath"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -14,6 +13,7 @@ import (
 	"github.com/go-sonic/sonic/dal"
 	"github.com/go-sonic/sonic/event"
 	"github.com/go-sonic/sonic/handler"
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/property"
 	"github.com/go-sonic/sonic/service"
 	"github.com/go-sonic/sonic/template"
@@ -102,8 +102,7 @@ func (t *TemplateConfigListener) loadThemeTemplate(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	err = t.Template.Load([]string{filepath.Join(t.Config.Sonic.TemplateDir, "common"), theme.ThemePath})
-	return err
+	return t.Template.Load(t.ThemeService.GetThemeTemplatePaths(ctx, theme))
 }
 
 func (t *TemplateConfigListener) loadThemeConfig(ctx context.Context) error {
@@ -175,6 +174,7 @@ func (t *TemplateConfigListener) loadOption(ctx context.Context) error {
 	tagPrefix := t.OptionService.GetOrByDefault(ctx, property.TagsPrefix)
 	linkPrefix := t.OptionService.GetOrByDefault(ctx, property.LinksPrefix)
 	photoPrefix := t.OptionService.GetOrByDefault(ctx, property.PhotosPrefix)
+	seriesPrefix := t.OptionService.GetOrByDefault(ctx, property.SeriesPrefix)
 	urlContext := "/"
 	if globalAbsolutePathEnabled.(bool) {
 		urlContext = blogBaseURL.(string) + "/"
@@ -190,12 +190,16 @@ func (t *TemplateConfigListener) loadOption(ctx context.Context) error {
 	t.Template.SetSharedVariable("seo_description", seoDescription)
 	t.Template.SetSharedVariable("rss_url", blogBaseURL.(string)+"/rss.xml")
 	t.Template.SetSharedVariable("atom_url", blogBaseURL.(string)+"/atom.xml")
+	t.Template.SetSharedVariable("json_feed_url", blogBaseURL.(string)+"/feed.json")
+	t.Template.SetSharedVariable("feeds", dto.NewFeedLinks(blogTitle.(string),
+		blogBaseURL.(string)+"/rss.xml", blogBaseURL.(string)+"/atom.xml", blogBaseURL.(string)+"/feed.json"))
 	t.Template.SetSharedVariable("sitemap_xml_url", blogBaseURL.(string)+"/sitemap.xml")
 	t.Template.SetSharedVariable("sitemap_html_url", blogBaseURL.(string)+"/sitemap.html")
 	t.Template.SetSharedVariable("links_url", urlContext+linkPrefix.(string))
 	t.Template.SetSharedVariable("photos_url", urlContext+photoPrefix.(string))
 	t.Template.SetSharedVariable("journals_url", urlContext+journalPrefix.(string))
 	t.Template.SetSharedVariable("archives_url", urlContext+archivePrefix.(string))
+	t.Template.SetSharedVariable("series_url", urlContext+seriesPrefix.(string))
 	t.Template.SetSharedVariable("categories_url", urlContext+categoryPrefix.(string))
 	t.Template.SetSharedVariable("tags_url", urlContext+tagPrefix.(string))
 	return nil
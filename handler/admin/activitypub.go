@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/handler/binding"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type ActivityPubHandler struct {
+	ActivityPubService service.ActivityPubService
+}
+
+func NewActivityPubHandler(activityPubService service.ActivityPubService) *ActivityPubHandler {
+	return &ActivityPubHandler{
+		ActivityPubService: activityPubService,
+	}
+}
+
+func (a *ActivityPubHandler) ListFollower(ctx *gin.Context) (interface{}, error) {
+	var page param.Page
+	err := ctx.ShouldBindWith(&page, binding.CustomFormBinding)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Parameter error")
+	}
+	followers, totalCount, err := a.ActivityPubService.PageFollower(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	followerDTOs := make([]*dto.Follower, 0, len(followers))
+	for _, follower := range followers {
+		followerDTOs = append(followerDTOs, a.ActivityPubService.ConvertToFollowerDTO(follower))
+	}
+	return dto.NewPage(followerDTOs, totalCount, page), nil
+}
+
+func (a *ActivityPubHandler) DeleteFollower(ctx *gin.Context) (interface{}, error) {
+	followerID, err := util.ParamInt32(ctx, "followerID")
+	if err != nil {
+		return nil, err
+	}
+	return nil, a.ActivityPubService.DeleteFollower(ctx, followerID)
+}
@@ -19,13 +19,19 @@ type AdminHandler struct {
 	OptionService       service.OptionService
 	AdminService        service.AdminService
 	TwoFactorMFAService service.TwoFactorTOTPMFAService
+	OIDCService         service.OIDCService
+	LoginProtectService service.LoginProtectService
 }
 
-func NewAdminHandler(optionService service.OptionService, adminService service.AdminService, twoFactorMFA service.TwoFactorTOTPMFAService) *AdminHandler {
+func NewAdminHandler(optionService service.OptionService, adminService service.AdminService, twoFactorMFA service.TwoFactorTOTPMFAService, oidcService service.OIDCService,
+	loginProtectService service.LoginProtectService,
+) *AdminHandler {
 	return &AdminHandler{
 		OptionService:       optionService,
 		AdminService:        adminService,
 		TwoFactorMFAService: twoFactorMFA,
+		OIDCService:         oidcService,
+		LoginProtectService: loginProtectService,
 	}
 }
 
@@ -65,6 +71,48 @@ func (a *AdminHandler) Auth(ctx *gin.Context) (interface{}, error) {
 	return a.AdminService.Auth(ctx, loginParam)
 }
 
+func (a *AdminHandler) GetLoginCaptcha(ctx *gin.Context) (interface{}, error) {
+	return a.LoginProtectService.GenerateCaptcha(ctx)
+}
+
+func (a *AdminHandler) ListOIDCProviders(ctx *gin.Context) (interface{}, error) {
+	return a.OIDCService.ListProviders(ctx), nil
+}
+
+func (a *AdminHandler) OIDCAuthorize(ctx *gin.Context) (interface{}, error) {
+	provider, err := util.ParamString(ctx, "provider")
+	if err != nil {
+		return nil, err
+	}
+	return a.OIDCService.Authorize(ctx, provider)
+}
+
+func (a *AdminHandler) OIDCCallback(ctx *gin.Context) (interface{}, error) {
+	provider, err := util.ParamString(ctx, "provider")
+	if err != nil {
+		return nil, err
+	}
+	if errCode, ok := ctx.GetQuery("error"); ok {
+		return nil, xerr.BadParam.New("oidc callback error=%s description=%s", errCode, ctx.Query("error_description")).
+			WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	code, err := util.MustGetQueryString(ctx, "code")
+	if err != nil {
+		return nil, err
+	}
+	state, err := util.MustGetQueryString(ctx, "state")
+	if err != nil {
+		return nil, err
+	}
+	// users with MFA repeat the callback with the TOTP code
+	loginParam := param.LoginParam{
+		AuthCode:    ctx.Query("authcode"),
+		CaptchaID:   ctx.Query("captchaId"),
+		CaptchaCode: ctx.Query("captchaCode"),
+	}
+	return a.AdminService.AuthByOIDC(ctx, provider, code, state, loginParam)
+}
+
 func (a *AdminHandler) LogOut(ctx *gin.Context) (interface{}, error) {
 	err := a.AdminService.ClearToken(ctx)
 	return nil, err
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type AuditLogHandler struct {
+	AuditLogService service.AuditLogService
+}
+
+func NewAuditLogHandler(auditLogService service.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		AuditLogService: auditLogService,
+	}
+}
+
+func (a *AuditLogHandler) PageAuditLog(ctx *gin.Context) (interface{}, error) {
+	var auditLogQuery param.AuditLogQuery
+	err := ctx.ShouldBindQuery(&auditLogQuery)
+	if err != nil {
+		return nil, xerr.WithMsg(err, "parameter error").WithStatus(xerr.StatusBadRequest)
+	}
+	if auditLogQuery.Sort == nil || len(auditLogQuery.Sort.Fields) == 0 {
+		auditLogQuery.Sort = &param.Sort{
+			Fields: []string{"createTime,desc"},
+		}
+	}
+	auditLogs, totalCount, err := a.AuditLogService.Page(ctx, auditLogQuery)
+	if err != nil {
+		return nil, err
+	}
+	auditLogDTOs := make([]*dto.AuditLog, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		auditLogDTOs = append(auditLogDTOs, a.AuditLogService.ConvertToDTO(auditLog))
+	}
+	return dto.NewPage(auditLogDTOs, totalCount, auditLogQuery.Page), nil
+}
+
+func (a *AuditLogHandler) ExportAuditLog(ctx *gin.Context) {
+	var auditLogQuery param.AuditLogQuery
+	err := ctx.ShouldBindQuery(&auditLogQuery)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &dto.BaseDTO{
+			Status:  http.StatusBadRequest,
+			Message: "parameter error",
+		})
+		return
+	}
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", "attachment; filename=audit-log.csv")
+	ctx.Status(http.StatusOK)
+	err = a.AuditLogService.ExportCSV(ctx, auditLogQuery, ctx.Writer)
+	if err != nil {
+		// the header has been written, so the error can only be logged
+		log.CtxErrorf(ctx, "export audit log err=%+v", err)
+	}
+}
+
+func (a *AuditLogHandler) ClearAuditLog(ctx *gin.Context) (interface{}, error) {
+	return nil, a.AuditLogService.Clear(ctx)
+}
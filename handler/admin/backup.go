@@ -120,12 +120,70 @@ func (b *BackupHandler) ImportMarkdown(ctx *gin.Context) (interface{}, error) {
 		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
 	}
 	filenameExt := path.Ext(fileHeader.Filename)
+	// the zip is the bundle exported with the attachments
+	if filenameExt == ".zip" {
+		return b.BackupService.ImportMarkdownBundle(ctx, fileHeader)
+	}
 	if filenameExt != ".md" && filenameExt != ".markdown" && filenameExt != ".mdown" {
 		return nil, xerr.WithMsg(err, "Unsupported format").WithStatus(xerr.StatusBadRequest)
 	}
 	return nil, b.BackupService.ImportMarkdown(ctx, fileHeader)
 }
 
+func (b *BackupHandler) ImportWordPress(ctx *gin.Context) (interface{}, error) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
+	}
+	if path.Ext(fileHeader.Filename) != ".xml" {
+		return nil, xerr.BadParam.New("").WithMsg("Unsupported format").WithStatus(xerr.StatusBadRequest)
+	}
+	// the zipped wp-content/uploads directory is optional, the media not in it are downloaded
+	uploadsHeader, err := ctx.FormFile("uploads")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
+	}
+	return b.BackupService.ImportWordPress(ctx, fileHeader, uploadsHeader)
+}
+
+func (b *BackupHandler) ImportHugo(ctx *gin.Context) (interface{}, error) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
+	}
+	if path.Ext(fileHeader.Filename) != ".zip" {
+		return nil, xerr.BadParam.New("").WithMsg("Unsupported format").WithStatus(xerr.StatusBadRequest)
+	}
+	return b.BackupService.ImportHugo(ctx, fileHeader)
+}
+
+func (b *BackupHandler) ImportHexo(ctx *gin.Context) (interface{}, error) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
+	}
+	if path.Ext(fileHeader.Filename) != ".zip" {
+		return nil, xerr.BadParam.New("").WithMsg("Unsupported format").WithStatus(xerr.StatusBadRequest)
+	}
+	return b.BackupService.ImportHexo(ctx, fileHeader)
+}
+
+func (b *BackupHandler) ImportHalo(ctx *gin.Context) (interface{}, error) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
+	}
+	if path.Ext(fileHeader.Filename) != ".json" {
+		return nil, xerr.BadParam.New("").WithMsg("Unsupported format").WithStatus(xerr.StatusBadRequest)
+	}
+	// the zipped upload directory of Halo is optional, the media not in it are downloaded
+	uploadsHeader, err := ctx.FormFile("uploads")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		return nil, xerr.WithMsg(err, "上传文件错误").WithStatus(xerr.StatusBadRequest)
+	}
+	return b.BackupService.ImportHalo(ctx, fileHeader, uploadsHeader)
+}
+
 func (b *BackupHandler) ExportData(ctx *gin.Context) (interface{}, error) {
 	return b.BackupService.ExportData(ctx)
 }
@@ -182,7 +240,7 @@ func (b *BackupHandler) ExportMarkdown(ctx *gin.Context) (interface{}, error) {
 		}
 		return nil, xerr.WithStatus(err, xerr.StatusBadRequest)
 	}
-	return b.BackupService.ExportMarkdown(ctx, exportMarkdownParam.NeedFrontMatter)
+	return b.BackupService.ExportMarkdown(ctx, exportMarkdownParam.NeedFrontMatter, exportMarkdownParam.WithAttachments)
 }
 
 func (b *BackupHandler) ListMarkdowns(ctx *gin.Context) (interface{}, error) {
@@ -238,6 +238,18 @@ func (s *SheetCommentHandler) ConvertToWithSheet(ctx context.Context, comments [
 	if err != nil {
 		return nil, err
 	}
+	postList := make([]*entity.Post, 0, len(posts))
+	for _, post := range posts {
+		postList = append(postList, post)
+	}
+	postMinimals, err := s.SheetAssembler.ConvertToMinimalDTOs(ctx, postList)
+	if err != nil {
+		return nil, err
+	}
+	postMinimalMap := make(map[int32]*dto.PostMinimal, len(postMinimals))
+	for _, postMinimal := range postMinimals {
+		postMinimalMap[postMinimal.ID] = postMinimal
+	}
 	result := make([]*vo.SheetCommentWithSheet, 0, len(comments))
 	for _, comment := range comments {
 		commentDTO, err := s.SheetCommentAssembler.ConvertToDTO(ctx, comment)
@@ -248,13 +260,7 @@ func (s *SheetCommentHandler) ConvertToWithSheet(ctx context.Context, comments [
 			Comment: *commentDTO,
 		}
 		result = append(result, commentWithSheet)
-		post, ok := posts[comment.PostID]
-		if ok {
-			commentWithSheet.PostMinimal, err = s.SheetAssembler.ConvertToMinimalDTO(ctx, post)
-			if err != nil {
-				return nil, err
-			}
-		}
+		commentWithSheet.PostMinimal = postMinimalMap[comment.PostID]
 	}
 	return result, nil
 }
@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type CustomFieldHandler struct {
+	CustomFieldService service.CustomFieldService
+}
+
+func NewCustomFieldHandler(customFieldService service.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		CustomFieldService: customFieldService,
+	}
+}
+
+func (c *CustomFieldHandler) ListCustomFields(ctx *gin.Context) (interface{}, error) {
+	fields, err := c.CustomFieldService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.CustomFieldService.ConvertToDTOs(fields)
+}
+
+// ListSchemas lists the fields the editor shows for the post type and template,
+// including the ones declared by the activated theme
+func (c *CustomFieldHandler) ListSchemas(ctx *gin.Context) (interface{}, error) {
+	postType, err := strconv.ParseInt(ctx.DefaultQuery("postType", "0"), 10, 32)
+	if err != nil || (consts.PostType(postType) != consts.PostTypePost && consts.PostType(postType) != consts.PostTypeSheet) {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("postType parameter error")
+	}
+	return c.CustomFieldService.ListSchemas(ctx, consts.PostType(postType), ctx.Query("template"))
+}
+
+func (c *CustomFieldHandler) GetCustomFieldByID(ctx *gin.Context) (interface{}, error) {
+	fieldID, err := util.ParamInt32(ctx, "fieldID")
+	if err != nil {
+		return nil, err
+	}
+	field, err := c.CustomFieldService.GetByID(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	return c.CustomFieldService.ConvertToDTO(field)
+}
+
+func (c *CustomFieldHandler) CreateCustomField(ctx *gin.Context) (interface{}, error) {
+	fieldParam, err := bindCustomFieldParam(ctx)
+	if err != nil {
+		return nil, err
+	}
+	field, err := c.CustomFieldService.Create(ctx, fieldParam)
+	if err != nil {
+		return nil, err
+	}
+	return c.CustomFieldService.ConvertToDTO(field)
+}
+
+func (c *CustomFieldHandler) UpdateCustomField(ctx *gin.Context) (interface{}, error) {
+	fieldID, err := util.ParamInt32(ctx, "fieldID")
+	if err != nil {
+		return nil, err
+	}
+	fieldParam, err := bindCustomFieldParam(ctx)
+	if err != nil {
+		return nil, err
+	}
+	field, err := c.CustomFieldService.Update(ctx, fieldID, fieldParam)
+	if err != nil {
+		return nil, err
+	}
+	return c.CustomFieldService.ConvertToDTO(field)
+}
+
+func (c *CustomFieldHandler) DeleteCustomField(ctx *gin.Context) (interface{}, error) {
+	fieldID, err := util.ParamInt32(ctx, "fieldID")
+	if err != nil {
+		return nil, err
+	}
+	return nil, c.CustomFieldService.Delete(ctx, fieldID)
+}
+
+func bindCustomFieldParam(ctx *gin.Context) (*param.CustomField, error) {
+	fieldParam := &param.CustomField{}
+	err := ctx.ShouldBindJSON(fieldParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	return fieldParam, nil
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type GitSyncHandler struct {
+	GitSyncService service.GitSyncService
+}
+
+func NewGitSyncHandler(gitSyncService service.GitSyncService) *GitSyncHandler {
+	return &GitSyncHandler{
+		GitSyncService: gitSyncService,
+	}
+}
+
+func (g *GitSyncHandler) GetStatus(ctx *gin.Context) (interface{}, error) {
+	return g.GitSyncService.Status(ctx)
+}
+
+func (g *GitSyncHandler) Pull(ctx *gin.Context) (interface{}, error) {
+	return g.GitSyncService.Pull(ctx)
+}
+
+func (g *GitSyncHandler) Push(ctx *gin.Context) (interface{}, error) {
+	return g.GitSyncService.Push(ctx)
+}
+
+func (g *GitSyncHandler) ResolveConflict(ctx *gin.Context) (interface{}, error) {
+	postID, err := util.ParamInt32(ctx, "postID")
+	if err != nil {
+		return nil, err
+	}
+	var resolveParam param.GitSyncResolve
+	err = ctx.ShouldBindJSON(&resolveParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	return nil, g.GitSyncService.Resolve(ctx, postID, resolveParam.Keep == "post")
+}
@@ -4,25 +4,35 @@ import "github.com/go-sonic/sonic/injection"
 
 func init() {
 	injection.Provide(
+		NewActivityPubHandler,
 		NewAdminHandler,
 		NewAttachmentHandler,
+		NewAuditLogHandler,
 		NewCategoryHandler,
 		NewBackupHandler,
+		NewGitSyncHandler,
+		NewMetaWeblogHandler,
 		NewInstallHandler,
 		NewJournalHandler,
 		NewJournalCommentHandler,
 		NewLinkHandler,
 		NewLogHandler,
+		NewMentionHandler,
 		NewMenuHandler,
 		NewOptionHandler,
 		NewPhotoHandler,
 		NewPostHandler,
 		NewPostCommentHandler,
+		NewRedirectHandler,
+		NewSeriesHandler,
+		NewCustomFieldHandler,
 		NewSheetHandler,
 		NewSheetCommentHandler,
 		NewStatisticHandler,
 		NewTagHandler,
+		NewTranslationHandler,
 		NewThemeHandler,
+		NewThemeSettingPresetHandler,
 		NewUserHandler,
 		NewEmailHandler,
 	)
@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/handler/binding"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/vo"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/service/assembler"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type MentionHandler struct {
+	MentionService service.MentionService
+	PostService    service.PostService
+	PostAssembler  assembler.PostAssembler
+}
+
+func NewMentionHandler(mentionService service.MentionService, postService service.PostService, postAssembler assembler.PostAssembler) *MentionHandler {
+	return &MentionHandler{
+		MentionService: mentionService,
+		PostService:    postService,
+		PostAssembler:  postAssembler,
+	}
+}
+
+func (m *MentionHandler) ListMention(ctx *gin.Context) (interface{}, error) {
+	var mentionQuery param.MentionQuery
+	err := ctx.ShouldBindWith(&mentionQuery, binding.CustomFormBinding)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Parameter error")
+	}
+	mentionQuery.Sort = &param.Sort{
+		Fields: []string{"createTime,desc"},
+	}
+	mentions, totalCount, err := m.MentionService.Page(ctx, mentionQuery)
+	if err != nil {
+		return nil, err
+	}
+	mentionVOs, err := m.convertToWithPost(ctx, mentions)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewPage(mentionVOs, totalCount, mentionQuery.Page), nil
+}
+
+func (m *MentionHandler) UpdateMentionStatus(ctx *gin.Context) (interface{}, error) {
+	mentionID, err := util.ParamInt32(ctx, "mentionID")
+	if err != nil {
+		return nil, err
+	}
+	strStatus, err := util.ParamString(ctx, "status")
+	if err != nil {
+		return nil, err
+	}
+	status, err := consts.CommentStatusFromString(strStatus)
+	if err != nil {
+		return nil, err
+	}
+	mention, err := m.MentionService.UpdateStatus(ctx, mentionID, status)
+	if err != nil {
+		return nil, err
+	}
+	return m.MentionService.ConvertToDTO(mention), nil
+}
+
+func (m *MentionHandler) UpdateMentionStatusBatch(ctx *gin.Context) (interface{}, error) {
+	strStatus, err := util.ParamString(ctx, "status")
+	if err != nil {
+		return nil, err
+	}
+	status, err := consts.CommentStatusFromString(strStatus)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int32, 0)
+	err = ctx.ShouldBindJSON(&ids)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("mention ids error")
+	}
+	mentions, err := m.MentionService.UpdateStatusBatch(ctx, ids, status)
+	if err != nil {
+		return nil, err
+	}
+	return m.MentionService.ConvertToDTOs(mentions), nil
+}
+
+func (m *MentionHandler) DeleteMention(ctx *gin.Context) (interface{}, error) {
+	mentionID, err := util.ParamInt32(ctx, "mentionID")
+	if err != nil {
+		return nil, err
+	}
+	return nil, m.MentionService.Delete(ctx, mentionID)
+}
+
+func (m *MentionHandler) DeleteMentionBatch(ctx *gin.Context) (interface{}, error) {
+	ids := make([]int32, 0)
+	err := ctx.ShouldBindJSON(&ids)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("mention ids error")
+	}
+	return nil, m.MentionService.DeleteBatch(ctx, ids)
+}
+
+func (m *MentionHandler) convertToWithPost(ctx *gin.Context, mentions []*entity.Mention) ([]*vo.MentionWithPost, error) {
+	postIDs := make([]int32, 0, len(mentions))
+	for _, mention := range mentions {
+		postIDs = append(postIDs, mention.PostID)
+	}
+	posts, err := m.PostService.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	postList := make([]*entity.Post, 0, len(posts))
+	for _, post := range posts {
+		postList = append(postList, post)
+	}
+	postMinimals, err := m.PostAssembler.ConvertToMinimalDTOs(ctx, postList)
+	if err != nil {
+		return nil, err
+	}
+	postMinimalMap := make(map[int32]*dto.PostMinimal, len(postMinimals))
+	for _, postMinimal := range postMinimals {
+		postMinimalMap[postMinimal.ID] = postMinimal
+	}
+	result := make([]*vo.MentionWithPost, 0, len(mentions))
+	for _, mention := range mentions {
+		mentionWithPost := &vo.MentionWithPost{
+			Mention: *m.MentionService.ConvertToDTO(mention),
+		}
+		mentionWithPost.Post = postMinimalMap[mention.PostID]
+		result = append(result, mentionWithPost)
+	}
+	return result, nil
+}
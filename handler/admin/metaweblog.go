@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/service"
+)
+
+type MetaWeblogHandler struct {
+	MetaWeblogService service.MetaWeblogService
+}
+
+func NewMetaWeblogHandler(metaWeblogService service.MetaWeblogService) *MetaWeblogHandler {
+	return &MetaWeblogHandler{
+		MetaWeblogService: metaWeblogService,
+	}
+}
+
+// GenerateAppPassword replaces the app password of the XML-RPC editors, the password can not be viewed again
+func (m *MetaWeblogHandler) GenerateAppPassword(ctx *gin.Context) (interface{}, error) {
+	return m.MetaWeblogService.GenerateAppPassword(ctx)
+}
+
+func (m *MetaWeblogHandler) RevokeAppPassword(ctx *gin.Context) (interface{}, error) {
+	return nil, m.MetaWeblogService.RevokeAppPassword(ctx)
+}
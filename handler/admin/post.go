@@ -40,6 +40,7 @@ func (p *PostHandler) ListPosts(ctx *gin.Context) (interface{}, error) {
 	if postQuery.Sort == nil {
 		postQuery.Sort = &param.Sort{Fields: []string{"topPriority,desc", "createTime,desc"}}
 	}
+	postQuery.Metas = ctx.QueryMap("metas")
 	posts, totalCount, err := p.PostService.Page(ctx, postQuery)
 	if err != nil {
 		return nil, err
@@ -48,13 +49,9 @@ func (p *PostHandler) ListPosts(ctx *gin.Context) (interface{}, error) {
 		postVOs, err := p.PostAssembler.ConvertToListVO(ctx, posts)
 		return dto.NewPage(postVOs, totalCount, postQuery.Page), err
 	}
-	postDTOs := make([]*dto.Post, 0)
-	for _, post := range posts {
-		postDTO, err := p.PostAssembler.ConvertToSimpleDTO(ctx, post)
-		if err != nil {
-			return nil, err
-		}
-		postDTOs = append(postDTOs, postDTO)
+	postDTOs, err := p.PostAssembler.ConvertToSimpleDTOs(ctx, posts)
+	if err != nil {
+		return nil, err
 	}
 	return dto.NewPage(postDTOs, totalCount, postQuery.Page), nil
 }
@@ -80,16 +77,7 @@ func (p *PostHandler) ListLatestPosts(ctx *gin.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	postMinimals := make([]*dto.PostMinimal, 0, len(posts))
-
-	for _, post := range posts {
-		postMinimal, err := p.PostAssembler.ConvertToMinimalDTO(ctx, post)
-		if err != nil {
-			return nil, err
-		}
-		postMinimals = append(postMinimals, postMinimal)
-	}
-	return postMinimals, nil
+	return p.PostAssembler.ConvertToMinimalDTOs(ctx, posts)
 }
 
 func (p *PostHandler) ListPostsByStatus(ctx *gin.Context) (interface{}, error) {
@@ -122,13 +110,9 @@ func (p *PostHandler) ListPostsByStatus(ctx *gin.Context) (interface{}, error) {
 		return dto.NewPage(postVOs, totalCount, postQuery.Page), err
 	}
 
-	postDTOs := make([]*dto.Post, 0)
-	for _, post := range posts {
-		postDTO, err := p.PostAssembler.ConvertToSimpleDTO(ctx, post)
-		if err != nil {
-			return nil, err
-		}
-		postDTOs = append(postDTOs, postDTO)
+	postDTOs, err := p.PostAssembler.ConvertToSimpleDTOs(ctx, posts)
+	if err != nil {
+		return nil, err
 	}
 
 	return dto.NewPage(postDTOs, totalCount, postQuery.Page), nil
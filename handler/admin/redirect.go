@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/go-sonic/sonic/handler/binding"
+	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type RedirectHandler struct {
+	RedirectService service.RedirectService
+}
+
+func NewRedirectHandler(redirectService service.RedirectService) *RedirectHandler {
+	return &RedirectHandler{
+		RedirectService: redirectService,
+	}
+}
+
+func (r *RedirectHandler) ListRedirects(ctx *gin.Context) (interface{}, error) {
+	sort := param.Sort{}
+	err := ctx.ShouldBindWith(&sort, binding.CustomFormBinding)
+	if err != nil {
+		return nil, xerr.WithMsg(err, "sort parameter error").WithStatus(xerr.StatusBadRequest)
+	}
+	if len(sort.Fields) == 0 {
+		sort.Fields = append(sort.Fields, "createTime,desc")
+	}
+	redirects, err := r.RedirectService.List(ctx, &sort)
+	if err != nil {
+		return nil, err
+	}
+	return r.RedirectService.ConvertToDTOs(redirects), nil
+}
+
+func (r *RedirectHandler) CreateRedirect(ctx *gin.Context) (interface{}, error) {
+	redirectParam := &param.Redirect{}
+	err := ctx.ShouldBindJSON(redirectParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	redirect, err := r.RedirectService.Create(ctx, redirectParam)
+	if err != nil {
+		return nil, err
+	}
+	return r.RedirectService.ConvertToDTO(redirect), nil
+}
+
+func (r *RedirectHandler) UpdateRedirect(ctx *gin.Context) (interface{}, error) {
+	id, err := util.ParamInt32(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	redirectParam := &param.Redirect{}
+	err = ctx.ShouldBindJSON(redirectParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	redirect, err := r.RedirectService.Update(ctx, id, redirectParam)
+	if err != nil {
+		return nil, err
+	}
+	return r.RedirectService.ConvertToDTO(redirect), nil
+}
+
+func (r *RedirectHandler) DeleteRedirect(ctx *gin.Context) (interface{}, error) {
+	id, err := util.ParamInt32(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.RedirectService.Delete(ctx, id)
+}
@@ -0,0 +1,132 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/vo"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/service/assembler"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type SeriesHandler struct {
+	SeriesService service.SeriesService
+	PostAssembler assembler.PostAssembler
+}
+
+func NewSeriesHandler(seriesService service.SeriesService, postAssembler assembler.PostAssembler) *SeriesHandler {
+	return &SeriesHandler{
+		SeriesService: seriesService,
+		PostAssembler: postAssembler,
+	}
+}
+
+func (s *SeriesHandler) ListSeries(ctx *gin.Context) (interface{}, error) {
+	series, err := s.SeriesService.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.SeriesService.ConvertToDTOs(ctx, series)
+}
+
+func (s *SeriesHandler) GetSeriesByID(ctx *gin.Context) (interface{}, error) {
+	seriesID, err := util.ParamInt32(ctx, "seriesID")
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToDetail(ctx, seriesID)
+}
+
+func (s *SeriesHandler) CreateSeries(ctx *gin.Context) (interface{}, error) {
+	seriesParam, err := bindSeriesParam(ctx)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.SeriesService.Create(ctx, seriesParam)
+	if err != nil {
+		return nil, err
+	}
+	return s.SeriesService.ConvertToDTO(ctx, series)
+}
+
+func (s *SeriesHandler) UpdateSeries(ctx *gin.Context) (interface{}, error) {
+	seriesID, err := util.ParamInt32(ctx, "seriesID")
+	if err != nil {
+		return nil, err
+	}
+	seriesParam, err := bindSeriesParam(ctx)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.SeriesService.Update(ctx, seriesID, seriesParam)
+	if err != nil {
+		return nil, err
+	}
+	return s.SeriesService.ConvertToDTO(ctx, series)
+}
+
+func (s *SeriesHandler) DeleteSeries(ctx *gin.Context) (interface{}, error) {
+	seriesID, err := util.ParamInt32(ctx, "seriesID")
+	if err != nil {
+		return nil, err
+	}
+	return nil, s.SeriesService.Delete(ctx, seriesID)
+}
+
+func (s *SeriesHandler) UpdateSeriesPosts(ctx *gin.Context) (interface{}, error) {
+	seriesID, err := util.ParamInt32(ctx, "seriesID")
+	if err != nil {
+		return nil, err
+	}
+	var seriesPosts param.SeriesPosts
+	if err := ctx.ShouldBindJSON(&seriesPosts); err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	if err := s.SeriesService.UpdatePosts(ctx, seriesID, seriesPosts.PostIDs); err != nil {
+		return nil, err
+	}
+	return s.convertToDetail(ctx, seriesID)
+}
+
+// convertToDetail gets the series with all its posts including the drafts
+func (s *SeriesHandler) convertToDetail(ctx *gin.Context, seriesID int32) (*vo.SeriesDetail, error) {
+	series, err := s.SeriesService.GetByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	seriesDTO, err := s.SeriesService.ConvertToDTO(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := s.SeriesService.ListPosts(ctx, seriesID, nil)
+	if err != nil {
+		return nil, err
+	}
+	postVOs, err := s.PostAssembler.ConvertToListVO(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	return &vo.SeriesDetail{
+		Series: seriesDTO,
+		Posts:  postVOs,
+	}, nil
+}
+
+func bindSeriesParam(ctx *gin.Context) (*param.Series, error) {
+	seriesParam := &param.Series{}
+	err := ctx.ShouldBindJSON(seriesParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	return seriesParam, nil
+}
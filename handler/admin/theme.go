@@ -2,12 +2,15 @@ package admin
 
 import (
 	"errors"
+	"io"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/param"
 	"github.com/go-sonic/sonic/service"
 	"github.com/go-sonic/sonic/util"
@@ -15,14 +18,16 @@ import (
 )
 
 type ThemeHandler struct {
-	ThemeService  service.ThemeService
-	OptionService service.OptionService
+	ThemeService        service.ThemeService
+	OptionService       service.OptionService
+	ThemePreviewService service.ThemePreviewService
 }
 
-func NewThemeHandler(l service.ThemeService, o service.OptionService) *ThemeHandler {
+func NewThemeHandler(l service.ThemeService, o service.OptionService, p service.ThemePreviewService) *ThemeHandler {
 	return &ThemeHandler{
-		ThemeService:  l,
-		OptionService: o,
+		ThemeService:        l,
+		OptionService:       o,
+		ThemePreviewService: p,
 	}
 }
 
@@ -294,7 +299,95 @@ func (t *ThemeHandler) FetchTheme(ctx *gin.Context) (interface{}, error) {
 }
 
 func (t *ThemeHandler) UpdateThemeByFetching(ctx *gin.Context) (interface{}, error) {
-	return nil, xerr.WithMsg(nil, "not support").WithStatus(xerr.StatusInternalServerError)
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	return t.ThemeService.UpdateThemeByFetching(ctx, themeID)
+}
+
+func (t *ThemeHandler) CheckThemeUpdate(ctx *gin.Context) (interface{}, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	return t.ThemeService.CheckThemeUpdate(ctx, themeID)
+}
+
+func (t *ThemeHandler) ValidateTheme(ctx *gin.Context) (interface{}, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	return t.ThemeService.ValidateTheme(ctx, themeID)
+}
+
+func (t *ThemeHandler) CreateThemePreview(ctx *gin.Context) (interface{}, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]interface{})
+	if ctx.Request.ContentLength > 0 {
+		err = ctx.ShouldBindJSON(&settings)
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusBadRequest)
+		}
+	}
+	preview, err := t.ThemePreviewService.CreatePreview(ctx, themeID, settings)
+	if err != nil {
+		return nil, err
+	}
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(consts.ThemePreviewAdminCookieName, preview.AdminSecret, int(consts.ThemePreviewValidDuration.Seconds()), "/", "", util.IsHTTPS(ctx), true)
+	return preview, nil
+}
+
+func (t *ThemeHandler) DeleteThemePreview(ctx *gin.Context) (interface{}, error) {
+	token, err := util.ParamString(ctx, "token")
+	if err != nil {
+		return nil, err
+	}
+	t.ThemePreviewService.DeletePreview(ctx, token)
+	return nil, nil
+}
+
+func (t *ThemeHandler) ExportThemeSettings(ctx *gin.Context) {
+	themeID := ctx.Param("themeID")
+	format := ctx.DefaultQuery("format", "yaml")
+	content, err := t.ThemeService.ExportThemeSettings(ctx, themeID, format)
+	if err != nil {
+		status := xerr.GetHTTPStatus(err)
+		ctx.AbortWithStatusJSON(status, &dto.BaseDTO{Status: status, Message: xerr.GetMessage(err)})
+		return
+	}
+	contentType := "application/x-yaml; charset=utf-8"
+	if format == "json" {
+		contentType = "application/json; charset=utf-8"
+	}
+	ctx.Header("Content-Disposition", "attachment; filename="+themeID+"-settings."+format)
+	ctx.Data(http.StatusOK, contentType, content)
+}
+
+func (t *ThemeHandler) ImportThemeSettings(ctx *gin.Context) (interface{}, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, xerr.WithMsg(err, "upload theme settings error").WithStatus(xerr.StatusBadRequest)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, xerr.WithMsg(err, "upload theme settings error").WithStatus(xerr.StatusBadRequest)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, xerr.WithMsg(err, "upload theme settings error").WithStatus(xerr.StatusBadRequest)
+	}
+	return t.ThemeService.ImportThemeSettings(ctx, themeID, content)
 }
 
 func (t *ThemeHandler) ReloadTheme(ctx *gin.Context) (interface{}, error) {
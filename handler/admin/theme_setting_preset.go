@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type ThemeSettingPresetHandler struct {
+	ThemeSettingPresetService service.ThemeSettingPresetService
+}
+
+func NewThemeSettingPresetHandler(themeSettingPresetService service.ThemeSettingPresetService) *ThemeSettingPresetHandler {
+	return &ThemeSettingPresetHandler{
+		ThemeSettingPresetService: themeSettingPresetService,
+	}
+}
+
+func (t *ThemeSettingPresetHandler) ListPresets(ctx *gin.Context) (interface{}, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	return t.ThemeSettingPresetService.ListPresets(ctx, themeID)
+}
+
+func (t *ThemeSettingPresetHandler) SavePreset(ctx *gin.Context) (interface{}, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return nil, err
+	}
+	var presetParam param.ThemeSettingPreset
+	err = ctx.ShouldBindJSON(&presetParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest)
+	}
+	return t.ThemeSettingPresetService.SavePreset(ctx, themeID, presetParam.Name)
+}
+
+func (t *ThemeSettingPresetHandler) ApplyPreset(ctx *gin.Context) (interface{}, error) {
+	themeID, presetID, err := t.getParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nil, t.ThemeSettingPresetService.ApplyPreset(ctx, themeID, presetID)
+}
+
+func (t *ThemeSettingPresetHandler) DiffPreset(ctx *gin.Context) (interface{}, error) {
+	themeID, presetID, err := t.getParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.ThemeSettingPresetService.DiffPreset(ctx, themeID, presetID)
+}
+
+func (t *ThemeSettingPresetHandler) DeletePreset(ctx *gin.Context) (interface{}, error) {
+	themeID, presetID, err := t.getParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nil, t.ThemeSettingPresetService.DeletePreset(ctx, themeID, presetID)
+}
+
+func (t *ThemeSettingPresetHandler) getParams(ctx *gin.Context) (string, int32, error) {
+	themeID, err := util.ParamString(ctx, "themeID")
+	if err != nil {
+		return "", 0, err
+	}
+	presetID, err := util.ParamInt32(ctx, "presetID")
+	if err != nil {
+		return "", 0, err
+	}
+	return themeID, presetID, nil
+}
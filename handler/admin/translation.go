@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/handler/trans"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type TranslationHandler struct {
+	TranslationService service.TranslationService
+}
+
+func NewTranslationHandler(translationService service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		TranslationService: translationService,
+	}
+}
+
+func (t *TranslationHandler) ListLanguages(ctx *gin.Context) (interface{}, error) {
+	return t.TranslationService.ListLanguages(ctx)
+}
+
+func (t *TranslationHandler) ListTranslations(ctx *gin.Context) (interface{}, error) {
+	translationType, contentID, err := bindTranslationContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.TranslationService.ListTranslations(ctx, translationType, contentID, false)
+}
+
+func (t *TranslationHandler) LinkTranslation(ctx *gin.Context) (interface{}, error) {
+	translationType, contentID, err := bindTranslationContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	translationParam := &param.Translation{}
+	err = ctx.ShouldBindJSON(translationParam)
+	if err != nil {
+		e := validator.ValidationErrors{}
+		if errors.As(err, &e) {
+			return nil, xerr.WithStatus(e, xerr.StatusBadRequest).WithMsg(trans.Translate(e))
+		}
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parameter error")
+	}
+	err = t.TranslationService.Link(ctx, translationType, contentID, translationParam.Language, translationParam.TranslationOf)
+	if err != nil {
+		return nil, err
+	}
+	return t.TranslationService.ListTranslations(ctx, translationType, contentID, false)
+}
+
+// bindTranslationContent gets the content from the path, the type is one of post, sheet, category and menu
+func bindTranslationContent(ctx *gin.Context) (consts.TranslationType, int32, error) {
+	translationType, err := consts.TranslationTypeFromString(ctx.Param("type"))
+	if err != nil {
+		return 0, 0, xerr.WithStatus(err, xerr.StatusBadRequest)
+	}
+	contentID, err := util.ParamInt32(ctx, "contentID")
+	if err != nil {
+		return 0, 0, err
+	}
+	return translationType, contentID, nil
+}
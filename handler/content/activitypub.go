@@ -0,0 +1,97 @@
+package content
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type ActivityPubHandler struct {
+	ActivityPubService service.ActivityPubService
+}
+
+func NewActivityPubHandler(activityPubService service.ActivityPubService) *ActivityPubHandler {
+	return &ActivityPubHandler{
+		ActivityPubService: activityPubService,
+	}
+}
+
+func (a *ActivityPubHandler) WebFinger(ctx *gin.Context) {
+	webFinger, err := a.ActivityPubService.WebFinger(ctx, ctx.Query("resource"))
+	a.render(ctx, "application/jrd+json; charset=utf-8", webFinger, err)
+}
+
+func (a *ActivityPubHandler) Actor(ctx *gin.Context) {
+	actor, err := a.ActivityPubService.Actor(ctx)
+	a.render(ctx, consts.ActivityPubContentType, actor, err)
+}
+
+func (a *ActivityPubHandler) Outbox(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	outbox, err := a.ActivityPubService.Outbox(ctx, page)
+	a.render(ctx, consts.ActivityPubContentType, outbox, err)
+}
+
+func (a *ActivityPubHandler) Followers(ctx *gin.Context) {
+	followers, err := a.ActivityPubService.Followers(ctx)
+	a.render(ctx, consts.ActivityPubContentType, followers, err)
+}
+
+func (a *ActivityPubHandler) Post(ctx *gin.Context) {
+	postID, err := util.ParamInt32(ctx, "postID")
+	if err != nil {
+		a.render(ctx, "", nil, err)
+		return
+	}
+	object, err := a.ActivityPubService.PostObject(ctx, postID)
+	a.render(ctx, consts.ActivityPubContentType, object, err)
+}
+
+func (a *ActivityPubHandler) Journal(ctx *gin.Context) {
+	journalID, err := util.ParamInt32(ctx, "journalID")
+	if err != nil {
+		a.render(ctx, "", nil, err)
+		return
+	}
+	object, err := a.ActivityPubService.JournalObject(ctx, journalID)
+	a.render(ctx, consts.ActivityPubContentType, object, err)
+}
+
+// Inbox receives the activities signed by the other servers
+func (a *ActivityPubHandler) Inbox(ctx *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, consts.ActivityPubFetchLimit))
+	if err != nil {
+		ctx.String(http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	err = a.ActivityPubService.HandleInbox(ctx, ctx.Request, body)
+	if err != nil {
+		log.CtxWarnf(ctx, "handle activity err=%v", err)
+		ctx.String(xerr.GetHTTPStatus(err), xerr.GetMessage(err))
+		return
+	}
+	ctx.Status(http.StatusAccepted)
+}
+
+func (a *ActivityPubHandler) render(ctx *gin.Context, contentType string, value any, err error) {
+	if err != nil {
+		ctx.String(xerr.GetHTTPStatus(err), xerr.GetMessage(err))
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.Header("Access-Control-Allow-Origin", "*")
+	ctx.Data(http.StatusOK, contentType, data)
+}
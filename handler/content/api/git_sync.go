@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type GitSyncHandler struct {
+	GitSyncService service.GitSyncService
+}
+
+func NewGitSyncHandler(gitSyncService service.GitSyncService) *GitSyncHandler {
+	return &GitSyncHandler{
+		GitSyncService: gitSyncService,
+	}
+}
+
+// Webhook pulls the repository in the background on the push webhook of GitHub, Gitea or GitLab
+func (g *GitSyncHandler) Webhook(ctx *gin.Context) (interface{}, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, consts.GitSyncWebhookLimit))
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("read body err")
+	}
+	if err := g.GitSyncService.VerifyWebhook(ctx, ctx.Request.Header, body); err != nil {
+		return nil, err
+	}
+	go func() {
+		ctx := context.Background()
+		if _, err := g.GitSyncService.Pull(ctx); err != nil {
+			log.CtxWarn(ctx, "git sync webhook pull err", zap.Error(err))
+		}
+	}()
+	return nil, nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"html/template"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -21,6 +22,8 @@ type PostHandler struct {
 	PostService          service.PostService
 	PostCommentService   service.PostCommentService
 	PostCommentAssembler assembler.PostCommentAssembler
+	RelatedPostService   service.RelatedPostService
+	PostAssembler        assembler.PostAssembler
 }
 
 func NewPostHandler(
@@ -28,12 +31,16 @@ func NewPostHandler(
 	postService service.PostService,
 	postCommentService service.PostCommentService,
 	postCommentAssembler assembler.PostCommentAssembler,
+	relatedPostService service.RelatedPostService,
+	postAssembler assembler.PostAssembler,
 ) *PostHandler {
 	return &PostHandler{
 		OptionService:        optionService,
 		PostService:          postService,
 		PostCommentService:   postCommentService,
 		PostCommentAssembler: postCommentAssembler,
+		RelatedPostService:   relatedPostService,
+		PostAssembler:        postAssembler,
 	}
 }
 
@@ -190,3 +197,27 @@ func (p *PostHandler) Like(ctx *gin.Context) (interface{}, error) {
 	}
 	return nil, p.PostService.IncreaseLike(ctx, postID)
 }
+
+// ListRelatedPosts lists the published posts related to the post, the post itself has to be published
+func (p *PostHandler) ListRelatedPosts(ctx *gin.Context) (interface{}, error) {
+	postID, err := util.ParamInt32(ctx, "postID")
+	if err != nil {
+		return nil, err
+	}
+	top, err := strconv.Atoi(ctx.DefaultQuery("top", strconv.Itoa(consts.RelatedPostsMaxCount)))
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Parameter error")
+	}
+	post, err := p.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Type != consts.PostTypePost || post.Status != consts.PostStatusPublished {
+		return nil, xerr.NoRecord.New("").WithStatus(xerr.StatusNotFound).WithMsg("post not exist")
+	}
+	posts, err := p.RelatedPostService.ListRelatedPosts(ctx, postID, top)
+	if err != nil {
+		return nil, err
+	}
+	return p.PostAssembler.ConvertToListVO(ctx, posts)
+}
@@ -1,15 +1,23 @@
 package content
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/entity"
 	"github.com/go-sonic/sonic/model/param"
 	"github.com/go-sonic/sonic/model/property"
@@ -18,6 +26,15 @@ import (
 	"github.com/go-sonic/sonic/service/assembler"
 	"github.com/go-sonic/sonic/template"
 	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type feedFormat int
+
+const (
+	feedFormatRSS feedFormat = iota
+	feedFormatAtom
+	feedFormatJSON
 )
 
 type FeedHandler struct {
@@ -25,117 +42,195 @@ type FeedHandler struct {
 	PostService         service.PostService
 	PostCategoryService service.PostCategoryService
 	CategoryService     service.CategoryService
+	PostTagService      service.PostTagService
+	TagService          service.TagService
+	JournalService      service.JournalService
+	PostCommentService  service.PostCommentService
+	UserService         service.UserService
+	ShortcodeService    service.ShortcodeService
+	SitemapService      service.SitemapService
 	PostAssembler       assembler.PostAssembler
 }
 
-func NewFeedHandler(optionService service.OptionService, postService service.PostService, categoryService service.CategoryService, postCategoryService service.PostCategoryService, postAssembler assembler.PostAssembler) *FeedHandler {
+func NewFeedHandler(
+	optionService service.OptionService,
+	postService service.PostService,
+	categoryService service.CategoryService,
+	postCategoryService service.PostCategoryService,
+	postTagService service.PostTagService,
+	tagService service.TagService,
+	journalService service.JournalService,
+	postCommentService service.PostCommentService,
+	userService service.UserService,
+	shortcodeService service.ShortcodeService,
+	sitemapService service.SitemapService,
+	postAssembler assembler.PostAssembler,
+) *FeedHandler {
 	return &FeedHandler{
 		OptionService:       optionService,
 		PostService:         postService,
 		CategoryService:     categoryService,
 		PostCategoryService: postCategoryService,
+		PostTagService:      postTagService,
+		TagService:          tagService,
+		JournalService:      journalService,
+		PostCommentService:  postCommentService,
+		UserService:         userService,
+		ShortcodeService:    shortcodeService,
+		SitemapService:      sitemapService,
 		PostAssembler:       postAssembler,
 	}
 }
 
 func (f *FeedHandler) Feed(ctx *gin.Context, model template.Model) (string, error) {
-	_, err := f.Atom(ctx, model)
+	feed, err := f.postFeed(ctx)
 	if err != nil {
 		return "", err
 	}
-	ctx.Header("Content-Type", "application/xml; charset=utf-8")
-	return "common/web/rss", nil
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatRSS))
 }
 
-func (f *FeedHandler) CategoryFeed(ctx *gin.Context, model template.Model) (string, error) {
-	_, err := f.CategoryAtom(ctx, model)
+func (f *FeedHandler) Atom(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.postFeed(ctx)
 	if err != nil {
 		return "", err
 	}
-	ctx.Header("Content-Type", "application/xml; charset=utf-8")
-	return "common/web/rss", nil
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatAtom))
 }
 
-func (f *FeedHandler) Atom(ctx *gin.Context, model template.Model) (string, error) {
-	rssPageSize := f.OptionService.GetOrByDefault(ctx, property.RssPageSize).(int)
-	postQuery := param.PostQuery{
-		Page:     param.Page{PageNum: 0, PageSize: rssPageSize},
-		Sort:     &param.Sort{Fields: []string{"createTime,desc"}},
-		Statuses: []*consts.PostStatus{consts.PostStatusPublished.Ptr()},
-	}
-	posts, _, err := f.PostService.Page(ctx, postQuery)
+func (f *FeedHandler) CategoryFeed(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.categoryFeed(ctx)
 	if err != nil {
 		return "", err
 	}
-	postDetailVOs, err := f.buildPost(ctx, posts)
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatRSS))
+}
+
+func (f *FeedHandler) CategoryAtom(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.categoryFeed(ctx)
 	if err != nil {
 		return "", err
 	}
-	lastModified := f.getLastModifiedTime(posts)
-	ctx.Header("Last-Modified", lastModified.Format(http.TimeFormat))
-	ctx.Header("Content-Type", "application/xml; charset=utf-8")
-	model["lastModified"] = lastModified
-	model["posts"] = postDetailVOs
-	return "common/web/atom", nil
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatAtom))
 }
 
-func (f *FeedHandler) CategoryAtom(ctx *gin.Context, model template.Model) (string, error) {
-	slug, err := util.ParamString(ctx, "slug")
+func (f *FeedHandler) TagFeed(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.tagFeed(ctx)
 	if err != nil {
 		return "", err
 	}
-	slug = strings.TrimSuffix(slug, ".xml")
-	category, err := f.CategoryService.GetBySlug(ctx, slug)
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatRSS))
+}
+
+func (f *FeedHandler) TagAtom(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.tagFeed(ctx)
 	if err != nil {
 		return "", err
 	}
-	categoryDTO, err := f.CategoryService.ConvertToCategoryDTO(ctx, category)
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatAtom))
+}
+
+func (f *FeedHandler) JournalFeed(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.journalFeed(ctx)
 	if err != nil {
 		return "", err
 	}
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatRSS))
+}
 
-	posts, err := f.PostCategoryService.ListByCategoryID(ctx, category.ID, consts.PostStatusPublished)
+func (f *FeedHandler) JournalAtom(ctx *gin.Context, model template.Model) (string, error) {
+	feed, err := f.journalFeed(ctx)
 	if err != nil {
 		return "", err
 	}
+	return f.render(ctx, model, feed, feedFormatOf(ctx, feedFormatAtom))
+}
 
-	postDetailVOs, err := f.buildPost(ctx, posts)
+func (f *FeedHandler) CommentFeed(ctx *gin.Context, model template.Model) (string, error) {
+	format := feedFormatOf(ctx, feedFormatRSS)
+	feed, err := f.commentFeed(ctx, format)
 	if err != nil {
 		return "", err
 	}
-	lastModified := f.getLastModifiedTime(posts)
+	return f.render(ctx, model, feed, format)
+}
 
-	model["category"] = categoryDTO
-	model["posts"] = postDetailVOs
-	model["lastModified"] = lastModified
-	ctx.Header("Content-Type", "application/xml; charset=utf-8")
-	return "common/web/atom", nil
+func (f *FeedHandler) CommentAtom(ctx *gin.Context, model template.Model) (string, error) {
+	format := feedFormatOf(ctx, feedFormatAtom)
+	feed, err := f.commentFeed(ctx, format)
+	if err != nil {
+		return "", err
+	}
+	return f.render(ctx, model, feed, format)
 }
 
 func (f *FeedHandler) Robots(ctx *gin.Context, model template.Model) (string, error) {
+	languages, err := f.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return "", err
+	}
+	// every language other than the default one has its own search page and sitemaps under its prefix
+	languagePrefixes := make([]string, 0, len(languages))
+	for _, language := range languages[1:] {
+		languagePrefixes = append(languagePrefixes, "/"+language)
+	}
+	model["language_prefixes"] = languagePrefixes
 	ctx.Header("Content-Type", "text/plain;charset=utf-8")
 	return "common/web/robots", nil
 }
 
+// SitemapXML renders the sitemap index linking the sitemaps of every type
 func (f *FeedHandler) SitemapXML(ctx *gin.Context, model template.Model) (string, error) {
-	posts, _, err := f.PostService.Page(ctx, param.PostQuery{
-		Page:     param.Page{PageNum: 0, PageSize: int(^uint(0) >> 1)},
-		Sort:     &param.Sort{Fields: []string{"createTime,desc"}},
-		Statuses: []*consts.PostStatus{consts.PostStatusPublished.Ptr()},
-	})
+	sitemaps, err := f.SitemapService.ListSitemaps(ctx)
 	if err != nil {
 		return "", err
 	}
-	postDetailVOs, err := f.buildPost(ctx, posts)
+	model["sitemaps"] = sitemaps
+
+	ctx.Header("Content-Type", "application/xml; charset=utf-8")
+	return "common/web/sitemap_index", nil
+}
+
+// Sitemap renders a child sitemap, whose name is like posts-1.xml
+func (f *FeedHandler) Sitemap(ctx *gin.Context, model template.Model) (string, error) {
+	name, err := util.ParamString(ctx, "name")
 	if err != nil {
 		return "", err
 	}
-	model["posts"] = postDetailVOs
+	sitemapType, page, ok := strings.Cut(strings.TrimSuffix(name, ".xml"), "-")
+	if !ok {
+		return "", xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("Sitemap not found")
+	}
+	pageNum, err := strconv.Atoi(page)
+	if err != nil {
+		return "", xerr.WithStatus(err, xerr.StatusNotFound).WithMsg("Sitemap not found")
+	}
+	urls, err := f.SitemapService.ListURLs(ctx, consts.SitemapType(sitemapType), pageNum)
+	if err != nil {
+		return "", err
+	}
+	for _, sitemapURL := range urls {
+		sitemapURL.Loc = xmlInValidChar.ReplaceAllString(sitemapURL.Loc, "")
+		for _, alternate := range sitemapURL.Alternates {
+			alternate.Href = xmlInValidChar.ReplaceAllString(alternate.Href, "")
+		}
+	}
+	model["urls"] = urls
 
 	ctx.Header("Content-Type", "application/xml; charset=utf-8")
 	return "common/web/sitemap_xml", nil
 }
 
+// IndexNowKey serves the key file for the search engines to verify IndexNow submissions
+func (f *FeedHandler) IndexNowKey(ctx *gin.Context) {
+	key := f.OptionService.GetOrByDefault(ctx, property.SeoIndexNowKey).(string)
+	if !f.OptionService.GetOrByDefault(ctx, property.SeoIndexNowEnabled).(bool) || key == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.String(http.StatusOK, key)
+}
+
 func (f *FeedHandler) SitemapHTML(ctx *gin.Context, model template.Model) (string, error) {
 	posts, _, err := f.PostService.Page(ctx, param.PostQuery{
 		Page:     param.Page{PageNum: 0, PageSize: int(^uint(0) >> 1)},
@@ -153,23 +248,401 @@ func (f *FeedHandler) SitemapHTML(ctx *gin.Context, model template.Model) (strin
 	return "common/web/sitemap_html", nil
 }
 
-func (f *FeedHandler) getLastModifiedTime(posts []*entity.Post) time.Time {
-	lastModifiedTime := time.Time{}
+func (f *FeedHandler) postFeed(ctx *gin.Context) (*dto.Feed, error) {
+	rssPageSize := f.OptionService.GetOrByDefault(ctx, property.RssPageSize).(int)
+	posts, _, err := f.PostService.Page(ctx, param.PostQuery{
+		Page:     param.Page{PageNum: 0, PageSize: rssPageSize},
+		Sort:     &param.Sort{Fields: []string{"createTime,desc"}},
+		Statuses: []*consts.PostStatus{consts.PostStatusPublished.Ptr()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	feed, err := f.newFeed(ctx, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	return feed, f.addPostItems(ctx, feed, posts)
+}
+
+func (f *FeedHandler) categoryFeed(ctx *gin.Context) (*dto.Feed, error) {
+	slug, err := feedSlug(ctx)
+	if err != nil {
+		return nil, err
+	}
+	category, err := f.CategoryService.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if category.Type == consts.CategoryTypeIntimate {
+		return nil, xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("分类不存在")
+	}
+	categoryDTO, err := f.CategoryService.ConvertToCategoryDTO(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := f.PostCategoryService.ListByCategoryID(ctx, category.ID, consts.PostStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := f.newFeed(ctx, "分类："+categoryDTO.Name, categoryDTO.Description, categoryDTO.FullPath)
+	if err != nil {
+		return nil, err
+	}
+	return feed, f.addPostItems(ctx, feed, latestFeedPosts(ctx, f.OptionService, posts))
+}
+
+func (f *FeedHandler) tagFeed(ctx *gin.Context) (*dto.Feed, error) {
+	slug, err := feedSlug(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := f.TagService.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	tagDTO, err := f.TagService.ConvertToDTO(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := f.PostTagService.ListPostByTagID(ctx, tag.ID, consts.PostStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := f.newFeed(ctx, "标签："+tagDTO.Name, "", tagDTO.FullPath)
+	if err != nil {
+		return nil, err
+	}
+	return feed, f.addPostItems(ctx, feed, latestFeedPosts(ctx, f.OptionService, posts))
+}
+
+func (f *FeedHandler) journalFeed(ctx *gin.Context) (*dto.Feed, error) {
+	rssPageSize := f.OptionService.GetOrByDefault(ctx, property.RssPageSize).(int)
+	journals, _, err := f.JournalService.ListJournal(ctx, param.JournalQuery{
+		Page:        param.Page{PageNum: 0, PageSize: rssPageSize},
+		Sort:        &param.Sort{Fields: []string{"createTime,desc"}},
+		JournalType: consts.JournalTypePublic.Ptr(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	journalsURL, err := f.absoluteURL(ctx, "/"+f.OptionService.GetOrByDefault(ctx, property.JournalsPrefix).(string))
+	if err != nil {
+		return nil, err
+	}
+	feed, err := f.newFeed(ctx, "日志", "", journalsURL)
+	if err != nil {
+		return nil, err
+	}
+	for _, journal := range journals {
+		updateTime := journal.CreateTime
+		if journal.UpdateTime != nil {
+			updateTime = *journal.UpdateTime
+		}
+		content := xmlInValidChar.ReplaceAllString(journal.Content, "")
+		feed.Items = append(feed.Items, &dto.FeedItem{
+			ID:         journalsURL + "#journal-" + strconv.Itoa(int(journal.ID)),
+			Title:      journal.CreateTime.Format("2006-01-02 15:04"),
+			Link:       journalsURL + "#journal-" + strconv.Itoa(int(journal.ID)),
+			Content:    content,
+			Author:     feed.Author,
+			CreateTime: journal.CreateTime,
+			UpdateTime: updateTime,
+		})
+	}
+	return feed, nil
+}
+
+// commentFeed lists the latest comments, the text written by the visitors is escaped for the format
+func (f *FeedHandler) commentFeed(ctx *gin.Context, format feedFormat) (*dto.Feed, error) {
+	rssPageSize := f.OptionService.GetOrByDefault(ctx, property.RssPageSize).(int)
+	comments, _, err := f.PostCommentService.Page(ctx, param.CommentQuery{
+		Page:          param.Page{PageNum: 0, PageSize: rssPageSize},
+		Sort:          &param.Sort{Fields: []string{"createTime,desc"}},
+		CommentStatus: consts.CommentStatusPublished.Ptr(),
+	}, consts.CommentTypePost)
+	if err != nil {
+		return nil, err
+	}
+	postIDs := make([]int32, 0, len(comments))
+	for _, comment := range comments {
+		postIDs = append(postIDs, comment.PostID)
+	}
+	posts, err := f.PostService.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	postList := make([]*entity.Post, 0, len(posts))
 	for _, post := range posts {
-		if post.EditTime != nil {
-			if post.EditTime.After(lastModifiedTime) {
-				lastModifiedTime = *post.EditTime
+		postList = append(postList, post)
+	}
+	fullPaths, err := f.PostService.BuildFullPaths(ctx, postList)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := f.newFeed(ctx, "最新评论", "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		post, ok := posts[comment.PostID]
+		if !ok || post.Status != consts.PostStatusPublished {
+			continue
+		}
+		postURL, err := f.absoluteURL(ctx, fullPaths[post.ID])
+		if err != nil {
+			return nil, err
+		}
+		updateTime := comment.CreateTime
+		if comment.UpdateTime != nil {
+			updateTime = *comment.UpdateTime
+		}
+		// the content is html in every format, escape it so that the comment is shown as text
+		content := html.EscapeString(xmlInValidChar.ReplaceAllString(comment.Content, ""))
+		author := xmlInValidChar.ReplaceAllString(comment.Author, "")
+		title := post.Title
+		// the titles and the authors are plain text in JSON, only the XML feeds need them escaped to stay well-formed
+		if format != feedFormatJSON {
+			author = html.EscapeString(author)
+			title = html.EscapeString(title)
+		}
+		feed.Items = append(feed.Items, &dto.FeedItem{
+			ID:         postURL + "#comment-" + strconv.Itoa(int(comment.ID)),
+			Title:      author + " 评论了《" + title + "》",
+			Link:       postURL + "#comment-" + strconv.Itoa(int(comment.ID)),
+			Content:    content,
+			Author:     author,
+			CreateTime: comment.CreateTime,
+			UpdateTime: updateTime,
+		})
+	}
+	return feed, nil
+}
+
+// newFeed returns a feed of the blog, the title is prefixed to the blog title if not empty
+func (f *FeedHandler) newFeed(ctx *gin.Context, title, description, link string) (*dto.Feed, error) {
+	blogURL, err := f.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blogTitle := f.OptionService.GetOrByDefault(ctx, property.BlogTitle).(string)
+	if title == "" {
+		title = blogTitle
+	} else {
+		title = title + " - " + blogTitle
+	}
+	// the feeds of a language other than the default one are under its prefix
+	pathPrefix := ctx.GetString(consts.RequestPathPrefix)
+	if link == "" {
+		link = blogURL + pathPrefix
+	} else if link, err = f.absoluteURL(ctx, link); err != nil {
+		return nil, err
+	}
+	language := ctx.GetString(consts.RequestLanguage)
+	if language == "" {
+		language = f.OptionService.GetOrByDefault(ctx, property.BlogLocale).(string)
+	}
+	feed := &dto.Feed{
+		Title:       title,
+		Description: description,
+		Link:        link,
+		FeedURL:     blogURL + pathPrefix + ctx.Request.URL.Path,
+		Language:    language,
+		Icon:        f.OptionService.GetOrByDefault(ctx, property.BlogFavicon).(string),
+		UpdateTime:  time.Now(),
+	}
+	users, err := f.UserService.GetAllUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > 0 {
+		feed.Author = users[0].Nickname
+		if feed.Description == "" {
+			feed.Description = users[0].Description
+		}
+	}
+	return feed, nil
+}
+
+func (f *FeedHandler) addPostItems(ctx context.Context, feed *dto.Feed, posts []*entity.Post) error {
+	postDetailVOs, err := f.buildPost(ctx, posts)
+	if err != nil {
+		return err
+	}
+	fullContent := f.isFullContent(ctx)
+	for _, postDetailVO := range postDetailVOs {
+		link, err := f.absoluteURL(ctx, postDetailVO.FullPath)
+		if err != nil {
+			return err
+		}
+		content := postDetailVO.Summary
+		if fullContent {
+			content, err = f.ShortcodeService.Render(ctx, postDetailVO.Content)
+			if err != nil {
+				return err
+			}
+		}
+		tags := make([]string, 0, len(postDetailVO.Tags))
+		for _, tag := range postDetailVO.Tags {
+			tags = append(tags, tag.Name)
+		}
+		updateTime := time.UnixMilli(postDetailVO.CreateTime)
+		if postDetailVO.EditTime > 0 {
+			updateTime = time.UnixMilli(postDetailVO.EditTime)
+		}
+		feed.Items = append(feed.Items, &dto.FeedItem{
+			ID:         link,
+			Title:      postDetailVO.Title,
+			Link:       link,
+			Content:    content,
+			Summary:    postDetailVO.Summary,
+			Author:     feed.Author,
+			Tags:       tags,
+			CreateTime: time.UnixMilli(postDetailVO.CreateTime),
+			UpdateTime: updateTime,
+		})
+	}
+	return nil
+}
+
+func (f *FeedHandler) render(ctx *gin.Context, model template.Model, feed *dto.Feed, format feedFormat) (string, error) {
+	if len(feed.Items) > 0 {
+		feed.UpdateTime = time.Time{}
+		for _, item := range feed.Items {
+			if item.UpdateTime.After(feed.UpdateTime) {
+				feed.UpdateTime = item.UpdateTime
 			}
-		} else {
-			if post.CreateTime.After(lastModifiedTime) {
-				lastModifiedTime = post.CreateTime
+		}
+	}
+	if f.notModified(ctx, feed, format) {
+		return "", nil
+	}
+	switch format {
+	case feedFormatJSON:
+		buf := bytes.Buffer{}
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(toJSONFeed(feed)); err != nil {
+			return "", xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError)
+		}
+		ctx.Data(http.StatusOK, consts.JSONFeedContentType, buf.Bytes())
+		return "", nil
+	case feedFormatAtom:
+		model["feed"] = feed
+		ctx.Header("Content-Type", "application/xml; charset=utf-8")
+		return "common/web/atom", nil
+	default:
+		model["feed"] = feed
+		ctx.Header("Content-Type", "application/xml; charset=utf-8")
+		return "common/web/rss", nil
+	}
+}
+
+// notModified sets the validators of the feed and reports whether the copy cached by the client is still fresh
+func (f *FeedHandler) notModified(ctx *gin.Context, feed *dto.Feed, format feedFormat) bool {
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%d:%t:%d", format, f.isFullContent(ctx), feed.UpdateTime.UnixMilli())
+	for _, item := range feed.Items {
+		_, _ = fmt.Fprintf(hash, ":%s:%d", item.ID, item.UpdateTime.UnixMilli())
+	}
+	etag := fmt.Sprintf(`W/"%x"`, hash.Sum64())
+	lastModified := feed.UpdateTime.UTC().Truncate(time.Second)
+	ctx.Header("ETag", etag)
+	ctx.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				ctx.Status(http.StatusNotModified)
+				return true
 			}
 		}
+		return false
+	}
+	if ifModifiedSince, err := http.ParseTime(ctx.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(ifModifiedSince) {
+		ctx.Status(http.StatusNotModified)
+		return true
 	}
-	if lastModifiedTime == (time.Time{}) {
-		lastModifiedTime = time.Now()
+	return false
+}
+
+func (f *FeedHandler) isFullContent(ctx context.Context) bool {
+	contentType := f.OptionService.GetOrByDefault(ctx, property.RssContentType).(string)
+	return strings.EqualFold(contentType, consts.RssContentTypeFull)
+}
+
+func (f *FeedHandler) absoluteURL(ctx context.Context, path string) (string, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path, nil
+	}
+	blogURL, err := f.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	return blogURL + path, nil
+}
+
+func toJSONFeed(feed *dto.Feed) *dto.JSONFeed {
+	jsonFeed := &dto.JSONFeed{
+		Version:     consts.JSONFeedVersion,
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Language:    feed.Language,
+		Icon:        feed.Icon,
+		Items:       make([]*dto.JSONFeedItem, 0, len(feed.Items)),
+	}
+	if feed.Author != "" {
+		jsonFeed.Authors = []*dto.JSONFeedAuthor{{Name: feed.Author}}
+	}
+	for _, item := range feed.Items {
+		jsonFeedItem := &dto.JSONFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: item.CreateTime.Format(time.RFC3339),
+			DateModified:  item.UpdateTime.Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		if item.Author != "" {
+			jsonFeedItem.Authors = []*dto.JSONFeedAuthor{{Name: item.Author}}
+		}
+		jsonFeed.Items = append(jsonFeed.Items, jsonFeedItem)
+	}
+	return jsonFeed
+}
+
+// feedFormatOf returns the JSON Feed format for the paths ending with .json
+func feedFormatOf(ctx *gin.Context, defaultFormat feedFormat) feedFormat {
+	if strings.HasSuffix(ctx.Request.URL.Path, ".json") {
+		return feedFormatJSON
+	}
+	return defaultFormat
+}
+
+func feedSlug(ctx *gin.Context) (string, error) {
+	slug, err := util.ParamString(ctx, "slug")
+	if err != nil {
+		return "", err
+	}
+	slug = strings.TrimSuffix(slug, ".xml")
+	return strings.TrimSuffix(slug, ".json"), nil
+}
+
+// latestFeedPosts returns the latest posts limited by the rss page size
+func latestFeedPosts(ctx context.Context, optionService service.OptionService, posts []*entity.Post) []*entity.Post {
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].CreateTime.After(posts[j].CreateTime)
+	})
+	rssPageSize := optionService.GetOrByDefault(ctx, property.RssPageSize).(int)
+	if rssPageSize > 0 && len(posts) > rssPageSize {
+		return posts[:rssPageSize]
 	}
-	return lastModifiedTime
+	return posts
 }
 
 var xmlInValidChar = regexp.MustCompile("[\x00-\x1F\x7F]")
@@ -11,9 +11,14 @@ func init() {
 		NewCategoryHandler,
 		NewSheetHandler,
 		NewTagHandler,
+		NewSeriesHandler,
 		NewLinkHandler,
 		NewPhotoHandler,
 		NewJournalHandler,
 		NewSearchHandler,
+		NewMentionHandler,
+		NewXMLRPCHandler,
+		NewMicropubHandler,
+		NewActivityPubHandler,
 	)
 }
@@ -0,0 +1,37 @@
+package content
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type MentionHandler struct {
+	MentionService service.MentionService
+}
+
+func NewMentionHandler(mentionService service.MentionService) *MentionHandler {
+	return &MentionHandler{
+		MentionService: mentionService,
+	}
+}
+
+// Webmention receives the webmention, the source is verified asynchronously, see https://www.w3.org/TR/webmention/
+func (m *MentionHandler) Webmention(ctx *gin.Context) {
+	source := ctx.PostForm("source")
+	target := ctx.PostForm("target")
+	if source == "" || target == "" {
+		ctx.String(http.StatusBadRequest, "source and target are required")
+		return
+	}
+	err := m.MentionService.Receive(ctx, consts.MentionProtocolWebmention, source, target, ctx.ClientIP())
+	if err != nil {
+		ctx.String(xerr.GetHTTPStatus(err), xerr.GetMessage(err))
+		return
+	}
+	ctx.String(http.StatusAccepted, "Accepted")
+}
@@ -0,0 +1,438 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// the scopes of the Micropub tokens, "post" is the scope of the legacy clients for "create"
+const (
+	micropubScopeCreate = "create"
+	micropubScopePost   = "post"
+	micropubScopeMedia  = "media"
+)
+
+// micropubForm is the request encoded as a form, the names of the array properties may end with []
+type micropubForm map[string][]string
+
+type MicropubHandler struct {
+	OptionService   service.OptionService
+	MicropubService service.MicropubService
+}
+
+func NewMicropubHandler(optionService service.OptionService, micropubService service.MicropubService) *MicropubHandler {
+	return &MicropubHandler{
+		OptionService:   optionService,
+		MicropubService: micropubService,
+	}
+}
+
+// Query serves q=config, q=source, q=syndicate-to and q=category, see https://www.w3.org/TR/micropub/#querying
+func (m *MicropubHandler) Query(ctx *gin.Context) {
+	if _, ok := m.authorize(ctx); !ok {
+		return
+	}
+	switch ctx.Query("q") {
+	case "config":
+		config, err := m.MicropubService.Config(ctx)
+		if err != nil {
+			m.error(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, config)
+	case "syndicate-to":
+		ctx.JSON(http.StatusOK, gin.H{"syndicate-to": []any{}})
+	case "category":
+		categories, err := m.MicropubService.ListCategories(ctx)
+		if err != nil {
+			m.error(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"categories": categories})
+	case "source":
+		sourceURL := ctx.Query("url")
+		if sourceURL == "" {
+			m.error(ctx, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("url is required"))
+			return
+		}
+		entry, err := m.MicropubService.Source(ctx, sourceURL)
+		if err != nil {
+			m.error(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, convertMicropubSource(entry, ctx.QueryArray("properties[]"), ctx.QueryArray("properties")))
+	default:
+		m.error(ctx, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("unsupported query: "+ctx.Query("q")))
+	}
+}
+
+// Create creates the h-entry sent as a form, a multipart form with the photos or a json object
+func (m *MicropubHandler) Create(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, consts.MicropubRequestLimit)
+	token, ok := m.authorize(ctx)
+	if !ok {
+		return
+	}
+	if !hasScope(token, micropubScopeCreate, micropubScopePost) {
+		m.insufficientScope(ctx, micropubScopeCreate)
+		return
+	}
+
+	var (
+		entry *param.MicropubEntry
+		err   error
+	)
+	if ctx.ContentType() == gin.MIMEJSON {
+		entry, err = parseMicropubJSON(ctx.Request.Body)
+	} else {
+		entry, err = m.parseMicropubForm(ctx)
+	}
+	if err != nil {
+		m.error(ctx, err)
+		return
+	}
+	location, err := m.MicropubService.Create(ctx, entry)
+	if err != nil {
+		m.error(ctx, err)
+		return
+	}
+	ctx.Header("Location", location)
+	ctx.Status(http.StatusCreated)
+}
+
+// Media uploads the file of the multipart form to the configured file storage, see https://www.w3.org/TR/micropub/#media-endpoint
+func (m *MicropubHandler) Media(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, consts.MicropubRequestLimit)
+	token, ok := m.authorize(ctx)
+	if !ok {
+		return
+	}
+	if !hasScope(token, micropubScopeMedia, micropubScopeCreate, micropubScopePost) {
+		m.insufficientScope(ctx, micropubScopeMedia)
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		m.error(ctx, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("file is required"))
+		return
+	}
+	location, err := m.MicropubService.UploadMedia(ctx, fileHeader)
+	if err != nil {
+		m.error(ctx, err)
+		return
+	}
+	ctx.Header("Location", location)
+	ctx.JSON(http.StatusCreated, gin.H{"url": location})
+}
+
+// authorize verifies the bearer token in the header, or the access_token in the form as the spec allows
+func (m *MicropubHandler) authorize(ctx *gin.Context) (*dto.MicropubToken, bool) {
+	if !m.OptionService.GetOrByDefault(ctx, property.MicropubEnabled).(bool) {
+		m.error(ctx, xerr.Forbidden.New("micropub disabled").WithStatus(xerr.StatusForbidden).WithMsg("Micropub is disabled"))
+		return nil, false
+	}
+	var token string
+	if authorization := ctx.GetHeader("Authorization"); len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		token = strings.TrimSpace(authorization[7:])
+	} else if ctx.Request.Method == http.MethodPost && ctx.ContentType() != gin.MIMEJSON {
+		token = ctx.PostForm("access_token")
+	}
+	verified, err := m.MicropubService.VerifyToken(ctx, token)
+	if err != nil {
+		m.error(ctx, err)
+		return nil, false
+	}
+	return verified, true
+}
+
+func (m *MicropubHandler) parseMicropubForm(ctx *gin.Context) (*param.MicropubEntry, error) {
+	var form micropubForm
+	if strings.HasPrefix(ctx.ContentType(), gin.MIMEMultipartPOSTForm) {
+		multipartForm, err := ctx.MultipartForm()
+		if err != nil {
+			return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid form")
+		}
+		form = multipartForm.Value
+		photos, err := m.uploadPhotos(ctx, multipartForm.File)
+		if err != nil {
+			return nil, err
+		}
+		form["photo[]"] = append(form["photo[]"], photos...)
+	} else {
+		if err := ctx.Request.ParseForm(); err != nil {
+			return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid form")
+		}
+		form = micropubForm(ctx.Request.PostForm)
+	}
+
+	if action := form.value("action"); action != "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("unsupported action: " + action)
+	}
+	if h := form.value("h"); h != "" && h != "entry" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("unsupported type: h-" + h)
+	}
+	entry := &param.MicropubEntry{
+		Name:       form.value("name"),
+		Content:    form.value("content"),
+		Summary:    form.value("summary"),
+		Categories: form.values("category"),
+		Slug:       form.value("mp-slug"),
+		Draft:      form.value("post-status") == "draft",
+		Private:    form.value("visibility") == "private",
+		Location:   form.value("location"),
+	}
+	for _, photo := range form.values("photo") {
+		entry.Photos = append(entry.Photos, param.MicropubPhoto{URL: photo})
+	}
+	published, err := parseMicropubTime(form.value("published"))
+	if err != nil {
+		return nil, err
+	}
+	entry.Published = published
+	return entry, nil
+}
+
+// uploadPhotos uploads the photo files of the multipart form, the urls of the files are returned
+func (m *MicropubHandler) uploadPhotos(ctx *gin.Context, files map[string][]*multipart.FileHeader) ([]string, error) {
+	urls := make([]string, 0)
+	for _, name := range []string{"photo", "photo[]"} {
+		for _, fileHeader := range files[name] {
+			photoURL, err := m.MicropubService.UploadMedia(ctx, fileHeader)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, photoURL)
+		}
+	}
+	return urls, nil
+}
+
+func (f micropubForm) values(key string) []string {
+	values := make([]string, 0)
+	for _, name := range []string{key, key + "[]"} {
+		for _, value := range f[name] {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func (f micropubForm) value(key string) string {
+	if values := f.values(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func parseMicropubJSON(r io.Reader) (*param.MicropubEntry, error) {
+	var request struct {
+		Type       []string         `json:"type"`
+		Action     string           `json:"action"`
+		Properties map[string][]any `json:"properties"`
+	}
+	if err := json.NewDecoder(r).Decode(&request); err != nil {
+		return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid json")
+	}
+	if request.Action != "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("unsupported action: " + request.Action)
+	}
+	if len(request.Type) == 0 || request.Type[0] != "h-entry" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("only h-entry is supported")
+	}
+	properties := request.Properties
+	entry := &param.MicropubEntry{
+		Name:       micropubString(properties["name"]),
+		Summary:    micropubString(properties["summary"]),
+		Categories: micropubStrings(properties["category"]),
+		Slug:       micropubString(properties["mp-slug"]),
+		Draft:      micropubString(properties["post-status"]) == "draft",
+		Private:    micropubString(properties["visibility"]) == "private",
+		Location:   micropubLocation(properties["location"]),
+	}
+	// the content is a string of plain text, or an object with the html
+	if len(properties["content"]) > 0 {
+		switch content := properties["content"][0].(type) {
+		case string:
+			entry.Content = content
+		case map[string]any:
+			if contentHTML, ok := content["html"].(string); ok {
+				entry.Content, entry.ContentHTML = contentHTML, true
+			} else {
+				entry.Content, _ = content["value"].(string)
+			}
+		}
+	}
+	for _, value := range properties["photo"] {
+		switch photo := value.(type) {
+		case string:
+			entry.Photos = append(entry.Photos, param.MicropubPhoto{URL: photo})
+		case map[string]any:
+			photoURL, _ := photo["value"].(string)
+			alt, _ := photo["alt"].(string)
+			if photoURL != "" {
+				entry.Photos = append(entry.Photos, param.MicropubPhoto{URL: photoURL, Alt: alt})
+			}
+		}
+	}
+	published, err := parseMicropubTime(micropubString(properties["published"]))
+	if err != nil {
+		return nil, err
+	}
+	entry.Published = published
+	return entry, nil
+}
+
+func micropubStrings(values []any) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
+			result = append(result, strings.TrimSpace(s))
+		}
+	}
+	return result
+}
+
+func micropubString(values []any) string {
+	if s := micropubStrings(values); len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// micropubLocation returns the location as a geo uri, or the name of the h-card or h-adr
+func micropubLocation(values []any) string {
+	if len(values) == 0 {
+		return ""
+	}
+	switch location := values[0].(type) {
+	case string:
+		return location
+	case map[string]any:
+		properties, _ := location["properties"].(map[string]any)
+		for _, key := range []string{"name", "locality", "region", "country-name"} {
+			if names, ok := properties[key].([]any); ok {
+				if name := micropubString(names); name != "" {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func parseMicropubTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05-0700", "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("invalid published: " + value)
+}
+
+// convertMicropubSource converts the entry to the mf2 json, only the requested properties are returned if any
+func convertMicropubSource(entry *dto.MicropubEntry, propertyNames ...[]string) gin.H {
+	properties := gin.H{
+		"url":       []string{entry.URL},
+		"published": []string{entry.Published.Format(time.RFC3339)},
+	}
+	if entry.Name != "" {
+		properties["name"] = []string{entry.Name}
+	}
+	if entry.ContentHTML != "" {
+		properties["content"] = []any{gin.H{"html": entry.ContentHTML, "value": entry.Content}}
+	} else if entry.Content != "" {
+		properties["content"] = []string{entry.Content}
+	}
+	if entry.Summary != "" {
+		properties["summary"] = []string{entry.Summary}
+	}
+	if len(entry.Categories) > 0 {
+		properties["category"] = entry.Categories
+	}
+	if len(entry.Photos) > 0 {
+		properties["photo"] = entry.Photos
+	}
+	if entry.Slug != "" {
+		properties["mp-slug"] = []string{entry.Slug}
+	}
+	if entry.Location != "" {
+		properties["location"] = []string{entry.Location}
+	}
+	if entry.Draft {
+		properties["post-status"] = []string{"draft"}
+	} else {
+		properties["post-status"] = []string{"published"}
+	}
+
+	requested := make(gin.H)
+	for _, names := range propertyNames {
+		for _, name := range names {
+			if value, ok := properties[name]; ok {
+				requested[name] = value
+			}
+		}
+	}
+	if len(requested) > 0 {
+		return gin.H{"properties": requested}
+	}
+	return gin.H{"type": []string{"h-entry"}, "properties": properties}
+}
+
+func hasScope(token *dto.MicropubToken, scopes ...string) bool {
+	for _, scope := range token.Scopes {
+		for _, s := range scopes {
+			if scope == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *MicropubHandler) insufficientScope(ctx *gin.Context, scope string) {
+	ctx.JSON(http.StatusForbidden, gin.H{
+		"error":             "insufficient_scope",
+		"error_description": "the scope " + scope + " is required",
+		"scope":             scope,
+	})
+}
+
+// error responds the error as the Micropub spec requires, see https://www.w3.org/TR/micropub/#error-response
+func (m *MicropubHandler) error(ctx *gin.Context, err error) {
+	status := xerr.GetHTTPStatus(err)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	code := "invalid_request"
+	switch status {
+	case http.StatusUnauthorized:
+		code = "unauthorized"
+	case http.StatusForbidden:
+		code = "forbidden"
+	case http.StatusInternalServerError:
+		code = "server_error"
+		log.CtxErrorf(ctx, "micropub err=%+v", err)
+	}
+	ctx.JSON(status, gin.H{"error": code, "error_description": xerr.GetMessage(err)})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-sonic/sonic/service"
 	"github.com/go-sonic/sonic/service/assembler"
 	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util/xerr"
 )
 
 func NewCategoryModel(optionService service.OptionService,
@@ -22,6 +23,7 @@ func NewCategoryModel(optionService service.OptionService,
 	tagService service.TagService,
 	postAssembler assembler.PostAssembler,
 	metaService service.MetaService,
+	translationService service.TranslationService,
 	categoryAuthentication *authentication.CategoryAuthentication,
 ) *CategoryModel {
 	return &CategoryModel{
@@ -34,6 +36,7 @@ func NewCategoryModel(optionService service.OptionService,
 		PostTagService:         postTagService,
 		TagService:             tagService,
 		MetaService:            metaService,
+		TranslationService:     translationService,
 		CategoryAuthentication: categoryAuthentication,
 	}
 }
@@ -47,6 +50,7 @@ type CategoryModel struct {
 	PostTagService         service.PostTagService
 	TagService             service.TagService
 	MetaService            service.MetaService
+	TranslationService     service.TranslationService
 	PostAssembler          assembler.PostAssembler
 	CategoryAuthentication *authentication.CategoryAuthentication
 }
@@ -66,6 +70,11 @@ func (c *CategoryModel) CategoryDetail(ctx context.Context, model template.Model
 	if err != nil {
 		return "", err
 	}
+	if match, err := c.TranslationService.MatchRequestLanguage(ctx, consts.TranslationTypeCategory, category.ID); err != nil {
+		return "", err
+	} else if !match {
+		return "", xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("分类不存在")
+	}
 
 	if category.Type == consts.CategoryTypeIntimate {
 		if isAuthenticated, err := c.CategoryAuthentication.IsAuthenticated(ctx, token, category.ID); err != nil || !isAuthenticated {
@@ -116,6 +125,17 @@ func (c *CategoryModel) CategoryDetail(ctx context.Context, model template.Model
 	model["posts"] = postPage
 	model["category"] = categoryDTO
 	model["meta_keywords"] = c.OptionService.GetOrByDefault(ctx, property.SeoKeywords)
+	if category.Type != consts.CategoryTypeIntimate {
+		blogURL, err := c.OptionService.GetBlogBaseURL(ctx)
+		if err != nil {
+			return "", err
+		}
+		// the category feeds of a language are under the prefix of the language as well
+		pathPrefix, _ := ctx.Value(consts.RequestPathPrefix).(string)
+		feedURL := blogURL + pathPrefix
+		model["page_feeds"] = dto.NewFeedLinks("分类："+category.Name,
+			feedURL+"/feed/categories/"+category.Slug+".xml", feedURL+"/atom/categories/"+category.Slug, feedURL+"/feed/categories/"+category.Slug+".json")
+	}
 
 	return c.ThemeService.Render(ctx, "category")
 }
@@ -12,4 +12,5 @@ func init() {
 	injection.Provide(NewLinkModel)
 	injection.Provide(NewPhotoModel)
 	injection.Provide(NewJournalModel)
+	injection.Provide(NewSeriesModel)
 }
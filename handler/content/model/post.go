@@ -25,6 +25,9 @@ func NewPostModel(optionService service.OptionService,
 	tagService service.TagService,
 	postAssembler assembler.PostAssembler,
 	metaService service.MetaService,
+	shortcodeService service.ShortcodeService,
+	seriesService service.SeriesService,
+	translationService service.TranslationService,
 	postAuthentication *authentication.PostAuthentication,
 ) *PostModel {
 	return &PostModel{
@@ -37,6 +40,9 @@ func NewPostModel(optionService service.OptionService,
 		PostTagService:      postTagService,
 		TagService:          tagService,
 		MetaService:         metaService,
+		ShortcodeService:    shortcodeService,
+		SeriesService:       seriesService,
+		TranslationService:  translationService,
 		PostAuthentication:  postAuthentication,
 	}
 }
@@ -50,6 +56,9 @@ type PostModel struct {
 	PostTagService      service.PostTagService
 	TagService          service.TagService
 	MetaService         service.MetaService
+	ShortcodeService    service.ShortcodeService
+	SeriesService       service.SeriesService
+	TranslationService  service.TranslationService
 	PostAssembler       assembler.PostAssembler
 	PostAuthentication  *authentication.PostAuthentication
 }
@@ -60,7 +69,13 @@ func (p *PostModel) Content(ctx context.Context, post *entity.Post, token string
 	}
 	if post.Status == consts.PostStatusRecycle || post.Status == consts.PostStatusDraft {
 		return "", xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("查询不到文章信息")
-	} else if post.Status == consts.PostStatusIntimate {
+	}
+	if match, err := p.TranslationService.MatchRequestLanguage(ctx, consts.TranslationTypePost, post.ID); err != nil {
+		return "", err
+	} else if !match {
+		return "", xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("查询不到文章信息")
+	}
+	if post.Status == consts.PostStatusIntimate {
 		if isAuthenticated, err := p.PostAuthentication.IsAuthenticated(ctx, token, post.ID); err != nil || !isAuthenticated {
 			model["slug"] = post.Slug
 			model["type"] = consts.EncryptTypePost.Name()
@@ -75,6 +90,10 @@ func (p *PostModel) Content(ctx context.Context, post *entity.Post, token string
 	if err != nil {
 		return "", err
 	}
+	postVO.Content, err = p.ShortcodeService.Render(ctx, postVO.Content)
+	if err != nil {
+		return "", err
+	}
 	model["post"] = postVO
 
 	prevPosts, err := p.PostService.GetPrevPosts(ctx, post, 1)
@@ -99,6 +118,9 @@ func (p *PostModel) Content(ctx context.Context, post *entity.Post, token string
 		}
 		model["nextPost"] = nextPost
 	}
+	if err := p.setSeriesPosts(ctx, post, model); err != nil {
+		return "", err
+	}
 
 	categories, err := p.PostCategoryService.ListCategoryByPostID(ctx, post.ID)
 	if err != nil {
@@ -224,6 +246,10 @@ func (p *PostModel) AdminPreview(ctx context.Context, post *entity.Post, model t
 	if err != nil {
 		return "", err
 	}
+	postVO.Content, err = p.ShortcodeService.Render(ctx, postVO.Content)
+	if err != nil {
+		return "", err
+	}
 	model["post"] = postVO
 
 	prevPosts, err := p.PostService.GetPrevPosts(ctx, post, 1)
@@ -248,6 +274,9 @@ func (p *PostModel) AdminPreview(ctx context.Context, post *entity.Post, model t
 		}
 		model["nextPost"] = nextPost
 	}
+	if err := p.setSeriesPosts(ctx, post, model); err != nil {
+		return "", err
+	}
 
 	categories, err := p.PostCategoryService.ListCategoryByPostID(ctx, post.ID)
 	if err != nil {
@@ -289,3 +318,26 @@ func (p *PostModel) AdminPreview(ctx context.Context, post *entity.Post, model t
 	model["type"] = "post"
 	return p.ThemeService.Render(ctx, "post")
 }
+
+// setSeriesPosts puts the posts before and after the post in its series into the model
+func (p *PostModel) setSeriesPosts(ctx context.Context, post *entity.Post, model template.Model) error {
+	prevPost, nextPost, err := p.SeriesService.GetAdjacentPosts(ctx, post.ID)
+	if err != nil {
+		return err
+	}
+	if prevPost != nil {
+		prevPostVO, err := p.PostAssembler.ConvertToDetailVO(ctx, prevPost)
+		if err != nil {
+			return err
+		}
+		model["seriesPrevPost"] = prevPostVO
+	}
+	if nextPost != nil {
+		nextPostVO, err := p.PostAssembler.ConvertToDetailVO(ctx, nextPost)
+		if err != nil {
+			return err
+		}
+		model["seriesNextPost"] = nextPostVO
+	}
+	return nil
+}
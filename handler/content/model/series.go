@@ -0,0 +1,81 @@
+package model
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/service/assembler"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util"
+)
+
+func NewSeriesModel(optionService service.OptionService,
+	themeService service.ThemeService,
+	seriesService service.SeriesService,
+	postAssembler assembler.PostAssembler,
+) *SeriesModel {
+	return &SeriesModel{
+		OptionService: optionService,
+		ThemeService:  themeService,
+		SeriesService: seriesService,
+		PostAssembler: postAssembler,
+	}
+}
+
+type SeriesModel struct {
+	OptionService service.OptionService
+	ThemeService  service.ThemeService
+	SeriesService service.SeriesService
+	PostAssembler assembler.PostAssembler
+}
+
+func (s *SeriesModel) ListSeries(ctx context.Context, model template.Model) (string, error) {
+	series, err := s.SeriesService.ListAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	seriesDTOs, err := s.SeriesService.ConvertToDTOs(ctx, series)
+	if err != nil {
+		return "", err
+	}
+	model["is_series_list"] = true
+	model["series_list"] = seriesDTOs
+	model["meta_keywords"] = s.OptionService.GetOrByDefault(ctx, property.SeoKeywords)
+	model["meta_description"] = s.OptionService.GetOrByDefault(ctx, property.SeoDescription)
+	return s.render(ctx, "series_list")
+}
+
+func (s *SeriesModel) SeriesDetail(ctx context.Context, model template.Model, slug string) (string, error) {
+	series, err := s.SeriesService.GetBySlug(ctx, slug)
+	if err != nil {
+		return "", err
+	}
+	seriesDTO, err := s.SeriesService.ConvertToDTO(ctx, series)
+	if err != nil {
+		return "", err
+	}
+	posts, err := s.SeriesService.ListPosts(ctx, series.ID, consts.PostStatusPublished.Ptr())
+	if err != nil {
+		return "", err
+	}
+	postVOs, err := s.PostAssembler.ConvertToListVO(ctx, posts)
+	if err != nil {
+		return "", err
+	}
+	model["is_series"] = true
+	model["series"] = seriesDTO
+	model["posts"] = postVOs
+	model["meta_keywords"] = s.OptionService.GetOrByDefault(ctx, property.SeoKeywords)
+	model["meta_description"] = util.IfElse(series.Description != "", series.Description, s.OptionService.GetOrByDefault(ctx, property.SeoDescription))
+	return s.render(ctx, "series")
+}
+
+// render renders the template of the theme, the built-in one is used when the theme has no template for the series
+func (s *SeriesModel) render(ctx context.Context, name string) (string, error) {
+	if exist, err := s.ThemeService.TemplateExist(ctx, name+".tmpl"); err == nil && exist {
+		return s.ThemeService.Render(ctx, name)
+	}
+	return "common/template/series", nil
+}
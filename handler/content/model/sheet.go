@@ -20,6 +20,8 @@ func NewSheetModel(optionService service.OptionService,
 	metaService service.MetaService,
 	sheetAssembler assembler.SheetAssembler,
 	sheetService service.SheetService,
+	shortcodeService service.ShortcodeService,
+	translationService service.TranslationService,
 	postAuthentication *authentication.PostAuthentication,
 ) *SheetModel {
 	return &SheetModel{
@@ -30,6 +32,8 @@ func NewSheetModel(optionService service.OptionService,
 		MetaService:        metaService,
 		SheetAssembler:     sheetAssembler,
 		SheetService:       sheetService,
+		ShortcodeService:   shortcodeService,
+		TranslationService: translationService,
 		PostAuthentication: postAuthentication,
 	}
 }
@@ -42,6 +46,8 @@ type SheetModel struct {
 	TagService         service.TagService
 	MetaService        service.MetaService
 	SheetAssembler     assembler.SheetAssembler
+	ShortcodeService   service.ShortcodeService
+	TranslationService service.TranslationService
 	PostAuthentication *authentication.PostAuthentication
 }
 
@@ -51,7 +57,13 @@ func (s *SheetModel) Content(ctx context.Context, sheet *entity.Post, token stri
 	}
 	if sheet.Status == consts.PostStatusRecycle || sheet.Status == consts.PostStatusDraft {
 		return "", xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("查询不到文章信息")
-	} else if sheet.Status == consts.PostStatusIntimate {
+	}
+	if match, err := s.TranslationService.MatchRequestLanguage(ctx, consts.TranslationTypePost, sheet.ID); err != nil {
+		return "", err
+	} else if !match {
+		return "", xerr.WithStatus(nil, xerr.StatusNotFound).WithMsg("查询不到文章信息")
+	}
+	if sheet.Status == consts.PostStatusIntimate {
 		if isAuthenticated, err := s.PostAuthentication.IsAuthenticated(ctx, token, sheet.ID); err != nil || !isAuthenticated {
 			model["slug"] = sheet.Slug
 			model["type"] = consts.EncryptTypePost.Name()
@@ -66,6 +78,10 @@ func (s *SheetModel) Content(ctx context.Context, sheet *entity.Post, token stri
 	if err != nil {
 		return "", err
 	}
+	sheetVO.Content, err = s.ShortcodeService.Render(ctx, sheetVO.Content)
+	if err != nil {
+		return "", err
+	}
 	model["target"] = sheetVO
 	model["type"] = "sheet"
 	model["post"] = sheetVO
@@ -115,6 +131,10 @@ func (s *SheetModel) AdminPreviewContent(ctx context.Context, sheet *entity.Post
 	if err != nil {
 		return "", err
 	}
+	sheetVO.Content, err = s.ShortcodeService.Render(ctx, sheetVO.Content)
+	if err != nil {
+		return "", err
+	}
 	model["target"] = sheetVO
 	model["type"] = "sheet"
 	model["post"] = sheetVO
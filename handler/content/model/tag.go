@@ -75,9 +75,17 @@ func (t *TagModel) TagPosts(ctx context.Context, model template.Model, slug stri
 		PageNum:  page,
 		PageSize: pageSize,
 	})
+	blogURL, err := t.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return "", err
+	}
 	model["is_tag"] = true
 	model["posts"] = postPage
 	model["tag"] = tagDTO
+	pathPrefix, _ := ctx.Value(consts.RequestPathPrefix).(string)
+	feedURL := blogURL + pathPrefix
+	model["page_feeds"] = dto.NewFeedLinks("标签："+tag.Name,
+		feedURL+"/feed/tags/"+tag.Slug+".xml", feedURL+"/atom/tags/"+tag.Slug, feedURL+"/feed/tags/"+tag.Slug+".json")
 	model["meta_keywords"] = t.OptionService.GetOrByDefault(ctx, property.SeoKeywords)
 	model["meta_description"] = t.OptionService.GetOrByDefault(ctx, property.SeoDescription)
 	return t.ThemeService.Render(ctx, "tag")
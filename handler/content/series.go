@@ -0,0 +1,31 @@
+package content
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/handler/content/model"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util"
+)
+
+type SeriesHandler struct {
+	SeriesModel *model.SeriesModel
+}
+
+func NewSeriesHandler(seriesModel *model.SeriesModel) *SeriesHandler {
+	return &SeriesHandler{
+		SeriesModel: seriesModel,
+	}
+}
+
+func (s *SeriesHandler) Series(ctx *gin.Context, model template.Model) (string, error) {
+	return s.SeriesModel.ListSeries(ctx, model)
+}
+
+func (s *SeriesHandler) SeriesDetail(ctx *gin.Context, model template.Model) (string, error) {
+	slug, err := util.ParamString(ctx, "slug")
+	if err != nil {
+		return "", err
+	}
+	return s.SeriesModel.SeriesDetail(ctx, model, slug)
+}
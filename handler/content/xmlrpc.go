@@ -0,0 +1,784 @@
+package content
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+	"github.com/go-sonic/sonic/util/xmlrpc"
+)
+
+// the fault codes of pingback, see https://www.hixie.ch/specs/pingback/pingback
+const (
+	pingbackFaultGeneric          = 0
+	pingbackFaultTargetNotExist   = 32
+	pingbackFaultTargetInvalid    = 33
+	pingbackFaultAccessDenied     = 49
+	xmlrpcFaultMethodNotFound     = -32601
+	xmlrpcFaultInvalidParameters  = -32602
+	xmlrpcFaultInvalidRequestBody = -32700
+	// xmlrpcFaultDisabled is the fault code WordPress responds when the XML-RPC methods are disabled
+	xmlrpcFaultDisabled = 405
+)
+
+// xmlrpcMethod is a MetaWeblog, Blogger, WordPress or Movable Type method,
+// the username and the password are the params at userIndex and userIndex+1
+type xmlrpcMethod struct {
+	params    int
+	userIndex int
+	call      func(ctx *gin.Context, user *entity.User, params []any) (any, error)
+}
+
+type XMLRPCHandler struct {
+	OptionService     service.OptionService
+	MentionService    service.MentionService
+	MetaWeblogService service.MetaWeblogService
+	methods           map[string]*xmlrpcMethod
+}
+
+func NewXMLRPCHandler(optionService service.OptionService, mentionService service.MentionService, metaWeblogService service.MetaWeblogService) *XMLRPCHandler {
+	x := &XMLRPCHandler{
+		OptionService:     optionService,
+		MentionService:    mentionService,
+		MetaWeblogService: metaWeblogService,
+	}
+	getUsersBlogs := &xmlrpcMethod{params: 3, userIndex: 1, call: x.getUsersBlogs}
+	getCategories := &xmlrpcMethod{params: 3, userIndex: 1, call: x.getCategories}
+	newMediaObject := &xmlrpcMethod{params: 4, userIndex: 1, call: x.newMediaObject}
+	x.methods = map[string]*xmlrpcMethod{
+		"blogger.getUsersBlogs":     getUsersBlogs,
+		"blogger.getUserInfo":       {params: 3, userIndex: 1, call: x.getUserInfo},
+		"blogger.deletePost":        {params: 4, userIndex: 2, call: x.bloggerDeletePost},
+		"metaWeblog.getUsersBlogs":  getUsersBlogs,
+		"metaWeblog.newPost":        {params: 4, userIndex: 1, call: x.newPost},
+		"metaWeblog.editPost":       {params: 4, userIndex: 1, call: x.editPost},
+		"metaWeblog.getPost":        {params: 3, userIndex: 1, call: x.getPost},
+		"metaWeblog.getRecentPosts": {params: 3, userIndex: 1, call: x.getRecentPosts},
+		"metaWeblog.deletePost":     {params: 4, userIndex: 2, call: x.bloggerDeletePost},
+		"metaWeblog.newMediaObject": newMediaObject,
+		"metaWeblog.getCategories":  getCategories,
+		"wp.getUsersBlogs":          {params: 2, userIndex: 0, call: x.getUsersBlogs},
+		"wp.getCategories":          getCategories,
+		"wp.newCategory":            {params: 4, userIndex: 1, call: x.newCategory},
+		"wp.getTags":                {params: 3, userIndex: 1, call: x.getTags},
+		"wp.uploadFile":             newMediaObject,
+		"wp.newPost":                {params: 4, userIndex: 1, call: x.wpNewPost},
+		"wp.editPost":               {params: 5, userIndex: 1, call: x.wpEditPost},
+		"wp.getPost":                {params: 4, userIndex: 1, call: x.wpGetPost},
+		"wp.getPosts":               {params: 3, userIndex: 1, call: x.wpGetPosts},
+		"wp.deletePost":             {params: 4, userIndex: 1, call: x.wpDeletePost},
+		"mt.getCategoryList":        {params: 3, userIndex: 1, call: x.getCategoryList},
+		"mt.getPostCategories":      {params: 3, userIndex: 1, call: x.getPostCategories},
+		"mt.setPostCategories":      {params: 4, userIndex: 1, call: x.setPostCategories},
+		"mt.supportedMethods":       {userIndex: -1, call: x.supportedMethods},
+		"mt.supportedTextFilters":   {userIndex: -1, call: x.supportedTextFilters},
+	}
+	return x
+}
+
+// XMLRPC serves pingback.ping and the methods of the desktop and mobile editors
+func (x *XMLRPCHandler) XMLRPC(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/xml; charset=utf-8")
+	methodCall, err := xmlrpc.DecodeMethodCall(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, consts.XMLRPCRequestLimit))
+	if err != nil {
+		x.fault(ctx, xmlrpcFaultInvalidRequestBody, "parse error")
+		return
+	}
+	if methodCall.MethodName == "pingback.ping" {
+		x.pingback(ctx, methodCall.Params)
+		return
+	}
+	method, ok := x.methods[methodCall.MethodName]
+	if !ok {
+		x.fault(ctx, xmlrpcFaultMethodNotFound, "method not found: "+methodCall.MethodName)
+		return
+	}
+	if len(methodCall.Params) < method.params {
+		x.fault(ctx, xmlrpcFaultInvalidParameters, methodCall.MethodName+" requires "+strconv.Itoa(method.params)+" params")
+		return
+	}
+	var user *entity.User
+	if method.userIndex >= 0 {
+		if !x.OptionService.GetOrByDefault(ctx, property.MetaWeblogEnabled).(bool) {
+			x.fault(ctx, xmlrpcFaultDisabled, "XML-RPC services are disabled on this site")
+			return
+		}
+		username, _ := methodCall.Params[method.userIndex].(string)
+		password, _ := methodCall.Params[method.userIndex+1].(string)
+		if user, err = x.MetaWeblogService.Authenticate(ctx, username, password); err != nil {
+			x.faultByErr(ctx, err)
+			return
+		}
+	}
+	result, err := method.call(ctx, user, methodCall.Params)
+	if err != nil {
+		x.faultByErr(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+	if err := xmlrpc.EncodeResponse(ctx.Writer, result); err != nil {
+		log.CtxErrorf(ctx, "encode xmlrpc response err=%+v", err)
+	}
+}
+
+func (x *XMLRPCHandler) pingback(ctx *gin.Context, params []any) {
+	if len(params) != 2 {
+		x.fault(ctx, xmlrpcFaultInvalidParameters, "pingback.ping requires source and target")
+		return
+	}
+	source, _ := params[0].(string)
+	target, _ := params[1].(string)
+	err := x.MentionService.Receive(ctx, consts.MentionProtocolPingback, source, target, ctx.ClientIP())
+	if err != nil {
+		switch xerr.GetHTTPStatus(err) {
+		case http.StatusNotFound:
+			x.fault(ctx, pingbackFaultTargetNotExist, xerr.GetMessage(err))
+		case http.StatusBadRequest:
+			x.fault(ctx, pingbackFaultTargetInvalid, xerr.GetMessage(err))
+		case http.StatusForbidden:
+			x.fault(ctx, pingbackFaultAccessDenied, xerr.GetMessage(err))
+		default:
+			x.fault(ctx, pingbackFaultGeneric, xerr.GetMessage(err))
+		}
+		return
+	}
+	ctx.Status(http.StatusOK)
+	if err := xmlrpc.EncodeResponse(ctx.Writer, "Pingback received, the source will be verified"); err != nil {
+		log.CtxErrorf(ctx, "encode xmlrpc response err=%+v", err)
+	}
+}
+
+func (x *XMLRPCHandler) getUsersBlogs(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	blog, err := x.MetaWeblogService.GetBlog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []any{map[string]any{
+		"blogid":   consts.MetaWeblogBlogID,
+		"blogName": blog.Name,
+		"url":      blog.URL,
+		"xmlrpc":   strings.TrimSuffix(blog.URL, "/") + consts.XMLRPCPath,
+		"isAdmin":  true,
+	}}, nil
+}
+
+func (x *XMLRPCHandler) getUserInfo(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	blog, err := x.MetaWeblogService.GetBlog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"userid":    strconv.Itoa(int(user.ID)),
+		"nickname":  user.Nickname,
+		"firstname": user.Nickname,
+		"lastname":  "",
+		"email":     user.Email,
+		"url":       blog.URL,
+	}, nil
+}
+
+func (x *XMLRPCHandler) newPost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postParam, err := x.convertPostParam(params[3], params, 4)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := x.MetaWeblogService.NewPost(ctx, postParam)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.Itoa(int(postID)), nil
+}
+
+func (x *XMLRPCHandler) editPost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postID, err := xmlrpcID(params[0])
+	if err != nil {
+		return nil, err
+	}
+	postParam, err := x.convertPostParam(params[3], params, 4)
+	if err != nil {
+		return nil, err
+	}
+	if err = x.MetaWeblogService.EditPost(ctx, postID, postParam); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func (x *XMLRPCHandler) getPost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postID, err := xmlrpcID(params[0])
+	if err != nil {
+		return nil, err
+	}
+	post, err := x.MetaWeblogService.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	return convertPost(post), nil
+}
+
+func (x *XMLRPCHandler) getRecentPosts(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	size, _ := params[3].(int)
+	posts, err := x.MetaWeblogService.GetRecentPosts(ctx, size)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(posts))
+	for _, post := range posts {
+		result = append(result, convertPost(post))
+	}
+	return result, nil
+}
+
+func (x *XMLRPCHandler) wpNewPost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postParam, err := convertWPPostParam(params[3], nil)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := x.MetaWeblogService.NewPost(ctx, postParam)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.Itoa(int(postID)), nil
+}
+
+func (x *XMLRPCHandler) wpEditPost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postID, err := xmlrpcID(params[3])
+	if err != nil {
+		return nil, err
+	}
+	// wp.editPost sends only the changed members
+	current, err := x.MetaWeblogService.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	postParam, err := convertWPPostParam(params[4], current)
+	if err != nil {
+		return nil, err
+	}
+	if err = x.MetaWeblogService.EditPost(ctx, postID, postParam); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func (x *XMLRPCHandler) wpGetPost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postID, err := xmlrpcID(params[3])
+	if err != nil {
+		return nil, err
+	}
+	post, err := x.MetaWeblogService.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := x.convertWPPosts(ctx, []*dto.MetaWeblogPost{post})
+	if err != nil {
+		return nil, err
+	}
+	return posts[0], nil
+}
+
+func (x *XMLRPCHandler) wpGetPosts(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	var filter map[string]any
+	if len(params) > 3 {
+		filter, _ = params[3].(map[string]any)
+	}
+	if postType, _ := filter["post_type"].(string); postType != "" && postType != "post" {
+		return []any{}, nil
+	}
+	number, offset := 10, 0
+	if n, ok := filter["number"].(int); ok && n > 0 {
+		number = n
+	}
+	if o, ok := filter["offset"].(int); ok && o > 0 {
+		offset = o
+	}
+	status, _ := filter["post_status"].(string)
+
+	posts, err := x.MetaWeblogService.GetRecentPosts(ctx, consts.MetaWeblogRecentPostsLimit)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*dto.MetaWeblogPost, 0, len(posts))
+	for _, post := range posts {
+		if status == "" || status == wpPostStatus(post) {
+			filtered = append(filtered, post)
+		}
+	}
+	filtered = filtered[min(offset, len(filtered)):]
+	filtered = filtered[:min(number, len(filtered))]
+	return x.convertWPPosts(ctx, filtered)
+}
+
+// convertWPPosts converts the posts to the post structs of the WordPress API, the categories and the tags are the terms
+func (x *XMLRPCHandler) convertWPPosts(ctx *gin.Context, posts []*dto.MetaWeblogPost) ([]any, error) {
+	categories, err := x.MetaWeblogService.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := x.MetaWeblogService.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(posts))
+	for _, post := range posts {
+		terms := make([]any, 0, len(post.Categories)+len(post.Tags))
+		for _, name := range post.Categories {
+			for _, category := range categories {
+				if category.Name == name {
+					terms = append(terms, map[string]any{
+						"term_id":          strconv.Itoa(int(category.ID)),
+						"term_taxonomy_id": strconv.Itoa(int(category.ID)),
+						"name":             category.Name,
+						"slug":             category.Slug,
+						"taxonomy":         "category",
+						"description":      category.Description,
+						"parent":           strconv.Itoa(int(category.ParentID)),
+					})
+					break
+				}
+			}
+		}
+		for _, name := range post.Tags {
+			for _, tag := range tags {
+				if tag.Name == name {
+					terms = append(terms, map[string]any{
+						"term_id":          strconv.Itoa(int(tag.ID)),
+						"term_taxonomy_id": strconv.Itoa(int(tag.ID)),
+						"name":             tag.Name,
+						"slug":             tag.Slug,
+						"taxonomy":         "post_tag",
+						"description":      "",
+						"parent":           "0",
+						"count":            int(tag.PostCount),
+					})
+					break
+				}
+			}
+		}
+		commentStatus := "open"
+		if post.DisallowComment {
+			commentStatus = "closed"
+		}
+		result = append(result, map[string]any{
+			"post_id":        strconv.Itoa(int(post.PostID)),
+			"post_title":     post.Title,
+			"post_date":      post.CreateTime,
+			"post_date_gmt":  post.CreateTime.UTC(),
+			"post_status":    wpPostStatus(post),
+			"post_type":      "post",
+			"post_format":    "standard",
+			"post_name":      post.Slug,
+			"post_password":  "",
+			"post_excerpt":   post.Excerpt,
+			"post_content":   post.Content,
+			"post_parent":    "0",
+			"link":           post.Link,
+			"guid":           post.Link,
+			"comment_status": commentStatus,
+			"sticky":         false,
+			"terms":          terms,
+			"custom_fields":  []any{},
+		})
+	}
+	return result, nil
+}
+
+func (x *XMLRPCHandler) bloggerDeletePost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	return x.deletePost(ctx, params[1])
+}
+
+func (x *XMLRPCHandler) wpDeletePost(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	return x.deletePost(ctx, params[3])
+}
+
+func (x *XMLRPCHandler) deletePost(ctx *gin.Context, id any) (any, error) {
+	postID, err := xmlrpcID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err = x.MetaWeblogService.DeletePost(ctx, postID); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func (x *XMLRPCHandler) newMediaObject(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	media, ok := params[3].(map[string]any)
+	if !ok {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("media struct is required")
+	}
+	name, _ := media["name"].(string)
+	bits, ok := media["bits"].([]byte)
+	if !ok {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("bits of the media is required")
+	}
+	attachment, err := x.MetaWeblogService.NewMediaObject(ctx, name, bits)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"id":   strconv.Itoa(int(attachment.ID)),
+		"file": attachment.Name,
+		"url":  attachment.Path,
+		"type": attachment.MediaType,
+	}, nil
+}
+
+func (x *XMLRPCHandler) getCategories(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	categories, err := x.MetaWeblogService.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, map[string]any{
+			"categoryId":          strconv.Itoa(int(category.ID)),
+			"parentId":            strconv.Itoa(int(category.ParentID)),
+			"categoryName":        category.Name,
+			"title":               category.Name,
+			"description":         category.Name,
+			"categoryDescription": category.Description,
+			"htmlUrl":             category.URL,
+			"rssUrl":              category.RSSURL,
+		})
+	}
+	return result, nil
+}
+
+func (x *XMLRPCHandler) getCategoryList(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	categories, err := x.MetaWeblogService.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, map[string]any{
+			"categoryId":   strconv.Itoa(int(category.ID)),
+			"categoryName": category.Name,
+		})
+	}
+	return result, nil
+}
+
+func (x *XMLRPCHandler) newCategory(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	category, ok := params[3].(map[string]any)
+	if !ok {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("category struct is required")
+	}
+	name, _ := category["name"].(string)
+	slug, _ := category["slug"].(string)
+	description, _ := category["description"].(string)
+	var parentID int32
+	if parent, ok := category["parent_id"]; ok {
+		// the editors send 0 or an empty string for the top level category
+		parentID, _ = xmlrpcID(parent)
+	}
+	categoryID, err := x.MetaWeblogService.NewCategory(ctx, name, slug, description, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return int(categoryID), nil
+}
+
+func (x *XMLRPCHandler) getTags(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	tags, err := x.MetaWeblogService.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, map[string]any{
+			"tag_id":   strconv.Itoa(int(tag.ID)),
+			"name":     tag.Name,
+			"slug":     tag.Slug,
+			"count":    int(tag.PostCount),
+			"html_url": tag.URL,
+		})
+	}
+	return result, nil
+}
+
+func (x *XMLRPCHandler) getPostCategories(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postID, err := xmlrpcID(params[0])
+	if err != nil {
+		return nil, err
+	}
+	post, err := x.MetaWeblogService.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := x.MetaWeblogService.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(post.Categories))
+	for i, name := range post.Categories {
+		for _, category := range categories {
+			if category.Name == name {
+				result = append(result, map[string]any{
+					"categoryId":   strconv.Itoa(int(category.ID)),
+					"categoryName": category.Name,
+					"isPrimary":    i == 0,
+				})
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (x *XMLRPCHandler) setPostCategories(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	postID, err := xmlrpcID(params[0])
+	if err != nil {
+		return nil, err
+	}
+	categories, _ := params[3].([]any)
+	categoryIDs := make([]int32, 0, len(categories))
+	seen := make(map[int32]struct{})
+	for _, c := range categories {
+		category, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		categoryID, err := xmlrpcID(category["categoryId"])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[categoryID]; ok {
+			continue
+		}
+		seen[categoryID] = struct{}{}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	if err = x.MetaWeblogService.SetPostCategories(ctx, postID, categoryIDs); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func (x *XMLRPCHandler) supportedMethods(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	methods := make([]string, 0, len(x.methods)+1)
+	methods = append(methods, "pingback.ping")
+	for name := range x.methods {
+		methods = append(methods, name)
+	}
+	sort.Strings(methods)
+	return methods, nil
+}
+
+func (x *XMLRPCHandler) supportedTextFilters(ctx *gin.Context, user *entity.User, params []any) (any, error) {
+	return []any{
+		map[string]any{"key": consts.MetaWeblogTextFilterMarkdown, "label": "Markdown"},
+	}, nil
+}
+
+// convertPostParam converts the post struct of MetaWeblog, the publish flag is the param at publishIndex.
+// The members absent from the struct are left nil so that they are kept when the post is edited
+func (x *XMLRPCHandler) convertPostParam(value any, params []any, publishIndex int) (*param.MetaWeblogPost, error) {
+	post, ok := value.(map[string]any)
+	if !ok {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("post struct is required")
+	}
+	postParam := &param.MetaWeblogPost{}
+	postParam.Title, _ = post["title"].(string)
+	postParam.Content, _ = post["description"].(string)
+	if more, _ := post["mt_text_more"].(string); more != "" {
+		postParam.Content += "\n\n" + more
+	}
+	postParam.Excerpt, _ = post["mt_excerpt"].(string)
+	postParam.Slug, _ = post["wp_slug"].(string)
+	if categories, ok := post["categories"].([]any); ok {
+		postParam.Categories = make([]string, 0, len(categories))
+		for _, category := range categories {
+			if name, ok := category.(string); ok {
+				postParam.Categories = append(postParam.Categories, name)
+			}
+		}
+	}
+	if keywords, ok := post["mt_keywords"].(string); ok {
+		postParam.Tags = make([]string, 0)
+		for _, keyword := range strings.Split(keywords, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				postParam.Tags = append(postParam.Tags, keyword)
+			}
+		}
+	}
+	for _, key := range []string{"date_created_gmt", "dateCreated"} {
+		if createTime, ok := post[key].(time.Time); ok && !createTime.IsZero() {
+			postParam.CreateTime = &createTime
+			break
+		}
+	}
+	if allowComments, ok := post["mt_allow_comments"]; ok {
+		var disallowComment bool
+		switch v := allowComments.(type) {
+		case int:
+			disallowComment = v == 0
+		case bool:
+			disallowComment = !v
+		case string:
+			disallowComment = v == "closed" || v == "0"
+		}
+		postParam.DisallowComment = &disallowComment
+	}
+	if textFilter, ok := post["mt_convert_breaks"].(string); ok {
+		postParam.TextFilter = &textFilter
+	}
+	if len(params) > publishIndex {
+		postParam.Published, _ = params[publishIndex].(bool)
+	}
+	if status, ok := post["post_status"].(string); ok && status != "" {
+		postParam.Published = status == "publish" || status == "private"
+	}
+	return postParam, nil
+}
+
+// convertWPPostParam converts the content struct of the WordPress API,
+// the members absent from the struct keep the values of current when the post is edited
+func convertWPPostParam(value any, current *dto.MetaWeblogPost) (*param.MetaWeblogPost, error) {
+	post, ok := value.(map[string]any)
+	if !ok {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("content struct is required")
+	}
+	if postType, _ := post["post_type"].(string); postType != "" && postType != "post" {
+		return nil, xerr.BadParam.New("post type %s", postType).WithStatus(xerr.StatusBadRequest).WithMsg("only posts are supported")
+	}
+	postParam := &param.MetaWeblogPost{}
+	if current != nil {
+		postParam.Title = current.Title
+		postParam.Content = current.Content
+		postParam.Excerpt = current.Excerpt
+		postParam.Slug = current.Slug
+		postParam.CreateTime = &current.CreateTime
+		postParam.Published = current.Published
+	}
+	if title, ok := post["post_title"].(string); ok {
+		postParam.Title = title
+	}
+	if content, ok := post["post_content"].(string); ok {
+		postParam.Content = content
+	}
+	if excerpt, ok := post["post_excerpt"].(string); ok {
+		postParam.Excerpt = excerpt
+	}
+	if slug, ok := post["post_name"].(string); ok {
+		postParam.Slug = slug
+	}
+	if status, ok := post["post_status"].(string); ok && status != "" {
+		postParam.Published = status == "publish" || status == "private"
+	}
+	for _, key := range []string{"post_date_gmt", "post_date"} {
+		if createTime, ok := post[key].(time.Time); ok && !createTime.IsZero() {
+			postParam.CreateTime = &createTime
+			break
+		}
+	}
+	if commentStatus, ok := post["comment_status"].(string); ok && commentStatus != "" {
+		disallowComment := commentStatus == "closed"
+		postParam.DisallowComment = &disallowComment
+	}
+	if terms, ok := post["terms"].(map[string]any); ok {
+		if categoryIDs, ok := terms["category"].([]any); ok {
+			postParam.CategoryIDs = make([]int32, 0, len(categoryIDs))
+			for _, id := range categoryIDs {
+				categoryID, err := xmlrpcID(id)
+				if err != nil {
+					return nil, err
+				}
+				postParam.CategoryIDs = append(postParam.CategoryIDs, categoryID)
+			}
+		}
+	}
+	if termsNames, ok := post["terms_names"].(map[string]any); ok {
+		if names, ok := termsNames["category"].([]any); ok {
+			postParam.Categories = xmlrpcStrings(names)
+		}
+		if names, ok := termsNames["post_tag"].([]any); ok {
+			postParam.Tags = xmlrpcStrings(names)
+		}
+	}
+	return postParam, nil
+}
+
+func wpPostStatus(post *dto.MetaWeblogPost) string {
+	if post.Published {
+		return "publish"
+	}
+	return "draft"
+}
+
+func convertPost(post *dto.MetaWeblogPost) map[string]any {
+	allowComments := 1
+	if post.DisallowComment {
+		allowComments = 0
+	}
+	return map[string]any{
+		"postid":            strconv.Itoa(int(post.PostID)),
+		"title":             post.Title,
+		"description":       post.Content,
+		"mt_excerpt":        post.Excerpt,
+		"wp_slug":           post.Slug,
+		"link":              post.Link,
+		"permaLink":         post.Link,
+		"categories":        post.Categories,
+		"mt_keywords":       strings.Join(post.Tags, ","),
+		"dateCreated":       post.CreateTime,
+		"date_created_gmt":  post.CreateTime.UTC(),
+		"post_status":       wpPostStatus(post),
+		"mt_allow_comments": allowComments,
+		"mt_convert_breaks": post.TextFilter,
+	}
+}
+
+func xmlrpcStrings(values []any) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// xmlrpcID parses the id sent as an int or a string
+func xmlrpcID(value any) (int32, error) {
+	switch v := value.(type) {
+	case int:
+		return int32(v), nil
+	case string:
+		id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 32)
+		if err == nil {
+			return int32(id), nil
+		}
+	}
+	return 0, xerr.BadParam.New("invalid id %v", value).WithStatus(xerr.StatusBadRequest).WithMsg("invalid id")
+}
+
+// faultByErr responds the error with its http status as the fault code, as WordPress does
+func (x *XMLRPCHandler) faultByErr(ctx *gin.Context, err error) {
+	status := xerr.GetHTTPStatus(err)
+	if status == http.StatusInternalServerError {
+		log.CtxErrorf(ctx, "xmlrpc call err=%+v", err)
+	}
+	x.fault(ctx, status, xerr.GetMessage(err))
+}
+
+func (x *XMLRPCHandler) fault(ctx *gin.Context, code int, message string) {
+	// the faults are responded with 200 as the XML-RPC spec requires
+	ctx.Status(http.StatusOK)
+	if err := xmlrpc.EncodeFault(ctx.Writer, &xmlrpc.Fault{Code: code, String: message}); err != nil {
+		log.CtxErrorf(ctx, "encode xmlrpc fault err=%+v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+)
+
+func (s *Server) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, s.HealthService.Liveness(ctx))
+}
+
+func (s *Server) readyz(ctx *gin.Context) {
+	health := s.HealthService.Readiness(ctx)
+	status := http.StatusOK
+	if health.Status != consts.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, health)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+)
+
+// liveReload streams a reload event to the page whenever the files of the theme changed, it is only registered in development mode
+func (s *Server) liveReload(ctx *gin.Context) {
+	reload, unsubscribe := s.Template.SubscribeLiveReload()
+	defer unsubscribe()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("connected", "")
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(consts.LiveReloadHeartbeat)
+	defer heartbeat.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case _, ok := <-reload:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("reload", "")
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", "")
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/service"
+)
+
+type AuditMiddleware struct {
+	logger          *zap.Logger
+	auditLogService service.AuditLogService
+}
+
+func NewAuditMiddleware(logger *zap.Logger, auditLogService service.AuditLogService) *AuditMiddleware {
+	return &AuditMiddleware{
+		logger:          logger,
+		auditLogService: auditLogService,
+	}
+}
+
+// Audit records every mutating admin request together with the rows it changed.
+// It must be used after the auth middleware so that the current user is known.
+func (a *AuditMiddleware) Audit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+		recorder := dal.NewAuditRecorder()
+		ctx.Set(consts.AuditRecorder, recorder)
+
+		ctx.Next()
+
+		// stop recording, the audit log itself and later writes are not part of the request
+		ctx.Set(consts.AuditRecorder, nil)
+
+		auditLog := &entity.AuditLog{
+			IPAddress: ctx.ClientIP(),
+			Method:    ctx.Request.Method,
+			Path:      ctx.Request.URL.Path,
+			Status:    int32(ctx.Writer.Status()),
+		}
+		if user, ok := ctx.Get(consts.AuthorizedUser); ok {
+			if user, ok := user.(*entity.User); ok && user != nil {
+				auditLog.UserID = user.ID
+				auditLog.Username = user.Username
+			}
+		}
+		auditLog.EntityType, auditLog.EntityID, auditLog.BeforeSummary, auditLog.AfterSummary = recorder.Summary()
+		if err := a.auditLogService.Create(ctx, auditLog); err != nil {
+			a.logger.Error("create audit log err", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/service"
+)
+
+type languageKey struct{}
+
+type LanguageMiddleware struct {
+	optionService service.OptionService
+}
+
+func NewLanguageMiddleware(optionService service.OptionService) *LanguageMiddleware {
+	return &LanguageMiddleware{
+		optionService: optionService,
+	}
+}
+
+// StripPrefix serves the content in a language other than the default one under the prefix of the language such as /en/archives,
+// the prefix is taken off the path before routing and the language is kept in the context of the request
+func (l *LanguageMiddleware) StripPrefix(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		language, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if language == "" || language == "api" || strings.HasPrefix(rest, "api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		languages, err := l.optionService.GetBlogLanguages(r.Context())
+		if err != nil || !slices.Contains(languages[1:], language) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		u := *r.URL
+		u.Path = "/" + rest
+		u.RawPath = ""
+		r = r.WithContext(context.WithValue(r.Context(), languageKey{}, language))
+		r.URL = &u
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Language tells the language of the content the request asks for, which is the default language without a prefix,
+// nothing is set when the blog publishes in a single language
+func (l *LanguageMiddleware) Language() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if language, ok := ctx.Request.Context().Value(languageKey{}).(string); ok {
+			ctx.Set(consts.RequestLanguage, language)
+			ctx.Set(consts.RequestPathPrefix, "/"+language)
+			return
+		}
+		languages, err := l.optionService.GetBlogLanguages(ctx)
+		if err != nil || len(languages) <= 1 {
+			return
+		}
+		ctx.Set(consts.RequestLanguage, languages[0])
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/log"
 )
 
 type GinLoggerMiddleware struct {
@@ -62,13 +64,15 @@ func (g *GinLoggerMiddleware) LoggerWithConfig(conf GinLoggerConfig) gin.Handler
 			clientIP := strings.ReplaceAll(ctx.ClientIP(), "\n", "")
 			clientIP = strings.ReplaceAll(clientIP, "\r", "")
 
-			logger.Info("[GIN]",
+			fields := []zap.Field{
 				zap.Time("beginTime", start),
 				zap.Int("status", ctx.Writer.Status()),
 				zap.Duration("latency", time.Since(start)),
 				zap.String("clientIP", clientIP),
 				zap.String("method", ctx.Request.Method),
-				zap.String("path", path))
+				zap.String("path", path),
+			}
+			logger.Info("[GIN]", append(fields, log.TraceFields(ctx.Request.Context())...)...)
 		}
 	}
 }
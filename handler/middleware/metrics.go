@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/metrics"
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+type MetricsMiddleware struct {
+	logger     *zap.Logger
+	token      string
+	allowedIPs []*net.IPNet
+}
+
+func NewMetricsMiddleware(conf *config.Config, logger *zap.Logger) *MetricsMiddleware {
+	m := &MetricsMiddleware{
+		logger: logger,
+		token:  conf.Metrics.Token,
+	}
+	for _, allowedIP := range conf.Metrics.AllowedIPs {
+		if !strings.Contains(allowedIP, "/") {
+			if strings.Contains(allowedIP, ":") {
+				allowedIP += "/128"
+			} else {
+				allowedIP += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(allowedIP)
+		if err != nil {
+			logger.Error("invalid metrics allowed ip", zap.String("ip", allowedIP), zap.Error(err))
+			continue
+		}
+		m.allowedIPs = append(m.allowedIPs, ipNet)
+	}
+	return m
+}
+
+// Collect records the count and latency of the requests handled by the route group
+func (m *MetricsMiddleware) Collect(group string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		metrics.ObserveHTTPRequest(group, ctx.Request.Method, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// Authorize protects the metrics endpoint with the token and ip allowlist in the config
+func (m *MetricsMiddleware) Authorize() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if m.token != "" {
+			token, hasScheme := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+			if !hasScheme || subtle.ConstantTimeCompare([]byte(token), []byte(m.token)) != 1 {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, &dto.BaseDTO{
+					Status:  http.StatusUnauthorized,
+					Message: "Unauthorized",
+				})
+				return
+			}
+		}
+		if !m.ipAllowed(net.ParseIP(ctx.ClientIP())) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, &dto.BaseDTO{
+				Status:  http.StatusForbidden,
+				Message: "Forbidden",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func (m *MetricsMiddleware) ipAllowed(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if len(m.allowedIPs) == 0 {
+		// a token alone is enough, otherwise only local scrapers are trusted
+		return m.token != "" || ip.IsLoopback()
+	}
+	for _, ipNet := range m.allowedIPs {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+)
+
+type ThemePreviewMiddleware struct {
+	themePreviewService service.ThemePreviewService
+	cache               cache.Cache
+}
+
+func NewThemePreviewMiddleware(themePreviewService service.ThemePreviewService, cache cache.Cache) *ThemePreviewMiddleware {
+	return &ThemePreviewMiddleware{
+		themePreviewService: themePreviewService,
+		cache:               cache,
+	}
+}
+
+// Preview renders the site with the theme of the preview token given in the query or kept in the cookie,
+// only for the admin who created the preview
+func (t *ThemePreviewMiddleware) Preview() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.Query(consts.ThemePreviewQueryName)
+		fromQuery := token != ""
+		if !fromQuery {
+			token, _ = ctx.Cookie(consts.ThemePreviewCookieName)
+		}
+		if token == "" {
+			return
+		}
+		preview, previewTemplate, ok := t.themePreviewService.GetPreview(ctx, token)
+		if ok {
+			ok = t.isPreviewAdmin(ctx, preview)
+		}
+		if !ok {
+			if !fromQuery {
+				ctx.SetCookie(consts.ThemePreviewCookieName, "", -1, "/", "", false, true)
+			}
+			return
+		}
+		if fromQuery {
+			maxAge := int(time.Until(time.UnixMilli(preview.ExpireTime)).Seconds())
+			ctx.SetCookie(consts.ThemePreviewCookieName, token, maxAge, "/", "", util.IsHTTPS(ctx), true)
+		}
+		ctx.Set(consts.ThemePreview, preview)
+		ctx.Set(consts.ThemePreviewTemplate, previewTemplate)
+		ctx.Header("Cache-Control", "no-store")
+	}
+}
+
+// isPreviewAdmin tells whether the request is made by the admin who created the preview
+func (t *ThemePreviewMiddleware) isPreviewAdmin(ctx *gin.Context, preview *dto.ThemePreview) bool {
+	secret, _ := ctx.Cookie(consts.ThemePreviewAdminCookieName)
+	if secret == "" {
+		return false
+	}
+	userID, ok := t.cache.Get(cache.BuildThemePreviewAdminKey(secret))
+	if !ok {
+		return false
+	}
+	id, _ := userID.(int32)
+	return id == preview.UserID
+}
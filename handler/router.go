@@ -19,6 +19,9 @@ import (
 
 func (s *Server) RegisterRouters() {
 	router := s.Router
+	if s.Config.Tracing.Enabled {
+		router.Use(s.TracingMiddleware.Trace())
+	}
 	if config.IsDev() {
 		router.Use(cors.New(cors.Config{
 			AllowAllOrigins:  true,
@@ -34,6 +37,15 @@ func (s *Server) RegisterRouters() {
 		router.GET("/ping", func(ctx *gin.Context) {
 			_, _ = ctx.Writer.Write([]byte("pong"))
 		})
+		router.GET("/healthz", s.healthz)
+		router.GET("/readyz", s.readyz)
+		router.NoRoute(s.noRoute)
+		if config.IsDev() {
+			router.GET(consts.LiveReloadPath, s.liveReload)
+		}
+		if s.Config.Metrics.Enabled {
+			router.GET("/metrics", s.MetricsMiddleware.Authorize(), gin.WrapH(s.MetricsService.Handler()))
+		}
 		{
 			staticRouter := router.Group("/")
 			staticRouter.StaticFS(s.Config.Sonic.AdminURLPath, gin.Dir(s.Config.Sonic.AdminResourcesDir, false))
@@ -46,15 +58,19 @@ func (s *Server) RegisterRouters() {
 		}
 		{
 			adminAPIRouter := router.Group("/api/admin")
-			adminAPIRouter.Use(s.LogMiddleware.LoggerWithConfig(middleware.GinLoggerConfig{}), s.RecoveryMiddleware.RecoveryWithLogger(), s.InstallRedirectMiddleware.InstallRedirect())
+			adminAPIRouter.Use(s.MetricsMiddleware.Collect("admin_api"), s.LogMiddleware.LoggerWithConfig(middleware.GinLoggerConfig{}), s.RecoveryMiddleware.RecoveryWithLogger(), s.InstallRedirectMiddleware.InstallRedirect())
 			adminAPIRouter.GET("/is_installed", s.wrapHandler(s.AdminHandler.IsInstalled))
 			adminAPIRouter.POST("/login/precheck", s.wrapHandler(s.AdminHandler.AuthPreCheck))
 			adminAPIRouter.POST("/login", s.wrapHandler(s.AdminHandler.Auth))
+			adminAPIRouter.GET("/login/captcha", s.wrapHandler(s.AdminHandler.GetLoginCaptcha))
 			adminAPIRouter.POST("/refresh/:refreshToken", s.wrapHandler(s.AdminHandler.RefreshToken))
+			adminAPIRouter.GET("/oidc/providers", s.wrapHandler(s.AdminHandler.ListOIDCProviders))
+			adminAPIRouter.GET("/oidc/:provider/authorization", s.wrapHandler(s.AdminHandler.OIDCAuthorize))
+			adminAPIRouter.GET("/oidc/:provider/callback", s.wrapHandler(s.AdminHandler.OIDCCallback))
 			adminAPIRouter.POST("/installations", s.wrapHandler(s.InstallHandler.InstallBlog))
 			{
 				authRouter := adminAPIRouter.Group("")
-				authRouter.Use(s.AuthMiddleware.GetWrapHandler())
+				authRouter.Use(s.AuthMiddleware.GetWrapHandler(), s.AuditMiddleware.Audit())
 				authRouter.POST("/logout", s.wrapHandler(s.AdminHandler.LogOut))
 				authRouter.POST("/password/code", s.wrapHandler(s.AdminHandler.SendResetCode))
 				authRouter.GET("/environments", s.wrapHandler(s.AdminHandler.GetEnvironments))
@@ -82,6 +98,10 @@ func (s *Server) RegisterRouters() {
 					backupRouter.GET("/data/*path", s.BackupHandler.HandleData)
 					backupRouter.POST("/markdown/export", s.wrapHandler(s.BackupHandler.ExportMarkdown))
 					backupRouter.POST("/markdown/import", s.wrapHandler(s.BackupHandler.ImportMarkdown))
+					backupRouter.POST("/wordpress/import", s.wrapHandler(s.BackupHandler.ImportWordPress))
+					backupRouter.POST("/hugo/import", s.wrapHandler(s.BackupHandler.ImportHugo))
+					backupRouter.POST("/hexo/import", s.wrapHandler(s.BackupHandler.ImportHexo))
+					backupRouter.POST("/halo/import", s.wrapHandler(s.BackupHandler.ImportHalo))
 					backupRouter.GET("/markdown/fetch", s.wrapHandler(s.BackupHandler.GetMarkDownBackup))
 					backupRouter.GET("/markdown/export", s.wrapHandler(s.BackupHandler.ListMarkdowns))
 					backupRouter.DELETE("/markdown/export", s.wrapHandler(s.BackupHandler.DeleteMarkdowns))
@@ -125,6 +145,31 @@ func (s *Server) RegisterRouters() {
 						postCommentRouter.DELETE("", s.wrapHandler(s.PostCommentHandler.DeletePostCommentBatch))
 					}
 				}
+				{
+					mentionRouter := authRouter.Group("/mentions")
+					mentionRouter.GET("", s.wrapHandler(s.MentionHandler.ListMention))
+					mentionRouter.PUT("/:mentionID/status/:status", s.wrapHandler(s.MentionHandler.UpdateMentionStatus))
+					mentionRouter.PUT("/status/:status", s.wrapHandler(s.MentionHandler.UpdateMentionStatusBatch))
+					mentionRouter.DELETE("/:mentionID", s.wrapHandler(s.MentionHandler.DeleteMention))
+					mentionRouter.DELETE("", s.wrapHandler(s.MentionHandler.DeleteMentionBatch))
+				}
+				{
+					activityPubRouter := authRouter.Group("/activitypub")
+					activityPubRouter.GET("/followers", s.wrapHandler(s.ActivityPubHandler.ListFollower))
+					activityPubRouter.DELETE("/followers/:followerID", s.wrapHandler(s.ActivityPubHandler.DeleteFollower))
+				}
+				{
+					gitSyncRouter := authRouter.Group("/git-sync")
+					gitSyncRouter.GET("", s.wrapHandler(s.GitSyncHandler.GetStatus))
+					gitSyncRouter.POST("/pull", s.wrapHandler(s.GitSyncHandler.Pull))
+					gitSyncRouter.POST("/push", s.wrapHandler(s.GitSyncHandler.Push))
+					gitSyncRouter.POST("/conflicts/:postID/resolve", s.wrapHandler(s.GitSyncHandler.ResolveConflict))
+				}
+				{
+					metaWeblogRouter := authRouter.Group("/metaweblog")
+					metaWeblogRouter.POST("/app-password", s.wrapHandler(s.MetaWeblogHandler.GenerateAppPassword))
+					metaWeblogRouter.DELETE("/app-password", s.wrapHandler(s.MetaWeblogHandler.RevokeAppPassword))
+				}
 				{
 					optionRouter := authRouter.Group("/options")
 					optionRouter.GET("", s.wrapHandler(s.OptionHandler.ListAllOptions))
@@ -139,6 +184,12 @@ func (s *Server) RegisterRouters() {
 					logRouter.GET("", s.wrapHandler(s.LogHandler.PageLog))
 					logRouter.GET("/clear", s.wrapHandler(s.LogHandler.ClearLog))
 				}
+				{
+					auditLogRouter := authRouter.Group("/audit_logs")
+					auditLogRouter.GET("", s.wrapHandler(s.AuditLogHandler.PageAuditLog))
+					auditLogRouter.GET("/export", s.AuditLogHandler.ExportAuditLog)
+					auditLogRouter.DELETE("", s.wrapHandler(s.AuditLogHandler.ClearAuditLog))
+				}
 				{
 					statisticRouter := authRouter.Group("/statistics")
 					statisticRouter.GET("", s.wrapHandler(s.StatisticHandler.Statistics))
@@ -199,6 +250,13 @@ func (s *Server) RegisterRouters() {
 					linkRouter.DELETE("/:id", s.wrapHandler(s.LinkHandler.DeleteLink))
 					linkRouter.GET("/teams", s.wrapHandler(s.LinkHandler.ListLinkTeams))
 				}
+				{
+					redirectRouter := authRouter.Group("/redirects")
+					redirectRouter.GET("", s.wrapHandler(s.RedirectHandler.ListRedirects))
+					redirectRouter.POST("", s.wrapHandler(s.RedirectHandler.CreateRedirect))
+					redirectRouter.PUT("/:id", s.wrapHandler(s.RedirectHandler.UpdateRedirect))
+					redirectRouter.DELETE("/:id", s.wrapHandler(s.RedirectHandler.DeleteRedirect))
+				}
 				{
 					menuRouter := authRouter.Group("/menus")
 					menuRouter.GET("", s.wrapHandler(s.MenuHandler.ListMenus))
@@ -221,6 +279,30 @@ func (s *Server) RegisterRouters() {
 					tagRouter.PUT("/:id", s.wrapHandler(s.TagHandler.UpdateTag))
 					tagRouter.DELETE("/:id", s.wrapHandler(s.TagHandler.DeleteTag))
 				}
+				{
+					seriesRouter := authRouter.Group("/series")
+					seriesRouter.GET("", s.wrapHandler(s.SeriesHandler.ListSeries))
+					seriesRouter.GET("/:seriesID", s.wrapHandler(s.SeriesHandler.GetSeriesByID))
+					seriesRouter.POST("", s.wrapHandler(s.SeriesHandler.CreateSeries))
+					seriesRouter.PUT("/:seriesID", s.wrapHandler(s.SeriesHandler.UpdateSeries))
+					seriesRouter.DELETE("/:seriesID", s.wrapHandler(s.SeriesHandler.DeleteSeries))
+					seriesRouter.PUT("/:seriesID/posts", s.wrapHandler(s.SeriesHandler.UpdateSeriesPosts))
+				}
+				{
+					customFieldRouter := authRouter.Group("/custom-fields")
+					customFieldRouter.GET("", s.wrapHandler(s.CustomFieldHandler.ListCustomFields))
+					customFieldRouter.GET("/schemas", s.wrapHandler(s.CustomFieldHandler.ListSchemas))
+					customFieldRouter.GET("/:fieldID", s.wrapHandler(s.CustomFieldHandler.GetCustomFieldByID))
+					customFieldRouter.POST("", s.wrapHandler(s.CustomFieldHandler.CreateCustomField))
+					customFieldRouter.PUT("/:fieldID", s.wrapHandler(s.CustomFieldHandler.UpdateCustomField))
+					customFieldRouter.DELETE("/:fieldID", s.wrapHandler(s.CustomFieldHandler.DeleteCustomField))
+				}
+				{
+					translationRouter := authRouter.Group("/translations")
+					translationRouter.GET("/languages", s.wrapHandler(s.TranslationHandler.ListLanguages))
+					translationRouter.GET("/:type/:contentID", s.wrapHandler(s.TranslationHandler.ListTranslations))
+					translationRouter.PUT("/:type/:contentID", s.wrapHandler(s.TranslationHandler.LinkTranslation))
+				}
 				{
 					photoRouter := authRouter.Group("/photos")
 					photoRouter.GET("/latest", s.wrapHandler(s.PhotoHandler.ListPhoto))
@@ -268,6 +350,17 @@ func (s *Server) RegisterRouters() {
 					themeRouter.PUT("upload/:themeID", s.wrapHandler(s.ThemeHandler.UpdateThemeByUpload))
 					themeRouter.POST("fetching", s.wrapHandler(s.ThemeHandler.FetchTheme))
 					themeRouter.PUT("fetching/:themeID", s.wrapHandler(s.ThemeHandler.UpdateThemeByFetching))
+					themeRouter.GET("fetching/:themeID", s.wrapHandler(s.ThemeHandler.CheckThemeUpdate))
+					themeRouter.GET("/:themeID/settings/export", s.ThemeHandler.ExportThemeSettings)
+					themeRouter.POST("/:themeID/settings/import", s.wrapHandler(s.ThemeHandler.ImportThemeSettings))
+					themeRouter.GET("/:themeID/presets", s.wrapHandler(s.ThemeSettingPresetHandler.ListPresets))
+					themeRouter.POST("/:themeID/presets", s.wrapHandler(s.ThemeSettingPresetHandler.SavePreset))
+					themeRouter.POST("/:themeID/presets/:presetID/activation", s.wrapHandler(s.ThemeSettingPresetHandler.ApplyPreset))
+					themeRouter.GET("/:themeID/presets/:presetID/diff", s.wrapHandler(s.ThemeSettingPresetHandler.DiffPreset))
+					themeRouter.DELETE("/:themeID/presets/:presetID", s.wrapHandler(s.ThemeSettingPresetHandler.DeletePreset))
+					themeRouter.GET("/:themeID/validation", s.wrapHandler(s.ThemeHandler.ValidateTheme))
+					themeRouter.POST("/:themeID/preview", s.wrapHandler(s.ThemeHandler.CreateThemePreview))
+					themeRouter.DELETE("preview/:token", s.wrapHandler(s.ThemeHandler.DeleteThemePreview))
 					themeRouter.POST("reload", s.wrapHandler(s.ThemeHandler.ReloadTheme))
 					themeRouter.GET("activation/template/exists", s.wrapHandler(s.ThemeHandler.TemplateExist))
 				}
@@ -279,7 +372,7 @@ func (s *Server) RegisterRouters() {
 		}
 		{
 			contentRouter := router.Group("")
-			contentRouter.Use(s.LogMiddleware.LoggerWithConfig(middleware.GinLoggerConfig{}), s.RecoveryMiddleware.RecoveryWithLogger(), s.InstallRedirectMiddleware.InstallRedirect())
+			contentRouter.Use(s.MetricsMiddleware.Collect("content"), s.LogMiddleware.LoggerWithConfig(middleware.GinLoggerConfig{}), s.RecoveryMiddleware.RecoveryWithLogger(), s.InstallRedirectMiddleware.InstallRedirect(), s.ThemePreviewMiddleware.Preview(), s.LanguageMiddleware.Language())
 
 			contentRouter.POST("/content/:type/:slug/authentication", s.wrapHTMLHandler(s.ViewHandler.Authenticate))
 
@@ -292,10 +385,36 @@ func (s *Server) RegisterRouters() {
 			contentRouter.GET("/rss.xml", s.wrapTextHandler(s.FeedHandler.Feed))
 			contentRouter.GET("/feed", s.wrapTextHandler(s.FeedHandler.Feed))
 			contentRouter.GET("/feed.xml", s.wrapTextHandler(s.FeedHandler.Feed))
+			contentRouter.GET("/feed.json", s.wrapTextHandler(s.FeedHandler.Feed))
 			contentRouter.GET("/feed/categories/:slug", s.wrapTextHandler(s.FeedHandler.CategoryFeed))
 			contentRouter.GET("/atom/categories/:slug", s.wrapTextHandler(s.FeedHandler.CategoryAtom))
+			contentRouter.GET("/feed/tags/:slug", s.wrapTextHandler(s.FeedHandler.TagFeed))
+			contentRouter.GET("/atom/tags/:slug", s.wrapTextHandler(s.FeedHandler.TagAtom))
+			contentRouter.GET("/feed/journals", s.wrapTextHandler(s.FeedHandler.JournalFeed))
+			contentRouter.GET("/feed/journals.json", s.wrapTextHandler(s.FeedHandler.JournalFeed))
+			contentRouter.GET("/atom/journals", s.wrapTextHandler(s.FeedHandler.JournalAtom))
+			contentRouter.GET("/feed/comments", s.wrapTextHandler(s.FeedHandler.CommentFeed))
+			contentRouter.GET("/feed/comments.json", s.wrapTextHandler(s.FeedHandler.CommentFeed))
+			contentRouter.GET("/atom/comments", s.wrapTextHandler(s.FeedHandler.CommentAtom))
 			contentRouter.GET("/sitemap.xml", s.wrapTextHandler(s.FeedHandler.SitemapXML))
+			contentRouter.GET("/sitemap/:name", s.wrapTextHandler(s.FeedHandler.Sitemap))
+			contentRouter.GET(consts.IndexNowKeyPath, s.FeedHandler.IndexNowKey)
 			contentRouter.GET("/sitemap.html", s.wrapHTMLHandler(s.FeedHandler.SitemapHTML))
+			contentRouter.POST(consts.WebmentionPath, s.ContentMentionHandler.Webmention)
+			contentRouter.POST(consts.XMLRPCPath, s.ContentXMLRPCHandler.XMLRPC)
+			contentRouter.GET(consts.MicropubPath, s.ContentMicropubHandler.Query)
+			contentRouter.POST(consts.MicropubPath, s.ContentMicropubHandler.Create)
+			contentRouter.POST(consts.MicropubMediaPath, s.ContentMicropubHandler.Media)
+			contentRouter.GET(consts.WebFingerPath, s.ContentActivityPubHandler.WebFinger)
+			{
+				activityPubRouter := contentRouter.Group(consts.ActivityPubPath)
+				activityPubRouter.GET("/actor", s.ContentActivityPubHandler.Actor)
+				activityPubRouter.GET("/outbox", s.ContentActivityPubHandler.Outbox)
+				activityPubRouter.GET("/followers", s.ContentActivityPubHandler.Followers)
+				activityPubRouter.GET("/posts/:postID", s.ContentActivityPubHandler.Post)
+				activityPubRouter.GET("/journals/:journalID", s.ContentActivityPubHandler.Journal)
+				activityPubRouter.POST("/inbox", s.ContentActivityPubHandler.Inbox)
+			}
 
 			contentRouter.GET("/version", s.wrapHandler(s.ViewHandler.Version))
 			contentRouter.GET("/install", s.ViewHandler.Install)
@@ -310,7 +429,7 @@ func (s *Server) RegisterRouters() {
 		}
 		{
 			contentAPIRouter := router.Group("/api/content")
-			contentAPIRouter.Use(s.LogMiddleware.LoggerWithConfig(middleware.GinLoggerConfig{}), s.RecoveryMiddleware.RecoveryWithLogger())
+			contentAPIRouter.Use(s.MetricsMiddleware.Collect("content_api"), s.LogMiddleware.LoggerWithConfig(middleware.GinLoggerConfig{}), s.RecoveryMiddleware.RecoveryWithLogger())
 
 			contentAPIRouter.GET("/archives/years", s.wrapHandler(s.ContentAPIArchiveHandler.ListYearArchives))
 			contentAPIRouter.GET("/archives/months", s.wrapHandler(s.ContentAPIArchiveHandler.ListMonthArchives))
@@ -335,6 +454,7 @@ func (s *Server) RegisterRouters() {
 			contentAPIRouter.GET("/posts/:postID/comments/list_view", s.wrapHandler(s.ContentAPIPostHandler.ListComment))
 			contentAPIRouter.POST("/posts/comments", s.wrapHandler(s.ContentAPIPostHandler.CreateComment))
 			contentAPIRouter.POST("/posts/:postID/likes", s.wrapHandler(s.ContentAPIPostHandler.Like))
+			contentAPIRouter.GET("/posts/:postID/related", s.wrapHandler(s.ContentAPIPostHandler.ListRelatedPosts))
 
 			contentAPIRouter.GET("/sheets/:sheetID/comments/top_view", s.wrapHandler(s.ContentAPISheetHandler.ListTopComment))
 			contentAPIRouter.GET("/sheets/:sheetID/comments/:parentID/children", s.wrapHandler(s.ContentAPISheetHandler.ListChildren))
@@ -348,6 +468,8 @@ func (s *Server) RegisterRouters() {
 			contentAPIRouter.GET("/options/comment", s.wrapHandler(s.ContentAPIOptionHandler.Comment))
 
 			contentAPIRouter.POST("/comments/:commentID/likes", s.wrapHandler(s.ContentAPICommentHandler.Like))
+
+			contentAPIRouter.POST("/git-sync/webhook", s.wrapHandler(s.ContentAPIGitSyncHandler.Webhook))
 		}
 	}
 }
@@ -391,6 +513,10 @@ func (s *Server) registerDynamicRouters(contentRouter *gin.RouterGroup) error {
 	if err != nil {
 		return err
 	}
+	seriesPath, err := s.OptionService.GetSeriesPrefix(ctx)
+	if err != nil {
+		return err
+	}
 	contentRouter.GET(archivePath, s.wrapHTMLHandler(s.ArchiveHandler.Archives))
 	contentRouter.GET(archivePath+"/page/:page", s.wrapHTMLHandler(s.ArchiveHandler.ArchivesPage))
 	contentRouter.GET(archivePath+"/:slug", s.wrapHTMLHandler(s.ArchiveHandler.ArchivesBySlug))
@@ -399,6 +525,9 @@ func (s *Server) registerDynamicRouters(contentRouter *gin.RouterGroup) error {
 	contentRouter.GET(tagPath+"/:slug/page/:page", s.wrapHTMLHandler(s.ContentTagHandler.TagPostPage))
 	contentRouter.GET(tagPath+"/:slug", s.wrapHTMLHandler(s.ContentTagHandler.TagPost))
 
+	contentRouter.GET(seriesPath, s.wrapHTMLHandler(s.ContentSeriesHandler.Series))
+	contentRouter.GET(seriesPath+"/:slug", s.wrapHTMLHandler(s.ContentSeriesHandler.SeriesDetail))
+
 	contentRouter.GET(categoryPath, s.wrapHTMLHandler(s.ContentCategoryHandler.Categories))
 	contentRouter.GET(categoryPath+"/:slug", s.wrapHTMLHandler(s.ContentCategoryHandler.CategoryDetail))
 	contentRouter.GET(categoryPath+"/:slug/page/:page", s.wrapHTMLHandler(s.ContentCategoryHandler.CategoryDetailPage))
@@ -3,9 +3,10 @@ package handler
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/dig"
@@ -13,32 +14,46 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/event"
 	"github.com/go-sonic/sonic/handler/admin"
 	"github.com/go-sonic/sonic/handler/content"
 	"github.com/go-sonic/sonic/handler/content/api"
 	"github.com/go-sonic/sonic/handler/middleware"
 	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/property"
 	"github.com/go-sonic/sonic/service"
 	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util"
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
 type Server struct {
 	logger                    *zap.Logger
+	shutdowner                fx.Shutdowner
 	Config                    *config.Config
 	HTTPServer                *http.Server
 	Router                    *gin.Engine
 	Template                  *template.Template
 	AuthMiddleware            *middleware.AuthMiddleware
+	AuditMiddleware           *middleware.AuditMiddleware
 	LogMiddleware             *middleware.GinLoggerMiddleware
 	RecoveryMiddleware        *middleware.RecoveryMiddleware
 	InstallRedirectMiddleware *middleware.InstallRedirectMiddleware
+	MetricsMiddleware         *middleware.MetricsMiddleware
+	TracingMiddleware         *middleware.TracingMiddleware
+	ThemePreviewMiddleware    *middleware.ThemePreviewMiddleware
+	LanguageMiddleware        *middleware.LanguageMiddleware
 	OptionService             service.OptionService
 	ThemeService              service.ThemeService
+	RedirectService           service.RedirectService
 	SheetService              service.SheetService
+	MetricsService            service.MetricsService
+	HealthService             service.HealthService
 	AdminHandler              *admin.AdminHandler
 	AttachmentHandler         *admin.AttachmentHandler
+	AuditLogHandler           *admin.AuditLogHandler
+	ThemeSettingPresetHandler *admin.ThemeSettingPresetHandler
 	BackupHandler             *admin.BackupHandler
 	CategoryHandler           *admin.CategoryHandler
 	InstallHandler            *admin.InstallHandler
@@ -51,13 +66,21 @@ type Server struct {
 	PhotoHandler              *admin.PhotoHandler
 	PostHandler               *admin.PostHandler
 	PostCommentHandler        *admin.PostCommentHandler
+	RedirectHandler           *admin.RedirectHandler
+	SeriesHandler             *admin.SeriesHandler
+	CustomFieldHandler        *admin.CustomFieldHandler
 	SheetHandler              *admin.SheetHandler
 	SheetCommentHandler       *admin.SheetCommentHandler
 	StatisticHandler          *admin.StatisticHandler
 	TagHandler                *admin.TagHandler
+	TranslationHandler        *admin.TranslationHandler
 	ThemeHandler              *admin.ThemeHandler
 	UserHandler               *admin.UserHandler
 	EmailHandler              *admin.EmailHandler
+	MentionHandler            *admin.MentionHandler
+	ActivityPubHandler        *admin.ActivityPubHandler
+	GitSyncHandler            *admin.GitSyncHandler
+	MetaWeblogHandler         *admin.MetaWeblogHandler
 	IndexHandler              *content.IndexHandler
 	FeedHandler               *content.FeedHandler
 	ArchiveHandler            *content.ArchiveHandler
@@ -65,10 +88,15 @@ type Server struct {
 	ContentCategoryHandler    *content.CategoryHandler
 	ContentSheetHandler       *content.SheetHandler
 	ContentTagHandler         *content.TagHandler
+	ContentSeriesHandler      *content.SeriesHandler
 	ContentLinkHandler        *content.LinkHandler
 	ContentPhotoHandler       *content.PhotoHandler
 	ContentJournalHandler     *content.JournalHandler
 	ContentSearchHandler      *content.SearchHandler
+	ContentMentionHandler     *content.MentionHandler
+	ContentXMLRPCHandler      *content.XMLRPCHandler
+	ContentMicropubHandler    *content.MicropubHandler
+	ContentActivityPubHandler *content.ActivityPubHandler
 	ContentAPIArchiveHandler  *api.ArchiveHandler
 	ContentAPICategoryHandler *api.CategoryHandler
 	ContentAPIJournalHandler  *api.JournalHandler
@@ -78,23 +106,35 @@ type Server struct {
 	ContentAPIOptionHandler   *api.OptionHandler
 	ContentAPIPhotoHandler    *api.PhotoHandler
 	ContentAPICommentHandler  *api.CommentHandler
+	ContentAPIGitSyncHandler  *api.GitSyncHandler
 }
 
 type ServerParams struct {
 	dig.In
 	Config                    *config.Config
 	Logger                    *zap.Logger
+	Shutdowner                fx.Shutdowner
 	Event                     event.Bus
 	Template                  *template.Template
 	AuthMiddleware            *middleware.AuthMiddleware
+	AuditMiddleware           *middleware.AuditMiddleware
 	LogMiddleware             *middleware.GinLoggerMiddleware
 	RecoveryMiddleware        *middleware.RecoveryMiddleware
 	InstallRedirectMiddleware *middleware.InstallRedirectMiddleware
+	MetricsMiddleware         *middleware.MetricsMiddleware
+	TracingMiddleware         *middleware.TracingMiddleware
+	ThemePreviewMiddleware    *middleware.ThemePreviewMiddleware
+	LanguageMiddleware        *middleware.LanguageMiddleware
 	OptionService             service.OptionService
 	ThemeService              service.ThemeService
+	RedirectService           service.RedirectService
 	SheetService              service.SheetService
+	MetricsService            service.MetricsService
+	HealthService             service.HealthService
 	AdminHandler              *admin.AdminHandler
 	AttachmentHandler         *admin.AttachmentHandler
+	AuditLogHandler           *admin.AuditLogHandler
+	ThemeSettingPresetHandler *admin.ThemeSettingPresetHandler
 	BackupHandler             *admin.BackupHandler
 	CategoryHandler           *admin.CategoryHandler
 	InstallHandler            *admin.InstallHandler
@@ -107,13 +147,21 @@ type ServerParams struct {
 	PhotoHandler              *admin.PhotoHandler
 	PostHandler               *admin.PostHandler
 	PostCommentHandler        *admin.PostCommentHandler
+	RedirectHandler           *admin.RedirectHandler
+	SeriesHandler             *admin.SeriesHandler
+	CustomFieldHandler        *admin.CustomFieldHandler
 	SheetHandler              *admin.SheetHandler
 	SheetCommentHandler       *admin.SheetCommentHandler
 	StatisticHandler          *admin.StatisticHandler
 	TagHandler                *admin.TagHandler
+	TranslationHandler        *admin.TranslationHandler
 	ThemeHandler              *admin.ThemeHandler
 	UserHandler               *admin.UserHandler
 	EmailHandler              *admin.EmailHandler
+	MentionHandler            *admin.MentionHandler
+	ActivityPubHandler        *admin.ActivityPubHandler
+	GitSyncHandler            *admin.GitSyncHandler
+	MetaWeblogHandler         *admin.MetaWeblogHandler
 	IndexHandler              *content.IndexHandler
 	FeedHandler               *content.FeedHandler
 	ArchiveHandler            *content.ArchiveHandler
@@ -121,10 +169,15 @@ type ServerParams struct {
 	ContentCategoryHandler    *content.CategoryHandler
 	ContentSheetHandler       *content.SheetHandler
 	ContentTagHandler         *content.TagHandler
+	ContentSeriesHandler      *content.SeriesHandler
 	ContentLinkHandler        *content.LinkHandler
 	ContentPhotoHandler       *content.PhotoHandler
 	ContentJournalHandler     *content.JournalHandler
 	ContentSearchHandler      *content.SearchHandler
+	ContentMentionHandler     *content.MentionHandler
+	ContentXMLRPCHandler      *content.XMLRPCHandler
+	ContentMicropubHandler    *content.MicropubHandler
+	ContentActivityPubHandler *content.ActivityPubHandler
 	ContentAPIArchiveHandler  *api.ArchiveHandler
 	ContentAPICategoryHandler *api.CategoryHandler
 	ContentAPIJournalHandler  *api.JournalHandler
@@ -134,30 +187,43 @@ type ServerParams struct {
 	ContentAPIOptionHandler   *api.OptionHandler
 	ContentAPIPhotoHandler    *api.PhotoHandler
 	ContentAPICommentHandler  *api.CommentHandler
+	ContentAPIGitSyncHandler  *api.GitSyncHandler
 }
 
 func NewServer(param ServerParams, lifecycle fx.Lifecycle) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	conf := param.Config
+	// the tracing middleware keeps the span in the request context, services only see it through the fallback
+	router.ContextWithFallback = true
 
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", conf.Server.Host, conf.Server.Port),
-		Handler: router,
+		Handler: param.LanguageMiddleware.StripPrefix(router),
 	}
+	// the live reload streams never become idle, so end them before Shutdown waits for the connections
+	httpServer.RegisterOnShutdown(param.Template.CloseLiveReload)
 
 	s := &Server{
 		logger:                    param.Logger,
+		shutdowner:                param.Shutdowner,
 		Config:                    param.Config,
 		HTTPServer:                httpServer,
 		Router:                    router,
 		Template:                  param.Template,
 		AuthMiddleware:            param.AuthMiddleware,
+		AuditMiddleware:           param.AuditMiddleware,
 		LogMiddleware:             param.LogMiddleware,
 		RecoveryMiddleware:        param.RecoveryMiddleware,
 		InstallRedirectMiddleware: param.InstallRedirectMiddleware,
+		MetricsMiddleware:         param.MetricsMiddleware,
+		TracingMiddleware:         param.TracingMiddleware,
+		ThemePreviewMiddleware:    param.ThemePreviewMiddleware,
+		LanguageMiddleware:        param.LanguageMiddleware,
 		AdminHandler:              param.AdminHandler,
 		AttachmentHandler:         param.AttachmentHandler,
+		AuditLogHandler:           param.AuditLogHandler,
+		ThemeSettingPresetHandler: param.ThemeSettingPresetHandler,
 		BackupHandler:             param.BackupHandler,
 		CategoryHandler:           param.CategoryHandler,
 		InstallHandler:            param.InstallHandler,
@@ -170,16 +236,27 @@ func NewServer(param ServerParams, lifecycle fx.Lifecycle) *Server {
 		PhotoHandler:              param.PhotoHandler,
 		PostHandler:               param.PostHandler,
 		PostCommentHandler:        param.PostCommentHandler,
+		RedirectHandler:           param.RedirectHandler,
+		SeriesHandler:             param.SeriesHandler,
+		CustomFieldHandler:        param.CustomFieldHandler,
 		SheetHandler:              param.SheetHandler,
 		SheetCommentHandler:       param.SheetCommentHandler,
 		StatisticHandler:          param.StatisticHandler,
 		TagHandler:                param.TagHandler,
+		TranslationHandler:        param.TranslationHandler,
 		ThemeHandler:              param.ThemeHandler,
 		UserHandler:               param.UserHandler,
 		EmailHandler:              param.EmailHandler,
+		MentionHandler:            param.MentionHandler,
+		ActivityPubHandler:        param.ActivityPubHandler,
+		GitSyncHandler:            param.GitSyncHandler,
+		MetaWeblogHandler:         param.MetaWeblogHandler,
 		OptionService:             param.OptionService,
 		ThemeService:              param.ThemeService,
+		RedirectService:           param.RedirectService,
 		SheetService:              param.SheetService,
+		MetricsService:            param.MetricsService,
+		HealthService:             param.HealthService,
 		IndexHandler:              param.IndexHandler,
 		FeedHandler:               param.FeedHandler,
 		ArchiveHandler:            param.ArchiveHandler,
@@ -187,6 +264,7 @@ func NewServer(param ServerParams, lifecycle fx.Lifecycle) *Server {
 		ContentCategoryHandler:    param.ContentCategoryHandler,
 		ContentSheetHandler:       param.ContentSheetHandler,
 		ContentTagHandler:         param.ContentTagHandler,
+		ContentSeriesHandler:      param.ContentSeriesHandler,
 		ContentLinkHandler:        param.ContentLinkHandler,
 		ContentPhotoHandler:       param.ContentPhotoHandler,
 		ContentJournalHandler:     param.ContentJournalHandler,
@@ -198,8 +276,13 @@ func NewServer(param ServerParams, lifecycle fx.Lifecycle) *Server {
 		ContentAPISheetHandler:    param.ContentAPISheetHandler,
 		ContentAPIOptionHandler:   param.ContentAPIOptionHandler,
 		ContentSearchHandler:      param.ContentSearchHandler,
+		ContentMentionHandler:     param.ContentMentionHandler,
+		ContentXMLRPCHandler:      param.ContentXMLRPCHandler,
+		ContentMicropubHandler:    param.ContentMicropubHandler,
+		ContentActivityPubHandler: param.ContentActivityPubHandler,
 		ContentAPIPhotoHandler:    param.ContentAPIPhotoHandler,
 		ContentAPICommentHandler:  param.ContentAPICommentHandler,
+		ContentAPIGitSyncHandler:  param.ContentAPIGitSyncHandler,
 	}
 	lifecycle.Append(fx.Hook{
 		OnStop:  httpServer.Shutdown,
@@ -212,12 +295,17 @@ func (s *Server) Run(ctx context.Context) error {
 	if config.IsDev() {
 		gin.SetMode(gin.DebugMode)
 	}
+	// listen synchronously so that a port in use fails the start of the app instead of killing it later
+	listener, err := net.Listen("tcp", s.HTTPServer.Addr)
+	if err != nil {
+		return xerr.WithMsg(err, "http server listen error").WithStatus(xerr.StatusInternalServerError)
+	}
 	go func() {
-		if err := s.HTTPServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// print err info when httpServer start failed
-			s.logger.Error("unexpected error from ListenAndServe", zap.Error(err))
-			fmt.Printf("http server start error:%s\n", err.Error())
-			os.Exit(1)
+		if err := s.HTTPServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("unexpected error from http server", zap.Error(err))
+			if err := s.shutdowner.Shutdown(fx.ExitCode(1)); err != nil {
+				s.logger.Error("shutdown app error", zap.Error(err))
+			}
 		}
 	}()
 	return nil
@@ -253,6 +341,12 @@ var (
 func (s *Server) wrapHTMLHandler(handler wrapperHTMLHandler) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		model := template.Model{}
+		// request_path is used by the seo template functions to build the canonical url
+		model["request_path"] = ctx.GetString(consts.RequestPathPrefix) + ctx.Request.URL.Path
+		model["language"] = ctx.GetString(consts.RequestLanguage)
+		if pathPrefix := ctx.GetString(consts.RequestPathPrefix); pathPrefix != "" {
+			s.setLanguageFeeds(ctx, model, pathPrefix)
+		}
 		templateName, err := handler(ctx, model)
 		if err != nil {
 			s.handleError(ctx, err)
@@ -265,13 +359,23 @@ func (s *Server) wrapHTMLHandler(handler wrapperHTMLHandler) gin.HandlerFunc {
 		if val := header["Content-Type"]; len(val) == 0 {
 			header["Content-Type"] = htmlContentType
 		}
-		err = s.Template.ExecuteTemplate(ctx.Writer, templateName, model)
+		err = s.getTemplate(ctx).ExecuteTemplate(ctx, ctx.Writer, templateName, model)
 		if err != nil {
 			s.logger.Error("render template err", zap.Error(err))
 		}
 	}
 }
 
+// setLanguageFeeds links the feeds of the language instead of the ones of the default language shared by the pages
+func (s *Server) setLanguageFeeds(ctx *gin.Context, model template.Model, pathPrefix string) {
+	blogURL, err := s.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return
+	}
+	blogTitle := s.OptionService.GetOrByDefault(ctx, property.BlogTitle).(string)
+	model["feeds"] = dto.NewFeedLinks(blogTitle, blogURL+pathPrefix+"/rss.xml", blogURL+pathPrefix+"/atom.xml", blogURL+pathPrefix+"/feed.json")
+}
+
 func (s *Server) wrapTextHandler(handler wrapperHTMLHandler) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		model := template.Model{}
@@ -280,11 +384,14 @@ func (s *Server) wrapTextHandler(handler wrapperHTMLHandler) gin.HandlerFunc {
 			s.handleError(ctx, err)
 			return
 		}
+		if templateName == "" {
+			return
+		}
 		header := ctx.Writer.Header()
 		if val := header["Content-Type"]; len(val) == 0 {
 			header["Content-Type"] = xmlContentType
 		}
-		err = s.Template.ExecuteTextTemplate(ctx.Writer, templateName, model)
+		err = s.getTemplate(ctx).ExecuteTextTemplate(ctx, ctx.Writer, templateName, model)
 		if err != nil {
 			s.logger.Error("render template err", zap.Error(err))
 		}
@@ -293,12 +400,15 @@ func (s *Server) wrapTextHandler(handler wrapperHTMLHandler) gin.HandlerFunc {
 
 func (s *Server) handleError(ctx *gin.Context, err error) {
 	status := xerr.GetHTTPStatus(err)
+	if status == http.StatusNotFound && s.redirect(ctx) {
+		return
+	}
 	message := xerr.GetMessage(err)
 	model := template.Model{}
 
+	t := s.getTemplate(ctx)
 	templateName, _ := s.ThemeService.Render(ctx, strconv.Itoa(status))
-	t := s.Template.HTMLTemplate.Lookup(templateName)
-	if t == nil {
+	if !t.Lookup(templateName) {
 		templateName = "common/error/error"
 	}
 
@@ -311,8 +421,40 @@ func (s *Server) handleError(ctx *gin.Context, err error) {
 	model["message"] = message
 	model["err"] = err
 
-	err = s.Template.ExecuteTemplate(ctx.Writer, templateName, model)
+	err = t.ExecuteTemplate(ctx, ctx.Writer, templateName, model)
 	if err != nil {
 		s.logger.Error("render error template err", zap.Error(err))
 	}
 }
+
+func (s *Server) noRoute(ctx *gin.Context) {
+	if s.redirect(ctx) {
+		return
+	}
+	ctx.String(http.StatusNotFound, "404 page not found")
+}
+
+// redirect redirects the request of the missing page if its path has a redirect, e.g. the old url of an imported post
+func (s *Server) redirect(ctx *gin.Context) bool {
+	if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+		return false
+	}
+	redirect, err := s.RedirectService.Match(ctx, ctx.Request.URL.Path)
+	if err != nil {
+		return false
+	}
+	target := redirect.Target
+	if ctx.Request.URL.RawQuery != "" && !strings.Contains(target, "?") {
+		target += "?" + ctx.Request.URL.RawQuery
+	}
+	ctx.Redirect(util.IfElse(redirect.Permanent, http.StatusMovedPermanently, http.StatusFound).(int), target)
+	return true
+}
+
+// getTemplate returns the template of the theme being previewed if any, otherwise the template of the activated theme
+func (s *Server) getTemplate(ctx *gin.Context) *template.Template {
+	if previewTemplate, ok := ctx.Value(consts.ThemePreviewTemplate).(*template.Template); ok {
+		return previewTemplate
+	}
+	return s.Template
+}
@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -52,43 +53,67 @@ func Fatal(msg string, fields ...zap.Field) {
 }
 
 func CtxDebugf(ctx context.Context, template string, args ...interface{}) {
-	exportUseSugarLogger.Debugf(template, args...)
+	sugarWithTrace(ctx).Debugf(template, args...)
 }
 
 func CtxInfof(ctx context.Context, template string, args ...interface{}) {
-	exportUseSugarLogger.Infof(template, args...)
+	sugarWithTrace(ctx).Infof(template, args...)
 }
 
 func CtxWarnf(ctx context.Context, template string, args ...interface{}) {
-	exportUseSugarLogger.Warnf(template, args...)
+	sugarWithTrace(ctx).Warnf(template, args...)
 }
 
 func CtxErrorf(ctx context.Context, template string, args ...interface{}) {
-	exportUseSugarLogger.Errorf(template, args...)
+	sugarWithTrace(ctx).Errorf(template, args...)
 }
 
 func CtxFatalf(ctx context.Context, template string, args ...interface{}) {
-	exportUseSugarLogger.Fatalf(template, args...)
+	sugarWithTrace(ctx).Fatalf(template, args...)
 }
 
 func CtxDebug(ctx context.Context, msg string, fields ...zap.Field) {
-	exportUseLogger.Debug(msg, fields...)
+	exportUseLogger.Debug(msg, append(fields, TraceFields(ctx)...)...)
 }
 
 func CtxInfo(ctx context.Context, msg string, fields ...zap.Field) {
-	exportUseLogger.Info(msg, fields...)
+	exportUseLogger.Info(msg, append(fields, TraceFields(ctx)...)...)
 }
 
 func CtxWarn(ctx context.Context, msg string, fields ...zap.Field) {
-	exportUseLogger.Warn(msg, fields...)
+	exportUseLogger.Warn(msg, append(fields, TraceFields(ctx)...)...)
 }
 
 func CtxError(ctx context.Context, msg string, fields ...zap.Field) {
-	exportUseLogger.Error(msg, fields...)
+	exportUseLogger.Error(msg, append(fields, TraceFields(ctx)...)...)
 }
 
 func CtxFatal(ctx context.Context, msg string, fields ...zap.Field) {
-	exportUseLogger.Fatal(msg, fields...)
+	exportUseLogger.Fatal(msg, append(fields, TraceFields(ctx)...)...)
+}
+
+// TraceFields returns the ids of the span in ctx so that logs can be joined with traces
+func TraceFields(ctx context.Context) []zap.Field {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("traceID", spanContext.TraceID().String()),
+		zap.String("spanID", spanContext.SpanID().String()),
+	}
+}
+
+func sugarWithTrace(ctx context.Context) *zap.SugaredLogger {
+	fields := TraceFields(ctx)
+	if len(fields) == 0 {
+		return exportUseSugarLogger
+	}
+	args := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	return exportUseSugarLogger.With(args...)
 }
 
 func Sync() {
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 
 	"github.com/go-sonic/sonic/cache"
 	"github.com/go-sonic/sonic/config"
@@ -16,9 +18,13 @@ import (
 	"github.com/go-sonic/sonic/log"
 	"github.com/go-sonic/sonic/template"
 	"github.com/go-sonic/sonic/template/extension"
+	"github.com/go-sonic/sonic/tracing"
 )
 
-var eventBus event.Bus
+var (
+	eventBus event.Bus
+	conf     *config.Config
+)
 
 func main() {
 	app := InitApp()
@@ -27,7 +33,19 @@ func main() {
 		panic(err)
 	}
 	eventBus.Publish(context.Background(), &event.StartEvent{})
-	<-app.Done()
+	signal := <-app.Wait()
+
+	// stop accepting requests, flush the counters, drain the events and close the database in order
+	ctx, cancel := context.WithTimeout(context.Background(), conf.Server.ShutdownTimeout)
+	defer cancel()
+	if err := app.Stop(ctx); err != nil {
+		log.Error("graceful shutdown error", zap.Error(err))
+	}
+	log.Sync()
+	if signal.ExitCode != 0 {
+		cancel()
+		os.Exit(signal.ExitCode)
+	}
 }
 
 func InitApp() *fx.App {
@@ -44,17 +62,29 @@ func InitApp() *fx.App {
 			handler.NewServer,
 			template.NewTemplate,
 			middleware.NewAuthMiddleware,
+			middleware.NewAuditMiddleware,
 			middleware.NewGinLoggerMiddleware,
 			middleware.NewRecoveryMiddleware,
 			middleware.NewInstallRedirectMiddleware,
+			middleware.NewMetricsMiddleware,
+			middleware.NewTracingMiddleware,
+			middleware.NewThemePreviewMiddleware,
+			middleware.NewLanguageMiddleware,
 		),
 		fx.Populate(&dal.DB),
 		fx.Populate(&eventBus),
+		fx.Populate(&conf),
 		fx.Invoke(
+			tracing.Setup,
 			listener.NewStartListener,
 			listener.NewTemplateConfigListener,
 			listener.NewLogEventListener,
 			listener.NewPostUpdateListener,
+			listener.NewSitemapNotifyListener,
+			listener.NewRelatedPostListener,
+			listener.NewMentionListener,
+			listener.NewActivityPubListener,
+			listener.NewGitSyncListener,
 			listener.NewCommentListener,
 			extension.RegisterCategoryFunc,
 			extension.RegisterCommentFunc,
@@ -65,7 +95,11 @@ func InitApp() *fx.App {
 			extension.RegisterToolFunc,
 			extension.RegisterPaginationFunc,
 			extension.RegisterPostFunc,
+			extension.RegisterSeriesFunc,
+			extension.RegisterTranslationFunc,
 			extension.RegisterStatisticFunc,
+			extension.RegisterSeoFunc,
+			extension.RegisterMentionFunc,
 			func(s *handler.Server) {
 				s.RegisterRouters()
 			},
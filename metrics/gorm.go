@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const gormStartKey = "sonic:metrics_start"
+
+// GormPlugin observes the latency of every statement executed by gorm, the operation is taken from
+// the callback so that the statement does not have to be rendered with its vars
+type GormPlugin struct{}
+
+var _ gorm.Plugin = &GormPlugin{}
+
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "sonic:metrics"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	errs := []error{
+		callback.Create().Before("gorm:create").Register("sonic:metrics_before_create", p.before),
+		callback.Create().After("gorm:create").Register("sonic:metrics_after_create", p.after("insert")),
+		callback.Query().Before("gorm:query").Register("sonic:metrics_before_query", p.before),
+		callback.Query().After("gorm:query").Register("sonic:metrics_after_query", p.after("select")),
+		callback.Update().Before("gorm:update").Register("sonic:metrics_before_update", p.before),
+		callback.Update().After("gorm:update").Register("sonic:metrics_after_update", p.after("update")),
+		callback.Delete().Before("gorm:delete").Register("sonic:metrics_before_delete", p.before),
+		callback.Delete().After("gorm:delete").Register("sonic:metrics_after_delete", p.after("delete")),
+		callback.Row().Before("gorm:row").Register("sonic:metrics_before_row", p.before),
+		callback.Row().After("gorm:row").Register("sonic:metrics_after_row", p.after("")),
+		callback.Raw().Before("gorm:raw").Register("sonic:metrics_before_raw", p.before),
+		callback.Raw().After("gorm:raw").Register("sonic:metrics_after_raw", p.after("")),
+	}
+	return errors.Join(errs...)
+}
+
+func (p *GormPlugin) before(db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+// after observes the statement, the raw statements are labeled by their leading keyword
+func (p *GormPlugin) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		op := operation
+		if op == "" {
+			op = sqlOperation(db.Statement.SQL.String())
+		}
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		dbQueryDuration.WithLabelValues(op, result(err)).Observe(time.Since(value.(time.Time)).Seconds())
+	}
+}
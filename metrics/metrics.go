@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const namespace = "sonic"
+
+// Registry holds every sonic collector, it is separated from the default registry
+// so that libraries can not leak their metrics into the /metrics endpoint
+var Registry = prometheus.NewRegistry()
+
+var (
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of http requests by route group, method and status code.",
+	}, []string{"group", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of http requests by route group and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"group", "method"})
+
+	templateRenderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "template",
+		Name:      "render_duration_seconds",
+		Help:      "Time spent rendering theme templates.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"template", "result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of database statements by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation", "result"})
+
+	cacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Total number of cache lookups by result.",
+	}, []string{"result"})
+
+	eventListenerErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "event",
+		Name:      "listener_errors_total",
+		Help:      "Total number of errors returned or panics raised by event listeners.",
+	}, []string{"event"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		templateRenderDuration,
+		dbQueryDuration,
+		cacheRequestsTotal,
+		eventListenerErrorsTotal,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "hit_ratio",
+			Help:      "Ratio of cache lookups that found a value since start.",
+		}, cacheHitRatio),
+	)
+}
+
+// Handler serves the metrics of Registry in the prometheus exposition format
+func Handler() http.Handler {
+	// the exemplars are only exposed in the OpenMetrics format
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry, EnableOpenMetrics: true})
+}
+
+func ObserveHTTPRequest(group, method string, status int, elapsed time.Duration) {
+	httpRequestsTotal.WithLabelValues(group, method, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(group, method).Observe(elapsed.Seconds())
+}
+
+// ObserveTemplateRender records the render time, the trace of ctx is attached as the exemplar when it is sampled
+func ObserveTemplateRender(ctx context.Context, name string, elapsed time.Duration, err error) {
+	if name == "" {
+		name = "default"
+	}
+	observer := templateRenderDuration.WithLabelValues(name, result(err))
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsSampled() {
+		observer.(prometheus.ExemplarObserver).ObserveWithExemplar(elapsed.Seconds(), prometheus.Labels{"trace_id": spanContext.TraceID().String()})
+		return
+	}
+	observer.Observe(elapsed.Seconds())
+}
+
+func ObserveCacheLookup(hit bool) {
+	if hit {
+		cacheHits.Add(1)
+		cacheRequestsTotal.WithLabelValues("hit").Inc()
+	} else {
+		cacheMisses.Add(1)
+		cacheRequestsTotal.WithLabelValues("miss").Inc()
+	}
+}
+
+func IncEventListenerError(eventType string) {
+	eventListenerErrorsTotal.WithLabelValues(eventType).Inc()
+}
+
+func cacheHitRatio() float64 {
+	hit, miss := cacheHits.Load(), cacheMisses.Load()
+	if hit+miss == 0 {
+		return 0
+	}
+	return float64(hit) / float64(hit+miss)
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// sqlOperation keeps the label cardinality low by only using the leading keyword of the statement
+func sqlOperation(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if i := strings.IndexAny(sql, " \n\t("); i > 0 {
+		sql = sql[:i]
+	}
+	switch operation := strings.ToLower(sql); operation {
+	case "select", "insert", "update", "delete", "replace", "create", "alter", "drop", "pragma", "savepoint", "release", "begin", "commit", "rollback":
+		return operation
+	}
+	return "other"
+}
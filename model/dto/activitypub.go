@@ -0,0 +1,105 @@
+package dto
+
+// WebFinger is the JSON Resource Descriptor of the actor, see https://www.rfc-editor.org/rfc/rfc7033
+type WebFinger struct {
+	Subject string           `json:"subject"`
+	Aliases []string         `json:"aliases,omitempty"`
+	Links   []*WebFingerLink `json:"links"`
+}
+
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+type ActivityPubActor struct {
+	Context                   []string              `json:"@context"`
+	ID                        string                `json:"id"`
+	Type                      string                `json:"type"`
+	PreferredUsername         string                `json:"preferredUsername"`
+	Name                      string                `json:"name"`
+	Summary                   string                `json:"summary"`
+	URL                       string                `json:"url"`
+	Icon                      *ActivityPubImage     `json:"icon,omitempty"`
+	Inbox                     string                `json:"inbox"`
+	Outbox                    string                `json:"outbox"`
+	Followers                 string                `json:"followers"`
+	Endpoints                 *ActivityPubEndpoints `json:"endpoints"`
+	PublicKey                 *ActivityPubPublicKey `json:"publicKey"`
+	ManuallyApprovesFollowers bool                  `json:"manuallyApprovesFollowers"`
+	Discoverable              bool                  `json:"discoverable"`
+}
+
+type ActivityPubEndpoints struct {
+	SharedInbox string `json:"sharedInbox"`
+}
+
+type ActivityPubPublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type ActivityPubImage struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// ActivityPubObject is the Article of a post or the Note of a journal
+type ActivityPubObject struct {
+	Context      string              `json:"@context,omitempty"`
+	ID           string              `json:"id"`
+	Type         string              `json:"type"`
+	AttributedTo string              `json:"attributedTo"`
+	Name         string              `json:"name,omitempty"`
+	Content      string              `json:"content"`
+	URL          string              `json:"url"`
+	Published    string              `json:"published"`
+	Updated      string              `json:"updated,omitempty"`
+	To           []string            `json:"to"`
+	Cc           []string            `json:"cc"`
+	Tag          []*ActivityPubTag   `json:"tag,omitempty"`
+	Attachment   []*ActivityPubImage `json:"attachment,omitempty"`
+}
+
+type ActivityPubTag struct {
+	Type string `json:"type"`
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+type ActivityPubActivity struct {
+	Context   string   `json:"@context,omitempty"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published,omitempty"`
+	To        []string `json:"to,omitempty"`
+	Cc        []string `json:"cc,omitempty"`
+	Object    any      `json:"object"`
+}
+
+type ActivityPubCollection struct {
+	Context      string                 `json:"@context,omitempty"`
+	ID           string                 `json:"id"`
+	Type         string                 `json:"type"`
+	TotalItems   int64                  `json:"totalItems"`
+	First        string                 `json:"first,omitempty"`
+	Last         string                 `json:"last,omitempty"`
+	PartOf       string                 `json:"partOf,omitempty"`
+	Prev         string                 `json:"prev,omitempty"`
+	Next         string                 `json:"next,omitempty"`
+	OrderedItems []*ActivityPubActivity `json:"orderedItems,omitempty"`
+}
+
+type Follower struct {
+	ID         int32  `json:"id"`
+	ActorID    string `json:"actorId"`
+	Username   string `json:"username"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Avatar     string `json:"avatar"`
+	Inbox      string `json:"inbox"`
+	CreateTime int64  `json:"createTime"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+type AuditLog struct {
+	ID            int64  `json:"id"`
+	UserID        int32  `json:"userId"`
+	Username      string `json:"username"`
+	IPAddress     string `json:"ipAddress"`
+	Method        string `json:"method"`
+	Path          string `json:"path"`
+	EntityType    string `json:"entityType"`
+	EntityID      string `json:"entityId"`
+	BeforeSummary string `json:"beforeSummary"`
+	AfterSummary  string `json:"afterSummary"`
+	Status        int32  `json:"status"`
+	CreateTime    int64  `json:"createTime"`
+}
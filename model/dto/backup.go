@@ -6,3 +6,25 @@ type BackupDTO struct {
 	UpdateTime   int64  `json:"updateTime"`
 	FileSize     int64  `json:"fileSize"`
 }
+
+// ImportReport is the result of importing the content exported by another blog
+type ImportReport struct {
+	Posts       int           `json:"posts"`
+	Sheets      int           `json:"sheets"`
+	Journals    int           `json:"journals"`
+	Categories  int           `json:"categories"`
+	Tags        int           `json:"tags"`
+	Series      int           `json:"series"`
+	Comments    int           `json:"comments"`
+	Attachments int           `json:"attachments"`
+	Redirects   int           `json:"redirects"`
+	Skipped     []*ImportItem `json:"skipped"`
+	Conflicts   []*ImportItem `json:"conflicts"`
+}
+
+type ImportItem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug,omitempty"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type Captcha struct {
+	ID    string `json:"id"`
+	Image string `json:"image"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/go-sonic/sonic/consts"
+
+// CustomField is the schema of a post meta, declared by the admins or by the theme in theme.yaml
+type CustomField struct {
+	ID           int32                  `json:"id" yaml:"-"`
+	PostType     consts.PostType        `json:"postType" yaml:"-"`
+	Template     string                 `json:"template" yaml:"template"`
+	Key          string                 `json:"key" yaml:"key"`
+	Label        string                 `json:"label" yaml:"label"`
+	Type         consts.CustomFieldType `json:"type" yaml:"type"`
+	Required     bool                   `json:"required" yaml:"required"`
+	Options      []*CustomFieldOption   `json:"options" yaml:"options"`
+	DefaultValue string                 `json:"defaultValue" yaml:"default"`
+	Description  string                 `json:"description" yaml:"description"`
+	Priority     int32                  `json:"priority" yaml:"priority"`
+	// ThemeID is set when the field is declared by the theme
+	ThemeID string `json:"themeId,omitempty" yaml:"-"`
+}
+
+type CustomFieldOption struct {
+	Label string `json:"label" yaml:"label"`
+	Value string `json:"value" yaml:"value"`
+}
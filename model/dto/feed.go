@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+// Feed is rendered as RSS, Atom or JSON Feed
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	Language    string
+	Author      string
+	Icon        string
+	UpdateTime  time.Time
+	Items       []*FeedItem
+}
+
+type FeedItem struct {
+	ID         string
+	Title      string
+	Link       string
+	Content    string
+	Summary    string
+	Author     string
+	Tags       []string
+	CreateTime time.Time
+	UpdateTime time.Time
+}
+
+// FeedLink is used by themes to render <link rel="alternate"> for feed auto-discovery
+type FeedLink struct {
+	Title string `json:"title"`
+	Type  string `json:"type"`
+	URL   string `json:"url"`
+}
+
+func NewFeedLinks(title, rssURL, atomURL, jsonURL string) []*FeedLink {
+	return []*FeedLink{
+		{Title: title, Type: "application/rss+xml", URL: rssURL},
+		{Title: title, Type: "application/atom+xml", URL: atomURL},
+		{Title: title, Type: "application/feed+json", URL: jsonURL},
+	}
+}
+
+// JSONFeed is the document of JSON Feed version 1.1, see https://www.jsonfeed.org/version/1.1/
+type JSONFeed struct {
+	Version     string            `json:"version"`
+	Title       string            `json:"title"`
+	HomePageURL string            `json:"home_page_url,omitempty"`
+	FeedURL     string            `json:"feed_url,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Language    string            `json:"language,omitempty"`
+	Icon        string            `json:"icon,omitempty"`
+	Authors     []*JSONFeedAuthor `json:"authors,omitempty"`
+	Items       []*JSONFeedItem   `json:"items"`
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type JSONFeedItem struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	ContentHTML   string            `json:"content_html"`
+	Summary       string            `json:"summary,omitempty"`
+	DatePublished string            `json:"date_published,omitempty"`
+	DateModified  string            `json:"date_modified,omitempty"`
+	Authors       []*JSONFeedAuthor `json:"authors,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+}
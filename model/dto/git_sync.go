@@ -0,0 +1,29 @@
+package dto
+
+type GitSyncStatus struct {
+	Enabled      bool               `json:"enabled"`
+	Repository   string             `json:"repository"`
+	Branch       string             `json:"branch"`
+	LastPullTime int64              `json:"lastPullTime"`
+	LastPushTime int64              `json:"lastPushTime"`
+	LastError    string             `json:"lastError"`
+	Conflicts    []*GitSyncConflict `json:"conflicts"`
+}
+
+// GitSyncConflict is a post changed both in the blog and in the repository since the last sync,
+// it is not synced until the conflict is resolved
+type GitSyncConflict struct {
+	PostID int32  `json:"postId"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// GitSyncReport is the result of pulling or pushing the repository
+type GitSyncReport struct {
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Pushed    int                `json:"pushed"`
+	Conflicts []*GitSyncConflict `json:"conflicts"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/go-sonic/sonic/consts"
+
+type Mention struct {
+	ID          int32                  `json:"id"`
+	PostID      int32                  `json:"postId"`
+	Source      string                 `json:"source"`
+	Target      string                 `json:"target"`
+	Protocol    consts.MentionProtocol `json:"protocol"`
+	Type        consts.MentionType     `json:"type"`
+	Status      consts.CommentStatus   `json:"status"`
+	Author      string                 `json:"author"`
+	AuthorURL   string                 `json:"authorUrl"`
+	AuthorPhoto string                 `json:"authorPhoto"`
+	Title       string                 `json:"title"`
+	Content     string                 `json:"content"`
+	IPAddress   string                 `json:"ipAddress"`
+	CreateTime  int64                  `json:"createTime"`
+	PublishTime int64                  `json:"publishTime"`
+}
+
+// PostMentions are the published mentions of a post grouped by the types for themes to render
+type PostMentions struct {
+	Replies   []*Mention `json:"replies"`
+	Likes     []*Mention `json:"likes"`
+	Reposts   []*Mention `json:"reposts"`
+	Bookmarks []*Mention `json:"bookmarks"`
+	Mentions  []*Mention `json:"mentions"`
+	Total     int        `json:"total"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// MetaWeblogPost is the post exchanged with the XML-RPC editors
+type MetaWeblogPost struct {
+	PostID          int32
+	Title           string
+	Content         string
+	Excerpt         string
+	Slug            string
+	Link            string
+	Categories      []string
+	Tags            []string
+	CreateTime      time.Time
+	Published       bool
+	DisallowComment bool
+	// TextFilter is consts.MetaWeblogTextFilterMarkdown when the content is Markdown, otherwise the content is HTML
+	TextFilter string
+}
+
+type MetaWeblogCategory struct {
+	ID          int32
+	Name        string
+	Slug        string
+	Description string
+	ParentID    int32
+	URL         string
+	RSSURL      string
+}
+
+type MetaWeblogBlog struct {
+	Name string
+	URL  string
+}
+
+type MetaWeblogTag struct {
+	ID        int32
+	Name      string
+	Slug      string
+	URL       string
+	PostCount int64
+}
+
+type MetaWeblogAppPassword struct {
+	Password string `json:"password"`
+}
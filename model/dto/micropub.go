@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// MicropubToken is the token verified by the token endpoint
+type MicropubToken struct {
+	Me       string   `json:"me"`
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
+}
+
+// MicropubEntry is the source of a post, a journal or a photo returned to the Micropub clients
+type MicropubEntry struct {
+	URL         string
+	Name        string
+	Content     string
+	ContentHTML string
+	Summary     string
+	Categories  []string
+	Photos      []string
+	Published   time.Time
+	Slug        string
+	Draft       bool
+	Location    string
+}
+
+type MicropubConfig struct {
+	MediaEndpoint string              `json:"media-endpoint"`
+	SyndicateTo   []any               `json:"syndicate-to"`
+	PostTypes     []*MicropubPostType `json:"post-types"`
+	Q             []string            `json:"q"`
+}
+
+type MicropubPostType struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
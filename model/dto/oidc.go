@@ -0,0 +1,11 @@
+package dto
+
+type OIDCProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizeURL string `json:"authorizeUrl"`
+	State        string `json:"state"`
+}
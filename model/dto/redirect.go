@@ -0,0 +1,9 @@
+package dto
+
+type Redirect struct {
+	ID         int32  `json:"id"`
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	Permanent  bool   `json:"permanent"`
+	CreateTime int64  `json:"createTime"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+type Series struct {
+	ID          int32  `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Thumbnail   string `json:"thumbnail"`
+	CreateTime  int64  `json:"createTime"`
+	FullPath    string `json:"fullPath"`
+	PostCount   int64  `json:"postCount"`
+}
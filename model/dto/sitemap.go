@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type Sitemap struct {
+	Loc     string
+	LastMod *time.Time
+}
+
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+	Images  []string
+	// Alternates are the versions of the url in each language including itself
+	Alternates []*SitemapAlternate
+}
+
+type SitemapAlternate struct {
+	Language string
+	Href     string
+}
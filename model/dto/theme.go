@@ -8,7 +8,7 @@ type ThemeProperty struct {
 	Website        string                     `json:"website"`
 	Branch         string                     `json:"branch"`
 	Repo           string                     `json:"repo"`
-	UpdateStrategy consts.ThemeUpdateStrategy `json:"updateStrategy"`
+	UpdateStrategy consts.ThemeUpdateStrategy `json:"updateStrategy" yaml:"updateStrategy"`
 	Description    string                     `json:"description"`
 	Logo           string                     `json:"logo"`
 	Version        string                     `json:"version"`
@@ -21,6 +21,9 @@ type ThemeProperty struct {
 	ScreenShots    string                     `json:"screenshots"`
 	PostMetaField  []string                   `json:"postMetaField"`
 	SheetMetaField []string                   `json:"sheetMetaField"`
+	// PostCustomFields and SheetCustomFields are the typed metas the theme declares for posts and sheets
+	PostCustomFields  []*CustomField `json:"postCustomFields" yaml:"postCustomFields"`
+	SheetCustomFields []*CustomField `json:"sheetCustomFields" yaml:"sheetCustomFields"`
 }
 
 type ThemeAuthor struct {
@@ -59,3 +62,67 @@ type ThemeConfigOption struct {
 	Label string      `json:"label"`
 	Value interface{} `json:"value"`
 }
+
+type ThemeUpdate struct {
+	ThemeID        string                     `json:"themeId"`
+	UpdateStrategy consts.ThemeUpdateStrategy `json:"updateStrategy"`
+	CurrentVersion string                     `json:"currentVersion"`
+	LatestVersion  string                     `json:"latestVersion"`
+	HasUpdate      bool                       `json:"hasUpdate"`
+	// VersionUnknown is true when the installed version can not be compared with the repo, e.g. the theme
+	// was not installed from git, HasUpdate is false then but the theme can still be updated
+	VersionUnknown bool              `json:"versionUnknown"`
+	Changelog      []*ThemeChangelog `json:"changelog"`
+}
+
+type ThemeChangelog struct {
+	Version    string `json:"version"`
+	Message    string `json:"message"`
+	Author     string `json:"author"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type ThemeValidation struct {
+	ThemeID string   `json:"themeId"`
+	Valid   bool     `json:"valid"`
+	Errors  []string `json:"errors"`
+}
+
+type ThemePreview struct {
+	Token      string `json:"token"`
+	ThemeID    string `json:"themeId"`
+	UserID     int32  `json:"userId"`
+	URL        string `json:"url"`
+	ExpireTime int64  `json:"expireTime"`
+	// AdminSecret is kept in the cookie of the admin, it is never sent in the response body
+	AdminSecret string `json:"-"`
+}
+
+type ThemeSettingExport struct {
+	ThemeID      string                 `json:"themeId" yaml:"themeId"`
+	ThemeVersion string                 `json:"themeVersion" yaml:"themeVersion"`
+	Settings     map[string]interface{} `json:"settings" yaml:"settings"`
+}
+
+type ThemeSettingImport struct {
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped"`
+}
+
+type ThemeSettingPreset struct {
+	ID         int32                  `json:"id"`
+	ThemeID    string                 `json:"themeId"`
+	Name       string                 `json:"name"`
+	Settings   map[string]interface{} `json:"settings"`
+	Active     bool                   `json:"active"`
+	CreateTime int64                  `json:"createTime"`
+	UpdateTime int64                  `json:"updateTime"`
+}
+
+type ThemeSettingDiff struct {
+	Group   string      `json:"group"`
+	Name    string      `json:"name"`
+	Label   string      `json:"label"`
+	Current interface{} `json:"current"`
+	Preset  interface{} `json:"preset"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/go-sonic/sonic/consts"
+
+// Translation is a version of the content in one of the blog languages
+type Translation struct {
+	Type     consts.TranslationType `json:"type"`
+	ID       int32                  `json:"id"`
+	Language string                 `json:"language"`
+	Title    string                 `json:"title"`
+	FullPath string                 `json:"fullPath"`
+	Current  bool                   `json:"current"`
+}
+
+type Language struct {
+	Language string `json:"language"`
+	Default  bool   `json:"default"`
+	FullPath string `json:"fullPath"`
+}
@@ -33,7 +33,9 @@ func (m *Category) BeforeUpdate(tx *gorm.DB) (err error) {
 // ------------------ Comment -----------
 
 func (m *Comment) BeforeCreate(tx *gorm.DB) (err error) {
-	m.CreateTime = time.Now()
+	if m.CreateTime == (time.Time{}) {
+		m.CreateTime = time.Now()
+	}
 	return nil
 }
 
@@ -57,7 +59,9 @@ func (m *CommentBlack) BeforeUpdate(tx *gorm.DB) (err error) {
 // ---------------------- Journal -----------
 
 func (m *Journal) BeforeCreate(tx *gorm.DB) (err error) {
-	m.CreateTime = time.Now()
+	if m.CreateTime == (time.Time{}) {
+		m.CreateTime = time.Now()
+	}
 	return nil
 }
 
@@ -211,3 +215,123 @@ func (m *Meta) BeforeUpdate(tx *gorm.DB) (err error) {
 	tx.Statement.SetColumn("update_time", time.Now())
 	return nil
 }
+
+// ----------------------- AuditLog ---------------------
+
+func (m *AuditLog) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *AuditLog) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- ThemeSettingPreset ---------------------
+
+func (m *ThemeSettingPreset) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *ThemeSettingPreset) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- Mention ---------------------
+
+func (m *Mention) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *Mention) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- Follower ---------------------
+
+func (m *Follower) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *Follower) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- Redirect ---------------------
+
+func (m *Redirect) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *Redirect) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- GitSyncFile ---------------------
+
+func (m *GitSyncFile) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *GitSyncFile) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- CustomField ---------------------
+
+func (m *CustomField) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *CustomField) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- Series ---------------------
+
+func (m *Series) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *Series) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- SeriesPost ---------------------
+
+func (m *SeriesPost) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *SeriesPost) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
+
+// ----------------------- Translation ---------------------
+
+func (m *Translation) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreateTime = time.Now()
+	return nil
+}
+
+func (m *Translation) BeforeUpdate(tx *gorm.DB) (err error) {
+	tx.Statement.SetColumn("update_time", time.Now())
+	return nil
+}
@@ -0,0 +1,14 @@
+package param
+
+type AuditLogQuery struct {
+	Page
+	*Sort
+	Username   *string `json:"username" form:"username"`
+	EntityType *string `json:"entityType" form:"entityType"`
+	Method     *string `json:"method" form:"method"`
+	IPAddress  *string `json:"ipAddress" form:"ipAddress"`
+	Keyword    *string `json:"keyword" form:"keyword"`
+	// StartTime and EndTime are unix milliseconds
+	StartTime *int64 `json:"startTime" form:"startTime"`
+	EndTime   *int64 `json:"endTime" form:"endTime"`
+}
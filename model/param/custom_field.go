@@ -0,0 +1,21 @@
+package param
+
+import "github.com/go-sonic/sonic/consts"
+
+type CustomField struct {
+	PostType     consts.PostType        `json:"postType" binding:"gte=0,lte=1"`
+	Template     string                 `json:"template" binding:"gte=0,lte=255"`
+	Key          string                 `json:"key" binding:"gte=1,lte=255"`
+	Label        string                 `json:"label" binding:"gte=0,lte=255"`
+	Type         consts.CustomFieldType `json:"type"`
+	Required     bool                   `json:"required"`
+	Options      []CustomFieldOption    `json:"options" binding:"dive"`
+	DefaultValue string                 `json:"defaultValue" binding:"gte=0,lte=1023"`
+	Description  string                 `json:"description" binding:"gte=0,lte=1023"`
+	Priority     int32                  `json:"priority"`
+}
+
+type CustomFieldOption struct {
+	Label string `json:"label" binding:"gte=0,lte=255"`
+	Value string `json:"value" binding:"gte=1,lte=255"`
+}
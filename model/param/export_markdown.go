@@ -2,4 +2,7 @@ package param
 
 type ExportMarkdown struct {
 	NeedFrontMatter bool `json:"needFrontMatter"`
+	// WithAttachments exports the sheets, journals and comments with the attachments they refer to,
+	// the exported zip can be imported back
+	WithAttachments bool `json:"withAttachments"`
 }
@@ -0,0 +1,6 @@
+package param
+
+// GitSyncResolve resolves the conflict by keeping the post or the file in the repository
+type GitSyncResolve struct {
+	Keep string `json:"keep" binding:"required,oneof=post repository"`
+}
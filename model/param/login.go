@@ -4,4 +4,7 @@ type LoginParam struct {
 	Username string `json:"username" binding:"gte=1,lte=20"`
 	Password string `json:"password" binding:"gte=6"`
 	AuthCode string `json:"authcode" `
+	// CaptchaID and CaptchaCode are only required after several failed attempts when the captcha is enabled
+	CaptchaID   string `json:"captchaId"`
+	CaptchaCode string `json:"captchaCode"`
 }
@@ -0,0 +1,12 @@
+package param
+
+import "github.com/go-sonic/sonic/consts"
+
+type MentionQuery struct {
+	Page
+	*Sort
+	PostID        *int32                `json:"postId" form:"postId"`
+	Keyword       *string               `json:"keyword" form:"keyword"`
+	MentionType   *consts.MentionType   `json:"type" form:"type"`
+	MentionStatus *consts.CommentStatus `json:"status" form:"status"`
+}
@@ -0,0 +1,19 @@
+package param
+
+import "time"
+
+// MetaWeblogPost is the post sent by the XML-RPC editors, the nil fields are kept when the post is edited
+type MetaWeblogPost struct {
+	Title      string
+	Content    string
+	Excerpt    string
+	Slug       string
+	Categories []string
+	// CategoryIDs takes the place of Categories when it is not nil
+	CategoryIDs     []int32
+	Tags            []string
+	CreateTime      *time.Time
+	Published       bool
+	DisallowComment *bool
+	TextFilter      *string
+}
@@ -0,0 +1,24 @@
+package param
+
+import "time"
+
+// MicropubEntry is the h-entry sent by the Micropub clients, see https://www.w3.org/TR/micropub/
+type MicropubEntry struct {
+	Name    string
+	Content string
+	// ContentHTML is true when the content is HTML, otherwise it is plain text or Markdown
+	ContentHTML bool
+	Summary     string
+	Categories  []string
+	Photos      []MicropubPhoto
+	Published   *time.Time
+	Slug        string
+	Draft       bool
+	Private     bool
+	Location    string
+}
+
+type MicropubPhoto struct {
+	URL string
+	Alt string
+}
@@ -23,6 +23,12 @@ type Post struct {
 	Content         string             `json:"content" form:"content"`
 	EditTime        *int64             `json:"editTime" form:"editTime"`
 	UpdateTime      *int64             `json:"updateTime" form:"updateTime"`
+	// SeriesID is the series the post is put into, 0 takes it out of its series and nil keeps it
+	SeriesID *int32 `json:"seriesId" form:"seriesId"`
+	// Language is the language of the post, nil keeps it
+	Language *string `json:"language" form:"language"`
+	// TranslationOf is the post this one translates, 0 takes it out of its translations and nil keeps them
+	TranslationOf *int32 `json:"translationOf" form:"translationOf"`
 }
 
 type PostContent struct {
@@ -39,4 +45,8 @@ type PostQuery struct {
 	More         *bool                `json:"more" form:"more"`
 	TagID        *int32               `json:"tagId" form:"tagId"`
 	WithPassword *bool                `json:"-" form:"-"`
+	// Language filters the posts by their language, the language of the request is used when it is nil
+	Language *string `json:"language" form:"language"`
+	// Metas filters the posts by their custom fields, see CustomFieldService.ListPostIDsByFilters
+	Metas map[string]string `json:"metas" form:"-"`
 }
@@ -0,0 +1,7 @@
+package param
+
+type Redirect struct {
+	Source    string `json:"source" form:"source" binding:"gte=1,lte=255"`
+	Target    string `json:"target" form:"target" binding:"gte=1,lte=1023"`
+	Permanent bool   `json:"permanent" form:"permanent"`
+}
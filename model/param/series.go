@@ -0,0 +1,13 @@
+package param
+
+type Series struct {
+	Name        string `json:"name" binding:"gte=1,lte=255"`
+	Slug        string `json:"slug" binding:"gte=0,lte=255"`
+	Description string `json:"description" binding:"gte=0,lte=1023"`
+	Thumbnail   string `json:"thumbnail" binding:"gte=0,lte=1023"`
+}
+
+// SeriesPosts is the posts of a series in the order they are read
+type SeriesPosts struct {
+	PostIDs []int32 `json:"postIds"`
+}
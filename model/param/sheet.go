@@ -19,4 +19,8 @@ type Sheet struct {
 	MetaKeywords    string             `json:"metaKeywords" form:"metaKeywords"`
 	MetaDescription string             `json:"metaDescription" form:"metaDescription"`
 	Metas           []Meta             `json:"metas" form:"metas"`
+	// Language is the language of the sheet, nil keeps it
+	Language *string `json:"language" form:"language"`
+	// TranslationOf is the sheet this one translates, 0 takes it out of its translations and nil keeps them
+	TranslationOf *int32 `json:"translationOf" form:"translationOf"`
 }
@@ -4,3 +4,7 @@ type ThemeContent struct {
 	Path    string `json:"path" form:"path" binding:"gte=1"`
 	Content string `json:"content" form:"path"`
 }
+
+type ThemeSettingPreset struct {
+	Name string `json:"name" binding:"gte=1,lte=255"`
+}
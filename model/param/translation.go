@@ -0,0 +1,7 @@
+package param
+
+type Translation struct {
+	Language string `json:"language" binding:"gte=0,lte=32"`
+	// TranslationOf is the content this one translates, 0 takes the content out of its translations and nil keeps them
+	TranslationOf *int32 `json:"translationOf" binding:"omitempty,gte=0"`
+}
@@ -0,0 +1,30 @@
+package property
+
+import "reflect"
+
+var (
+	// ActivityPubEnabled makes the blog followable from Mastodon and the other fediverse servers
+	ActivityPubEnabled = Property{
+		KeyValue:     "activitypub_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	// ActivityPubUsername is the username of the actor, the username of the blog owner is used when it is empty
+	ActivityPubUsername = Property{
+		KeyValue:     "activitypub_username",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+	// ActivityPubReplyEnabled saves the replies from the fediverse to the posts and journals as comments
+	ActivityPubReplyEnabled = Property{
+		KeyValue:     "activitypub_reply_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	// ActivityPubPrivateKey is generated at the first use for signing the requests to the other servers
+	ActivityPubPrivateKey = Property{
+		KeyValue:     "activitypub_private_key",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+)
@@ -41,6 +41,7 @@ var AllProperty = []Property{
 	UploadMaxFiles,
 	AttachmentType,
 	BlogLocale,
+	BlogLanguages,
 	BlogTitle,
 	BlogLogo,
 	BlogURL,
@@ -68,6 +69,7 @@ var AllProperty = []Property{
 	LinksPrefix,
 	PhotosPrefix,
 	JournalsPrefix,
+	SeriesPrefix,
 	PathSuffix,
 	IsInstalled,
 	Theme,
@@ -76,6 +78,30 @@ var AllProperty = []Property{
 	SeoKeywords,
 	SeoDescription,
 	SeoSpiderDisabled,
+	SeoIndexNowEnabled,
+	SeoIndexNowKey,
+	SeoIndexNowEndpoint,
+	SeoSitemapPingURLs,
+	WebmentionEnabled,
+	PingbackEnabled,
+	MentionNewNeedCheck,
+	ActivityPubEnabled,
+	ActivityPubUsername,
+	ActivityPubReplyEnabled,
+	ActivityPubPrivateKey,
+	GitSyncEnabled,
+	GitSyncRepository,
+	GitSyncBranch,
+	GitSyncDirectory,
+	GitSyncUsername,
+	GitSyncPassword,
+	GitSyncInterval,
+	GitSyncWebhookSecret,
+	MetaWeblogEnabled,
+	MetaWeblogAppPassword,
+	MicropubEnabled,
+	MicropubAuthorizationEndpoint,
+	MicropubTokenEndpoint,
 	SummaryLength,
 	RssPageSize,
 	RssContentType,
@@ -147,4 +173,7 @@ var AllProperty = []Property{
 	PhotoPageSize,
 	JournalPageSize,
 	JWTSecret,
+	LoginMaxFailedAttempts,
+	LoginLockMinutes,
+	LoginCaptchaEnabled,
 }
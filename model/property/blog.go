@@ -8,6 +8,12 @@ var (
 		DefaultValue: "zh",
 		Kind:         reflect.String,
 	}
+	// BlogLanguages are the languages content is published in besides BlogLocale, separated by comma
+	BlogLanguages = Property{
+		KeyValue:     "blog_languages",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
 	BlogTitle = Property{
 		KeyValue:     "blog_title",
 		DefaultValue: "",
@@ -0,0 +1,52 @@
+package property
+
+import "reflect"
+
+var (
+	// GitSyncEnabled commits the published posts to the repository and updates the posts by pulling it
+	GitSyncEnabled = Property{
+		KeyValue:     "git_sync_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	// GitSyncRepository is the url of the remote repository or the path of a local bare repository
+	GitSyncRepository = Property{
+		KeyValue:     "git_sync_repository",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+	GitSyncBranch = Property{
+		KeyValue:     "git_sync_branch",
+		DefaultValue: "main",
+		Kind:         reflect.String,
+	}
+	// GitSyncDirectory is the directory of the Markdown files in the repository, the root when it is empty
+	GitSyncDirectory = Property{
+		KeyValue:     "git_sync_directory",
+		DefaultValue: "posts",
+		Kind:         reflect.String,
+	}
+	GitSyncUsername = Property{
+		KeyValue:     "git_sync_username",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+	// GitSyncPassword is the password or the access token of the user for the http remote
+	GitSyncPassword = Property{
+		KeyValue:     "git_sync_password",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+	// GitSyncInterval is the minutes between pulling the repository on schedule, 0 disables the schedule
+	GitSyncInterval = Property{
+		KeyValue:     "git_sync_interval",
+		DefaultValue: 0,
+		Kind:         reflect.Int,
+	}
+	// GitSyncWebhookSecret verifies the push webhooks of GitHub, Gitea and GitLab, the webhook is disabled when it is empty
+	GitSyncWebhookSecret = Property{
+		KeyValue:     "git_sync_webhook_secret",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+)
@@ -0,0 +1,23 @@
+package property
+
+import "reflect"
+
+var (
+	// WebmentionEnabled enables receiving webmentions and sending them to the links of the published posts
+	WebmentionEnabled = Property{
+		KeyValue:     "webmention_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	// PingbackEnabled enables receiving pingbacks and sending them to the links without webmention endpoints
+	PingbackEnabled = Property{
+		KeyValue:     "pingback_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	MentionNewNeedCheck = Property{
+		KeyValue:     "mention_new_need_check",
+		DefaultValue: true,
+		Kind:         reflect.Bool,
+	}
+)
@@ -0,0 +1,18 @@
+package property
+
+import "reflect"
+
+var (
+	// MetaWeblogEnabled serves the MetaWeblog, Blogger and WordPress XML-RPC methods for the desktop and mobile editors
+	MetaWeblogEnabled = Property{
+		KeyValue:     "metaweblog_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	// MetaWeblogAppPassword is the hashed app password accepted by the XML-RPC methods besides the account password
+	MetaWeblogAppPassword = Property{
+		KeyValue:     "metaweblog_app_password",
+		DefaultValue: "",
+		Kind:         reflect.String,
+	}
+)
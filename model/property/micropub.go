@@ -0,0 +1,24 @@
+package property
+
+import "reflect"
+
+var (
+	// MicropubEnabled lets the IndieWeb clients publish the posts, journals and photos by Micropub
+	MicropubEnabled = Property{
+		KeyValue:     "micropub_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+	// MicropubAuthorizationEndpoint is advertised to the clients for signing in with the blog url
+	MicropubAuthorizationEndpoint = Property{
+		KeyValue:     "micropub_authorization_endpoint",
+		DefaultValue: "https://indieauth.com/auth",
+		Kind:         reflect.String,
+	}
+	// MicropubTokenEndpoint issues the tokens to the clients and verifies the tokens sent to the Micropub endpoint
+	MicropubTokenEndpoint = Property{
+		KeyValue:     "micropub_token_endpoint",
+		DefaultValue: "https://tokens.indieauth.com/token",
+		Kind:         reflect.String,
+	}
+)
@@ -48,6 +48,11 @@ var (
 		KeyValue:     "journals_prefix",
 		Kind:         reflect.String,
 	}
+	SeriesPrefix = Property{
+		DefaultValue: "series",
+		KeyValue:     "series_prefix",
+		Kind:         reflect.String,
+	}
 	PathSuffix = Property{
 		DefaultValue: "",
 		KeyValue:     "path_suffix",
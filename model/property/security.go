@@ -0,0 +1,21 @@
+package property
+
+import "reflect"
+
+var (
+	LoginMaxFailedAttempts = Property{
+		KeyValue:     "login_max_failed_attempts",
+		DefaultValue: 5,
+		Kind:         reflect.Int,
+	}
+	LoginLockMinutes = Property{
+		KeyValue:     "login_lock_minutes",
+		DefaultValue: 15,
+		Kind:         reflect.Int,
+	}
+	LoginCaptchaEnabled = Property{
+		KeyValue:     "login_captcha_enabled",
+		DefaultValue: false,
+		Kind:         reflect.Bool,
+	}
+)
@@ -19,3 +19,30 @@ var SeoSpiderDisabled = Property{
 	DefaultValue: false,
 	Kind:         reflect.Bool,
 }
+
+var SeoIndexNowEnabled = Property{
+	KeyValue:     "seo_index_now_enabled",
+	DefaultValue: false,
+	Kind:         reflect.Bool,
+}
+
+// SeoIndexNowKey is served at consts.IndexNowKeyPath for the search engines to verify the submissions
+var SeoIndexNowKey = Property{
+	KeyValue:     "seo_index_now_key",
+	DefaultValue: "",
+	Kind:         reflect.String,
+}
+
+var SeoIndexNowEndpoint = Property{
+	KeyValue:     "seo_index_now_endpoint",
+	DefaultValue: "https://api.indexnow.org/indexnow",
+	Kind:         reflect.String,
+}
+
+// SeoSitemapPingURLs are requested after a post is published, one per line,
+// consts.SitemapPingURLHolder is replaced by the url of the sitemap index
+var SeoSitemapPingURLs = Property{
+	KeyValue:     "seo_sitemap_ping_urls",
+	DefaultValue: "",
+	Kind:         reflect.String,
+}
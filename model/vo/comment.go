@@ -31,3 +31,8 @@ type CommentWithHasChildren struct {
 	HasChildren   bool  `json:"hasChildren"`
 	ChildrenCount int64 `json:"childrenCount"`
 }
+
+type MentionWithPost struct {
+	dto.Mention
+	Post *dto.PostMinimal `json:"post"`
+}
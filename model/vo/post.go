@@ -8,6 +8,7 @@ type Post struct {
 	Tags         []*dto.Tag             `json:"tags"`
 	Categories   []*dto.CategoryDTO     `json:"categories"`
 	Metas        map[string]interface{} `json:"metas"`
+	Fields       map[string]interface{} `json:"fields"`
 }
 
 type PostDetailVO struct {
@@ -18,4 +19,8 @@ type PostDetailVO struct {
 	Categories  []*dto.CategoryDTO `json:"categories"`
 	MetaIDs     []int32            `json:"metaIds"`
 	Metas       []*dto.Meta        `json:"metas"`
+	SeriesID    int32              `json:"seriesId"`
+	Series      *dto.Series        `json:"series"`
+	// Fields are the typed values of the custom fields
+	Fields map[string]interface{} `json:"fields"`
 }
@@ -0,0 +1,9 @@
+package vo
+
+import "github.com/go-sonic/sonic/model/dto"
+
+// SeriesDetail is a series with its posts in order
+type SeriesDetail struct {
+	*dto.Series
+	Posts []*Post `json:"posts"`
+}
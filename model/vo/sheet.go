@@ -6,6 +6,8 @@ type SheetDetail struct {
 	dto.PostDetail
 	MetaIDs []int32     `json:"metaIds"`
 	Metas   []*dto.Meta `json:"metas"`
+	// Fields are the typed values of the custom fields
+	Fields map[string]interface{} `json:"fields"`
 }
 
 type SheetList struct {
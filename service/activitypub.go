@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type ActivityPubService interface {
+	// WebFinger resolves the acct: uri or the url of the actor
+	WebFinger(ctx context.Context, resource string) (*dto.WebFinger, error)
+	Actor(ctx context.Context) (*dto.ActivityPubActor, error)
+	// Outbox returns the collection of the published posts and journals, the items are paged when page > 0
+	Outbox(ctx context.Context, page int) (*dto.ActivityPubCollection, error)
+	Followers(ctx context.Context) (*dto.ActivityPubCollection, error)
+	PostObject(ctx context.Context, postID int32) (*dto.ActivityPubObject, error)
+	JournalObject(ctx context.Context, journalID int32) (*dto.ActivityPubObject, error)
+	// HandleInbox verifies the signature of the activity, and handles Follow, Undo, Delete and the replies
+	HandleInbox(ctx context.Context, req *http.Request, body []byte) error
+	// Deliver sends the Create activity of the post to the inboxes of the followers
+	Deliver(ctx context.Context, post *entity.Post) error
+	PageFollower(ctx context.Context, page param.Page) ([]*entity.Follower, int64, error)
+	DeleteFollower(ctx context.Context, followerID int32) error
+	ConvertToFollowerDTO(follower *entity.Follower) *dto.Follower
+}
@@ -11,6 +11,8 @@ import (
 type AdminService interface {
 	Authenticate(ctx context.Context, loginParam param.LoginParam) (*entity.User, error)
 	Auth(ctx context.Context, loginParam param.LoginParam) (*dto.AuthTokenDTO, error)
+	// AuthByOIDC logs in with the authorization code, users with MFA call it again with the same state and the TOTP code in loginParam
+	AuthByOIDC(ctx context.Context, providerName, code, state string, loginParam param.LoginParam) (*dto.AuthTokenDTO, error)
 	ClearToken(ctx context.Context) error
 	SendResetPasswordCode(ctx context.Context, resetParam param.ResetPasswordParam) error
 	RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthTokenDTO, error)
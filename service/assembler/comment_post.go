@@ -3,6 +3,7 @@ package assembler
 import (
 	"context"
 
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/entity"
 	"github.com/go-sonic/sonic/model/vo"
 	"github.com/go-sonic/sonic/service"
@@ -46,6 +47,18 @@ func (p *postCommentAssembler) ConvertToWithPost(ctx context.Context, comments [
 	if err != nil {
 		return nil, err
 	}
+	postList := make([]*entity.Post, 0, len(posts))
+	for _, post := range posts {
+		postList = append(postList, post)
+	}
+	postMinimals, err := p.PostAssembler.ConvertToMinimalDTOs(ctx, postList)
+	if err != nil {
+		return nil, err
+	}
+	postMinimalMap := make(map[int32]*dto.PostMinimal, len(postMinimals))
+	for _, postMinimal := range postMinimals {
+		postMinimalMap[postMinimal.ID] = postMinimal
+	}
 	result := make([]*vo.PostCommentWithPost, 0, len(comments))
 	for _, comment := range comments {
 		commentDTO, err := p.BaseCommentAssembler.ConvertToDTO(ctx, comment)
@@ -56,13 +69,7 @@ func (p *postCommentAssembler) ConvertToWithPost(ctx context.Context, comments [
 			Comment: *commentDTO,
 		}
 		result = append(result, commentWithPost)
-		post, ok := posts[comment.PostID]
-		if ok {
-			commentWithPost.Post, err = p.PostAssembler.ConvertToMinimalDTO(ctx, post)
-			if err != nil {
-				return nil, err
-			}
-		}
+		commentWithPost.Post = postMinimalMap[comment.PostID]
 	}
 	return result, nil
 }
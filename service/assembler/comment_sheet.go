@@ -3,6 +3,7 @@ package assembler
 import (
 	"context"
 
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/entity"
 	"github.com/go-sonic/sonic/model/vo"
 	"github.com/go-sonic/sonic/service"
@@ -46,6 +47,18 @@ func (p *sheetCommentAssembler) ConvertToWithSheet(ctx context.Context, comments
 	if err != nil {
 		return nil, err
 	}
+	postList := make([]*entity.Post, 0, len(sheets))
+	for _, post := range sheets {
+		postList = append(postList, post)
+	}
+	postMinimals, err := p.SheetAssembler.ConvertToMinimalDTOs(ctx, postList)
+	if err != nil {
+		return nil, err
+	}
+	postMinimalMap := make(map[int32]*dto.PostMinimal, len(postMinimals))
+	for _, postMinimal := range postMinimals {
+		postMinimalMap[postMinimal.ID] = postMinimal
+	}
 	result := make([]*vo.SheetCommentWithSheet, 0, len(comments))
 	for _, comment := range comments {
 		commentDTO, err := p.BaseCommentAssembler.ConvertToDTO(ctx, comment)
@@ -56,13 +69,7 @@ func (p *sheetCommentAssembler) ConvertToWithSheet(ctx context.Context, comments
 			Comment: *commentDTO,
 		}
 		result = append(result, commentWithSheet)
-		sheet, ok := sheets[comment.PostID]
-		if ok {
-			commentWithSheet.PostMinimal, err = p.SheetAssembler.ConvertToMinimalDTO(ctx, sheet)
-			if err != nil {
-				return nil, err
-			}
-		}
+		commentWithSheet.PostMinimal = postMinimalMap[comment.PostID]
 	}
 	return result, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/go-sonic/sonic/model/entity"
 	"github.com/go-sonic/sonic/model/vo"
 	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
 )
 
 type PostAssembler interface {
@@ -29,6 +30,7 @@ func NewPostAssembler(basePostService service.BasePostService,
 	categoryService service.CategoryService,
 	postCommentService service.PostCommentService,
 	metaService service.MetaService,
+	seriesService service.SeriesService,
 	basePostAssembler BasePostAssembler,
 ) PostAssembler {
 	return &postAssembler{
@@ -41,6 +43,7 @@ func NewPostAssembler(basePostService service.BasePostService,
 		TagService:          tagService,
 		CategoryService:     categoryService,
 		MetaService:         metaService,
+		SeriesService:       seriesService,
 	}
 }
 
@@ -54,6 +57,7 @@ type postAssembler struct {
 	CategoryService     service.CategoryService
 	PostCommentService  service.PostCommentService
 	MetaService         service.MetaService
+	SeriesService       service.SeriesService
 }
 
 func (p *postAssembler) ConvertToListVO(ctx context.Context, posts []*entity.Post) ([]*vo.Post, error) {
@@ -82,17 +86,9 @@ func (p *postAssembler) ConvertToListVO(ctx context.Context, posts []*entity.Pos
 	if err != nil {
 		return nil, err
 	}
-	categoryDTOMap := make(map[int32]*dto.CategoryDTO, 0)
-	for _, categories := range categoryMap {
-		for _, category := range categories {
-			if _, ok := categoryDTOMap[category.ID]; !ok {
-				categoryDTO, err := p.CategoryService.ConvertToCategoryDTO(ctx, category)
-				if err != nil {
-					return nil, err
-				}
-				categoryDTOMap[category.ID] = categoryDTO
-			}
-		}
+	categoryDTOMap, err := p.convertToCategoryDTOMap(ctx, categoryMap)
+	if err != nil {
+		return nil, err
 	}
 
 	commentCountMap, err := p.PostCommentService.CountByStatusAndContentIDs(ctx, consts.CommentStatusPublished, postIDs)
@@ -104,8 +100,17 @@ func (p *postAssembler) ConvertToListVO(ctx context.Context, posts []*entity.Pos
 	if err != nil {
 		return nil, err
 	}
-	for _, post := range posts {
+	fieldsMap, err := p.ConvertToFields(ctx, posts, postMetaMap)
+	if err != nil {
+		return nil, err
+	}
+	postDTOs, err := p.ConvertToSimpleDTOs(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	for i, post := range posts {
 		postVO := &vo.Post{}
+		postVO.Fields = fieldsMap[post.ID]
 		if commentCount, ok := commentCountMap[post.ID]; ok {
 			postVO.CommentCount = commentCount
 		}
@@ -134,11 +139,7 @@ func (p *postAssembler) ConvertToListVO(ctx context.Context, posts []*entity.Pos
 			}
 			postVO.Metas = metaMap
 		}
-		postDTO, err := p.ConvertToSimpleDTO(ctx, post)
-		if err != nil {
-			return nil, err
-		}
-		postVO.Post = *postDTO
+		postVO.Post = *postDTOs[i]
 		postVOs = append(postVOs, postVO)
 	}
 	return postVOs, nil
@@ -201,7 +202,24 @@ func (p *postAssembler) ConvertToDetailVO(ctx context.Context, post *entity.Post
 	}
 	postDetailVO.MetaIDs = metaIDs
 	postDetailVO.Metas = metaDTOs
+	fieldsMap, err := p.ConvertToFields(ctx, []*entity.Post{post}, map[int32][]*entity.Meta{post.ID: postMetas})
+	if err != nil {
+		return nil, err
+	}
+	postDetailVO.Fields = fieldsMap[post.ID]
 
+	series, err := p.SeriesService.GetByPostID(ctx, post.ID)
+	if err != nil && xerr.GetType(err) != xerr.NoRecord {
+		return nil, err
+	}
+	if series != nil {
+		seriesDTO, err := p.SeriesService.ConvertToDTO(ctx, series)
+		if err != nil {
+			return nil, err
+		}
+		postDetailVO.SeriesID = series.ID
+		postDetailVO.Series = seriesDTO
+	}
 	return postDetailVO, nil
 }
 
@@ -231,25 +249,44 @@ func (p *postAssembler) ConvertToDetailVOs(ctx context.Context, posts []*entity.
 	if err != nil {
 		return nil, err
 	}
-	categoryDTOMap := make(map[int32]*dto.CategoryDTO, 0)
-	for _, categories := range categoryMap {
-		for _, category := range categories {
-			if _, ok := categoryDTOMap[category.ID]; !ok {
-				categoryDTO, err := p.CategoryService.ConvertToCategoryDTO(ctx, category)
-				if err != nil {
-					return nil, err
-				}
-				categoryDTOMap[category.ID] = categoryDTO
-			}
-		}
+	categoryDTOMap, err := p.convertToCategoryDTOMap(ctx, categoryMap)
+	if err != nil {
+		return nil, err
 	}
 
 	postMetaMap, err := p.MetaService.GetPostsMeta(ctx, postIDs)
 	if err != nil {
 		return nil, err
 	}
-	for _, post := range posts {
+	fieldsMap, err := p.ConvertToFields(ctx, posts, postMetaMap)
+	if err != nil {
+		return nil, err
+	}
+	seriesMap, err := p.SeriesService.ListSeriesMapByPostID(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	seriesDTOMap := make(map[int32]*dto.Series)
+	for _, series := range seriesMap {
+		if _, ok := seriesDTOMap[series.ID]; !ok {
+			seriesDTO, err := p.SeriesService.ConvertToDTO(ctx, series)
+			if err != nil {
+				return nil, err
+			}
+			seriesDTOMap[series.ID] = seriesDTO
+		}
+	}
+	postDetailDTOs, err := p.ConvertToDetailDTOs(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	for i, post := range posts {
 		postDetailVO := &vo.PostDetailVO{}
+		postDetailVO.Fields = fieldsMap[post.ID]
+		if series, ok := seriesMap[post.ID]; ok {
+			postDetailVO.SeriesID = series.ID
+			postDetailVO.Series = seriesDTOMap[series.ID]
+		}
 		if categories, ok := categoryMap[post.ID]; ok {
 			categoryDTOs := make([]*dto.CategoryDTO, 0)
 			categoryIDs := make([]int32, 0)
@@ -284,11 +321,7 @@ func (p *postAssembler) ConvertToDetailVOs(ctx context.Context, posts []*entity.
 			postDetailVO.Metas = metaDTOs
 			postDetailVO.MetaIDs = metaIDs
 		}
-		postDetailDTO, err := p.ConvertToDetailDTO(ctx, post)
-		if err != nil {
-			return nil, err
-		}
-		postDetailVO.PostDetail = *postDetailDTO
+		postDetailVO.PostDetail = *postDetailDTOs[i]
 		postDetailVOs = append(postDetailVOs, postDetailVO)
 	}
 	return postDetailVOs, nil
@@ -337,3 +370,26 @@ func (p *postAssembler) ConvertTOArchiveMonthVOs(ctx context.Context, posts []*e
 	})
 	return archiveMonthVos, nil
 }
+
+// convertToCategoryDTOMap converts the categories of the posts at once
+func (p *postAssembler) convertToCategoryDTOMap(ctx context.Context, categoryMap map[int32][]*entity.Category) (map[int32]*dto.CategoryDTO, error) {
+	categories := make([]*entity.Category, 0)
+	categoryIDs := make(map[int32]struct{})
+	for _, postCategories := range categoryMap {
+		for _, category := range postCategories {
+			if _, ok := categoryIDs[category.ID]; !ok {
+				categoryIDs[category.ID] = struct{}{}
+				categories = append(categories, category)
+			}
+		}
+	}
+	categoryDTOs, err := p.CategoryService.ConvertToCategoryDTOs(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	categoryDTOMap := make(map[int32]*dto.CategoryDTO, len(categoryDTOs))
+	for _, categoryDTO := range categoryDTOs {
+		categoryDTOMap[categoryDTO.ID] = categoryDTO
+	}
+	return categoryDTOMap, nil
+}
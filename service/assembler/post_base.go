@@ -2,6 +2,7 @@ package assembler
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/model/dto"
@@ -13,21 +14,31 @@ type BasePostAssembler interface {
 	ConvertToSimpleDTO(ctx context.Context, post *entity.Post) (*dto.Post, error)
 	ConvertToMinimalDTO(ctx context.Context, post *entity.Post) (*dto.PostMinimal, error)
 	ConvertToDetailDTO(ctx context.Context, post *entity.Post) (*dto.PostDetail, error)
+	// ConvertToSimpleDTOs, ConvertToMinimalDTOs and ConvertToDetailDTOs convert the lists of posts in the same order,
+	// the full paths of the posts are built at once
+	ConvertToSimpleDTOs(ctx context.Context, posts []*entity.Post) ([]*dto.Post, error)
+	ConvertToMinimalDTOs(ctx context.Context, posts []*entity.Post) ([]*dto.PostMinimal, error)
+	ConvertToDetailDTOs(ctx context.Context, posts []*entity.Post) ([]*dto.PostDetail, error)
+	// ConvertToFields converts the metas of the posts to the typed values of their custom fields
+	ConvertToFields(ctx context.Context, posts []*entity.Post, postMetas map[int32][]*entity.Meta) (map[int32]map[string]interface{}, error)
 }
 
 func NewBasePostAssembler(
 	basePostService service.BasePostService,
 	baseCommentService service.BaseCommentService,
+	customFieldService service.CustomFieldService,
 ) BasePostAssembler {
 	return &basePostAssembler{
 		BasePostService:    basePostService,
 		BaseCommentService: baseCommentService,
+		CustomFieldService: customFieldService,
 	}
 }
 
 type basePostAssembler struct {
 	BasePostService    service.BasePostService
 	BaseCommentService service.BaseCommentService
+	CustomFieldService service.CustomFieldService
 }
 
 func (p *basePostAssembler) ConvertToSimpleDTO(ctx context.Context, post *entity.Post) (*dto.Post, error) {
@@ -35,6 +46,22 @@ func (p *basePostAssembler) ConvertToSimpleDTO(ctx context.Context, post *entity
 	if err != nil {
 		return nil, err
 	}
+	return p.convertToSimpleDTO(ctx, post, postMinimal), nil
+}
+
+func (p *basePostAssembler) ConvertToSimpleDTOs(ctx context.Context, posts []*entity.Post) ([]*dto.Post, error) {
+	postMinimals, err := p.ConvertToMinimalDTOs(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	postDTOs := make([]*dto.Post, 0, len(posts))
+	for i, post := range posts {
+		postDTOs = append(postDTOs, p.convertToSimpleDTO(ctx, post, postMinimals[i]))
+	}
+	return postDTOs, nil
+}
+
+func (p *basePostAssembler) convertToSimpleDTO(ctx context.Context, post *entity.Post, postMinimal *dto.PostMinimal) *dto.Post {
 	postDTO := &dto.Post{
 		Summary:         post.Summary,
 		Thumbnail:       post.Thumbnail,
@@ -52,10 +79,30 @@ func (p *basePostAssembler) ConvertToSimpleDTO(ctx context.Context, post *entity
 	if post.Summary == "" {
 		postDTO.Summary = p.BasePostService.GenerateSummary(ctx, post.FormatContent)
 	}
-	return postDTO, nil
+	return postDTO
 }
 
 func (p *basePostAssembler) ConvertToMinimalDTO(ctx context.Context, post *entity.Post) (*dto.PostMinimal, error) {
+	fullPath, err := p.BasePostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	return convertToMinimalDTO(post, fullPath), nil
+}
+
+func (p *basePostAssembler) ConvertToMinimalDTOs(ctx context.Context, posts []*entity.Post) ([]*dto.PostMinimal, error) {
+	fullPaths, err := p.BasePostService.BuildFullPaths(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	minimalPosts := make([]*dto.PostMinimal, 0, len(posts))
+	for _, post := range posts {
+		minimalPosts = append(minimalPosts, convertToMinimalDTO(post, fullPaths[post.ID]))
+	}
+	return minimalPosts, nil
+}
+
+func convertToMinimalDTO(post *entity.Post, fullPath string) *dto.PostMinimal {
 	minimalPost := &dto.PostMinimal{
 		ID:              post.ID,
 		Title:           post.Title,
@@ -65,6 +112,7 @@ func (p *basePostAssembler) ConvertToMinimalDTO(ctx context.Context, post *entit
 		CreateTime:      post.CreateTime.UnixMilli(),
 		MetaKeywords:    post.MetaKeywords,
 		MetaDescription: post.MetaDescription,
+		FullPath:        fullPath,
 	}
 	if post.EditTime != nil {
 		minimalPost.EditTime = post.EditTime.UnixMilli()
@@ -74,12 +122,7 @@ func (p *basePostAssembler) ConvertToMinimalDTO(ctx context.Context, post *entit
 	} else {
 		minimalPost.UpdateTime = post.CreateTime.UnixMilli()
 	}
-	fullPath, err := p.BasePostService.BuildFullPath(ctx, post)
-	if err != nil {
-		return nil, err
-	}
-	minimalPost.FullPath = fullPath
-	return minimalPost, nil
+	return minimalPost
 }
 
 func (p *basePostAssembler) ConvertToDetailDTO(ctx context.Context, post *entity.Post) (*dto.PostDetail, error) {
@@ -90,6 +133,26 @@ func (p *basePostAssembler) ConvertToDetailDTO(ctx context.Context, post *entity
 	if err != nil {
 		return nil, err
 	}
+	return p.convertToDetailDTO(ctx, post, postSimple)
+}
+
+func (p *basePostAssembler) ConvertToDetailDTOs(ctx context.Context, posts []*entity.Post) ([]*dto.PostDetail, error) {
+	postSimples, err := p.ConvertToSimpleDTOs(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	postDetailDTOs := make([]*dto.PostDetail, 0, len(posts))
+	for i, post := range posts {
+		postDetailDTO, err := p.convertToDetailDTO(ctx, post, postSimples[i])
+		if err != nil {
+			return nil, err
+		}
+		postDetailDTOs = append(postDetailDTOs, postDetailDTO)
+	}
+	return postDetailDTOs, nil
+}
+
+func (p *basePostAssembler) convertToDetailDTO(ctx context.Context, post *entity.Post, postSimple *dto.Post) (*dto.PostDetail, error) {
 	postDetailDTO := &dto.PostDetail{
 		Post:            *postSimple,
 		OriginalContent: post.OriginalContent,
@@ -102,3 +165,84 @@ func (p *basePostAssembler) ConvertToDetailDTO(ctx context.Context, post *entity
 	postDetailDTO.CommentCount = commentCount
 	return postDetailDTO, nil
 }
+
+// ConvertToFields uses the defaults for the fields without values, keeps the values stored before their fields were declared
+// as they are and converts the referenced posts to dto.Post when they are published
+func (p *basePostAssembler) ConvertToFields(ctx context.Context, posts []*entity.Post, postMetas map[int32][]*entity.Meta) (map[int32]map[string]interface{}, error) {
+	result := make(map[int32]map[string]interface{}, len(posts))
+	schemas := make(map[string][]*dto.CustomField)
+	refPostIDs := make([]int32, 0)
+	for _, post := range posts {
+		schemaKey := strconv.Itoa(int(post.Type)) + "/" + post.Template
+		fields, ok := schemas[schemaKey]
+		if !ok {
+			var err error
+			fields, err = p.CustomFieldService.ListSchemas(ctx, post.Type, post.Template)
+			if err != nil {
+				return nil, err
+			}
+			schemas[schemaKey] = fields
+		}
+		values := make(map[string]string)
+		for _, meta := range postMetas[post.ID] {
+			values[meta.MetaKey] = meta.MetaValue
+		}
+		postFields := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			value, ok := values[field.Key]
+			if !ok {
+				value = field.DefaultValue
+			}
+			if value == "" {
+				postFields[field.Key] = nil
+				continue
+			}
+			typedValue, err := p.CustomFieldService.ParseValue(field, value)
+			if err != nil {
+				postFields[field.Key] = value
+				continue
+			}
+			if refPostID, ok := typedValue.(int32); ok {
+				refPostIDs = append(refPostIDs, refPostID)
+			}
+			postFields[field.Key] = typedValue
+		}
+		result[post.ID] = postFields
+	}
+	if len(refPostIDs) == 0 {
+		return result, nil
+	}
+
+	refPosts, err := p.BasePostService.GetByPostIDs(ctx, refPostIDs)
+	if err != nil {
+		return nil, err
+	}
+	publishedRefPosts := make([]*entity.Post, 0, len(refPosts))
+	for _, refPost := range refPosts {
+		if refPost.Status == consts.PostStatusPublished {
+			publishedRefPosts = append(publishedRefPosts, refPost)
+		}
+	}
+	refPostDTOList, err := p.ConvertToSimpleDTOs(ctx, publishedRefPosts)
+	if err != nil {
+		return nil, err
+	}
+	refPostDTOs := make(map[int32]*dto.Post, len(refPostDTOList))
+	for _, refPostDTO := range refPostDTOList {
+		refPostDTOs[refPostDTO.ID] = refPostDTO
+	}
+	for _, postFields := range result {
+		for key, value := range postFields {
+			refPostID, ok := value.(int32)
+			if !ok {
+				continue
+			}
+			if refPostDTO, ok := refPostDTOs[refPostID]; ok {
+				postFields[key] = refPostDTO
+			} else {
+				postFields[key] = nil
+			}
+		}
+	}
+	return result, nil
+}
@@ -53,21 +53,28 @@ func (s *sheetAssembler) ConvertToDetailVO(ctx context.Context, sheet *entity.Po
 		metaDTOs = append(metaDTOs, s.MetaService.ConvertToMetaDTO(meta))
 	}
 
+	fieldsMap, err := s.ConvertToFields(ctx, []*entity.Post{sheet}, map[int32][]*entity.Meta{sheet.ID: metas})
+	if err != nil {
+		return nil, err
+	}
+
 	sheetDetailVO.PostDetail = *detailDTO
 	sheetDetailVO.MetaIDs = metaIDs
 	sheetDetailVO.Metas = metaDTOs
+	sheetDetailVO.Fields = fieldsMap[sheet.ID]
 	return &sheetDetailVO, nil
 }
 
 func (s *sheetAssembler) ConvertToListVO(ctx context.Context, sheets []*entity.Post) ([]*vo.SheetList, error) {
 	sheetListVOs := make([]*vo.SheetList, 0, len(sheets))
 
-	for _, sheet := range sheets {
+	postDTOs, err := s.ConvertToSimpleDTOs(ctx, sheets)
+	if err != nil {
+		return nil, err
+	}
+	for i, sheet := range sheets {
 		var sheetListVO vo.SheetList
-		postDTO, err := s.ConvertToSimpleDTO(ctx, sheet)
-		if err != nil {
-			return nil, err
-		}
+		postDTO := postDTOs[i]
 		commentCount, err := s.SheetCommentService.CountByContentID(ctx, sheet.ID, consts.CommentTypeSheet, consts.CommentStatusPublished)
 		if err != nil {
 			return nil, err
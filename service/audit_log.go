@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type AuditLogService interface {
+	Create(ctx context.Context, auditLog *entity.AuditLog) error
+	Page(ctx context.Context, auditLogQuery param.AuditLogQuery) ([]*entity.AuditLog, int64, error)
+	// ExportCSV writes all audit logs matching the query filters, ignoring the page
+	ExportCSV(ctx context.Context, auditLogQuery param.AuditLogQuery, writer io.Writer) error
+	ConvertToDTO(auditLog *entity.AuditLog) *dto.AuditLog
+	Clear(ctx context.Context) error
+}
@@ -22,8 +22,18 @@ type BackupService interface {
 	ExportData(ctx context.Context) (*dto.BackupDTO, error)
 	// ImportMarkdown import markdown file as post
 	ImportMarkdown(ctx context.Context, fileHeader *multipart.FileHeader) error
-	// ExportMarkdown export posts to markdown files
-	ExportMarkdown(ctx context.Context, needFrontMatter bool) (*dto.BackupDTO, error)
+	// ImportMarkdownBundle import the zip exported by ExportMarkdown with the attachments
+	ImportMarkdownBundle(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ImportReport, error)
+	// ImportWordPress import the WordPress export file, with the zipped uploads directory optionally
+	ImportWordPress(ctx context.Context, fileHeader, uploadsHeader *multipart.FileHeader) (*dto.ImportReport, error)
+	// ImportHugo import the zipped Hugo site
+	ImportHugo(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ImportReport, error)
+	// ImportHexo import the zipped Hexo site
+	ImportHexo(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ImportReport, error)
+	// ImportHalo import the data exported by Halo, with the zipped upload directory optionally
+	ImportHalo(ctx context.Context, fileHeader, uploadsHeader *multipart.FileHeader) (*dto.ImportReport, error)
+	// ExportMarkdown export posts to markdown files, or export the site with the attachments to the bundle which can be imported back
+	ExportMarkdown(ctx context.Context, needFrontMatter, withAttachments bool) (*dto.BackupDTO, error)
 	ListToBackupItems(ctx context.Context) ([]string, error)
 }
 
@@ -15,6 +15,8 @@ type BasePostService interface {
 	GetBySlug(ctx context.Context, slug string) (*entity.Post, error)
 	GenerateSummary(ctx context.Context, htmlContent string) string
 	BuildFullPath(ctx context.Context, post *entity.Post) (string, error)
+	// BuildFullPaths builds the full paths of the posts by their ids, it is for the lists of posts
+	BuildFullPaths(ctx context.Context, posts []*entity.Post) (map[int32]string, error)
 	Delete(ctx context.Context, postID int32) error
 	DeleteBatch(ctx context.Context, postIDs []int32) error
 	UpdateDraftContent(ctx context.Context, postID int32, content, originalContent string) (*entity.Post, error)
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type CustomFieldService interface {
+	// List lists the fields declared by the admins
+	List(ctx context.Context) ([]*entity.CustomField, error)
+	GetByID(ctx context.Context, id int32) (*entity.CustomField, error)
+	Create(ctx context.Context, fieldParam *param.CustomField) (*entity.CustomField, error)
+	Update(ctx context.Context, id int32, fieldParam *param.CustomField) (*entity.CustomField, error)
+	Delete(ctx context.Context, id int32) error
+	// ListSchemas lists the fields of the post type and template declared by the activated theme and the admins,
+	// a field declared by the admins takes the place of the theme one with the same key
+	ListSchemas(ctx context.Context, postType consts.PostType, template string) ([]*dto.CustomField, error)
+	// Validate checks the metas of the post against its fields and returns them with the values normalized,
+	// the required fields without a default are only enforced once the post is not a draft and not imported
+	Validate(ctx context.Context, post *entity.Post, metas []param.Meta) ([]param.Meta, error)
+	// ParseValue converts the value of the field to its type: float64 for NUMBER, time.Time for DATE,
+	// int32 for POST and string for the others
+	ParseValue(field *dto.CustomField, value string) (interface{}, error)
+	// ListPostIDsByFilters lists the posts whose metas match all the filters, a filter value can be
+	// prefixed with one of the operators eq:, ne:, gt:, gte:, lt: and lte:, the values are compared by the field types
+	ListPostIDsByFilters(ctx context.Context, postType consts.PostType, filters map[string]string) ([]int32, error)
+	ConvertToDTO(field *entity.CustomField) (*dto.CustomField, error)
+	ConvertToDTOs(fields []*entity.CustomField) ([]*dto.CustomField, error)
+}
@@ -3,11 +3,32 @@ package service
 import (
 	"context"
 	"io"
+	"io/fs"
 
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
 )
 
 type ExportImport interface {
 	CreateByMarkdown(ctx context.Context, filename string, reader io.Reader) (*entity.Post, error)
+	// ParseMarkdown parses the front matter and the content of the Markdown file into the post,
+	// the categories and tags in the front matter are created if they do not exist
+	ParseMarkdown(ctx context.Context, filename string, reader io.Reader) (*param.Post, error)
+	// RenderMarkdown renders the post into the Markdown file with the front matter parsed by ParseMarkdown
+	RenderMarkdown(ctx context.Context, post *entity.Post) (string, error)
 	ExportMarkdown(ctx context.Context, needFrontMatter bool) (string, error)
+	// ExportMarkdownBundle exports the posts, sheets and journals with their comments and the attachments they refer to
+	ExportMarkdownBundle(ctx context.Context) (string, error)
+	// ImportMarkdownBundle imports the bundle exported by ExportMarkdownBundle
+	ImportMarkdownBundle(ctx context.Context, bundle fs.FS) (*dto.ImportReport, error)
+	// ImportWordPress imports the posts, pages, terms, comments and media of the WordPress eXtended RSS,
+	// the media are copied from the uploads directory if it is not nil, or downloaded
+	ImportWordPress(ctx context.Context, reader io.Reader, uploads fs.FS) (*dto.ImportReport, error)
+	// ImportHugo imports the Markdown pages with their bundled or static media of the Hugo site
+	ImportHugo(ctx context.Context, site fs.FS) (*dto.ImportReport, error)
+	// ImportHexo imports the posts, drafts and pages with their asset folders of the Hexo site
+	ImportHexo(ctx context.Context, site fs.FS) (*dto.ImportReport, error)
+	// ImportHalo imports the data exported by Halo 1.x, the media are copied from the uploads directory if it is not nil, or downloaded
+	ImportHalo(ctx context.Context, reader io.Reader, uploads fs.FS) (*dto.ImportReport, error)
 }
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+type GitSyncService interface {
+	// Pull creates and updates the posts by the Markdown files of the repository, the posts are matched by slug
+	Pull(ctx context.Context) (*dto.GitSyncReport, error)
+	// Push commits all the published posts changed since the last sync to the repository
+	Push(ctx context.Context) (*dto.GitSyncReport, error)
+	// PushPost commits the post to the repository if it is published
+	PushPost(ctx context.Context, postID int32) error
+	// Resolve resolves the conflict of the post by overwriting the file with the post, or the post with the file
+	Resolve(ctx context.Context, postID int32, keepPost bool) error
+	Status(ctx context.Context) (*dto.GitSyncStatus, error)
+	// VerifyWebhook verifies the signature or the token of the push webhook by the secret
+	VerifyWebhook(ctx context.Context, header http.Header, body []byte) error
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+type HealthService interface {
+	// Liveness only tells that the process is able to serve requests
+	Liveness(ctx context.Context) *dto.Health
+	// Readiness checks the database, the writable directories and the templates
+	Readiness(ctx context.Context) *dto.Health
+}
@@ -0,0 +1,855 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/httpsig"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+var errActorGone = errors.New("the actor is gone")
+
+type activityPubServiceImpl struct {
+	OptionService      service.OptionService
+	UserService        service.UserService
+	PostService        service.PostService
+	PostTagService     service.PostTagService
+	TagService         service.TagService
+	BaseCommentService service.BaseCommentService
+	ShortcodeService   service.ShortcodeService
+	client             *http.Client
+	keyMutex           sync.Mutex
+	privateKey         *rsa.PrivateKey
+}
+
+func NewActivityPubService(
+	optionService service.OptionService,
+	userService service.UserService,
+	postService service.PostService,
+	postTagService service.PostTagService,
+	tagService service.TagService,
+	baseCommentService service.BaseCommentService,
+	shortcodeService service.ShortcodeService,
+) service.ActivityPubService {
+	return &activityPubServiceImpl{
+		OptionService:      optionService,
+		UserService:        userService,
+		PostService:        postService,
+		PostTagService:     postTagService,
+		TagService:         tagService,
+		BaseCommentService: baseCommentService,
+		ShortcodeService:   shortcodeService,
+		client:             newPublicHTTPClient(consts.ActivityPubTimeout),
+	}
+}
+
+// remoteActor is the actor of the other servers, only the used properties are decoded
+type remoteActor struct {
+	ID                string          `json:"id"`
+	PreferredUsername string          `json:"preferredUsername"`
+	Name              string          `json:"name"`
+	URL               json.RawMessage `json:"url"`
+	Icon              json.RawMessage `json:"icon"`
+	Inbox             string          `json:"inbox"`
+	Endpoints         struct {
+		SharedInbox string `json:"sharedInbox"`
+	} `json:"endpoints"`
+	PublicKey struct {
+		ID           string `json:"id"`
+		Owner        string `json:"owner"`
+		PublicKeyPem string `json:"publicKeyPem"`
+	} `json:"publicKey"`
+}
+
+type inboxActivity struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  json.RawMessage `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+type remoteNote struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	InReplyTo json.RawMessage `json:"inReplyTo"`
+	Content   string          `json:"content"`
+}
+
+func (a *activityPubServiceImpl) WebFinger(ctx context.Context, resource string) (*dto.WebFinger, error) {
+	if err := a.checkEnabled(ctx); err != nil {
+		return nil, err
+	}
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	username, err := a.username(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(blogURL)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("Invalid blog url")
+	}
+	subject := "acct:" + username + "@" + u.Host
+	if !strings.EqualFold(strings.TrimPrefix(resource, "acct:"), strings.TrimPrefix(subject, "acct:")) &&
+		resource != actorURL && strings.TrimSuffix(resource, "/") != blogURL {
+		return nil, xerr.NoRecord.New("webfinger resource not exist resource=%s", resource).WithStatus(xerr.StatusNotFound).WithMsg("Resource not found")
+	}
+	return &dto.WebFinger{
+		Subject: subject,
+		Aliases: []string{actorURL, blogURL},
+		Links: []*dto.WebFingerLink{
+			{Rel: "self", Type: "application/activity+json", Href: actorURL},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: blogURL},
+		},
+	}, nil
+}
+
+func (a *activityPubServiceImpl) Actor(ctx context.Context) (*dto.ActivityPubActor, error) {
+	if err := a.checkEnabled(ctx); err != nil {
+		return nil, err
+	}
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	username, err := a.username(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := a.key(ctx)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPem, err := httpsig.EncodePublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	baseURL := blogURL + consts.ActivityPubPath
+	actor := &dto.ActivityPubActor{
+		Context:           []string{consts.ActivityStreamsContext, "https://w3id.org/security/v1"},
+		ID:                actorURL,
+		Type:              "Person",
+		PreferredUsername: username,
+		Name:              util.IfElse(user.Nickname == "", user.Username, user.Nickname).(string),
+		Summary:           html.EscapeString(user.Description),
+		URL:               blogURL,
+		Inbox:             baseURL + "/inbox",
+		Outbox:            baseURL + "/outbox",
+		Followers:         baseURL + "/followers",
+		Endpoints:         &dto.ActivityPubEndpoints{SharedInbox: baseURL + "/inbox"},
+		PublicKey: &dto.ActivityPubPublicKey{
+			ID:           actorURL + "#main-key",
+			Owner:        actorURL,
+			PublicKeyPem: publicKeyPem,
+		},
+		Discoverable: true,
+	}
+	if user.Avatar != "" {
+		actor.Icon = &dto.ActivityPubImage{Type: "Image", URL: absoluteURL(blogURL, user.Avatar)}
+	}
+	return actor, nil
+}
+
+func (a *activityPubServiceImpl) Outbox(ctx context.Context, page int) (*dto.ActivityPubCollection, error) {
+	if err := a.checkEnabled(ctx); err != nil {
+		return nil, err
+	}
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	outboxURL := blogURL + consts.ActivityPubPath + "/outbox"
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	journalDAL := dal.GetQueryByCtx(ctx).Journal
+	postDO := postDAL.WithContext(ctx).Where(postDAL.Type.Eq(consts.PostTypePost), postDAL.Status.Eq(consts.PostStatusPublished), postDAL.Password.Eq(""))
+	journalDO := journalDAL.WithContext(ctx).Where(journalDAL.Type.Eq(consts.JournalTypePublic))
+	postCount, err := postDO.Count()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	journalCount, err := journalDO.Count()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	total := postCount + journalCount
+	if page <= 0 {
+		lastPage := (total + consts.ActivityPubPageSize - 1) / consts.ActivityPubPageSize
+		return &dto.ActivityPubCollection{
+			Context:    consts.ActivityStreamsContext,
+			ID:         outboxURL,
+			Type:       "OrderedCollection",
+			TotalItems: total,
+			First:      outboxURL + "?page=1",
+			Last:       outboxURL + "?page=" + strconv.FormatInt(util.IfElse(lastPage == 0, int64(1), lastPage).(int64), 10),
+		}, nil
+	}
+
+	// posts and journals are merged by the create time, so the first page*size of both are needed
+	limit := page * consts.ActivityPubPageSize
+	collection := &dto.ActivityPubCollection{
+		Context:    consts.ActivityStreamsContext,
+		ID:         outboxURL + "?page=" + strconv.Itoa(page),
+		Type:       "OrderedCollectionPage",
+		TotalItems: total,
+		PartOf:     outboxURL,
+	}
+	if page > 1 {
+		collection.Prev = outboxURL + "?page=" + strconv.Itoa(page-1)
+	}
+	if int64(limit-consts.ActivityPubPageSize) >= total {
+		return collection, nil
+	}
+	if int64(limit) < total {
+		collection.Next = outboxURL + "?page=" + strconv.Itoa(page+1)
+	}
+	posts, err := postDO.Order(postDAL.CreateTime.Desc()).Limit(limit).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	journals, err := journalDO.Order(journalDAL.CreateTime.Desc()).Limit(limit).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	type outboxItem struct {
+		createTime time.Time
+		post       *entity.Post
+		journal    *entity.Journal
+	}
+	items := make([]*outboxItem, 0, len(posts)+len(journals))
+	for _, post := range posts {
+		items = append(items, &outboxItem{createTime: post.CreateTime, post: post})
+	}
+	for _, journal := range journals {
+		items = append(items, &outboxItem{createTime: journal.CreateTime, journal: journal})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].createTime.After(items[j].createTime)
+	})
+	end := util.IfElse(limit > len(items), len(items), limit).(int)
+	for _, item := range items[limit-consts.ActivityPubPageSize : end] {
+		var object *dto.ActivityPubObject
+		if item.post != nil {
+			object, err = a.convertPost(ctx, item.post, blogURL, actorURL)
+		} else {
+			object, err = a.convertJournal(ctx, item.journal, blogURL, actorURL)
+		}
+		if err != nil {
+			return nil, err
+		}
+		collection.OrderedItems = append(collection.OrderedItems, a.createActivity(object, actorURL))
+	}
+	return collection, nil
+}
+
+func (a *activityPubServiceImpl) Followers(ctx context.Context) (*dto.ActivityPubCollection, error) {
+	if err := a.checkEnabled(ctx); err != nil {
+		return nil, err
+	}
+	blogURL, err := a.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	followerDAL := dal.GetQueryByCtx(ctx).Follower
+	count, err := followerDAL.WithContext(ctx).Count()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	// the followers are not listed for their privacy
+	return &dto.ActivityPubCollection{
+		Context:    consts.ActivityStreamsContext,
+		ID:         blogURL + consts.ActivityPubPath + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: count,
+	}, nil
+}
+
+func (a *activityPubServiceImpl) PostObject(ctx context.Context, postID int32) (*dto.ActivityPubObject, error) {
+	if err := a.checkEnabled(ctx); err != nil {
+		return nil, err
+	}
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	post, err := a.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Type != consts.PostTypePost || post.Status != consts.PostStatusPublished || post.Password != "" {
+		return nil, xerr.NoRecord.New("post not exist postID=%d", postID).WithStatus(xerr.StatusNotFound).WithMsg("Post not found")
+	}
+	object, err := a.convertPost(ctx, post, blogURL, actorURL)
+	if err != nil {
+		return nil, err
+	}
+	object.Context = consts.ActivityStreamsContext
+	return object, nil
+}
+
+func (a *activityPubServiceImpl) JournalObject(ctx context.Context, journalID int32) (*dto.ActivityPubObject, error) {
+	if err := a.checkEnabled(ctx); err != nil {
+		return nil, err
+	}
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	journalDAL := dal.GetQueryByCtx(ctx).Journal
+	journal, err := journalDAL.WithContext(ctx).Where(journalDAL.ID.Eq(journalID), journalDAL.Type.Eq(consts.JournalTypePublic)).First()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	object, err := a.convertJournal(ctx, journal, blogURL, actorURL)
+	if err != nil {
+		return nil, err
+	}
+	object.Context = consts.ActivityStreamsContext
+	return object, nil
+}
+
+func (a *activityPubServiceImpl) HandleInbox(ctx context.Context, req *http.Request, body []byte) error {
+	if err := a.checkEnabled(ctx); err != nil {
+		return err
+	}
+	var activity inboxActivity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Invalid activity")
+	}
+	actorID := idOf(activity.Actor)
+	signature, err := httpsig.Parse(req)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusUnauthorized).WithMsg("Invalid signature")
+	}
+	keyOwner, _, _ := strings.Cut(signature.KeyID, "#")
+	actor, err := a.fetchActor(ctx, keyOwner)
+	if errors.Is(err, errActorGone) && activity.Type == "Delete" && keyOwner == actorID && idOf(activity.Object) == actorID {
+		// the deleted actor can not be fetched any more, the gone actor proves the deletion
+		return a.deleteFollowerByActor(ctx, actorID)
+	}
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusUnauthorized).WithMsg("Failed to fetch the key of the signature")
+	}
+	publicKey, err := httpsig.ParsePublicKey(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusUnauthorized).WithMsg("Invalid public key")
+	}
+	if err := signature.Verify(req, body, publicKey, consts.ActivityPubSignatureMaxAge); err != nil {
+		return xerr.WithStatus(err, xerr.StatusUnauthorized).WithMsg("Invalid signature")
+	}
+	if actor.ID != actorID {
+		return xerr.Forbidden.New("activity actor %s is not the key owner %s", actorID, actor.ID).WithStatus(xerr.StatusForbidden).WithMsg("The actor is not the signer")
+	}
+
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return err
+	}
+	switch activity.Type {
+	case "Follow":
+		if idOf(activity.Object) != actorURL {
+			return nil
+		}
+		if err := a.saveFollower(ctx, actor); err != nil {
+			return err
+		}
+		go a.accept(actorURL, actor, body)
+	case "Undo":
+		var undone inboxActivity
+		if err := json.Unmarshal(activity.Object, &undone); err == nil && undone.Type == "Follow" {
+			return a.deleteFollowerByActor(ctx, actorID)
+		}
+	case "Delete":
+		if idOf(activity.Object) == actorID {
+			return a.deleteFollowerByActor(ctx, actorID)
+		}
+	case "Create":
+		var note remoteNote
+		if err := json.Unmarshal(activity.Object, &note); err != nil || note.Type != "Note" {
+			return nil
+		}
+		return a.saveReply(ctx, blogURL, actor, &note)
+	}
+	return nil
+}
+
+func (a *activityPubServiceImpl) Deliver(ctx context.Context, post *entity.Post) error {
+	if !a.OptionService.GetOrByDefault(ctx, property.ActivityPubEnabled).(bool) {
+		return nil
+	}
+	if post.Type != consts.PostTypePost || post.Status != consts.PostStatusPublished || post.Password != "" {
+		return nil
+	}
+	blogURL, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return err
+	}
+	followerDAL := dal.GetQueryByCtx(ctx).Follower
+	followers, err := followerDAL.WithContext(ctx).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if len(followers) == 0 {
+		return nil
+	}
+	object, err := a.convertPost(ctx, post, blogURL, actorURL)
+	if err != nil {
+		return err
+	}
+	// the servers ignore the Create of a known object, so the updated posts are delivered again safely
+	activity := a.createActivity(object, actorURL)
+	activity.Context = consts.ActivityStreamsContext
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	inboxes := make(map[string]struct{})
+	var deliverErr error
+	for _, follower := range followers {
+		inbox := util.IfElse(follower.SharedInbox != "", follower.SharedInbox, follower.Inbox).(string)
+		if _, ok := inboxes[inbox]; ok {
+			continue
+		}
+		inboxes[inbox] = struct{}{}
+		if err := a.post(ctx, actorURL, inbox, body); err != nil {
+			log.CtxWarn(ctx, "deliver activity err", zap.String("inbox", inbox), zap.Error(err))
+			deliverErr = err
+		}
+	}
+	return deliverErr
+}
+
+func (a *activityPubServiceImpl) PageFollower(ctx context.Context, page param.Page) ([]*entity.Follower, int64, error) {
+	if page.PageNum < 0 || page.PageSize <= 0 || page.PageSize > 100 {
+		return nil, 0, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("Paging parameter error")
+	}
+	followerDAL := dal.GetQueryByCtx(ctx).Follower
+	followers, totalCount, err := followerDAL.WithContext(ctx).Order(followerDAL.CreateTime.Desc()).FindByPage(page.PageNum*page.PageSize, page.PageSize)
+	if err != nil {
+		return nil, 0, WrapDBErr(err)
+	}
+	return followers, totalCount, nil
+}
+
+func (a *activityPubServiceImpl) DeleteFollower(ctx context.Context, followerID int32) error {
+	followerDAL := dal.GetQueryByCtx(ctx).Follower
+	deleteResult, err := followerDAL.WithContext(ctx).Where(followerDAL.ID.Eq(followerID)).Delete()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if deleteResult.RowsAffected != 1 {
+		return xerr.NoRecord.New("follower not exist id=%d", followerID).WithStatus(xerr.StatusNotFound).WithMsg("Follower not found")
+	}
+	return nil
+}
+
+func (a *activityPubServiceImpl) ConvertToFollowerDTO(follower *entity.Follower) *dto.Follower {
+	return &dto.Follower{
+		ID:         follower.ID,
+		ActorID:    follower.ActorID,
+		Username:   follower.Username,
+		Name:       follower.Name,
+		URL:        follower.URL,
+		Avatar:     follower.Avatar,
+		Inbox:      follower.Inbox,
+		CreateTime: follower.CreateTime.UnixMilli(),
+	}
+}
+
+func (a *activityPubServiceImpl) checkEnabled(ctx context.Context) error {
+	if !a.OptionService.GetOrByDefault(ctx, property.ActivityPubEnabled).(bool) {
+		return xerr.NoRecord.New("activitypub disabled").WithStatus(xerr.StatusNotFound).WithMsg("Not found")
+	}
+	return nil
+}
+
+// urls returns the blog url and the id of the actor
+func (a *activityPubServiceImpl) urls(ctx context.Context) (string, string, error) {
+	blogURL, err := a.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return blogURL, blogURL + consts.ActivityPubPath + "/actor", nil
+}
+
+func (a *activityPubServiceImpl) owner(ctx context.Context) (*entity.User, error) {
+	users, err := a.UserService.GetAllUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, xerr.NoRecord.New("user not exist").WithStatus(xerr.StatusNotFound).WithMsg("User not found")
+	}
+	return users[0], nil
+}
+
+func (a *activityPubServiceImpl) username(ctx context.Context) (string, error) {
+	if username := a.OptionService.GetOrByDefault(ctx, property.ActivityPubUsername).(string); username != "" {
+		return username, nil
+	}
+	user, err := a.owner(ctx)
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+// key returns the private key of the actor, it is generated and saved at the first use
+func (a *activityPubServiceImpl) key(ctx context.Context) (*rsa.PrivateKey, error) {
+	a.keyMutex.Lock()
+	defer a.keyMutex.Unlock()
+	if a.privateKey != nil {
+		return a.privateKey, nil
+	}
+	if privateKeyPem := a.OptionService.GetOrByDefault(ctx, property.ActivityPubPrivateKey).(string); privateKeyPem != "" {
+		privateKey, err := httpsig.ParsePrivateKey(privateKeyPem)
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("Invalid ActivityPub private key")
+		}
+		a.privateKey = privateKey
+		return privateKey, nil
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	privateKeyPem, err := httpsig.EncodePrivateKey(privateKey)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	err = a.OptionService.Save(ctx, map[string]string{property.ActivityPubPrivateKey.KeyValue: privateKeyPem})
+	if err != nil {
+		return nil, err
+	}
+	a.privateKey = privateKey
+	return privateKey, nil
+}
+
+func (a *activityPubServiceImpl) convertPost(ctx context.Context, post *entity.Post, blogURL, actorURL string) (*dto.ActivityPubObject, error) {
+	fullPath, err := a.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	content, err := a.ShortcodeService.Render(ctx, post.FormatContent)
+	if err != nil {
+		return nil, err
+	}
+	object := &dto.ActivityPubObject{
+		ID:           blogURL + consts.ActivityPubPath + "/posts/" + strconv.Itoa(int(post.ID)),
+		Type:         "Article",
+		AttributedTo: actorURL,
+		Name:         post.Title,
+		Content:      content,
+		URL:          absoluteURL(blogURL, fullPath),
+		Published:    post.CreateTime.UTC().Format(time.RFC3339),
+		To:           []string{consts.ActivityStreamsPublic},
+		Cc:           []string{blogURL + consts.ActivityPubPath + "/followers"},
+	}
+	if post.EditTime != nil && post.EditTime.After(post.CreateTime) {
+		object.Updated = post.EditTime.UTC().Format(time.RFC3339)
+	}
+	if post.Thumbnail != "" {
+		object.Attachment = []*dto.ActivityPubImage{{Type: "Image", URL: absoluteURL(blogURL, post.Thumbnail)}}
+	}
+	tags, err := a.PostTagService.ListTagByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	tagDTOs, err := a.TagService.ConvertToDTOs(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tagDTOs {
+		object.Tag = append(object.Tag, &dto.ActivityPubTag{
+			Type: "Hashtag",
+			Href: absoluteURL(blogURL, tag.FullPath),
+			Name: "#" + strings.Join(strings.Fields(tag.Name), ""),
+		})
+	}
+	return object, nil
+}
+
+func (a *activityPubServiceImpl) convertJournal(ctx context.Context, journal *entity.Journal, blogURL, actorURL string) (*dto.ActivityPubObject, error) {
+	journalPrefix, err := a.OptionService.GetJournalPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	object := &dto.ActivityPubObject{
+		ID:           blogURL + consts.ActivityPubPath + "/journals/" + strconv.Itoa(int(journal.ID)),
+		Type:         "Note",
+		AttributedTo: actorURL,
+		Content:      journal.Content,
+		URL:          absoluteURL(blogURL, journalPrefix),
+		Published:    journal.CreateTime.UTC().Format(time.RFC3339),
+		To:           []string{consts.ActivityStreamsPublic},
+		Cc:           []string{blogURL + consts.ActivityPubPath + "/followers"},
+	}
+	if journal.UpdateTime != nil && journal.UpdateTime.After(journal.CreateTime) {
+		object.Updated = journal.UpdateTime.UTC().Format(time.RFC3339)
+	}
+	return object, nil
+}
+
+func (a *activityPubServiceImpl) createActivity(object *dto.ActivityPubObject, actorURL string) *dto.ActivityPubActivity {
+	return &dto.ActivityPubActivity{
+		ID:        object.ID + "#create",
+		Type:      "Create",
+		Actor:     actorURL,
+		Published: object.Published,
+		To:        object.To,
+		Cc:        object.Cc,
+		Object:    object,
+	}
+}
+
+func (a *activityPubServiceImpl) saveFollower(ctx context.Context, actor *remoteActor) error {
+	if actor.Inbox == "" {
+		return xerr.BadParam.New("actor without inbox actor=%s", actor.ID).WithStatus(xerr.StatusBadRequest).WithMsg("The actor has no inbox")
+	}
+	follower := &entity.Follower{
+		ActorID:     actor.ID,
+		Inbox:       actor.Inbox,
+		SharedInbox: actor.Endpoints.SharedInbox,
+		Username:    truncateRunes(actor.PreferredUsername, 255),
+		Name:        truncateRunes(actor.Name, 255),
+		URL:         truncateRunes(util.IfElse(urlOf(actor.URL) != "", urlOf(actor.URL), actor.ID).(string), 1023),
+		Avatar:      truncateRunes(urlOf(actor.Icon), 1023),
+	}
+	followerDAL := dal.GetQueryByCtx(ctx).Follower
+	existing, err := followerDAL.WithContext(ctx).Where(followerDAL.ActorID.Eq(actor.ID)).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if len(existing) > 0 {
+		_, err = followerDAL.WithContext(ctx).Where(followerDAL.ID.Eq(existing[0].ID)).
+			Select(followerDAL.Inbox, followerDAL.SharedInbox, followerDAL.Username, followerDAL.Name, followerDAL.URL, followerDAL.Avatar).Updates(follower)
+		return WrapDBErr(err)
+	}
+	return WrapDBErr(followerDAL.WithContext(ctx).Create(follower))
+}
+
+func (a *activityPubServiceImpl) deleteFollowerByActor(ctx context.Context, actorID string) error {
+	followerDAL := dal.GetQueryByCtx(ctx).Follower
+	_, err := followerDAL.WithContext(ctx).Where(followerDAL.ActorID.Eq(actorID)).Delete()
+	return WrapDBErr(err)
+}
+
+// accept sends the Accept of the Follow to the follower
+func (a *activityPubServiceImpl) accept(actorURL string, actor *remoteActor, follow []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), consts.ActivityPubTimeout*2)
+	defer cancel()
+	body, err := json.Marshal(&dto.ActivityPubActivity{
+		Context: consts.ActivityStreamsContext,
+		ID:      actorURL + "#accepts/follows/" + util.GenUUIDWithOutDash(),
+		Type:    "Accept",
+		Actor:   actorURL,
+		Object:  json.RawMessage(follow),
+	})
+	if err == nil {
+		err = a.post(ctx, actorURL, actor.Inbox, body)
+	}
+	if err != nil {
+		log.CtxWarn(ctx, "accept follow err", zap.String("actor", actor.ID), zap.Error(err))
+	}
+}
+
+// saveReply saves the reply to a post or a journal as a comment
+func (a *activityPubServiceImpl) saveReply(ctx context.Context, blogURL string, actor *remoteActor, note *remoteNote) error {
+	if !a.OptionService.GetOrByDefault(ctx, property.ActivityPubReplyEnabled).(bool) {
+		return nil
+	}
+	inReplyTo := idOf(note.InReplyTo)
+	objectType, rawID, ok := strings.Cut(strings.TrimPrefix(inReplyTo, blogURL+consts.ActivityPubPath+"/"), "/")
+	if !ok || !strings.HasPrefix(inReplyTo, blogURL) {
+		return nil
+	}
+	contentID, err := strconv.ParseInt(rawID, 10, 32)
+	if err != nil {
+		return nil
+	}
+	comment := &entity.Comment{
+		Author:    truncateRunes(util.IfElse(actor.Name != "", actor.Name, actor.PreferredUsername).(string), 50),
+		AuthorURL: truncateRunes(util.IfElse(urlOf(actor.URL) != "", urlOf(actor.URL), actor.ID).(string), 255),
+		Content:   truncateRunes(strings.TrimSpace(html.UnescapeString(util.CleanHTMLTag(note.Content))), 1023),
+		PostID:    int32(contentID),
+	}
+	switch objectType {
+	case "posts":
+		comment.Type = consts.CommentTypePost
+		post, err := a.PostService.GetByPostID(ctx, comment.PostID)
+		if err != nil || post.Type != consts.PostTypePost || post.Status != consts.PostStatusPublished || post.DisallowComment {
+			return nil
+		}
+	case "journals":
+		comment.Type = consts.CommentTypeJournal
+		journalDAL := dal.GetQueryByCtx(ctx).Journal
+		if _, err := journalDAL.WithContext(ctx).Where(journalDAL.ID.Eq(comment.PostID), journalDAL.Type.Eq(consts.JournalTypePublic)).First(); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if comment.Content == "" {
+		return nil
+	}
+	// the activities may be delivered more than once
+	commentDAL := dal.GetQueryByCtx(ctx).Comment
+	count, err := commentDAL.WithContext(ctx).Where(commentDAL.Type.Eq(comment.Type), commentDAL.PostID.Eq(comment.PostID),
+		commentDAL.AuthorURL.Eq(comment.AuthorURL), commentDAL.Content.Eq(comment.Content)).Count()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err = a.BaseCommentService.Create(ctx, comment)
+	return err
+}
+
+func (a *activityPubServiceImpl) fetchActor(ctx context.Context, actorURL string) (*remoteActor, error) {
+	if _, err := parseMentionURL(actorURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, actorURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", consts.ActivityPubAccept)
+	// the servers in the secure mode only respond to the signed requests
+	if err := a.sign(ctx, req, nil); err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusNotFound {
+		return nil, errActorGone
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerr.NoType.New("fetch actor %s failed status=%d", actorURL, resp.StatusCode)
+	}
+	actor := &remoteActor{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, consts.ActivityPubFetchLimit)).Decode(actor); err != nil {
+		return nil, err
+	}
+	// the document must be the actor at the url, otherwise any server could claim to be any actor with its own key
+	if actor.ID != actorURL {
+		return nil, xerr.NoType.New("the actor fetched from %s claims to be %s", actorURL, actor.ID)
+	}
+	if actor.PublicKey.Owner != "" && actor.PublicKey.Owner != actor.ID {
+		return nil, xerr.NoType.New("the key of %s is owned by %s", actor.ID, actor.PublicKey.Owner)
+	}
+	return actor, nil
+}
+
+func (a *activityPubServiceImpl) post(ctx context.Context, actorURL, inbox string, body []byte) error {
+	if _, err := parseMentionURL(inbox); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", consts.ActivityPubContentType)
+	if err := a.sign(ctx, req, body); err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return xerr.NoType.New("post activity to %s failed status=%d", inbox, resp.StatusCode)
+	}
+	return nil
+}
+
+func (a *activityPubServiceImpl) sign(ctx context.Context, req *http.Request, body []byte) error {
+	key, err := a.key(ctx)
+	if err != nil {
+		return err
+	}
+	_, actorURL, err := a.urls(ctx)
+	if err != nil {
+		return err
+	}
+	return httpsig.Sign(req, actorURL+"#main-key", key, body)
+}
+
+// idOf returns the id of a property which is an id, an object or an array of them
+func idOf(raw json.RawMessage) string {
+	var id string
+	if json.Unmarshal(raw, &id) == nil {
+		return id
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(raw, &object) == nil {
+		return object.ID
+	}
+	var array []json.RawMessage
+	if json.Unmarshal(raw, &array) == nil && len(array) > 0 {
+		return idOf(array[0])
+	}
+	return ""
+}
+
+// urlOf returns the url of a property which is an url, a Link, an Image or an array of them
+func urlOf(raw json.RawMessage) string {
+	var u string
+	if json.Unmarshal(raw, &u) == nil {
+		return u
+	}
+	var object struct {
+		URL  json.RawMessage `json:"url"`
+		Href string          `json:"href"`
+	}
+	if json.Unmarshal(raw, &object) == nil {
+		if object.Href != "" {
+			return object.Href
+		}
+		if len(object.URL) > 0 {
+			return urlOf(object.URL)
+		}
+		return ""
+	}
+	var array []json.RawMessage
+	if json.Unmarshal(raw, &array) == nil && len(array) > 0 {
+		return urlOf(array[0])
+	}
+	return ""
+}
@@ -36,9 +36,13 @@ type adminServiceImpl struct {
 	Event            event.Bus
 	TwoFactorTOTPMFA service.TwoFactorTOTPMFAService
 	EmailService     service.EmailService
+	OIDCService      service.OIDCService
+	LoginProtect     service.LoginProtectService
 }
 
-func NewAdminService(userService service.UserService, cache cache.Cache, config *config.Config, event event.Bus, twoFactorMFA service.TwoFactorTOTPMFAService, emailService service.EmailService) service.AdminService {
+func NewAdminService(userService service.UserService, cache cache.Cache, config *config.Config, event event.Bus, twoFactorMFA service.TwoFactorTOTPMFAService, emailService service.EmailService, oidcService service.OIDCService,
+	loginProtect service.LoginProtectService,
+) service.AdminService {
 	return &adminServiceImpl{
 		UserService:      userService,
 		Cache:            cache,
@@ -46,12 +50,18 @@ func NewAdminService(userService service.UserService, cache cache.Cache, config
 		Event:            event,
 		TwoFactorTOTPMFA: twoFactorMFA,
 		EmailService:     emailService,
+		OIDCService:      oidcService,
+		LoginProtect:     loginProtect,
 	}
 }
 
 func (a *adminServiceImpl) Authenticate(ctx context.Context, loginParam param.LoginParam) (*entity.User, error) {
 	missMatchTip := "用户名或密码不正确"
 
+	if err := a.LoginProtect.CheckAllowed(ctx, loginParam); err != nil {
+		return nil, err
+	}
+
 	var user *entity.User
 	err := util.Validate.Var(loginParam.Username, "email")
 
@@ -62,6 +72,7 @@ func (a *adminServiceImpl) Authenticate(ctx context.Context, loginParam param.Lo
 	}
 
 	if xerr.GetType(err) == xerr.NoRecord {
+		a.loginFailed(ctx, loginParam)
 		return nil, xerr.WithMsg(err, missMatchTip).WithStatus(xerr.StatusBadRequest)
 	}
 	if err != nil {
@@ -74,11 +85,22 @@ func (a *adminServiceImpl) Authenticate(ctx context.Context, loginParam param.Lo
 	}
 
 	if !a.UserService.PasswordMatch(ctx, user.Password, loginParam.Password) {
+		a.loginFailed(ctx, loginParam)
 		return nil, xerr.BadParam.New("").WithMsg(missMatchTip).WithStatus(xerr.StatusBadRequest)
 	}
 	return user, nil
 }
 
+func (a *adminServiceImpl) loginFailed(ctx context.Context, loginParam param.LoginParam) {
+	a.LoginProtect.RecordFailure(ctx, loginParam)
+	a.Event.Publish(ctx, &event.LogEvent{
+		LogKey:    loginParam.Username,
+		LogType:   consts.LogTypeLoginFailed,
+		Content:   loginParam.Username,
+		IPAddress: util.GetClientIP(ctx),
+	})
+}
+
 func (a *adminServiceImpl) Auth(ctx context.Context, loginParam param.LoginParam) (*dto.AuthTokenDTO, error) {
 	user, err := a.Authenticate(ctx, loginParam)
 	if err != nil {
@@ -90,9 +112,11 @@ func (a *adminServiceImpl) Auth(ctx context.Context, loginParam param.LoginParam
 		}
 		mfaAuth := a.TwoFactorTOTPMFA.ValidateTFACode(user.MfaKey, loginParam.AuthCode)
 		if !mfaAuth {
+			a.loginFailed(ctx, loginParam)
 			return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("两步验证码验证错误")
 		}
 	}
+	a.LoginProtect.RecordSuccess(ctx, loginParam.Username)
 	a.Event.Publish(ctx, &event.LogEvent{
 		LogKey:    user.Username,
 		LogType:   consts.LogTypeLoggedIn,
@@ -102,6 +126,51 @@ func (a *adminServiceImpl) Auth(ctx context.Context, loginParam param.LoginParam
 	return a.buildAuthToken(user), nil
 }
 
+// oidcMFALogin is an identity verified by the provider that still waits for the TOTP code
+type oidcMFALogin struct {
+	Provider string
+	UserID   int32
+}
+
+func (a *adminServiceImpl) AuthByOIDC(ctx context.Context, providerName, code, state string, loginParam param.LoginParam) (*dto.AuthTokenDTO, error) {
+	var user *entity.User
+	var err error
+	mfaKey := cache.BuildOIDCMFAKey(state)
+	if value, ok := a.Cache.Get(mfaKey); ok && value.(*oidcMFALogin).Provider == providerName {
+		user, err = a.UserService.GetByID(ctx, value.(*oidcMFALogin).UserID)
+	} else {
+		user, err = a.OIDCService.Authenticate(ctx, providerName, code, state)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if a.TwoFactorTOTPMFA.UseMFA(user.MfaType) && !a.OIDCService.SkipMFA(providerName) {
+		loginParam.Username = user.Username
+		if err = a.LoginProtect.CheckAllowed(ctx, loginParam); err != nil {
+			return nil, err
+		}
+		if len(loginParam.AuthCode) != 6 {
+			// the code of the provider can be used only once, keep the identity for the retry with the TOTP code
+			a.Cache.Set(mfaKey, &oidcMFALogin{Provider: providerName, UserID: user.ID}, consts.OIDCStateValidDuration)
+			return nil, xerr.WithMsg(nil, "请输入6位两步验证码").WithStatus(xerr.StatusBadRequest)
+		}
+		if !a.TwoFactorTOTPMFA.ValidateTFACode(user.MfaKey, loginParam.AuthCode) {
+			a.loginFailed(ctx, loginParam)
+			return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("两步验证码验证错误")
+		}
+		a.Cache.Delete(mfaKey)
+		a.LoginProtect.RecordSuccess(ctx, user.Username)
+	}
+	a.Event.Publish(ctx, &event.LogEvent{
+		LogKey:    user.Username,
+		LogType:   consts.LogTypeLoggedIn,
+		Content:   user.Nickname + " (" + providerName + ")",
+		IPAddress: util.GetClientIP(ctx),
+	})
+	return a.buildAuthToken(user), nil
+}
+
 func (a *adminServiceImpl) ClearToken(ctx context.Context) error {
 	user, ok := GetAuthorizedUser(ctx)
 	if !ok || user == nil {
@@ -0,0 +1,152 @@
+package impl
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type auditLogServiceImpl struct{}
+
+func NewAuditLogService() service.AuditLogService {
+	return &auditLogServiceImpl{}
+}
+
+func (a *auditLogServiceImpl) Create(ctx context.Context, auditLog *entity.AuditLog) error {
+	auditLogDAL := dal.GetQueryByCtx(ctx).AuditLog
+	return WrapDBErr(auditLogDAL.WithContext(ctx).Create(auditLog))
+}
+
+func (a *auditLogServiceImpl) Page(ctx context.Context, auditLogQuery param.AuditLogQuery) ([]*entity.AuditLog, int64, error) {
+	auditLogDAL := dal.GetQueryByCtx(ctx).AuditLog
+	auditLogDO := auditLogDAL.WithContext(ctx).Where(a.buildConditions(ctx, auditLogQuery)...)
+	err := BuildSort(auditLogQuery.Sort, &auditLogDAL, &auditLogDO)
+	if err != nil {
+		return nil, 0, err
+	}
+	auditLogs, totalCount, err := auditLogDO.FindByPage(auditLogQuery.PageNum*auditLogQuery.PageSize, auditLogQuery.PageSize)
+	if err != nil {
+		return nil, 0, WrapDBErr(err)
+	}
+	return auditLogs, totalCount, nil
+}
+
+func (a *auditLogServiceImpl) ExportCSV(ctx context.Context, auditLogQuery param.AuditLogQuery, writer io.Writer) error {
+	auditLogDAL := dal.GetQueryByCtx(ctx).AuditLog
+	csvWriter := csv.NewWriter(writer)
+	err := csvWriter.Write([]string{"id", "create_time", "user_id", "username", "ip_address", "method", "path", "status", "entity_type", "entity_id", "before", "after"})
+	if err != nil {
+		return xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError)
+	}
+	conditions := a.buildConditions(ctx, auditLogQuery)
+	// the logs are paged by the id instead of FindInBatches, which only pages by the ascending id
+	var lastID int64
+	for {
+		auditLogDO := auditLogDAL.WithContext(ctx).Where(conditions...)
+		if lastID > 0 {
+			auditLogDO = auditLogDO.Where(auditLogDAL.ID.Lt(lastID))
+		}
+		auditLogs, err := auditLogDO.Order(auditLogDAL.ID.Desc()).Limit(500).Find()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		for _, auditLog := range auditLogs {
+			err := csvWriter.Write([]string{
+				strconv.FormatInt(auditLog.ID, 10),
+				auditLog.CreateTime.Format(time.RFC3339),
+				strconv.Itoa(int(auditLog.UserID)),
+				auditLog.Username,
+				auditLog.IPAddress,
+				auditLog.Method,
+				auditLog.Path,
+				strconv.Itoa(int(auditLog.Status)),
+				auditLog.EntityType,
+				auditLog.EntityID,
+				auditLog.BeforeSummary,
+				auditLog.AfterSummary,
+			})
+			if err != nil {
+				return xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError)
+			}
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError)
+		}
+		if len(auditLogs) < 500 {
+			return nil
+		}
+		nextID := auditLogs[len(auditLogs)-1].ID
+		if nextID <= 0 || (lastID > 0 && nextID >= lastID) {
+			// the ids are not generated by the database, the logs can not be paged any further
+			return nil
+		}
+		lastID = nextID
+	}
+}
+
+func (a *auditLogServiceImpl) ConvertToDTO(auditLog *entity.AuditLog) *dto.AuditLog {
+	return &dto.AuditLog{
+		ID:            auditLog.ID,
+		UserID:        auditLog.UserID,
+		Username:      auditLog.Username,
+		IPAddress:     auditLog.IPAddress,
+		Method:        auditLog.Method,
+		Path:          auditLog.Path,
+		EntityType:    auditLog.EntityType,
+		EntityID:      auditLog.EntityID,
+		BeforeSummary: auditLog.BeforeSummary,
+		AfterSummary:  auditLog.AfterSummary,
+		Status:        auditLog.Status,
+		CreateTime:    auditLog.CreateTime.UnixMilli(),
+	}
+}
+
+func (a *auditLogServiceImpl) Clear(ctx context.Context) error {
+	auditLogDAL := dal.GetQueryByCtx(ctx).AuditLog
+	_, err := auditLogDAL.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete()
+	return WrapDBErr(err)
+}
+
+func (a *auditLogServiceImpl) buildConditions(ctx context.Context, auditLogQuery param.AuditLogQuery) []gen.Condition {
+	auditLogDAL := dal.GetQueryByCtx(ctx).AuditLog
+	conditions := make([]gen.Condition, 0)
+	if auditLogQuery.Username != nil && *auditLogQuery.Username != "" {
+		conditions = append(conditions, auditLogDAL.Username.Eq(*auditLogQuery.Username))
+	}
+	if auditLogQuery.EntityType != nil && *auditLogQuery.EntityType != "" {
+		conditions = append(conditions, auditLogDAL.EntityType.Like("%"+*auditLogQuery.EntityType+"%"))
+	}
+	if auditLogQuery.Method != nil && *auditLogQuery.Method != "" {
+		conditions = append(conditions, auditLogDAL.Method.Eq(strings.ToUpper(*auditLogQuery.Method)))
+	}
+	if auditLogQuery.IPAddress != nil && *auditLogQuery.IPAddress != "" {
+		conditions = append(conditions, auditLogDAL.IPAddress.Eq(*auditLogQuery.IPAddress))
+	}
+	if auditLogQuery.Keyword != nil && *auditLogQuery.Keyword != "" {
+		keyword := "%" + *auditLogQuery.Keyword + "%"
+		conditions = append(conditions, field.Or(auditLogDAL.Path.Like(keyword),
+			auditLogDAL.BeforeSummary.Like(keyword), auditLogDAL.AfterSummary.Like(keyword)))
+	}
+	if auditLogQuery.StartTime != nil {
+		conditions = append(conditions, auditLogDAL.CreateTime.Gte(time.UnixMilli(*auditLogQuery.StartTime)))
+	}
+	if auditLogQuery.EndTime != nil {
+		conditions = append(conditions, auditLogDAL.CreateTime.Lte(time.UnixMilli(*auditLogQuery.EndTime)))
+	}
+	return conditions
+}
@@ -1,6 +1,7 @@
 package impl
 
 import (
+	"archive/zip"
 	"context"
 	"encoding/json"
 	"io/fs"
@@ -149,6 +150,83 @@ func (b *backupServiceImpl) ImportMarkdown(ctx context.Context, fileHeader *mult
 	return err
 }
 
+func (b *backupServiceImpl) ImportMarkdownBundle(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ImportReport, error) {
+	file, bundle, err := openZip(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return b.ExportImportService.ImportMarkdownBundle(ctx, bundle)
+}
+
+func (b *backupServiceImpl) ImportWordPress(ctx context.Context, fileHeader, uploadsHeader *multipart.FileHeader) (*dto.ImportReport, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err).WithMsg("upload file error")
+	}
+	defer file.Close()
+	var uploads fs.FS
+	if uploadsHeader != nil {
+		uploadsFile, uploadsZip, err := openZip(uploadsHeader)
+		if err != nil {
+			return nil, err
+		}
+		defer uploadsFile.Close()
+		uploads = uploadsZip
+	}
+	return b.ExportImportService.ImportWordPress(ctx, file, uploads)
+}
+
+func (b *backupServiceImpl) ImportHugo(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ImportReport, error) {
+	file, site, err := openZip(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return b.ExportImportService.ImportHugo(ctx, site)
+}
+
+func (b *backupServiceImpl) ImportHexo(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ImportReport, error) {
+	file, site, err := openZip(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return b.ExportImportService.ImportHexo(ctx, site)
+}
+
+func (b *backupServiceImpl) ImportHalo(ctx context.Context, fileHeader, uploadsHeader *multipart.FileHeader) (*dto.ImportReport, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err).WithMsg("upload file error")
+	}
+	defer file.Close()
+	var uploads fs.FS
+	if uploadsHeader != nil {
+		uploadsFile, uploadsZip, err := openZip(uploadsHeader)
+		if err != nil {
+			return nil, err
+		}
+		defer uploadsFile.Close()
+		uploads = uploadsZip
+	}
+	return b.ExportImportService.ImportHalo(ctx, file, uploads)
+}
+
+// openZip opens the uploaded zip file as a file system, the file should be closed after using the file system
+func openZip(fileHeader *multipart.FileHeader) (multipart.File, fs.FS, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, nil, xerr.NoType.Wrap(err).WithMsg("upload file error")
+	}
+	zipReader, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		file.Close()
+		return nil, nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("the file must be a zip file")
+	}
+	return file, zipReader, nil
+}
+
 func (b *backupServiceImpl) ExportData(ctx context.Context) (*dto.BackupDTO, error) {
 	data := make(map[string]interface{})
 	data["version"] = consts.SonicVersion
@@ -157,6 +235,7 @@ func (b *backupServiceImpl) ExportData(ctx context.Context) (*dto.BackupDTO, err
 	err = fillData(data, "category", dal.GetQueryByCtx(ctx).Category.WithContext(ctx).Find, err)
 	err = fillData(data, "comment", dal.GetQueryByCtx(ctx).Comment.WithContext(ctx).Find, err)
 	err = fillData(data, "comment_black", dal.GetQueryByCtx(ctx).CommentBlack.WithContext(ctx).Find, err)
+	err = fillData(data, "custom_field", dal.GetQueryByCtx(ctx).CustomField.WithContext(ctx).Find, err)
 	err = fillData(data, "journal", dal.GetQueryByCtx(ctx).Journal.WithContext(ctx).Find, err)
 	err = fillData(data, "link", dal.GetQueryByCtx(ctx).Link.WithContext(ctx).Find, err)
 	err = fillData(data, "log", dal.GetQueryByCtx(ctx).Log.WithContext(ctx).Find, err)
@@ -167,6 +246,9 @@ func (b *backupServiceImpl) ExportData(ctx context.Context) (*dto.BackupDTO, err
 	err = fillData(data, "post", dal.GetQueryByCtx(ctx).Post.WithContext(ctx).Find, err)
 	err = fillData(data, "post_category", dal.GetQueryByCtx(ctx).PostCategory.WithContext(ctx).Find, err)
 	err = fillData(data, "post_tag", dal.GetQueryByCtx(ctx).PostTag.WithContext(ctx).Find, err)
+	err = fillData(data, "series", dal.GetQueryByCtx(ctx).Series.WithContext(ctx).Find, err)
+	err = fillData(data, "series_post", dal.GetQueryByCtx(ctx).SeriesPost.WithContext(ctx).Find, err)
+	err = fillData(data, "translation", dal.GetQueryByCtx(ctx).Translation.WithContext(ctx).Find, err)
 	err = fillData(data, "theme_setting", dal.GetQueryByCtx(ctx).ThemeSetting.WithContext(ctx).Find, err)
 	err = fillData(data, "user", dal.GetQueryByCtx(ctx).User.WithContext(ctx).Find, err)
 	if err != nil {
@@ -202,8 +284,14 @@ func (b *backupServiceImpl) ExportData(ctx context.Context) (*dto.BackupDTO, err
 	return b.buildBackupDTO(ctx, string(service.JSONData), filepath.Join(backupFilePath, backupFilename))
 }
 
-func (b *backupServiceImpl) ExportMarkdown(ctx context.Context, needFrontMatter bool) (*dto.BackupDTO, error) {
-	fileName, err := b.ExportImportService.ExportMarkdown(ctx, needFrontMatter)
+func (b *backupServiceImpl) ExportMarkdown(ctx context.Context, needFrontMatter, withAttachments bool) (*dto.BackupDTO, error) {
+	var fileName string
+	var err error
+	if withAttachments {
+		fileName, err = b.ExportImportService.ExportMarkdownBundle(ctx)
+	} else {
+		fileName, err = b.ExportImportService.ExportMarkdown(ctx, needFrontMatter)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"go.uber.org/fx"
 	"gorm.io/gen/field"
 
 	"github.com/go-sonic/sonic/consts"
@@ -24,10 +25,11 @@ import (
 type basePostServiceImpl struct {
 	OptionService      service.OptionService
 	BaseCommentService service.BaseCommentService
+	CustomFieldService service.CustomFieldService
 	CounterCache       *util.CounterCache[int32]
 }
 
-func NewBasePostService(optionService service.OptionService, baseCommentService service.BaseCommentService) service.BasePostService {
+func NewBasePostService(optionService service.OptionService, baseCommentService service.BaseCommentService, customFieldService service.CustomFieldService, lifecycle fx.Lifecycle) service.BasePostService {
 	counterCache := util.NewCounterCache(time.Second*5, nil, func(postID int32, count int64) {
 		ctx := context.Background()
 		postDAL := dal.GetQueryByCtx(ctx).Post
@@ -36,10 +38,17 @@ func NewBasePostService(optionService service.OptionService, baseCommentService
 			log.CtxErrorf(ctx, "increase visit err postID=%v", postID)
 		}
 	})
+	lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			counterCache.Close()
+			return nil
+		},
+	})
 	b := &basePostServiceImpl{
 		CounterCache:       counterCache,
 		OptionService:      optionService,
 		BaseCommentService: baseCommentService,
+		CustomFieldService: customFieldService,
 	}
 	return b
 }
@@ -62,10 +71,38 @@ func (b basePostServiceImpl) GetByStatus(ctx context.Context, status []consts.Po
 }
 
 func (b basePostServiceImpl) BuildFullPath(ctx context.Context, post *entity.Post) (string, error) {
+	languagePrefix, err := languagePathPrefix(ctx, b.OptionService, consts.TranslationTypePost, post.ID)
+	if err != nil {
+		return "", err
+	}
+	return b.buildFullPath(ctx, post, languagePrefix)
+}
+
+// BuildFullPaths queries the languages of all the posts at once
+func (b basePostServiceImpl) BuildFullPaths(ctx context.Context, posts []*entity.Post) (map[int32]string, error) {
+	postIDs := make([]int32, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	languagePrefixes, err := languagePathPrefixes(ctx, b.OptionService, consts.TranslationTypePost, postIDs...)
+	if err != nil {
+		return nil, err
+	}
+	fullPaths := make(map[int32]string, len(posts))
+	for _, post := range posts {
+		fullPaths[post.ID], err = b.buildFullPath(ctx, post, languagePrefixes[post.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fullPaths, nil
+}
+
+func (b basePostServiceImpl) buildFullPath(ctx context.Context, post *entity.Post, languagePrefix string) (string, error) {
 	if post.Type == consts.PostTypePost {
-		return b.buildPostFullPath(ctx, post)
+		return b.buildPostFullPath(ctx, post, languagePrefix)
 	}
-	return b.buildSheetFullPath(ctx, post)
+	return b.buildSheetFullPath(ctx, post, languagePrefix)
 }
 
 func (b basePostServiceImpl) GetByPostIDs(ctx context.Context, postIDs []int32) (map[int32]*entity.Post, error) {
@@ -90,7 +127,7 @@ func (b basePostServiceImpl) GetBySlug(ctx context.Context, slug string) (*entit
 	return post, nil
 }
 
-func (b basePostServiceImpl) buildPostFullPath(ctx context.Context, post *entity.Post) (string, error) {
+func (b basePostServiceImpl) buildPostFullPath(ctx context.Context, post *entity.Post, languagePrefix string) (string, error) {
 	postPermaLinkType, err := b.OptionService.GetOrByDefaultWithErr(ctx, property.PostPermalinkType, property.PostPermalinkType.DefaultValue)
 	if err != nil {
 		return "", err
@@ -120,6 +157,7 @@ func (b basePostServiceImpl) buildPostFullPath(ctx context.Context, post *entity
 		}
 		fullPath.WriteString(blogBaseURL)
 	}
+	fullPath.WriteString(languagePrefix)
 	fullPath.WriteString("/")
 	switch consts.PostPermalinkType(postPermaLinkType.(string)) {
 	case consts.PostPermalinkTypeDefault:
@@ -160,7 +198,7 @@ func (b basePostServiceImpl) buildPostFullPath(ctx context.Context, post *entity
 	return fullPath.String(), nil
 }
 
-func (b basePostServiceImpl) buildSheetFullPath(ctx context.Context, sheet *entity.Post) (string, error) {
+func (b basePostServiceImpl) buildSheetFullPath(ctx context.Context, sheet *entity.Post, languagePrefix string) (string, error) {
 	sheetPermaLinkType, err := b.OptionService.GetOrByDefaultWithErr(ctx, property.SheetPermalinkType, property.SheetPermalinkType.DefaultValue)
 	if err != nil {
 		return "", err
@@ -186,6 +224,7 @@ func (b basePostServiceImpl) buildSheetFullPath(ctx context.Context, sheet *enti
 		}
 		fullPath.WriteString(blogBaseURL)
 	}
+	fullPath.WriteString(languagePrefix)
 	fullPath.WriteString("/")
 	switch consts.SheetPermaLinkType(sheetPermaLinkType.(string)) {
 	case consts.SheetPermaLinkTypeSecondary:
@@ -212,7 +251,8 @@ func (b basePostServiceImpl) GetByPostID(ctx context.Context, postID int32) (*en
 var summaryPattern = regexp.MustCompile(`[\t\r\n]`)
 
 func (b basePostServiceImpl) GenerateSummary(ctx context.Context, htmlContent string) string {
-	text := util.CleanHTMLTag(htmlContent)
+	text, restore := protectCodeBlocks(htmlContent)
+	text = util.CleanHTMLTag(restore(escapedShortcodeRegexp.ReplaceAllString(text, "")))
 	text = summaryPattern.ReplaceAllString(text, "")
 	summaryLength := b.OptionService.GetPostSummaryLength(ctx)
 	end := summaryLength
@@ -230,6 +270,8 @@ func (b basePostServiceImpl) Delete(ctx context.Context, postID int32) error {
 		postCategoryDAL := tx.PostCategory
 		postMetaDAL := tx.Meta
 		postCommentDAL := tx.Comment
+		seriesPostDAL := tx.SeriesPost
+		translationDAL := tx.Translation
 
 		deleteResult, err := postDAL.WithContext(ctx).Where(postDAL.ID.Eq(postID)).Delete()
 		if err != nil {
@@ -254,6 +296,14 @@ func (b basePostServiceImpl) Delete(ctx context.Context, postID int32) error {
 		if err != nil {
 			return WrapDBErr(err)
 		}
+		_, err = seriesPostDAL.WithContext(ctx).Where(seriesPostDAL.PostID.Eq(postID)).Delete()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		_, err = translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(consts.TranslationTypePost), translationDAL.ContentID.Eq(postID)).Delete()
+		if err != nil {
+			return WrapDBErr(err)
+		}
 		return nil
 	})
 	return err
@@ -287,6 +337,8 @@ func (b basePostServiceImpl) DeleteBatch(ctx context.Context, postIDs []int32) e
 		postCategoryDAL := tx.PostCategory
 		postMetaDAL := tx.Meta
 		postCommentDAL := tx.Comment
+		seriesPostDAL := tx.SeriesPost
+		translationDAL := tx.Translation
 
 		deleteResult, err := postDAL.WithContext(ctx).Where(postDAL.ID.In(postIDs...)).Delete()
 		if err != nil {
@@ -311,13 +363,25 @@ func (b basePostServiceImpl) DeleteBatch(ctx context.Context, postIDs []int32) e
 		if err != nil {
 			return WrapDBErr(err)
 		}
+		_, err = seriesPostDAL.WithContext(ctx).Where(seriesPostDAL.PostID.In(postIDs...)).Delete()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		_, err = translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(consts.TranslationTypePost), translationDAL.ContentID.In(postIDs...)).Delete()
+		if err != nil {
+			return WrapDBErr(err)
+		}
 		return nil
 	})
 	return err
 }
 
 func (b basePostServiceImpl) CreateOrUpdate(ctx context.Context, post *entity.Post, categoryIDs, tagIDs []int32, metas []param.Meta) (*entity.Post, error) {
-	err := dal.GetQueryByCtx(ctx).Transaction(func(tx *dal.Query) error {
+	metas, err := b.CustomFieldService.Validate(ctx, post, metas)
+	if err != nil {
+		return nil, err
+	}
+	err = dal.GetQueryByCtx(ctx).Transaction(func(tx *dal.Query) error {
 		postDAL := tx.Post
 		postCategoryDAL := tx.PostCategory
 		postTagDAL := tx.PostTag
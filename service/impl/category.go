@@ -158,6 +158,11 @@ func (c categoryServiceImpl) ConvertToCategoryDTO(ctx context.Context, e *entity
 		}
 		fullPath.WriteString(blogBaseURL)
 	}
+	languagePrefix, err := languagePathPrefix(ctx, c.OptionService, consts.TranslationTypeCategory, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	fullPath.WriteString(languagePrefix)
 	fullPath.WriteString("/")
 	categoryPrefix, err := c.OptionService.GetOrByDefaultWithErr(ctx, property.CategoriesPrefix, "categories")
 	if err != nil {
@@ -193,6 +198,14 @@ func (c categoryServiceImpl) ConvertToCategoryDTOs(ctx context.Context, categori
 	if err != nil {
 		return nil, err
 	}
+	categoryIDs := make([]int32, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	languagePrefixes, err := languagePathPrefixes(ctx, c.OptionService, consts.TranslationTypeCategory, categoryIDs...)
+	if err != nil {
+		return nil, err
+	}
 	for i, category := range categories {
 		categoryDTO := &dto.CategoryDTO{}
 		categoryDTO.ID = category.ID
@@ -209,6 +222,7 @@ func (c categoryServiceImpl) ConvertToCategoryDTOs(ctx context.Context, categori
 		if isEnabled {
 			fullPath.WriteString(blogBaseURL)
 		}
+		fullPath.WriteString(languagePrefixes[category.ID])
 		fullPath.WriteString("/")
 		fullPath.WriteString(categoryPrefix.(string))
 		fullPath.WriteString("/")
@@ -783,5 +797,8 @@ func (c *categoryUpdateExecutor) removeCategory(ctx context.Context, categoryID
 		}
 	}
 	_, err := categoryDAL.WithContext(ctx).Where(categoryDAL.ID.Eq(categoryID)).Delete()
-	return WrapDBErr(err)
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	return deleteTranslations(ctx, consts.TranslationTypeCategory, categoryID)
 }
@@ -102,6 +102,10 @@ func (c *clientOptionServiceImpl) getPrivateOption() map[string]struct{} {
 		property.UpOssStyleRule,
 		property.UpOssThumbnailStyleRule,
 		property.JWTSecret,
+		property.ActivityPubPrivateKey,
+		property.GitSyncPassword,
+		property.GitSyncWebhookSecret,
+		property.MetaWeblogAppPassword,
 	}
 	for _, p := range privateProperty {
 		privateOption[p.KeyValue] = struct{}{}
@@ -0,0 +1,551 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// customFieldDateLayouts are the layouts a DATE value is accepted in, the ones without a zone are in local time
+var customFieldDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+type customFieldServiceImpl struct {
+	ThemeService service.ThemeService
+}
+
+func NewCustomFieldService(themeService service.ThemeService) service.CustomFieldService {
+	return &customFieldServiceImpl{
+		ThemeService: themeService,
+	}
+}
+
+func (c *customFieldServiceImpl) List(ctx context.Context) ([]*entity.CustomField, error) {
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	fields, err := customFieldDAL.WithContext(ctx).Order(customFieldDAL.PostType, customFieldDAL.Priority, customFieldDAL.ID).Find()
+	return fields, WrapDBErr(err)
+}
+
+func (c *customFieldServiceImpl) GetByID(ctx context.Context, id int32) (*entity.CustomField, error) {
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	field, err := customFieldDAL.WithContext(ctx).Where(customFieldDAL.ID.Eq(id)).First()
+	return field, WrapDBErr(err)
+}
+
+func (c *customFieldServiceImpl) Create(ctx context.Context, fieldParam *param.CustomField) (*entity.CustomField, error) {
+	field, err := c.convertParam(fieldParam)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkKey(ctx, 0, field); err != nil {
+		return nil, err
+	}
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	if err := customFieldDAL.WithContext(ctx).Create(field); err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return field, nil
+}
+
+func (c *customFieldServiceImpl) Update(ctx context.Context, id int32, fieldParam *param.CustomField) (*entity.CustomField, error) {
+	if _, err := c.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	field, err := c.convertParam(fieldParam)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkKey(ctx, id, field); err != nil {
+		return nil, err
+	}
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	_, err = customFieldDAL.WithContext(ctx).Where(customFieldDAL.ID.Eq(id)).UpdateSimple(
+		customFieldDAL.PostType.Value(field.PostType),
+		customFieldDAL.Template.Value(field.Template),
+		customFieldDAL.FieldKey.Value(field.FieldKey),
+		customFieldDAL.Label.Value(field.Label),
+		customFieldDAL.Type.Value(field.Type),
+		customFieldDAL.Required.Value(field.Required),
+		customFieldDAL.Options.Value(field.Options),
+		customFieldDAL.DefaultValue.Value(field.DefaultValue),
+		customFieldDAL.Description.Value(field.Description),
+		customFieldDAL.Priority.Value(field.Priority),
+	)
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return c.GetByID(ctx, id)
+}
+
+func (c *customFieldServiceImpl) Delete(ctx context.Context, id int32) error {
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	result, err := customFieldDAL.WithContext(ctx).Where(customFieldDAL.ID.Eq(id)).Delete()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if result.RowsAffected != 1 {
+		return xerr.NoRecord.New("").WithStatus(xerr.StatusNotFound).WithMsg("custom field not exist")
+	}
+	return nil
+}
+
+func (c *customFieldServiceImpl) ListSchemas(ctx context.Context, postType consts.PostType, template string) ([]*dto.CustomField, error) {
+	fields, err := c.listAllSchemas(ctx, postType)
+	if err != nil {
+		return nil, err
+	}
+	// the fields of the template take the place of the ones of any template
+	result := make([]*dto.CustomField, 0, len(fields))
+	indexes := make(map[string]int)
+	for _, field := range fields {
+		if field.Template != "" && field.Template != template {
+			continue
+		}
+		index, ok := indexes[field.Key]
+		if !ok {
+			indexes[field.Key] = len(result)
+			result = append(result, field)
+		} else if field.Template != "" || result[index].Template == "" {
+			result[index] = field
+		}
+	}
+	return result, nil
+}
+
+// listAllSchemas lists the fields of every template, the theme ones come first so that the admin ones take their places
+func (c *customFieldServiceImpl) listAllSchemas(ctx context.Context, postType consts.PostType) ([]*dto.CustomField, error) {
+	theme, err := c.ThemeService.GetActivateTheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+	themeFields := theme.PostCustomFields
+	if postType == consts.PostTypeSheet {
+		themeFields = theme.SheetCustomFields
+	}
+	fields := make([]*dto.CustomField, 0, len(themeFields))
+	for _, themeField := range themeFields {
+		if themeField == nil || themeField.Key == "" {
+			continue
+		}
+		field := *themeField
+		field.PostType = postType
+		field.ThemeID = theme.ID
+		if field.Label == "" {
+			field.Label = field.Key
+		}
+		fields = append(fields, &field)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Priority < fields[j].Priority
+	})
+
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	adminFields, err := customFieldDAL.WithContext(ctx).Where(customFieldDAL.PostType.Eq(postType)).Order(customFieldDAL.Priority, customFieldDAL.ID).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	adminFieldDTOs, err := c.ConvertToDTOs(adminFields)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.CustomField, 0, len(fields)+len(adminFieldDTOs))
+	for _, field := range fields {
+		overridden := false
+		for _, adminField := range adminFieldDTOs {
+			if adminField.Key == field.Key && adminField.Template == field.Template {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			result = append(result, field)
+		}
+	}
+	return append(result, adminFieldDTOs...), nil
+}
+
+func (c *customFieldServiceImpl) Validate(ctx context.Context, post *entity.Post, metas []param.Meta) ([]param.Meta, error) {
+	fields, err := c.ListSchemas(ctx, post.Type, post.Template)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return metas, nil
+	}
+	fieldMap := make(map[string]*dto.CustomField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.Key] = field
+	}
+
+	result := make([]param.Meta, 0, len(metas))
+	invalid := make([]string, 0)
+	present := make(map[string]struct{})
+	postIDs := make([]int32, 0)
+	for _, meta := range metas {
+		field, ok := fieldMap[meta.Key]
+		if !ok {
+			result = append(result, meta)
+			continue
+		}
+		value, err := c.ParseValue(field, meta.Value)
+		if err != nil {
+			invalid = append(invalid, field.Key)
+			continue
+		}
+		present[field.Key] = struct{}{}
+		result = append(result, param.Meta{
+			Key:   meta.Key,
+			Value: formatCustomFieldValue(value),
+		})
+		if postID, ok := value.(int32); ok {
+			postIDs = append(postIDs, postID)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("custom field value invalid: " + strings.Join(invalid, ", "))
+	}
+
+	if imported, _ := ctx.Value(consts.ContentImport).(bool); !imported && post.Status != consts.PostStatusDraft {
+		missing := make([]string, 0)
+		for _, field := range fields {
+			if _, ok := present[field.Key]; !ok && field.Required && field.DefaultValue == "" {
+				missing = append(missing, field.Key)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("custom field required: " + strings.Join(missing, ", "))
+		}
+	}
+
+	if len(postIDs) > 0 {
+		postDAL := dal.GetQueryByCtx(ctx).Post
+		var existIDs []int32
+		err := postDAL.WithContext(ctx).Where(postDAL.ID.In(postIDs...)).Pluck(postDAL.ID, &existIDs)
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		exist := make(map[int32]struct{}, len(existIDs))
+		for _, id := range existIDs {
+			exist[id] = struct{}{}
+		}
+		for _, postID := range postIDs {
+			if _, ok := exist[postID]; !ok {
+				return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("referenced post not exist: " + strconv.Itoa(int(postID)))
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *customFieldServiceImpl) ParseValue(field *dto.CustomField, value string) (interface{}, error) {
+	switch field.Type {
+	case consts.CustomFieldTypeNumber:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg(field.Key + " is not a number")
+		}
+		return number, nil
+	case consts.CustomFieldTypeDate:
+		value = strings.TrimSpace(value)
+		for _, layout := range customFieldDateLayouts {
+			if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return date, nil
+			}
+		}
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg(field.Key + " is not a date")
+	case consts.CustomFieldTypeSelect:
+		if len(field.Options) == 0 {
+			return value, nil
+		}
+		for _, option := range field.Options {
+			if option.Value == value {
+				return value, nil
+			}
+		}
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg(field.Key + " is not one of the options")
+	case consts.CustomFieldTypePost:
+		postID, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil || postID <= 0 {
+			return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg(field.Key + " is not a post id")
+		}
+		return int32(postID), nil
+	default:
+		return value, nil
+	}
+}
+
+func (c *customFieldServiceImpl) ListPostIDsByFilters(ctx context.Context, postType consts.PostType, filters map[string]string) ([]int32, error) {
+	fields, err := c.listAllSchemas(ctx, postType)
+	if err != nil {
+		return nil, err
+	}
+	// a filter on a field declared for some templates only uses the type of the first declaration found
+	fieldMap := make(map[string]*dto.CustomField)
+	for _, field := range fields {
+		if existing, ok := fieldMap[field.Key]; !ok || (existing.Template != "" && field.Template == "") {
+			fieldMap[field.Key] = field
+		}
+	}
+
+	var postIDs map[int32]struct{}
+	metaDAL := dal.GetQueryByCtx(ctx).Meta
+	for key, filter := range filters {
+		field, ok := fieldMap[key]
+		if !ok {
+			field = &dto.CustomField{Key: key, Type: consts.CustomFieldTypeText}
+		}
+		operator, operand := parseCustomFieldFilter(filter)
+		target, err := c.ParseValue(field, operand)
+		if err != nil {
+			return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("invalid filter of custom field " + key)
+		}
+
+		metaQuery := metaDAL.WithContext(ctx).Where(metaDAL.MetaKey.Eq(key))
+		if postIDs != nil {
+			ids := make([]int32, 0, len(postIDs))
+			for postID := range postIDs {
+				ids = append(ids, postID)
+			}
+			metaQuery = metaQuery.Where(metaDAL.PostID.In(ids...))
+		}
+		matched := make(map[int32]struct{})
+		_, isText := target.(string)
+		if isText || operator == "eq" || operator == "ne" {
+			// the values are stored normalized by Validate, so the text and the equality are compared by the database
+			var matchedIDs []int32
+			err = metaQuery.Where(customFieldFilterCondition(metaDAL.MetaValue, operator, formatCustomFieldValue(target))).Pluck(metaDAL.PostID, &matchedIDs)
+			if err != nil {
+				return nil, WrapDBErr(err)
+			}
+			for _, postID := range matchedIDs {
+				matched[postID] = struct{}{}
+			}
+		} else {
+			metas, err := metaQuery.Select(metaDAL.PostID, metaDAL.MetaValue).Find()
+			if err != nil {
+				return nil, WrapDBErr(err)
+			}
+			for _, meta := range metas {
+				value, err := c.ParseValue(field, meta.MetaValue)
+				if err != nil {
+					continue
+				}
+				if matchCustomFieldFilter(operator, compareCustomFieldValue(value, target)) {
+					matched[meta.PostID] = struct{}{}
+				}
+			}
+		}
+		postIDs = matched
+		if len(postIDs) == 0 {
+			break
+		}
+	}
+
+	result := make([]int32, 0, len(postIDs))
+	for postID := range postIDs {
+		result = append(result, postID)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+func (c *customFieldServiceImpl) ConvertToDTO(field *entity.CustomField) (*dto.CustomField, error) {
+	options := make([]*dto.CustomFieldOption, 0)
+	if field.Options != "" {
+		if err := json.Unmarshal([]byte(field.Options), &options); err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("custom field options invalid")
+		}
+	}
+	return &dto.CustomField{
+		ID:           field.ID,
+		PostType:     field.PostType,
+		Template:     field.Template,
+		Key:          field.FieldKey,
+		Label:        field.Label,
+		Type:         field.Type,
+		Required:     field.Required,
+		Options:      options,
+		DefaultValue: field.DefaultValue,
+		Description:  field.Description,
+		Priority:     field.Priority,
+	}, nil
+}
+
+func (c *customFieldServiceImpl) ConvertToDTOs(fields []*entity.CustomField) ([]*dto.CustomField, error) {
+	fieldDTOs := make([]*dto.CustomField, 0, len(fields))
+	for _, field := range fields {
+		fieldDTO, err := c.ConvertToDTO(field)
+		if err != nil {
+			return nil, err
+		}
+		fieldDTOs = append(fieldDTOs, fieldDTO)
+	}
+	return fieldDTOs, nil
+}
+
+func (c *customFieldServiceImpl) convertParam(fieldParam *param.CustomField) (*entity.CustomField, error) {
+	if fieldParam.Type.String() == "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("unknown custom field type")
+	}
+	if fieldParam.Type == consts.CustomFieldTypeSelect && len(fieldParam.Options) == 0 {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("select field without options")
+	}
+	options := make([]*dto.CustomFieldOption, 0, len(fieldParam.Options))
+	for _, option := range fieldParam.Options {
+		label := option.Label
+		if label == "" {
+			label = option.Value
+		}
+		options = append(options, &dto.CustomFieldOption{Label: label, Value: option.Value})
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("custom field options invalid")
+	}
+	label := fieldParam.Label
+	if label == "" {
+		label = fieldParam.Key
+	}
+	field := &entity.CustomField{
+		PostType:     fieldParam.PostType,
+		Template:     strings.TrimSpace(fieldParam.Template),
+		FieldKey:     strings.TrimSpace(fieldParam.Key),
+		Label:        label,
+		Type:         fieldParam.Type,
+		Required:     fieldParam.Required,
+		Options:      string(optionsJSON),
+		DefaultValue: fieldParam.DefaultValue,
+		Description:  fieldParam.Description,
+		Priority:     fieldParam.Priority,
+	}
+	if field.FieldKey == "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("custom field key is empty")
+	}
+	if field.DefaultValue != "" {
+		fieldDTO, err := c.ConvertToDTO(field)
+		if err != nil {
+			return nil, err
+		}
+		value, err := c.ParseValue(fieldDTO, field.DefaultValue)
+		if err != nil {
+			return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("custom field default value invalid")
+		}
+		field.DefaultValue = formatCustomFieldValue(value)
+	}
+	return field, nil
+}
+
+func (c *customFieldServiceImpl) checkKey(ctx context.Context, id int32, field *entity.CustomField) error {
+	customFieldDAL := dal.GetQueryByCtx(ctx).CustomField
+	count, err := customFieldDAL.WithContext(ctx).Where(
+		customFieldDAL.PostType.Eq(field.PostType),
+		customFieldDAL.Template.Eq(field.Template),
+		customFieldDAL.FieldKey.Eq(field.FieldKey),
+		customFieldDAL.ID.Neq(id),
+	).Count()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if count > 0 {
+		return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("custom field key already exists")
+	}
+	return nil
+}
+
+// formatCustomFieldValue formats the value parsed by ParseValue back to the form stored in the metas
+func formatCustomFieldValue(value interface{}) string {
+	switch data := value.(type) {
+	case float64:
+		return strconv.FormatFloat(data, 'f', -1, 64)
+	case time.Time:
+		return data.Format(time.RFC3339)
+	case int32:
+		return strconv.Itoa(int(data))
+	case string:
+		return data
+	}
+	return ""
+}
+
+func parseCustomFieldFilter(filter string) (operator, operand string) {
+	if index := strings.Index(filter, ":"); index > 0 {
+		switch operator := filter[:index]; operator {
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			return operator, filter[index+1:]
+		}
+	}
+	return "eq", filter
+}
+
+// compareCustomFieldValue compares two values parsed by ParseValue of the same field
+func compareCustomFieldValue(a, b interface{}) int {
+	switch x := a.(type) {
+	case float64:
+		y := b.(float64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	case int32:
+		y := b.(int32)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a.(string), b.(string))
+}
+
+func customFieldFilterCondition(metaValue field.String, operator, value string) field.Expr {
+	switch operator {
+	case "ne":
+		return metaValue.Neq(value)
+	case "gt":
+		return metaValue.Gt(value)
+	case "gte":
+		return metaValue.Gte(value)
+	case "lt":
+		return metaValue.Lt(value)
+	case "lte":
+		return metaValue.Lte(value)
+	}
+	return metaValue.Eq(value)
+}
+
+func matchCustomFieldFilter(operator string, result int) bool {
+	switch operator {
+	case "ne":
+		return result != 0
+	case "gt":
+		return result > 0
+	case "gte":
+		return result >= 0
+	case "lt":
+		return result < 0
+	case "lte":
+		return result <= 0
+	}
+	return result == 0
+}
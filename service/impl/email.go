@@ -7,9 +7,11 @@ import (
 	"net/smtp"
 
 	"github.com/jordan-wright/email"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/go-sonic/sonic/model/property"
 	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
@@ -50,7 +52,7 @@ func (e *emailServiceImpl) SendTextEmail(ctx context.Context, to, subject, conte
 		Subject: subject,
 		Text:    []byte(content),
 	}
-	err = e.sendEmail(email, emailProperties)
+	err = e.sendEmail(ctx, email, emailProperties)
 	return err
 }
 
@@ -72,7 +74,7 @@ func (e *emailServiceImpl) SendTemplateEmail(ctx context.Context, to, subject st
 		Subject: subject,
 		HTML:    []byte(content),
 	}
-	err = e.sendEmail(email, emailProperties)
+	err = e.sendEmail(ctx, email, emailProperties)
 	return err
 }
 
@@ -107,9 +109,11 @@ func (e *emailServiceImpl) getEmailProperties(ctx context.Context) (emailPropert
 	return emailProperties, nil
 }
 
-func (e *emailServiceImpl) sendEmail(email *email.Email, properties emailProperties) error {
+func (e *emailServiceImpl) sendEmail(ctx context.Context, email *email.Email, properties emailProperties) error {
+	_, span := tracing.Start(ctx, "smtp.Send", attribute.String("smtp.host", properties.Host), attribute.Int("smtp.port", properties.SSLPort))
 	err := email.SendWithTLS(fmt.Sprintf("%s:%d", properties.Host, properties.SSLPort),
 		smtp.PlainAuth("", properties.Username, properties.Password, properties.Host), &tls.Config{ServerName: properties.Host, MinVersion: tls.VersionTLS12})
+	tracing.End(span, err)
 	if err != nil {
 		return xerr.Email.Wrapf(err, "发送邮件错误 emailProperties=%v", properties).WithStatus(xerr.StatusInternalServerError).
 			WithMsg("发送邮件错误")
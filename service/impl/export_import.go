@@ -4,13 +4,19 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cast"
 	"github.com/yuin/goldmark"
@@ -19,6 +25,7 @@ import (
 	"github.com/go-sonic/sonic/config"
 	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/entity"
 	"github.com/go-sonic/sonic/model/param"
 	"github.com/go-sonic/sonic/service"
@@ -28,29 +35,60 @@ import (
 )
 
 type exportImport struct {
+	Config              *config.Config
 	CategoryService     service.CategoryService
 	PostService         service.PostService
+	SheetService        service.SheetService
 	TagService          service.TagService
 	PostTagService      service.PostTagService
 	PostCategoryService service.PostCategoryService
+	AttachmentService   service.AttachmentService
+	RedirectService     service.RedirectService
+	MetaService         service.MetaService
+	OptionService       service.OptionService
+	SeriesService       service.SeriesService
+	client              *http.Client
 }
 
-func NewExportImport(categoryService service.CategoryService,
+func NewExportImport(config *config.Config,
+	categoryService service.CategoryService,
 	postService service.PostService,
+	sheetService service.SheetService,
 	tagService service.TagService,
 	postTagService service.PostTagService,
 	postCategoryService service.PostCategoryService,
+	attachmentService service.AttachmentService,
+	redirectService service.RedirectService,
+	metaService service.MetaService,
+	optionService service.OptionService,
+	seriesService service.SeriesService,
 ) service.ExportImport {
 	return &exportImport{
+		Config:              config,
 		CategoryService:     categoryService,
 		PostService:         postService,
+		SheetService:        sheetService,
 		TagService:          tagService,
 		PostTagService:      postTagService,
 		PostCategoryService: postCategoryService,
+		AttachmentService:   attachmentService,
+		RedirectService:     redirectService,
+		MetaService:         metaService,
+		OptionService:       optionService,
+		SeriesService:       seriesService,
+		client:              newPublicHTTPClient(consts.ImportMediaTimeout),
 	}
 }
 
 func (e *exportImport) CreateByMarkdown(ctx context.Context, filename string, reader io.Reader) (*entity.Post, error) {
+	post, err := e.ParseMarkdown(ctx, filename, reader)
+	if err != nil {
+		return nil, err
+	}
+	return e.PostService.Create(ctx, post)
+}
+
+func (e *exportImport) ParseMarkdown(ctx context.Context, filename string, reader io.Reader) (*param.Post, error) {
 	contentFrontMatter, err := pageparser.ParseFrontMatterAndContent(reader)
 	if err != nil {
 		return nil, xerr.WithMsg(err, "parse markdown failed").WithStatus(xerr.StatusInternalServerError)
@@ -155,7 +193,7 @@ func (e *exportImport) CreateByMarkdown(ctx context.Context, filename string, re
 						Name: v.(string),
 						Slug: util.Slug(v.(string)),
 					})
-					if err != nil {
+					if err == nil {
 						post.TagIDs = append(post.TagIDs, tag.ID)
 					}
 				} else if err == nil {
@@ -225,7 +263,7 @@ func (e *exportImport) CreateByMarkdown(ctx context.Context, filename string, re
 			}
 		}
 	}
-	return e.PostService.Create(ctx, &post)
+	return &post, nil
 }
 
 func (e *exportImport) ExportMarkdown(ctx context.Context, needFrontMatter bool) (string, error) {
@@ -256,7 +294,7 @@ func (e *exportImport) ExportMarkdown(ctx context.Context, needFrontMatter bool)
 	for _, post := range posts {
 		var markdown strings.Builder
 		if needFrontMatter {
-			frontMatter, err := e.getFrontMatterYaml(ctx, post)
+			frontMatter, err := e.getFrontMatterYaml(ctx, post, true)
 			if err == nil {
 				markdown.WriteString("---\n")
 				markdown.WriteString(frontMatter)
@@ -286,7 +324,16 @@ func (e *exportImport) ExportMarkdown(ctx context.Context, needFrontMatter bool)
 	return backupFile, nil
 }
 
-func (e *exportImport) getFrontMatterYaml(ctx context.Context, post *entity.Post) (string, error) {
+func (e *exportImport) RenderMarkdown(ctx context.Context, post *entity.Post) (string, error) {
+	// the update time is left out so that the file changes only when the post is edited
+	frontMatter, err := e.getFrontMatterYaml(ctx, post, false)
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + frontMatter + "---\n" + post.OriginalContent, nil
+}
+
+func (e *exportImport) getFrontMatterYaml(ctx context.Context, post *entity.Post, withUpdateTime bool) (string, error) {
 	tags, err := e.PostTagService.ListTagByPostID(ctx, post.ID)
 	if err != nil {
 		return "", err
@@ -306,15 +353,15 @@ func (e *exportImport) getFrontMatterYaml(ctx context.Context, post *entity.Post
 	frontMatter := make(map[string]any)
 	frontMatter["title"] = post.Title
 	frontMatter["draft"] = post.Status == consts.PostStatusDraft
-	frontMatter["date"] = post.CreateTime.Format("2006-01-02 15:04:05 MST")
+	frontMatter["date"] = post.CreateTime.Format(time.RFC3339)
 	frontMatter["comments"] = !post.DisallowComment
 	frontMatter["slug"] = post.Slug
 	if post.EditTime != nil {
-		frontMatter["lastmod"] = post.EditTime.Format("2006-01-02 15:04:05 MST")
+		frontMatter["lastmod"] = post.EditTime.Format(time.RFC3339)
 	}
 
-	if post.UpdateTime != nil && post.UpdateTime != (&time.Time{}) {
-		frontMatter["updated"] = post.UpdateTime.Format("2006-01-02 15:04:05 MST")
+	if withUpdateTime && post.UpdateTime != nil && post.UpdateTime != (&time.Time{}) {
+		frontMatter["updated"] = post.UpdateTime.Format(time.RFC3339)
 	}
 	if post.Summary != "" {
 		frontMatter["summary"] = post.Summary
@@ -517,3 +564,182 @@ func parseJekyllFilename(filename string) (time.Time, string, error) {
 
 	return postDate, postName, nil
 }
+
+// contentImport keeps the state of importing the content exported by another blog
+type contentImport struct {
+	report *dto.ImportReport
+	// uploads is the uploaded media directory, the media not in it are downloaded
+	uploads fs.FS
+	// uploadsPrefixes are the directories in uploads to look for the media besides the root
+	uploadsPrefixes []string
+	// media maps the source url or path of the imported media to the path of the attachment,
+	// it is empty for the media that failed to import
+	media map[string]string
+}
+
+func newContentImport(uploads fs.FS, uploadsPrefixes ...string) *contentImport {
+	return &contentImport{
+		report: &dto.ImportReport{
+			Skipped:   make([]*dto.ImportItem, 0),
+			Conflicts: make([]*dto.ImportItem, 0),
+		},
+		uploads:         uploads,
+		uploadsPrefixes: uploadsPrefixes,
+		media:           make(map[string]string),
+	}
+}
+
+func (c *contentImport) skip(itemType, title, slug, reason string) {
+	c.report.Skipped = append(c.report.Skipped, &dto.ImportItem{Type: itemType, Title: title, Slug: slug, Reason: reason})
+}
+
+func (c *contentImport) conflict(itemType, title, slug, reason string) {
+	c.report.Conflicts = append(c.report.Conflicts, &dto.ImportItem{Type: itemType, Title: title, Slug: slug, Reason: reason})
+}
+
+// importMedia copies the media from the uploaded directory by the relative path, or downloads it by the source url,
+// into the active file storage, and returns the path of the attachment
+func (e *exportImport) importMedia(ctx context.Context, c *contentImport, source, relativePath string) (string, bool) {
+	if attachmentPath, ok := c.media[source]; ok {
+		return attachmentPath, attachmentPath != ""
+	}
+	c.media[source] = ""
+	content, err := e.readMedia(ctx, c, source, relativePath)
+	if err != nil {
+		c.skip("attachment", source, "", err.Error())
+		return "", false
+	}
+	filename := path.Base(relativePath)
+	if relativePath == "" {
+		sourceURL, _ := url.Parse(source)
+		filename = path.Base(sourceURL.Path)
+	}
+	fileHeader, err := newFileHeader(filename, content)
+	if err != nil {
+		c.skip("attachment", source, "", err.Error())
+		return "", false
+	}
+	attachment, err := e.AttachmentService.Upload(ctx, fileHeader)
+	if err != nil {
+		c.skip("attachment", source, "", err.Error())
+		return "", false
+	}
+	c.media[source] = attachment.Path
+	c.report.Attachments++
+	return attachment.Path, true
+}
+
+func (e *exportImport) readMedia(ctx context.Context, c *contentImport, source, relativePath string) ([]byte, error) {
+	if c.uploads != nil && relativePath != "" && fs.ValidPath(relativePath) {
+		names := []string{relativePath}
+		for _, prefix := range c.uploadsPrefixes {
+			names = append(names, path.Join(prefix, relativePath))
+		}
+		for _, name := range names {
+			file, err := c.uploads.Open(name)
+			if err != nil {
+				continue
+			}
+			content, err := io.ReadAll(io.LimitReader(file, consts.ImportMediaLimit+1))
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+			if len(content) > consts.ImportMediaLimit {
+				return nil, xerr.BadParam.New("the media is too large")
+			}
+			return content, nil
+		}
+	}
+	sourceURL, err := url.Parse(source)
+	if err != nil || (sourceURL.Scheme != "http" && sourceURL.Scheme != "https") {
+		return nil, xerr.BadParam.New("the media is not found")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerr.BadParam.New("download the media status=%d", resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, consts.ImportMediaLimit+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > consts.ImportMediaLimit {
+		return nil, xerr.BadParam.New("the media is too large")
+	}
+	return content, nil
+}
+
+// newFileHeader wraps the content into a multipart file header accepted by the file storages
+func newFileHeader(filename string, content []byte) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(content); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	// the form is kept in memory since the max memory is larger than the content
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(len(content)) + 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, xerr.NoType.New("empty multipart form")
+	}
+	return files[0], nil
+}
+
+// addRedirect redirects the old path of the imported post or sheet to its new path if they differ
+func (e *exportImport) addRedirect(ctx context.Context, c *contentImport, post *entity.Post, oldPath string) {
+	fullPath, err := e.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		c.conflict("redirect", post.Title, oldPath, err.Error())
+		return
+	}
+	target := fullPath
+	if fullURL, err := url.Parse(fullPath); err == nil {
+		target = fullURL.RequestURI()
+	}
+	if normalizeRedirectSource(oldPath) == target {
+		return
+	}
+	_, err = e.RedirectService.Create(ctx, &param.Redirect{
+		Source:    oldPath,
+		Target:    target,
+		Permanent: true,
+	})
+	if err != nil {
+		c.conflict("redirect", post.Title, oldPath, xerr.GetMessage(err))
+		return
+	}
+	c.report.Redirects++
+}
+
+// importSlug converts the url encoded slug or name of other blogs to the slug,
+// the fallback is used when nothing is left, e.g. for the names in Chinese
+func importSlug(name, fallback string) string {
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	hasSlugRune := strings.IndexFunc(name, func(r rune) bool {
+		return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+	}) >= 0
+	if !hasSlugRune {
+		return fallback
+	}
+	return util.Slug(strings.ToLower(name))
+}
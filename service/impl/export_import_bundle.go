@@ -0,0 +1,913 @@
+package impl
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// the layout of the Markdown bundle, the links to the attachments are relative to the files referring to them
+const (
+	bundlePostsDir       = "posts"
+	bundleSheetsDir      = "sheets"
+	bundleJournalsDir    = "journals"
+	bundleAttachmentsDir = "attachments"
+	bundleTaxonomiesName = "taxonomies.json"
+	bundleCommentsSuffix = ".comments.json"
+)
+
+type bundleTaxonomies struct {
+	Categories []*bundleCategory `json:"categories"`
+	Tags       []*bundleTag      `json:"tags"`
+	Series     []*bundleSeries   `json:"series,omitempty"`
+}
+
+type bundleCategory struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description,omitempty"`
+	Thumbnail   string `json:"thumbnail,omitempty"`
+	Password    string `json:"password,omitempty"`
+	Priority    int32  `json:"priority,omitempty"`
+	// Parent is the slug of the parent category
+	Parent string `json:"parent,omitempty"`
+}
+
+type bundleTag struct {
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Color     string `json:"color,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+type bundleSeries struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description,omitempty"`
+	Thumbnail   string `json:"thumbnail,omitempty"`
+	// Posts are the slugs of the posts in order
+	Posts []string `json:"posts"`
+}
+
+type bundleComment struct {
+	ID                int32                `json:"id"`
+	ParentID          int32                `json:"parentId"`
+	Author            string               `json:"author"`
+	Email             string               `json:"email"`
+	AuthorURL         string               `json:"authorUrl"`
+	IPAddress         string               `json:"ipAddress"`
+	UserAgent         string               `json:"userAgent"`
+	Content           string               `json:"content"`
+	Status            consts.CommentStatus `json:"status"`
+	IsAdmin           bool                 `json:"isAdmin"`
+	AllowNotification bool                 `json:"allowNotification"`
+	TopPriority       int32                `json:"topPriority"`
+	Likes             int32                `json:"likes"`
+	CreateTime        time.Time            `json:"createTime"`
+}
+
+// bundleExport keeps the state of exporting the Markdown bundle
+type bundleExport struct {
+	writer *zip.Writer
+	// files maps the urls and the paths referring to the attachments to their files in the bundle
+	files map[string]*bundleFile
+	// hosts are the hosts serving the attachments, the urls of other hosts are kept
+	hosts      map[string]struct{}
+	categories map[int32]*entity.Category
+	client     *http.Client
+}
+
+// bundleFile is an attachment or its thumbnail, it is written into the bundle on the first reference
+type bundleFile struct {
+	name           string
+	attachmentType consts.AttachmentType
+	// relativePath is the path in the file storage and url is the url it is served at
+	relativePath string
+	url          string
+	written      bool
+	failed       bool
+}
+
+// ExportMarkdownBundle exports the posts, sheets and journals in Markdown with their comments, metas and taxonomies,
+// the attachments they refer to are bundled and the links are rewritten to the bundled files,
+// the bundle is imported back by ImportMarkdownBundle
+func (e *exportImport) ExportMarkdownBundle(ctx context.Context) (string, error) {
+	backupFilePath := config.BackupMarkdownDir
+	if err := os.MkdirAll(backupFilePath, os.ModePerm); err != nil {
+		return "", xerr.NoType.Wrap(err).WithMsg("create dir err")
+	}
+	backupFilename := consts.SonicBackupMarkdownPrefix + time.Now().Format("2006-01-02-15-04-05") + util.GenUUIDWithOutDash() + ".zip"
+	backupFile := filepath.Join(backupFilePath, backupFilename)
+	file, err := os.Create(backupFile)
+	if err != nil {
+		return "", xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("create file err")
+	}
+	defer file.Close()
+
+	b := &bundleExport{
+		writer:     zip.NewWriter(file),
+		files:      make(map[string]*bundleFile),
+		hosts:      make(map[string]struct{}),
+		categories: make(map[int32]*entity.Category),
+		client:     &http.Client{Timeout: consts.ImportMediaTimeout},
+	}
+	err = e.loadBundleFiles(ctx, b)
+	if err == nil {
+		err = e.exportBundleTaxonomies(ctx, b)
+	}
+	if err == nil {
+		err = e.exportBundlePosts(ctx, b)
+	}
+	if err == nil {
+		err = e.exportBundleJournals(ctx, b)
+	}
+	if err == nil {
+		err = b.writer.Close()
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(backupFile)
+		return "", err
+	}
+	return backupFile, nil
+}
+
+func (e *exportImport) loadBundleFiles(ctx context.Context, b *bundleExport) error {
+	attachmentDAL := dal.GetQueryByCtx(ctx).Attachment
+	attachments, err := attachmentDAL.WithContext(ctx).Order(attachmentDAL.ID).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	attachmentDTOs, err := e.AttachmentService.ConvertToDTOs(ctx, attachments)
+	if err != nil {
+		return err
+	}
+	if blogURL, err := e.OptionService.GetBlogBaseURL(ctx); err == nil {
+		if blogURL, err := url.Parse(blogURL); err == nil && blogURL.Host != "" {
+			b.hosts[blogURL.Host] = struct{}{}
+		}
+	}
+	for i, attachment := range attachments {
+		b.addFile(attachment.Type, attachment.Path, attachmentDTOs[i].Path)
+		if attachment.ThumbPath != attachment.Path {
+			b.addFile(attachment.Type, attachment.ThumbPath, attachmentDTOs[i].ThumbPath)
+		}
+	}
+	return nil
+}
+
+func (b *bundleExport) addFile(attachmentType consts.AttachmentType, relativePath, fileURL string) {
+	if relativePath == "" || fileURL == "" {
+		return
+	}
+	parsedURL, err := url.Parse(fileURL)
+	if err != nil {
+		return
+	}
+	if parsedURL.Host != "" {
+		b.hosts[parsedURL.Host] = struct{}{}
+	}
+	file := &bundleFile{
+		name:           path.Join(bundleAttachmentsDir, strings.TrimPrefix(path.Clean("/"+parsedURL.Path), "/")),
+		attachmentType: attachmentType,
+		relativePath:   relativePath,
+		url:            fileURL,
+	}
+	b.files[fileURL] = file
+	b.files[parsedURL.Path] = file
+	if attachmentType == consts.AttachmentTypeLocal {
+		b.files["/"+strings.TrimPrefix(relativePath, "/")] = file
+	}
+}
+
+// bundleLink writes the attachment referred by the reference into the bundle and returns the link relative to the dir,
+// the empty string is returned if the reference is not an attachment
+func (e *exportImport) bundleLink(ctx context.Context, b *bundleExport, dir, reference string) string {
+	file, ok := b.files[reference]
+	if !ok {
+		referenceURL, err := url.Parse(reference)
+		if err != nil || (referenceURL.Host == "" && referenceURL.Scheme != "") {
+			return ""
+		}
+		if _, ok := b.hosts[referenceURL.Host]; referenceURL.Host != "" && !ok {
+			return ""
+		}
+		if file, ok = b.files[referenceURL.Path]; !ok {
+			return ""
+		}
+	}
+	if file.failed {
+		return ""
+	}
+	if !file.written {
+		if err := e.writeBundleFile(ctx, b, file); err != nil {
+			log.CtxWarnf(ctx, "export attachment %s err=%v", file.url, err)
+			file.failed = true
+			return ""
+		}
+		file.written = true
+	}
+	if dir == "." {
+		return file.name
+	}
+	return strings.Repeat("../", strings.Count(dir, "/")+1) + file.name
+}
+
+func (e *exportImport) writeBundleFile(ctx context.Context, b *bundleExport, file *bundleFile) error {
+	var content []byte
+	if file.attachmentType == consts.AttachmentTypeLocal {
+		localContent, err := os.ReadFile(filepath.Join(e.Config.Sonic.WorkDir, filepath.FromSlash(file.relativePath)))
+		if err != nil {
+			return err
+		}
+		content = localContent
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := b.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return xerr.NoType.New("download the attachment status=%d", resp.StatusCode)
+		}
+		// the remote attachment is bounded as the importing does, so that the bundle can be imported again
+		if content, err = io.ReadAll(io.LimitReader(resp.Body, consts.ImportMediaLimit+1)); err != nil {
+			return err
+		}
+		if len(content) > consts.ImportMediaLimit {
+			return xerr.NoType.New("the attachment is too large")
+		}
+	}
+	writer, err := b.writer.Create(file.name)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(content)
+	return err
+}
+
+// rewriteBundleMedia rewrites the links to the attachments in the Markdown or the html to the bundled files
+func (e *exportImport) rewriteBundleMedia(ctx context.Context, b *bundleExport, dir, content string) string {
+	replace := func(pattern *regexp.Regexp) func(string) string {
+		return func(match string) string {
+			groups := pattern.FindStringSubmatch(match)
+			if link := e.bundleLink(ctx, b, dir, groups[2]); link != "" {
+				return groups[1] + link
+			}
+			return match
+		}
+	}
+	content = markdownLinkPattern.ReplaceAllStringFunc(content, replace(markdownLinkPattern))
+	return htmlMediaPattern.ReplaceAllStringFunc(content, replace(htmlMediaPattern))
+}
+
+func (e *exportImport) bundleMediaLink(ctx context.Context, b *bundleExport, dir, reference string) string {
+	if reference == "" {
+		return ""
+	}
+	if link := e.bundleLink(ctx, b, dir, reference); link != "" {
+		return link
+	}
+	return reference
+}
+
+func (e *exportImport) exportBundleTaxonomies(ctx context.Context, b *bundleExport) error {
+	categories, err := e.CategoryService.ListAll(ctx, nil)
+	if err != nil {
+		return err
+	}
+	tags, err := e.TagService.ListAll(ctx, nil)
+	if err != nil {
+		return err
+	}
+	taxonomies := &bundleTaxonomies{
+		Categories: make([]*bundleCategory, 0, len(categories)),
+		Tags:       make([]*bundleTag, 0, len(tags)),
+	}
+	for _, category := range categories {
+		b.categories[category.ID] = category
+	}
+	for _, category := range categories {
+		bundled := &bundleCategory{
+			Name:        category.Name,
+			Slug:        category.Slug,
+			Description: category.Description,
+			Thumbnail:   e.bundleMediaLink(ctx, b, ".", category.Thumbnail),
+			Password:    category.Password,
+			Priority:    category.Priority,
+		}
+		if parent, ok := b.categories[category.ParentID]; ok {
+			bundled.Parent = parent.Slug
+		}
+		taxonomies.Categories = append(taxonomies.Categories, bundled)
+	}
+	for _, tag := range tags {
+		taxonomies.Tags = append(taxonomies.Tags, &bundleTag{
+			Name:      tag.Name,
+			Slug:      tag.Slug,
+			Color:     tag.Color,
+			Thumbnail: e.bundleMediaLink(ctx, b, ".", tag.Thumbnail),
+		})
+	}
+	series, err := e.SeriesService.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, one := range series {
+		posts, err := e.SeriesService.ListPosts(ctx, one.ID, nil)
+		if err != nil {
+			return err
+		}
+		slugs := make([]string, 0, len(posts))
+		for _, post := range posts {
+			slugs = append(slugs, post.Slug)
+		}
+		taxonomies.Series = append(taxonomies.Series, &bundleSeries{
+			Name:        one.Name,
+			Slug:        one.Slug,
+			Description: one.Description,
+			Thumbnail:   e.bundleMediaLink(ctx, b, ".", one.Thumbnail),
+			Posts:       slugs,
+		})
+	}
+	return writeBundleJSON(b, bundleTaxonomiesName, taxonomies)
+}
+
+func (e *exportImport) exportBundlePosts(ctx context.Context, b *bundleExport) error {
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	posts, err := postDAL.WithContext(ctx).Order(postDAL.ID).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	for _, post := range posts {
+		dir, name := bundlePostsDir, post.CreateTime.Format("2006-01-02")+"-"+strings.ReplaceAll(post.Slug, "/", "-")
+		commentType := consts.CommentTypePost
+		if post.Type == consts.PostTypeSheet {
+			dir, name = bundleSheetsDir, strings.ReplaceAll(post.Slug, "/", "-")
+			commentType = consts.CommentTypeSheet
+		}
+		frontMatter, err := e.bundleFrontMatter(ctx, b, dir, post)
+		if err != nil {
+			return err
+		}
+		content := e.rewriteBundleMedia(ctx, b, dir, post.OriginalContent)
+		if err := writeBundleMarkdown(b, path.Join(dir, name+".md"), frontMatter, content); err != nil {
+			return err
+		}
+		if err := e.exportBundleComments(ctx, b, path.Join(dir, name+bundleCommentsSuffix), post.ID, commentType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *exportImport) bundleFrontMatter(ctx context.Context, b *bundleExport, dir string, post *entity.Post) (map[string]any, error) {
+	frontMatter := map[string]any{
+		"title":    post.Title,
+		"slug":     post.Slug,
+		"status":   bundleEnumName(post.Status),
+		"editor":   util.IfElse(post.EditorType == consts.EditorTypeRichText, "richtext", "markdown"),
+		"date":     post.CreateTime.Format(time.RFC3339),
+		"comments": !post.DisallowComment,
+	}
+	if post.EditTime != nil {
+		frontMatter["lastmod"] = post.EditTime.Format(time.RFC3339)
+	}
+	optional := map[string]string{
+		"summary":     post.Summary,
+		"thumbnail":   e.bundleMediaLink(ctx, b, dir, post.Thumbnail),
+		"password":    post.Password,
+		"template":    post.Template,
+		"keywords":    post.MetaKeywords,
+		"description": post.MetaDescription,
+	}
+	for key, value := range optional {
+		if value != "" {
+			frontMatter[key] = value
+		}
+	}
+	if post.TopPriority > 0 {
+		frontMatter["top"] = post.TopPriority
+	}
+	if post.Visits > 0 {
+		frontMatter["visits"] = post.Visits
+	}
+	if post.Likes > 0 {
+		frontMatter["likes"] = post.Likes
+	}
+
+	if post.Type == consts.PostTypePost {
+		categories, err := e.PostCategoryService.ListCategoryByPostID(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		// a category is written as the names from the root so that the hierarchy is kept
+		categoryPaths := make([][]string, 0, len(categories))
+		for _, category := range categories {
+			categoryPath := []string{category.Name}
+			for parent, ok := b.categories[category.ParentID]; ok && len(categoryPath) < len(b.categories); parent, ok = b.categories[parent.ParentID] {
+				categoryPath = append([]string{parent.Name}, categoryPath...)
+			}
+			categoryPaths = append(categoryPaths, categoryPath)
+		}
+		if len(categoryPaths) > 0 {
+			frontMatter["categories"] = categoryPaths
+		}
+		tags, err := e.PostTagService.ListTagByPostID(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		tagNames := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+		if len(tagNames) > 0 {
+			frontMatter["tags"] = tagNames
+		}
+	}
+
+	metas, err := e.MetaService.GetPostMeta(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(metas) > 0 {
+		metaMap := make(map[string]string, len(metas))
+		for _, meta := range metas {
+			metaMap[meta.MetaKey] = meta.MetaValue
+		}
+		frontMatter["metas"] = metaMap
+	}
+	return frontMatter, nil
+}
+
+func (e *exportImport) exportBundleJournals(ctx context.Context, b *bundleExport) error {
+	journalDAL := dal.GetQueryByCtx(ctx).Journal
+	journals, err := journalDAL.WithContext(ctx).Order(journalDAL.ID).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	for _, journal := range journals {
+		name := path.Join(bundleJournalsDir, journal.CreateTime.Format("2006-01-02-150405")+"-"+strconv.Itoa(int(journal.ID)))
+		frontMatter := map[string]any{
+			"date": journal.CreateTime.Format(time.RFC3339),
+			"type": bundleEnumName(journal.Type),
+		}
+		if journal.Likes > 0 {
+			frontMatter["likes"] = journal.Likes
+		}
+		content := e.rewriteBundleMedia(ctx, b, bundleJournalsDir, journal.SourceContent)
+		if err := writeBundleMarkdown(b, name+".md", frontMatter, content); err != nil {
+			return err
+		}
+		if err := e.exportBundleComments(ctx, b, name+bundleCommentsSuffix, journal.ID, consts.CommentTypeJournal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportBundleComments writes the comments of the post, sheet or journal into the sidecar file if there are any
+func (e *exportImport) exportBundleComments(ctx context.Context, b *bundleExport, name string, postID int32, commentType consts.CommentType) error {
+	commentDAL := dal.GetQueryByCtx(ctx).Comment
+	comments, err := commentDAL.WithContext(ctx).Where(commentDAL.PostID.Eq(postID), commentDAL.Type.Eq(commentType)).Order(commentDAL.ID).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	bundled := make([]*bundleComment, 0, len(comments))
+	for _, comment := range comments {
+		bundled = append(bundled, &bundleComment{
+			ID:                comment.ID,
+			ParentID:          comment.ParentID,
+			Author:            comment.Author,
+			Email:             comment.Email,
+			AuthorURL:         comment.AuthorURL,
+			IPAddress:         comment.IPAddress,
+			UserAgent:         comment.UserAgent,
+			Content:           comment.Content,
+			Status:            comment.Status,
+			IsAdmin:           comment.IsAdmin,
+			AllowNotification: comment.AllowNotification,
+			TopPriority:       comment.TopPriority,
+			Likes:             comment.Likes,
+			CreateTime:        comment.CreateTime,
+		})
+	}
+	return writeBundleJSON(b, name, bundled)
+}
+
+// bundleEnumName returns the name of the enum in json, like PUBLISHED of the post status
+func bundleEnumName(value any) string {
+	name, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(string(name), `"`)
+}
+
+func writeBundleMarkdown(b *bundleExport, name string, frontMatter map[string]any, content string) error {
+	out, err := yaml.Marshal(frontMatter)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	writer, err := b.writer.Create(name)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("write file err")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(out)
+	buf.WriteString("---\n")
+	buf.WriteString(content)
+	if _, err = writer.Write(buf.Bytes()); err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("write file err")
+	}
+	return nil
+}
+
+func writeBundleJSON(b *bundleExport, name string, value any) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("json marshal err")
+	}
+	writer, err := b.writer.Create(name)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("write file err")
+	}
+	if _, err = writer.Write(content); err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("write file err")
+	}
+	return nil
+}
+
+// ImportMarkdownBundle imports the bundle exported by ExportMarkdownBundle, the posts and sheets whose slugs are used
+// and the journals of the same time are skipped so that importing the same bundle again does not duplicate them
+func (e *exportImport) ImportMarkdownBundle(ctx context.Context, bundle fs.FS) (*dto.ImportReport, error) {
+	ctx = context.WithValue(ctx, consts.ContentImport, true)
+	bundle, err := siteRoot(bundle, bundleTaxonomiesName, bundlePostsDir, bundleSheetsDir, bundleJournalsDir)
+	if err != nil {
+		return nil, err
+	}
+	s := newSiteImport(bundle)
+	taxonomies, err := readBundleTaxonomies(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.importBundleTaxonomies(ctx, s, taxonomies); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{bundlePostsDir, bundleSheetsDir} {
+		names, err := fs.Glob(bundle, dir+"/*.md")
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			p, err := parseBundlePost(bundle, name, dir == bundleSheetsDir)
+			if err != nil {
+				s.skip(util.IfElse(dir == bundleSheetsDir, "sheet", "post").(string), name, "", err.Error())
+				continue
+			}
+			post, err := e.importSitePost(ctx, s, p)
+			if err != nil {
+				return nil, err
+			}
+			if post == nil {
+				continue
+			}
+			commentType := util.IfElse(post.Type == consts.PostTypeSheet, consts.CommentTypeSheet, consts.CommentTypePost).(consts.CommentType)
+			if err := e.importBundleComments(ctx, s, name, post.ID, commentType, post.Slug); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	names, err := fs.Glob(bundle, bundleJournalsDir+"/*.md")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := e.importBundleJournal(ctx, s, name); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.importBundleSeries(ctx, s, taxonomies.Series); err != nil {
+		return nil, err
+	}
+	return s.report, nil
+}
+
+// readBundleTaxonomies reads the taxonomies of the bundle, they are empty when the bundle has no taxonomies file
+func readBundleTaxonomies(bundle fs.FS) (*bundleTaxonomies, error) {
+	taxonomies := &bundleTaxonomies{}
+	content, err := fs.ReadFile(bundle, bundleTaxonomiesName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return taxonomies, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, taxonomies); err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parse " + bundleTaxonomiesName + " failed")
+	}
+	return taxonomies, nil
+}
+
+// importBundleTaxonomies creates the categories with their parents first and the tags,
+// the existing ones of the same slugs are reused
+func (e *exportImport) importBundleTaxonomies(ctx context.Context, s *siteImport, taxonomies *bundleTaxonomies) error {
+
+	// the paths of names from the root are the keys of the categories used by the posts
+	categoryPaths := make(map[string][]string)
+	categoryIDs := make(map[string]int32)
+	pending := taxonomies.Categories
+	for len(pending) > 0 {
+		next := make([]*bundleCategory, 0)
+		for _, category := range pending {
+			parentID, ok := categoryIDs[category.Parent]
+			if category.Parent != "" && !ok {
+				next = append(next, category)
+				continue
+			}
+			existing, err := e.CategoryService.GetBySlug(ctx, category.Slug)
+			switch {
+			case err == nil:
+			case xerr.GetType(err) == xerr.NoRecord:
+				thumbnail := category.Thumbnail
+				if attachmentPath := e.resolveSiteMedia(ctx, s, []string{"."}, thumbnail); attachmentPath != "" {
+					thumbnail = attachmentPath
+				}
+				existing, err = e.CategoryService.Create(ctx, &param.Category{
+					Name:        category.Name,
+					Slug:        category.Slug,
+					Description: category.Description,
+					Thumbnail:   thumbnail,
+					Password:    category.Password,
+					ParentID:    parentID,
+					Priority:    category.Priority,
+				})
+				if err != nil {
+					return err
+				}
+				s.report.Categories++
+			default:
+				return err
+			}
+			categoryIDs[category.Slug] = existing.ID
+			categoryPaths[category.Slug] = append(append([]string{}, categoryPaths[category.Parent]...), category.Name)
+			s.categories[strings.Join(categoryPaths[category.Slug], "/")] = existing.ID
+		}
+		if len(next) == len(pending) {
+			// the parents are missing in the file, the categories are created at the top level
+			for _, category := range next {
+				category.Parent = ""
+			}
+		}
+		pending = next
+	}
+
+	for _, tag := range taxonomies.Tags {
+		existing, err := e.TagService.GetBySlug(ctx, tag.Slug)
+		switch {
+		case err == nil:
+		case xerr.GetType(err) == xerr.NoRecord:
+			thumbnail := tag.Thumbnail
+			if attachmentPath := e.resolveSiteMedia(ctx, s, []string{"."}, thumbnail); attachmentPath != "" {
+				thumbnail = attachmentPath
+			}
+			existing, err = e.TagService.Create(ctx, &param.Tag{
+				Name:      tag.Name,
+				Slug:      tag.Slug,
+				Color:     tag.Color,
+				Thumbnail: thumbnail,
+			})
+			if err != nil {
+				return err
+			}
+			s.report.Tags++
+		default:
+			return err
+		}
+		s.tags[tag.Name] = existing.ID
+	}
+	return nil
+}
+
+// importBundleSeries creates the series after the posts are imported and appends the posts to them in order,
+// the existing series of the same slugs are reused and the posts already in a series are kept there
+func (e *exportImport) importBundleSeries(ctx context.Context, s *siteImport, series []*bundleSeries) error {
+	for _, bundled := range series {
+		existing, err := e.SeriesService.GetBySlug(ctx, bundled.Slug)
+		switch {
+		case err == nil:
+		case xerr.GetType(err) == xerr.NoRecord:
+			thumbnail := bundled.Thumbnail
+			if attachmentPath := e.resolveSiteMedia(ctx, s, []string{"."}, thumbnail); attachmentPath != "" {
+				thumbnail = attachmentPath
+			}
+			existing, err = e.SeriesService.Create(ctx, &param.Series{
+				Name:        bundled.Name,
+				Slug:        bundled.Slug,
+				Description: bundled.Description,
+				Thumbnail:   thumbnail,
+			})
+			if err != nil {
+				return err
+			}
+			s.report.Series++
+		default:
+			return err
+		}
+		for _, slug := range bundled.Posts {
+			post, err := e.PostService.GetBySlug(ctx, slug)
+			if xerr.GetType(err) == xerr.NoRecord || (err == nil && post.Type != consts.PostTypePost) {
+				s.skip("series", bundled.Name, slug, "the post is not found")
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := e.SeriesService.GetByPostID(ctx, post.ID); err == nil {
+				continue
+			} else if xerr.GetType(err) != xerr.NoRecord {
+				return err
+			}
+			if err := e.SeriesService.AssignPost(ctx, post.ID, existing.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseBundlePost(bundle fs.FS, name string, sheet bool) (*sitePost, error) {
+	frontMatter, content, err := readSiteFile(bundle, name)
+	if err != nil {
+		return nil, err
+	}
+	p := &sitePost{
+		sheet:           sheet,
+		name:            strings.TrimSuffix(path.Base(name), path.Ext(name)),
+		dirs:            []string{path.Dir(name)},
+		content:         content,
+		title:           frontMatterString(frontMatter, "title"),
+		slug:            frontMatterString(frontMatter, "slug"),
+		summary:         frontMatterString(frontMatter, "summary"),
+		password:        frontMatterString(frontMatter, "password"),
+		keywords:        frontMatterString(frontMatter, "keywords"),
+		thumbnail:       frontMatterString(frontMatter, "thumbnail"),
+		topPriority:     cast.ToInt32(frontMatter["top"]),
+		createTime:      frontMatterTime(frontMatter, "date"),
+		editTime:        frontMatterTime(frontMatter, "lastmod"),
+		categories:      hexoCategories(frontMatter["categories"]),
+		tags:            frontMatterStrings(frontMatter, "tags"),
+		oldPaths:        make([]string, 0),
+		richText:        frontMatterString(frontMatter, "editor") == "richtext",
+		template:        frontMatterString(frontMatter, "template"),
+		metaDescription: frontMatterString(frontMatter, "description"),
+		visits:          cast.ToInt64(frontMatter["visits"]),
+		likes:           cast.ToInt64(frontMatter["likes"]),
+		metas:           make([]param.Meta, 0),
+	}
+	if comments, ok := frontMatterBool(frontMatter, "comments"); ok {
+		p.disallowComment = !comments
+	}
+	if status, err := consts.PostStatusFromString(strings.ToUpper(frontMatterString(frontMatter, "status"))); err == nil {
+		p.status = &status
+	}
+	metas := cast.ToStringMapString(frontMatter["metas"])
+	keys := make([]string, 0, len(metas))
+	for key := range metas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		p.metas = append(p.metas, param.Meta{Key: key, Value: metas[key]})
+	}
+	return p, nil
+}
+
+func (e *exportImport) importBundleJournal(ctx context.Context, s *siteImport, name string) error {
+	frontMatter, content, err := readSiteFile(s.uploads, name)
+	if err != nil {
+		s.skip("journal", name, "", err.Error())
+		return nil
+	}
+	createTime := frontMatterTime(frontMatter, "date")
+	if createTime.IsZero() {
+		createTime = time.Now()
+	}
+	journalDAL := dal.GetQueryByCtx(ctx).Journal
+	// the times are exported in seconds
+	count, err := journalDAL.WithContext(ctx).Where(journalDAL.CreateTime.Gte(createTime), journalDAL.CreateTime.Lt(createTime.Add(time.Second))).Count()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if count > 0 {
+		s.conflict("journal", name, "", "a journal of the same time exists")
+		return nil
+	}
+
+	content = e.rewriteSiteMedia(ctx, s, []string{path.Dir(name)}, content)
+	var buf bytes.Buffer
+	if err := importMarkdown.Convert([]byte(content), &buf); err != nil {
+		s.skip("journal", name, "", err.Error())
+		return nil
+	}
+	journal := &entity.Journal{
+		CreateTime:    createTime,
+		SourceContent: content,
+		Content:       buf.String(),
+		Likes:         cast.ToInt64(frontMatter["likes"]),
+		Type:          util.IfElse(strings.EqualFold(frontMatterString(frontMatter, "type"), "INTIMATE"), consts.JournalTypeIntimate, consts.JournalTypePublic).(consts.JournalType),
+	}
+	if err := journalDAL.WithContext(ctx).Create(journal); err != nil {
+		return WrapDBErr(err)
+	}
+	s.report.Journals++
+	return e.importBundleComments(ctx, s, name, journal.ID, consts.CommentTypeJournal, name)
+}
+
+// importBundleComments creates the comments in the sidecar file of the Markdown file if there is one,
+// the replies are attached to the top level when their parents are not imported
+func (e *exportImport) importBundleComments(ctx context.Context, s *siteImport, name string, postID int32, commentType consts.CommentType, slug string) error {
+	sidecar := strings.TrimSuffix(name, path.Ext(name)) + bundleCommentsSuffix
+	content, err := fs.ReadFile(s.uploads, sidecar)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var comments []*bundleComment
+	if err := json.Unmarshal(content, &comments); err != nil {
+		s.skip("comment", sidecar, slug, err.Error())
+		return nil
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].ID < comments[j].ID
+	})
+	commentDAL := dal.GetQueryByCtx(ctx).Comment
+	imported := make(map[int32]int32, len(comments))
+	for _, source := range comments {
+		comment := &entity.Comment{
+			Type:              commentType,
+			CreateTime:        source.CreateTime,
+			AllowNotification: source.AllowNotification,
+			Author:            truncateRunes(util.IfElse(source.Author != "", source.Author, "Anonymous").(string), 50),
+			AuthorURL:         truncateRunes(source.AuthorURL, 511),
+			Content:           truncateRunes(source.Content, 1023),
+			Email:             truncateRunes(source.Email, 255),
+			GravatarMd5:       util.Md5Hex(source.Email),
+			IPAddress:         truncateRunes(source.IPAddress, 127),
+			IsAdmin:           source.IsAdmin,
+			ParentID:          imported[source.ParentID],
+			PostID:            postID,
+			Status:            source.Status,
+			TopPriority:       source.TopPriority,
+			UserAgent:         truncateRunes(source.UserAgent, 511),
+			Likes:             source.Likes,
+		}
+		err := commentDAL.WithContext(ctx).Select(field.Star).Omit(commentDAL.UpdateTime).Create(comment)
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		imported[source.ID] = comment.ID
+		s.report.Comments++
+	}
+	return nil
+}
@@ -0,0 +1,545 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// haloMediaPattern matches the urls of the files uploaded to Halo, with or without the host,
+// the url is preceded by a delimiter so that the paths like /foo/upload/ are not matched
+var haloMediaPattern = regexp.MustCompile(`(^|[\s"'(=,\]>])((?:(?:https?:)?//[^/\s"'()<>]+)?/upload/[^\s"'()<>?#\]]+)`)
+
+// the data exported by Halo 1.x, the enums are in their names and the times are in milliseconds
+type haloBackup struct {
+	Version        string             `json:"version"`
+	Options        []haloOption       `json:"options"`
+	Categories     []haloCategory     `json:"categories"`
+	Tags           []haloTag          `json:"tags"`
+	Posts          []haloPost         `json:"posts"`
+	Sheets         []haloPost         `json:"sheets"`
+	Contents       []haloContent      `json:"contents"`
+	PostCategories []haloPostCategory `json:"post_categories"`
+	PostTags       []haloPostTag      `json:"post_tags"`
+	PostComments   []haloComment      `json:"post_comments"`
+	SheetComments  []haloComment      `json:"sheet_comments"`
+	PostMetas      []haloMeta         `json:"post_metas"`
+	SheetMetas     []haloMeta         `json:"sheet_metas"`
+}
+
+type haloOption struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+type haloCategory struct {
+	ID          int32  `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Thumbnail   string `json:"thumbnail"`
+	Password    string `json:"password"`
+	ParentID    int32  `json:"parentId"`
+	Priority    int32  `json:"priority"`
+}
+
+type haloTag struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Color     string `json:"color"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+type haloPost struct {
+	ID              int32    `json:"id"`
+	Title           string   `json:"title"`
+	Slug            string   `json:"slug"`
+	Status          string   `json:"status"`
+	EditorType      string   `json:"editorType"`
+	OriginalContent string   `json:"originalContent"`
+	FormatContent   string   `json:"formatContent"`
+	Summary         string   `json:"summary"`
+	Thumbnail       string   `json:"thumbnail"`
+	Password        string   `json:"password"`
+	Template        string   `json:"template"`
+	MetaKeywords    string   `json:"metaKeywords"`
+	MetaDescription string   `json:"metaDescription"`
+	DisallowComment bool     `json:"disallowComment"`
+	TopPriority     int32    `json:"topPriority"`
+	Likes           int64    `json:"likes"`
+	Visits          int64    `json:"visits"`
+	CreateTime      haloTime `json:"createTime"`
+	EditTime        haloTime `json:"editTime"`
+	UpdateTime      haloTime `json:"updateTime"`
+}
+
+// haloContent holds the content of a post since Halo 1.5
+type haloContent struct {
+	ID              int32  `json:"id"`
+	Content         string `json:"content"`
+	OriginalContent string `json:"originalContent"`
+}
+
+type haloPostCategory struct {
+	PostID     int32 `json:"postId"`
+	CategoryID int32 `json:"categoryId"`
+}
+
+type haloPostTag struct {
+	PostID int32 `json:"postId"`
+	TagID  int32 `json:"tagId"`
+}
+
+type haloComment struct {
+	ID                int32    `json:"id"`
+	Author            string   `json:"author"`
+	Email             string   `json:"email"`
+	IPAddress         string   `json:"ipAddress"`
+	AuthorURL         string   `json:"authorUrl"`
+	Content           string   `json:"content"`
+	Status            string   `json:"status"`
+	UserAgent         string   `json:"userAgent"`
+	IsAdmin           bool     `json:"isAdmin"`
+	AllowNotification bool     `json:"allowNotification"`
+	PostID            int32    `json:"postId"`
+	ParentID          int32    `json:"parentId"`
+	TopPriority       int32    `json:"topPriority"`
+	CreateTime        haloTime `json:"createTime"`
+}
+
+type haloMeta struct {
+	PostID int32  `json:"postId"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+// haloTime is the time exported in milliseconds, or in the formatted string by the customized exports
+type haloTime struct {
+	time.Time
+}
+
+func (t *haloTime) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case float64:
+		t.Time = time.UnixMilli(int64(value))
+	case string:
+		if value == "" {
+			return nil
+		}
+		parsed, err := cast.StringToDateInDefaultLocation(value, time.Local)
+		if err != nil {
+			return err
+		}
+		t.Time = parsed
+	}
+	return nil
+}
+
+// haloImport keeps the state of importing the Halo data, the ids of Halo are mapped to the imported ones
+type haloImport struct {
+	*contentImport
+	options map[string]string
+	blogURL string
+	hosts   map[string]struct{}
+	// categories and tags map the ids of Halo to the imported ones
+	categories map[int32]int32
+	tags       map[int32]int32
+}
+
+// ImportHalo imports the posts, sheets, categories, tags, comments and metas of the data exported by Halo 1.x,
+// the media are copied from the uploads directory if it is not nil, or downloaded from the blog,
+// and the old urls are redirected to the new ones
+func (e *exportImport) ImportHalo(ctx context.Context, reader io.Reader, uploads fs.FS) (*dto.ImportReport, error) {
+	ctx = context.WithValue(ctx, consts.ContentImport, true)
+	var backup haloBackup
+	if err := json.NewDecoder(reader).Decode(&backup); err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parse Halo data failed")
+	}
+	if backup.Version == "" || (backup.Posts == nil && backup.Sheets == nil) {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("not a Halo data export file")
+	}
+
+	h := &haloImport{
+		contentImport: newContentImport(uploads, consts.SonicUploadDir),
+		options:       make(map[string]string, len(backup.Options)),
+		hosts:         make(map[string]struct{}),
+		categories:    make(map[int32]int32),
+		tags:          make(map[int32]int32),
+	}
+	for _, option := range backup.Options {
+		h.options[option.Key] = cast.ToString(option.Value)
+	}
+	h.blogURL = strings.TrimSuffix(h.options["blog_url"], "/")
+	if blogURL, err := url.Parse(h.blogURL); err == nil && blogURL.Host != "" {
+		h.hosts[blogURL.Host] = struct{}{}
+	}
+
+	if err := e.importHaloCategories(ctx, h, backup.Categories); err != nil {
+		return nil, err
+	}
+	for i := range backup.Tags {
+		if err := e.importHaloTag(ctx, h, &backup.Tags[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	contents := make(map[int32]*haloContent, len(backup.Contents))
+	for i := range backup.Contents {
+		contents[backup.Contents[i].ID] = &backup.Contents[i]
+	}
+	categoryIDs := make(map[int32][]int32)
+	for _, postCategory := range backup.PostCategories {
+		if categoryID, ok := h.categories[postCategory.CategoryID]; ok {
+			categoryIDs[postCategory.PostID] = append(categoryIDs[postCategory.PostID], categoryID)
+		}
+	}
+	tagIDs := make(map[int32][]int32)
+	for _, postTag := range backup.PostTags {
+		if tagID, ok := h.tags[postTag.TagID]; ok {
+			tagIDs[postTag.PostID] = append(tagIDs[postTag.PostID], tagID)
+		}
+	}
+	groupComments := func(comments []haloComment) map[int32][]haloComment {
+		result := make(map[int32][]haloComment)
+		for _, comment := range comments {
+			result[comment.PostID] = append(result[comment.PostID], comment)
+		}
+		return result
+	}
+	groupMetas := func(metas []haloMeta) map[int32][]param.Meta {
+		result := make(map[int32][]param.Meta)
+		for _, meta := range metas {
+			result[meta.PostID] = append(result[meta.PostID], param.Meta{Key: meta.Key, Value: meta.Value})
+		}
+		return result
+	}
+	postComments, sheetComments := groupComments(backup.PostComments), groupComments(backup.SheetComments)
+	postMetas, sheetMetas := groupMetas(backup.PostMetas), groupMetas(backup.SheetMetas)
+
+	for i := range backup.Posts {
+		post := &backup.Posts[i]
+		err := e.importHaloPost(ctx, h, post, false, contents[post.ID], categoryIDs[post.ID], tagIDs[post.ID], postMetas[post.ID], postComments[post.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range backup.Sheets {
+		sheet := &backup.Sheets[i]
+		err := e.importHaloPost(ctx, h, sheet, true, contents[sheet.ID], nil, nil, sheetMetas[sheet.ID], sheetComments[sheet.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return h.report, nil
+}
+
+// importHaloCategories creates the categories with their parents first, the existing categories of the same slugs are reused
+func (e *exportImport) importHaloCategories(ctx context.Context, h *haloImport, categories []haloCategory) error {
+	pending := categories
+	for len(pending) > 0 {
+		next := make([]haloCategory, 0)
+		for _, category := range pending {
+			parentID, ok := h.categories[category.ParentID]
+			if category.ParentID != 0 && !ok {
+				next = append(next, category)
+				continue
+			}
+			name := truncateRunes(strings.TrimSpace(category.Name), 255)
+			slug := importSlug(category.Slug, importSlug(name, "category-"+strconv.Itoa(int(category.ID))))
+			existing, err := e.CategoryService.GetBySlug(ctx, slug)
+			switch {
+			case err == nil:
+			case xerr.GetType(err) == xerr.NoRecord:
+				existing, err = e.CategoryService.Create(ctx, &param.Category{
+					Name:        name,
+					Slug:        slug,
+					Description: truncateRunes(category.Description, 100),
+					Thumbnail:   e.rewriteHaloMedia(ctx, h, category.Thumbnail),
+					Password:    truncateRunes(category.Password, 255),
+					ParentID:    parentID,
+					Priority:    category.Priority,
+				})
+				if err != nil {
+					return err
+				}
+				h.report.Categories++
+			default:
+				return err
+			}
+			h.categories[category.ID] = existing.ID
+		}
+		if len(next) == len(pending) {
+			// the parents are missing in the file, the categories are created at the top level
+			for i := range next {
+				next[i].ParentID = 0
+			}
+		}
+		pending = next
+	}
+	return nil
+}
+
+func (e *exportImport) importHaloTag(ctx context.Context, h *haloImport, tag *haloTag) error {
+	name := truncateRunes(strings.TrimSpace(tag.Name), 255)
+	slug := importSlug(tag.Slug, importSlug(name, "tag-"+strconv.Itoa(int(tag.ID))))
+	existing, err := e.TagService.GetBySlug(ctx, slug)
+	switch {
+	case err == nil:
+	case xerr.GetType(err) == xerr.NoRecord:
+		existing, err = e.TagService.Create(ctx, &param.Tag{
+			Name:      name,
+			Slug:      slug,
+			Color:     tag.Color,
+			Thumbnail: e.rewriteHaloMedia(ctx, h, tag.Thumbnail),
+		})
+		if err != nil {
+			return err
+		}
+		h.report.Tags++
+	default:
+		return err
+	}
+	h.tags[tag.ID] = existing.ID
+	return nil
+}
+
+// importHaloPost imports the post or the sheet with its comments, the posts in the recycle bin are skipped,
+// and the posts whose slugs are used are skipped so that importing the same file again does not duplicate them
+func (e *exportImport) importHaloPost(ctx context.Context, h *haloImport, source *haloPost, sheet bool, content *haloContent,
+	categoryIDs, tagIDs []int32, metas []param.Meta, comments []haloComment,
+) error {
+	itemType := util.IfElse(sheet, "sheet", "post").(string)
+	title := truncateRunes(strings.TrimSpace(source.Title), 100)
+	if title == "" {
+		title = "Untitled"
+	}
+	var status consts.PostStatus
+	switch source.Status {
+	case "PUBLISHED":
+		status = consts.PostStatusPublished
+	case "INTIMATE":
+		status = consts.PostStatusIntimate
+	case "DRAFT", "":
+		status = consts.PostStatusDraft
+	default:
+		h.skip(itemType, title, source.Slug, "unsupported status "+source.Status)
+		return nil
+	}
+
+	slug := importSlug(source.Slug, importSlug(title, itemType+"-"+strconv.Itoa(int(source.ID))))
+	existing, err := e.PostService.GetBySlug(ctx, slug)
+	if err == nil {
+		h.conflict(itemType, title, slug, "the slug is used by "+existing.Title)
+		return nil
+	}
+	if xerr.GetType(err) != xerr.NoRecord {
+		return err
+	}
+
+	originalContent, formatContent := source.OriginalContent, source.FormatContent
+	if content != nil {
+		originalContent = util.IfElse(originalContent != "", originalContent, content.OriginalContent).(string)
+		formatContent = util.IfElse(formatContent != "", formatContent, content.Content).(string)
+	}
+	originalContent = e.rewriteHaloMedia(ctx, h, originalContent)
+	formatContent = e.rewriteHaloMedia(ctx, h, formatContent)
+	editorType := util.IfElse(source.EditorType == "RICHTEXT", consts.EditorTypeRichText, consts.EditorTypeMarkdown).(consts.EditorType)
+	if formatContent == "" {
+		if editorType == consts.EditorTypeMarkdown {
+			var buf bytes.Buffer
+			if err := importMarkdown.Convert([]byte(originalContent), &buf); err != nil {
+				h.skip(itemType, title, slug, err.Error())
+				return nil
+			}
+			formatContent = buf.String()
+		} else {
+			formatContent = originalContent
+		}
+	}
+
+	post := &entity.Post{
+		Type:            util.IfElse(sheet, consts.PostTypeSheet, consts.PostTypePost).(consts.PostType),
+		Title:           title,
+		Slug:            slug,
+		Status:          status,
+		EditorType:      editorType,
+		OriginalContent: originalContent,
+		FormatContent:   formatContent,
+		Summary:         source.Summary,
+		Thumbnail:       e.rewriteHaloMedia(ctx, h, source.Thumbnail),
+		Password:        truncateRunes(source.Password, 255),
+		Template:        truncateRunes(source.Template, 255),
+		MetaKeywords:    truncateRunes(source.MetaKeywords, 500),
+		MetaDescription: truncateRunes(source.MetaDescription, 1023),
+		DisallowComment: source.DisallowComment,
+		TopPriority:     source.TopPriority,
+		Likes:           source.Likes,
+		Visits:          source.Visits,
+		WordCount:       util.HTMLFormatWordCount(formatContent),
+		CreateTime:      source.CreateTime.Time,
+	}
+	if post.CreateTime.IsZero() {
+		post.CreateTime = time.Now()
+	}
+	if !source.EditTime.IsZero() {
+		post.EditTime = util.TimePtr(source.EditTime.Time)
+	}
+	if !source.UpdateTime.IsZero() {
+		post.UpdateTime = util.TimePtr(source.UpdateTime.Time)
+	}
+
+	// the services are not asked to create the post, so that importing does not publish the update events
+	if sheet {
+		post, err = e.SheetService.CreateOrUpdate(ctx, post, nil, nil, metas)
+	} else {
+		post, err = e.PostService.CreateOrUpdate(ctx, post, categoryIDs, tagIDs, metas)
+	}
+	if err != nil {
+		h.skip(itemType, title, slug, err.Error())
+		return nil
+	}
+	if sheet {
+		h.report.Sheets++
+	} else {
+		h.report.Posts++
+	}
+	if status != consts.PostStatusDraft {
+		if oldPath := h.oldPath(source, sheet); oldPath != "" {
+			e.addRedirect(ctx, h.contentImport, post, oldPath)
+		}
+	}
+	return e.importHaloComments(ctx, h, post, comments)
+}
+
+// oldPath returns the path of the post or the sheet by the permalink options of Halo,
+// the empty string is returned for the query urls like /?p=1
+func (h *haloImport) oldPath(source *haloPost, sheet bool) string {
+	suffix := h.options["path_suffix"]
+	if sheet {
+		if h.options["sheet_permalink_type"] == "ROOT" {
+			return "/" + source.Slug + suffix
+		}
+		return "/" + util.IfElse(h.options["sheet_prefix"] != "", h.options["sheet_prefix"], "s").(string) + "/" + source.Slug + suffix
+	}
+	archivesPrefix := util.IfElse(h.options["archives_prefix"] != "", h.options["archives_prefix"], "archives").(string)
+	createTime := source.CreateTime.Time
+	switch h.options["post_permalink_type"] {
+	case "", "DEFAULT":
+		return "/" + archivesPrefix + "/" + source.Slug + suffix
+	case "YEAR":
+		return createTime.Format("/2006/") + source.Slug + suffix
+	case "DATE":
+		return createTime.Format("/2006/01/") + source.Slug + suffix
+	case "DAY":
+		return createTime.Format("/2006/01/02/") + source.Slug + suffix
+	case "ID_SLUG":
+		return "/" + archivesPrefix + "/" + strconv.Itoa(int(source.ID)) + suffix
+	}
+	return ""
+}
+
+// importHaloComments creates the comments with their original authors, dates and statuses,
+// the replies are attached to the top level when their parents are not imported
+func (e *exportImport) importHaloComments(ctx context.Context, h *haloImport, post *entity.Post, comments []haloComment) error {
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].ID < comments[j].ID
+	})
+	commentDAL := dal.GetQueryByCtx(ctx).Comment
+	imported := make(map[int32]int32, len(comments))
+	for _, source := range comments {
+		var status consts.CommentStatus
+		switch source.Status {
+		case "PUBLISHED":
+			status = consts.CommentStatusPublished
+		case "AUDITING":
+			status = consts.CommentStatusAuditing
+		default:
+			h.skip("comment", source.Author, post.Slug, "the comment is "+source.Status)
+			continue
+		}
+		content := strings.TrimSpace(source.Content)
+		if content == "" {
+			h.skip("comment", source.Author, post.Slug, "empty content")
+			continue
+		}
+		comment := &entity.Comment{
+			Type:              util.IfElse(post.Type == consts.PostTypeSheet, consts.CommentTypeSheet, consts.CommentTypePost).(consts.CommentType),
+			CreateTime:        source.CreateTime.Time,
+			AllowNotification: source.AllowNotification,
+			Author:            truncateRunes(util.IfElse(source.Author != "", source.Author, "Anonymous").(string), 50),
+			AuthorURL:         truncateRunes(source.AuthorURL, 511),
+			Content:           truncateRunes(content, 1023),
+			Email:             truncateRunes(source.Email, 255),
+			GravatarMd5:       util.Md5Hex(source.Email),
+			IPAddress:         truncateRunes(source.IPAddress, 127),
+			IsAdmin:           source.IsAdmin,
+			ParentID:          imported[source.ParentID],
+			PostID:            post.ID,
+			Status:            status,
+			TopPriority:       source.TopPriority,
+			UserAgent:         truncateRunes(source.UserAgent, 511),
+		}
+		err := commentDAL.WithContext(ctx).Select(field.Star).Omit(commentDAL.UpdateTime).Create(comment)
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		imported[source.ID] = comment.ID
+		h.report.Comments++
+	}
+	return nil
+}
+
+// rewriteHaloMedia imports the files uploaded to Halo referenced by the content
+// and replaces the urls with the paths of the attachments
+func (e *exportImport) rewriteHaloMedia(ctx context.Context, h *haloImport, content string) string {
+	return haloMediaPattern.ReplaceAllStringFunc(content, func(match string) string {
+		groups := haloMediaPattern.FindStringSubmatch(match)
+		delimiter, source := groups[1], groups[2]
+		if !strings.HasPrefix(source, "/upload/") {
+			if strings.HasPrefix(source, "//") {
+				source = "https:" + source
+			}
+			sourceURL, err := url.Parse(source)
+			if err != nil {
+				return match
+			}
+			if _, ok := h.hosts[sourceURL.Host]; !ok {
+				return match
+			}
+		} else if h.blogURL != "" {
+			source = h.blogURL + source
+		}
+		relativePath, err := url.PathUnescape(source[strings.Index(source, "/upload/")+len("/upload/"):])
+		if err != nil {
+			return match
+		}
+		if attachmentPath, ok := e.importMedia(ctx, h.contentImport, source, relativePath); ok {
+			return delimiter + attachmentPath
+		}
+		return match
+	})
+}
@@ -0,0 +1,303 @@
+package impl
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+var (
+	hexoAssetTagPattern = regexp.MustCompile(`\{%\s*(asset_img|asset_path|asset_link)\s+(.*?)\s*%\}`)
+	hexoTagArgPattern   = regexp.MustCompile(`"[^"]*"|'[^']*'|\S+`)
+	hexoNumberPattern   = regexp.MustCompile(`^\d+(px|%)?$`)
+)
+
+// hexoSite is the part of the Hexo config used by importing
+type hexoSite struct {
+	root      string
+	permalink string
+	// filenamePattern extracts the title from the file name named by the new_post_name config
+	filenamePattern *regexp.Regexp
+}
+
+func readHexoSite(site fs.FS) *hexoSite {
+	config := readSiteConfig(site, "_config.yml", "_config.yaml")
+	h := &hexoSite{
+		root:      "/",
+		permalink: ":year/:month/:day/:title/",
+	}
+	if root := cast.ToString(config["root"]); root != "" {
+		h.root = "/" + strings.Trim(root, "/") + "/"
+		h.root = strings.Replace(h.root, "//", "/", 1)
+	}
+	if permalink := cast.ToString(config["permalink"]); permalink != "" {
+		h.permalink = permalink
+	}
+	newPostName := strings.TrimSuffix(cast.ToString(config["new_post_name"]), path.Ext(cast.ToString(config["new_post_name"])))
+	if strings.Contains(newPostName, ":title") {
+		pattern := regexp.QuoteMeta(newPostName)
+		pattern = strings.NewReplacer(
+			":year", `\d{4}`, ":month", `\d{2}`, ":i_month", `\d{1,2}`,
+			":day", `\d{2}`, ":i_day", `\d{1,2}`, ":title", `(.+)`,
+		).Replace(pattern)
+		h.filenamePattern = regexp.MustCompile("^" + pattern + "$")
+	}
+	return h
+}
+
+// ImportHexo imports the posts and the drafts in source/_posts and source/_drafts of the Hexo site,
+// the other Markdown pages in source are imported as sheets, the old urls are redirected to the new ones
+func (e *exportImport) ImportHexo(ctx context.Context, site fs.FS) (*dto.ImportReport, error) {
+	ctx = context.WithValue(ctx, consts.ContentImport, true)
+	site, err := siteRoot(site, "source", "_config.yml")
+	if err != nil {
+		return nil, err
+	}
+	h := readHexoSite(site)
+	s := newSiteImport(site, "source")
+
+	names := make([]string, 0)
+	err = fs.WalkDir(site, "source", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isMarkdownFile(name) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		relativeName := strings.TrimPrefix(name, "source/")
+		var p *sitePost
+		switch {
+		case strings.HasPrefix(relativeName, "_posts/"):
+			p, err = h.parsePost(site, name, strings.TrimPrefix(relativeName, "_posts/"), false)
+		case strings.HasPrefix(relativeName, "_drafts/"):
+			p, err = h.parsePost(site, name, strings.TrimPrefix(relativeName, "_drafts/"), true)
+		case isHiddenPath(relativeName) || relativeName == "index.md":
+			// the files in the directories like _data are not rendered by Hexo, and the index page of the site is not a sheet
+			continue
+		default:
+			p, err = h.parsePage(site, name, relativeName)
+		}
+		if err != nil {
+			s.skip("post", relativeName, "", err.Error())
+			continue
+		}
+		p.content = h.convertAssetTags(p.content)
+		if _, err := e.importSitePost(ctx, s, p); err != nil {
+			return nil, err
+		}
+	}
+	return s.report, nil
+}
+
+// parsePost parses the post of _posts or _drafts, the name is relative to the directory,
+// the assets of the post are in the directory of the same name next to it
+func (h *hexoSite) parsePost(site fs.FS, name, relativeName string, draft bool) (*sitePost, error) {
+	frontMatter, content, err := readSiteFile(site, name)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSuffix(relativeName, path.Ext(relativeName))
+	fileName := path.Base(title)
+	if h.filenamePattern != nil {
+		if groups := h.filenamePattern.FindStringSubmatch(fileName); groups != nil {
+			fileName = groups[1]
+			title = path.Join(path.Dir(title), fileName)
+		}
+	}
+	p := h.parseFrontMatter(frontMatter, content)
+	p.name = fileName
+	p.dirs = []string{strings.TrimSuffix(name, path.Ext(name)), path.Dir(name)}
+	if slug := frontMatterString(frontMatter, "slug"); slug != "" {
+		p.slug = slug
+	}
+	if published, ok := frontMatterBool(frontMatter, "published"); ok && !published {
+		draft = true
+	}
+	p.draft = draft
+	if draft {
+		return p, nil
+	}
+
+	if permalink := frontMatterString(frontMatter, "permalink"); permalink != "" {
+		p.oldPaths = append(p.oldPaths, h.root+strings.TrimPrefix(permalink, "/"))
+		return p, nil
+	}
+	date := p.createTime
+	if date.IsZero() {
+		date = time.Now()
+	}
+	tokens := dateTokens(date)
+	tokens["title"] = title
+	tokens["name"] = fileName
+	tokens["post_title"] = hugoURLize(p.title)
+	tokens["category"] = ""
+	if len(p.categories) > 0 {
+		categorySlugs := make([]string, 0, len(p.categories[0]))
+		for _, category := range p.categories[0] {
+			categorySlugs = append(categorySlugs, hugoURLize(category))
+		}
+		tokens["category"] = strings.Join(categorySlugs, "/")
+	}
+	for key, value := range frontMatter {
+		if _, ok := tokens[key]; !ok {
+			if str, ok := value.(string); ok {
+				tokens[key] = str
+			}
+		}
+	}
+	p.oldPaths = append(p.oldPaths, h.root+strings.TrimPrefix(expandPermalink(h.permalink, tokens), "/"))
+	return p, nil
+}
+
+// parsePage parses the page in source, it is served at the directory for index.md, or at the html file of the same name
+func (h *hexoSite) parsePage(site fs.FS, name, relativeName string) (*sitePost, error) {
+	frontMatter, content, err := readSiteFile(site, name)
+	if err != nil {
+		return nil, err
+	}
+	p := h.parseFrontMatter(frontMatter, content)
+	p.sheet = true
+	p.dirs = []string{path.Dir(name)}
+	baseName := strings.TrimSuffix(path.Base(relativeName), path.Ext(relativeName))
+	dir := path.Dir(relativeName)
+	oldPath := path.Join(dir, baseName) + ".html"
+	p.name = baseName
+	if baseName == "index" {
+		p.name = path.Base(dir)
+		oldPath = dir + "/"
+	}
+	if permalink := frontMatterString(frontMatter, "permalink"); permalink != "" {
+		oldPath = strings.TrimPrefix(permalink, "/")
+	}
+	p.oldPaths = append(p.oldPaths, h.root+oldPath)
+	return p, nil
+}
+
+func (h *hexoSite) parseFrontMatter(frontMatter map[string]any, content string) *sitePost {
+	p := &sitePost{
+		content:    content,
+		title:      frontMatterString(frontMatter, "title"),
+		summary:    frontMatterString(frontMatter, "excerpt", "description", "summary"),
+		password:   frontMatterString(frontMatter, "password"),
+		keywords:   strings.Join(frontMatterStrings(frontMatter, "keywords"), ","),
+		thumbnail:  frontMatterString(frontMatter, "cover", "thumbnail", "banner", "index_img", "banner_img", "photos"),
+		createTime: frontMatterTime(frontMatter, "date"),
+		editTime:   frontMatterTime(frontMatter, "updated"),
+		categories: hexoCategories(frontMatter["categories"]),
+		tags:       frontMatterStrings(frontMatter, "tags"),
+		oldPaths:   make([]string, 0),
+	}
+	if len(p.categories) == 0 {
+		p.categories = hexoCategories(frontMatter["category"])
+	}
+	if comments, ok := frontMatterBool(frontMatter, "comments"); ok {
+		p.disallowComment = !comments
+	}
+	for _, key := range []string{"top", "sticky"} {
+		// top is either a bool or the priority
+		if top, err := cast.ToInt32E(frontMatter[key]); err == nil && top > 0 {
+			p.topPriority = top
+		} else if top, _ := frontMatterBool(frontMatter, key); top {
+			p.topPriority = 1
+		}
+	}
+	return p
+}
+
+// hexoCategories returns the category hierarchies, a list is a hierarchy from the root
+// and the nested lists are the hierarchies of the post in multiple categories
+func hexoCategories(value any) [][]string {
+	result := make([][]string, 0)
+	switch value := value.(type) {
+	case string:
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, []string{value})
+		}
+	case []any:
+		hierarchy := make([]string, 0)
+		for _, item := range value {
+			if items, ok := item.([]any); ok {
+				if nested := cast.ToStringSlice(items); len(nested) > 0 {
+					result = append(result, nested)
+				}
+				continue
+			}
+			if str := strings.TrimSpace(cast.ToString(item)); str != "" {
+				hierarchy = append(hierarchy, str)
+			}
+		}
+		if len(hierarchy) > 0 {
+			result = append(result, hierarchy)
+		}
+	}
+	return result
+}
+
+// convertAssetTags converts the asset tag plugins of Hexo into the Markdown referring the assets of the post
+func (h *hexoSite) convertAssetTags(content string) string {
+	return hexoAssetTagPattern.ReplaceAllStringFunc(content, func(match string) string {
+		groups := hexoAssetTagPattern.FindStringSubmatch(match)
+		args := hexoTagArgPattern.FindAllString(groups[2], -1)
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, `"'`)
+		}
+		if len(args) == 0 {
+			return match
+		}
+		switch groups[1] {
+		case "asset_path":
+			return args[0]
+		case "asset_link":
+			title := strings.Join(args[1:], " ")
+			if title == "" {
+				title = path.Base(args[0])
+			}
+			return "[" + title + "](" + args[0] + ")"
+		}
+		// {% asset_img [class names] slug [width] [height] [title text [alt text]] %}
+		slugIndex := 0
+		for i, arg := range args {
+			if path.Ext(arg) != "" {
+				slugIndex = i
+				break
+			}
+		}
+		titleArgs := args[slugIndex+1:]
+		for len(titleArgs) > 0 && hexoNumberPattern.MatchString(titleArgs[0]) {
+			titleArgs = titleArgs[1:]
+		}
+		return "![" + strings.Join(titleArgs, " ") + "](" + args[slugIndex] + ")"
+	})
+}
+
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown":
+		return true
+	}
+	return false
+}
+
+// isHiddenPath reports whether any element of the path starts with _ or .
+func isHiddenPath(name string) bool {
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, "_") || strings.HasPrefix(element, ".") {
+			return true
+		}
+	}
+	return false
+}
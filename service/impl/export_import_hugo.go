@@ -0,0 +1,250 @@
+package impl
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cast"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+var hugoConfigFilenames = []string{
+	"hugo.toml", "hugo.yaml", "hugo.yml", "hugo.json",
+	"config.toml", "config.yaml", "config.yml", "config.json",
+}
+
+// hugoSite is the part of the Hugo config used by importing
+type hugoSite struct {
+	contentDir string
+	staticDirs []string
+	// permalinks maps the section to the permalink pattern of its pages
+	permalinks map[string]string
+	// taxonomies are the plural names of the taxonomies imported as tags, the categories are imported as categories
+	taxonomies []string
+}
+
+func readHugoSite(site fs.FS) *hugoSite {
+	config := readSiteConfig(site, hugoConfigFilenames...)
+	h := &hugoSite{
+		contentDir: "content",
+		staticDirs: []string{"static", "assets"},
+		permalinks: make(map[string]string),
+		taxonomies: []string{"tags"},
+	}
+	if contentDir := cast.ToString(config["contentdir"]); contentDir != "" {
+		h.contentDir = path.Clean(strings.Trim(contentDir, "/"))
+	}
+	if staticDirs := cast.ToStringSlice(config["staticdir"]); len(staticDirs) > 0 {
+		h.staticDirs = make([]string, 0, len(staticDirs))
+		for _, staticDir := range staticDirs {
+			h.staticDirs = append(h.staticDirs, path.Clean(strings.Trim(staticDir, "/")))
+		}
+	}
+	permalinks := cast.ToStringMap(config["permalinks"])
+	// the permalinks of the pages are nested since Hugo 0.112
+	if pagePermalinks, ok := permalinks["page"].(map[string]any); ok {
+		permalinks = pagePermalinks
+	}
+	for section, pattern := range permalinks {
+		if pattern, ok := pattern.(string); ok {
+			h.permalinks[strings.ToLower(section)] = pattern
+		}
+	}
+	if taxonomies, ok := config["taxonomies"]; ok {
+		h.taxonomies = make([]string, 0)
+		for _, plural := range cast.ToStringMapString(taxonomies) {
+			if plural != "categories" {
+				h.taxonomies = append(h.taxonomies, strings.ToLower(plural))
+			}
+		}
+		sort.Strings(h.taxonomies)
+	}
+	return h
+}
+
+// ImportHugo imports the Markdown pages of the Hugo site, the pages in the sections are imported as posts
+// and the top level pages as sheets, the old urls and the aliases are redirected to the new ones
+func (e *exportImport) ImportHugo(ctx context.Context, site fs.FS) (*dto.ImportReport, error) {
+	ctx = context.WithValue(ctx, consts.ContentImport, true)
+	site, err := siteRoot(site, append([]string{"content"}, hugoConfigFilenames...)...)
+	if err != nil {
+		return nil, err
+	}
+	h := readHugoSite(site)
+	if info, err := fs.Stat(site, h.contentDir); err != nil || !info.IsDir() {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("The content directory of the Hugo site is not found")
+	}
+	s := newSiteImport(site, h.staticDirs...)
+
+	names := make([]string, 0)
+	err = fs.WalkDir(site, h.contentDir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		relativeName := strings.TrimPrefix(strings.TrimPrefix(name, h.contentDir), "/")
+		switch strings.ToLower(path.Ext(name)) {
+		case ".md", ".markdown", ".mdown":
+		case ".html", ".htm", ".adoc", ".asciidoc", ".org", ".rst", ".pandoc", ".pdc":
+			s.skip("post", relativeName, "", "only the Markdown pages are supported")
+			continue
+		default:
+			continue
+		}
+		p, ok := h.parsePage(s, name, relativeName)
+		if !ok {
+			continue
+		}
+		if _, err := e.importSitePost(ctx, s, p); err != nil {
+			return nil, err
+		}
+	}
+	return s.report, nil
+}
+
+// parsePage parses the page of the content dir, the list pages and the headless bundles are not imported
+func (h *hugoSite) parsePage(s *siteImport, name, relativeName string) (*sitePost, bool) {
+	baseName := strings.TrimSuffix(path.Base(relativeName), path.Ext(relativeName))
+	if baseName == "_index" {
+		return nil, false
+	}
+	frontMatter, content, err := readSiteFile(s.uploads, name)
+	if err != nil {
+		s.skip("post", relativeName, "", err.Error())
+		return nil, false
+	}
+	if headless, _ := frontMatterBool(frontMatter, "headless"); headless {
+		return nil, false
+	}
+
+	dir := path.Dir(relativeName)
+	// the leaf bundle is named by its directory and holds its resources next to index.md
+	contentBaseName := baseName
+	if baseName == "index" && dir != "." {
+		contentBaseName = path.Base(dir)
+		dir = path.Dir(dir)
+	}
+	sections := make([]string, 0)
+	if dir != "." {
+		sections = strings.Split(dir, "/")
+	}
+
+	p := &sitePost{
+		sheet:      len(sections) == 0,
+		name:       contentBaseName,
+		dirs:       []string{path.Dir(name)},
+		content:    content,
+		title:      frontMatterString(frontMatter, "title", "linktitle"),
+		slug:       frontMatterString(frontMatter, "slug"),
+		summary:    frontMatterString(frontMatter, "summary", "description"),
+		password:   frontMatterString(frontMatter, "password"),
+		keywords:   strings.Join(frontMatterStrings(frontMatter, "keywords"), ","),
+		thumbnail:  frontMatterString(frontMatter, "featured_image", "image", "cover", "thumbnail", "images"),
+		createTime: frontMatterTime(frontMatter, "date", "publishdate", "pubdate", "published"),
+		editTime:   frontMatterTime(frontMatter, "lastmod", "modified"),
+		categories: make([][]string, 0),
+		tags:       make([]string, 0),
+		oldPaths:   make([]string, 0),
+	}
+	p.draft, _ = frontMatterBool(frontMatter, "draft")
+	if comments, ok := frontMatterBool(frontMatter, "comments"); ok {
+		p.disallowComment = !comments
+	}
+	for _, key := range []string{"sticky", "pinned", "top"} {
+		if top, _ := frontMatterBool(frontMatter, key); top {
+			p.topPriority = 1
+		}
+	}
+	for _, category := range frontMatterStrings(frontMatter, "categories") {
+		p.categories = append(p.categories, []string{category})
+	}
+	for _, taxonomy := range h.taxonomies {
+		p.tags = append(p.tags, frontMatterStrings(frontMatter, taxonomy)...)
+	}
+
+	if p.draft {
+		return p, true
+	}
+	pageURL := frontMatterString(frontMatter, "url")
+	if pageURL == "" {
+		pageURL = h.permalink(p, sections, contentBaseName)
+	}
+	p.oldPaths = append(p.oldPaths, pageURL)
+	for _, alias := range frontMatterStrings(frontMatter, "aliases") {
+		// the relative aliases are relative to the section of the page
+		if !strings.HasPrefix(alias, "/") {
+			alias = path.Join("/", path.Join(sections...), alias)
+		}
+		p.oldPaths = append(p.oldPaths, alias)
+	}
+	return p, true
+}
+
+// permalink returns the url of the page by the permalinks config of its section, or the default url of Hugo
+func (h *hugoSite) permalink(p *sitePost, sections []string, contentBaseName string) string {
+	slugOrContentBaseName := hugoURLize(contentBaseName)
+	if p.slug != "" {
+		slugOrContentBaseName = hugoURLize(p.slug)
+	}
+	section := ""
+	if len(sections) > 0 {
+		section = sections[0]
+	}
+	pattern, ok := h.permalinks[strings.ToLower(section)]
+	if !ok {
+		return path.Join("/", path.Join(sections...), slugOrContentBaseName) + "/"
+	}
+	date := p.createTime
+	if date.IsZero() {
+		date = time.Now()
+	}
+	tokens := dateTokens(date)
+	tokens["monthname"] = strings.ToLower(date.Month().String())
+	tokens["weekday"] = strconv.Itoa(int(date.Weekday()))
+	tokens["weekdayname"] = strings.ToLower(date.Weekday().String())
+	tokens["yearday"] = strconv.Itoa(date.YearDay())
+	tokens["section"] = section
+	tokens["sections"] = path.Join(sections...)
+	tokens["title"] = hugoURLize(p.title)
+	tokens["slug"] = hugoURLize(p.slug)
+	if p.slug == "" {
+		tokens["slug"] = tokens["title"]
+	}
+	// the file name of a bundle is the name of its directory
+	tokens["filename"] = hugoURLize(contentBaseName)
+	tokens["contentbasename"] = tokens["filename"]
+	tokens["slugorfilename"] = slugOrContentBaseName
+	tokens["slugorcontentbasename"] = slugOrContentBaseName
+	return expandPermalink(pattern, tokens)
+}
+
+// hugoURLize converts the title to the path segment like the urlize of Hugo, the non-ASCII letters are kept
+func hugoURLize(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsSpace(r):
+			sb.WriteRune('-')
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
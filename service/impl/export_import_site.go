@@ -0,0 +1,473 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/pageparser"
+	"github.com/go-sonic/sonic/util/pageparser/metadecoders"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`(!?\[[^\]]*\]\(\s*<?)([^)\s>]+)`)
+	htmlMediaPattern    = regexp.MustCompile(`(\b(?:src|href|poster)\s*=\s*["'])([^"']+)`)
+	// importMarkdown renders the Markdown imported or sent by the remote editors with the raw html kept, since the posts often embed html
+	importMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM), goldmark.WithRendererOptions(html.WithUnsafe()))
+	// permalinkTokenPattern matches the tokens like :year of the permalink patterns
+	permalinkTokenPattern = regexp.MustCompile(`:[a-z_]+`)
+)
+
+// siteImport keeps the state of importing a static site generated from the Markdown files, like Hugo and Hexo
+type siteImport struct {
+	*contentImport
+	// staticDirs are the directories of the site serving the media of the absolute paths
+	staticDirs []string
+	categories map[string]int32
+	tags       map[string]int32
+}
+
+func newSiteImport(site fs.FS, staticDirs ...string) *siteImport {
+	return &siteImport{
+		contentImport: newContentImport(site),
+		staticDirs:    staticDirs,
+		categories:    make(map[string]int32),
+		tags:          make(map[string]int32),
+	}
+}
+
+// sitePost is a post or a sheet parsed from the Markdown file of a static site
+type sitePost struct {
+	sheet bool
+	// name is the name of the file or the bundle, the fallback of the title and the slug
+	name string
+	// dirs are the directories to resolve the relative media in order
+	dirs            []string
+	content         string
+	title           string
+	slug            string
+	summary         string
+	password        string
+	keywords        string
+	thumbnail       string
+	draft           bool
+	disallowComment bool
+	topPriority     int32
+	createTime      time.Time
+	editTime        time.Time
+	// categories are the paths of the categories from the root
+	categories [][]string
+	tags       []string
+	// oldPaths are the paths of the post on the site, they are redirected to the new path
+	oldPaths []string
+	// status overrides the status derived from draft and password if it is not nil
+	status          *consts.PostStatus
+	richText        bool
+	template        string
+	metaDescription string
+	visits          int64
+	likes           int64
+	metas           []param.Meta
+}
+
+// siteRoot returns the directory of the site containing any of the markers, the site may be zipped with its parent directory
+func siteRoot(site fs.FS, markers ...string) (fs.FS, error) {
+	hasMarker := func(dir string) bool {
+		for _, marker := range markers {
+			if _, err := fs.Stat(site, path.Join(dir, marker)); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	if hasMarker(".") {
+		return site, nil
+	}
+	entries, err := fs.ReadDir(site, ".")
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("read site failed")
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && hasMarker(entry.Name()) {
+			return fs.Sub(site, entry.Name())
+		}
+	}
+	return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("The site is not found, missing " + strings.Join(markers, " or "))
+}
+
+// readSiteConfig reads the first config file found, the keys are in lower case
+func readSiteConfig(site fs.FS, filenames ...string) map[string]any {
+	for _, filename := range filenames {
+		data, err := fs.ReadFile(site, filename)
+		if err != nil {
+			continue
+		}
+		config, err := metadecoders.Default.UnmarshalToMap(data, metadecoders.FormatFromString(path.Ext(filename)))
+		if err != nil {
+			continue
+		}
+		return lowerKeys(config)
+	}
+	return map[string]any{}
+}
+
+// readSiteFile parses the front matter and the content of the Markdown file, the keys of the front matter are in lower case
+func readSiteFile(site fs.FS, name string) (map[string]any, string, error) {
+	file, err := site.Open(name)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+	contentFrontMatter, err := pageparser.ParseFrontMatterAndContent(file)
+	if err != nil {
+		return nil, "", err
+	}
+	return lowerKeys(contentFrontMatter.FrontMatter), string(contentFrontMatter.Content), nil
+}
+
+func lowerKeys(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for key, value := range m {
+		result[strings.ToLower(key)] = value
+	}
+	return result
+}
+
+// importSitePost creates the post or the sheet, the media it refers to and the redirects of its old paths,
+// the posts whose slugs are used are skipped so that importing the same site again does not duplicate them,
+// the post is nil if it is skipped
+func (e *exportImport) importSitePost(ctx context.Context, s *siteImport, p *sitePost) (*entity.Post, error) {
+	itemType := util.IfElse(p.sheet, "sheet", "post").(string)
+	title := truncateRunes(strings.TrimSpace(util.IfElse(p.title != "", p.title, p.name).(string)), 100)
+	slug := importSlug(util.IfElse(p.slug != "", p.slug, p.name).(string), itemType+"-"+util.Md5Hex(p.name)[:8])
+	existing, err := e.PostService.GetBySlug(ctx, slug)
+	if err == nil {
+		s.conflict(itemType, title, slug, "the slug is used by "+existing.Title)
+		return nil, nil
+	}
+	if xerr.GetType(err) != xerr.NoRecord {
+		return nil, err
+	}
+
+	content := e.rewriteSiteMedia(ctx, s, p.dirs, p.content)
+	formatContent := content
+	if !p.richText {
+		var buf bytes.Buffer
+		if err := importMarkdown.Convert([]byte(content), &buf); err != nil {
+			s.skip(itemType, title, slug, err.Error())
+			return nil, nil
+		}
+		formatContent = buf.String()
+	}
+	status := consts.PostStatusPublished
+	switch {
+	case p.status != nil:
+		status = *p.status
+	case p.draft:
+		status = consts.PostStatusDraft
+	case p.password != "":
+		status = consts.PostStatusIntimate
+	}
+	post := &entity.Post{
+		Type:            util.IfElse(p.sheet, consts.PostTypeSheet, consts.PostTypePost).(consts.PostType),
+		Title:           title,
+		Slug:            slug,
+		Status:          status,
+		EditorType:      util.IfElse(p.richText, consts.EditorTypeRichText, consts.EditorTypeMarkdown).(consts.EditorType),
+		OriginalContent: content,
+		FormatContent:   formatContent,
+		Summary:         p.summary,
+		Password:        truncateRunes(p.password, 255),
+		MetaKeywords:    truncateRunes(p.keywords, 500),
+		MetaDescription: truncateRunes(p.metaDescription, 1023),
+		Template:        truncateRunes(p.template, 255),
+		Visits:          p.visits,
+		Likes:           p.likes,
+		DisallowComment: p.disallowComment,
+		TopPriority:     p.topPriority,
+		WordCount:       util.HTMLFormatWordCount(formatContent),
+		CreateTime:      p.createTime,
+	}
+	if post.CreateTime.IsZero() {
+		post.CreateTime = time.Now()
+	}
+	if !p.editTime.IsZero() {
+		post.EditTime = util.TimePtr(p.editTime)
+		post.UpdateTime = util.TimePtr(p.editTime)
+	}
+	if p.thumbnail != "" {
+		post.Thumbnail = p.thumbnail
+		if attachmentPath := e.resolveSiteMedia(ctx, s, p.dirs, p.thumbnail); attachmentPath != "" {
+			post.Thumbnail = attachmentPath
+		}
+	}
+
+	// the services are not asked to create the post, so that importing does not publish the update events
+	if p.sheet {
+		post, err = e.SheetService.CreateOrUpdate(ctx, post, nil, nil, p.metas)
+	} else {
+		categoryIDs := make([]int32, 0, len(p.categories))
+		for _, categoryPath := range p.categories {
+			categoryID, err := e.siteCategoryID(ctx, s, categoryPath)
+			if err != nil {
+				return nil, err
+			}
+			categoryIDs = append(categoryIDs, categoryID)
+		}
+		tagIDs := make([]int32, 0, len(p.tags))
+		for _, tagName := range p.tags {
+			tagID, err := e.siteTagID(ctx, s, tagName)
+			if err != nil {
+				return nil, err
+			}
+			tagIDs = append(tagIDs, tagID)
+		}
+		post, err = e.PostService.CreateOrUpdate(ctx, post, categoryIDs, tagIDs, p.metas)
+	}
+	if err != nil {
+		s.skip(itemType, title, slug, err.Error())
+		return nil, nil
+	}
+	if p.sheet {
+		s.report.Sheets++
+	} else {
+		s.report.Posts++
+	}
+	for _, oldPath := range p.oldPaths {
+		e.addRedirect(ctx, s.contentImport, post, oldPath)
+	}
+	return post, nil
+}
+
+// siteCategoryID returns the id of the last category of the path, the missing categories are created under their parents,
+// the existing categories of the same slug or name are reused
+func (e *exportImport) siteCategoryID(ctx context.Context, s *siteImport, categoryPath []string) (int32, error) {
+	var parentID int32
+	for i, name := range categoryPath {
+		name = truncateRunes(strings.TrimSpace(name), 255)
+		key := strings.Join(categoryPath[:i+1], "/")
+		if categoryID, ok := s.categories[key]; ok {
+			parentID = categoryID
+			continue
+		}
+		slug := importSlug(name, "category-"+util.Md5Hex(key)[:8])
+		category, err := e.CategoryService.GetBySlug(ctx, slug)
+		if xerr.GetType(err) == xerr.NoRecord {
+			category, err = e.CategoryService.GetByName(ctx, name)
+		}
+		switch {
+		case err == nil:
+		case xerr.GetType(err) == xerr.NoRecord:
+			category, err = e.CategoryService.Create(ctx, &param.Category{
+				Name:     name,
+				Slug:     slug,
+				ParentID: parentID,
+			})
+			if err != nil {
+				return 0, err
+			}
+			s.report.Categories++
+		default:
+			return 0, err
+		}
+		s.categories[key] = category.ID
+		parentID = category.ID
+	}
+	return parentID, nil
+}
+
+func (e *exportImport) siteTagID(ctx context.Context, s *siteImport, name string) (int32, error) {
+	name = truncateRunes(strings.TrimSpace(name), 255)
+	if tagID, ok := s.tags[name]; ok {
+		return tagID, nil
+	}
+	slug := importSlug(name, "tag-"+util.Md5Hex(name)[:8])
+	tag, err := e.TagService.GetBySlug(ctx, slug)
+	if xerr.GetType(err) == xerr.NoRecord {
+		tag, err = e.TagService.GetByName(ctx, name)
+	}
+	switch {
+	case err == nil:
+	case xerr.GetType(err) == xerr.NoRecord:
+		tag, err = e.TagService.Create(ctx, &param.Tag{
+			Name: name,
+			Slug: slug,
+		})
+		if err != nil {
+			return 0, err
+		}
+		s.report.Tags++
+	default:
+		return 0, err
+	}
+	s.tags[name] = tag.ID
+	return tag.ID, nil
+}
+
+// rewriteSiteMedia imports the local files referred by the Markdown images and links and the html attributes,
+// and replaces the references with the paths of the attachments
+func (e *exportImport) rewriteSiteMedia(ctx context.Context, s *siteImport, dirs []string, content string) string {
+	replace := func(pattern *regexp.Regexp) func(string) string {
+		return func(match string) string {
+			groups := pattern.FindStringSubmatch(match)
+			if attachmentPath := e.resolveSiteMedia(ctx, s, dirs, groups[2]); attachmentPath != "" {
+				return groups[1] + attachmentPath
+			}
+			return match
+		}
+	}
+	content = markdownLinkPattern.ReplaceAllStringFunc(content, replace(markdownLinkPattern))
+	return htmlMediaPattern.ReplaceAllStringFunc(content, replace(htmlMediaPattern))
+}
+
+// resolveSiteMedia imports the media of the site by the reference relative to the dirs, or the absolute path in the static dirs,
+// the empty string is returned if the reference is not a file of the site
+func (e *exportImport) resolveSiteMedia(ctx context.Context, s *siteImport, dirs []string, reference string) string {
+	refURL, err := url.Parse(reference)
+	if err != nil || refURL.Scheme != "" || refURL.Host != "" || refURL.Path == "" {
+		return ""
+	}
+	switch strings.ToLower(path.Ext(refURL.Path)) {
+	case "", ".md", ".markdown", ".html", ".htm":
+		return ""
+	}
+	candidates := make([]string, 0)
+	if strings.HasPrefix(refURL.Path, "/") {
+		for _, staticDir := range s.staticDirs {
+			candidates = append(candidates, path.Join(staticDir, refURL.Path))
+		}
+	} else {
+		for _, dir := range dirs {
+			candidates = append(candidates, path.Join(dir, refURL.Path))
+		}
+	}
+	for _, candidate := range candidates {
+		if info, err := fs.Stat(s.uploads, candidate); err != nil || info.IsDir() {
+			continue
+		}
+		if attachmentPath, ok := e.importMedia(ctx, s.contentImport, candidate, candidate); ok {
+			return attachmentPath
+		}
+		return ""
+	}
+	return ""
+}
+
+func frontMatterString(frontMatter map[string]any, keys ...string) string {
+	for _, key := range keys {
+		switch value := frontMatter[key].(type) {
+		case string:
+			if strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		case []any:
+			if len(value) > 0 {
+				return strings.TrimSpace(cast.ToString(value[0]))
+			}
+		case map[string]any:
+			// e.g. the cover of the themes: {image: cover.jpg}
+			if image := frontMatterString(lowerKeys(value), "image", "src", "url"); image != "" {
+				return image
+			}
+		}
+	}
+	return ""
+}
+
+// frontMatterStrings returns the list of strings, a string is split by the commas
+func frontMatterStrings(frontMatter map[string]any, key string) []string {
+	result := make([]string, 0)
+	switch value := frontMatter[key].(type) {
+	case string:
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if str := strings.TrimSpace(cast.ToString(item)); str != "" {
+				result = append(result, str)
+			}
+		}
+	case []string:
+		for _, item := range value {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+func frontMatterTime(frontMatter map[string]any, keys ...string) time.Time {
+	for _, key := range keys {
+		switch value := frontMatter[key].(type) {
+		case time.Time:
+			return value
+		case string:
+			if t, err := cast.StringToDateInDefaultLocation(value, time.Local); err == nil {
+				return t
+			}
+		case nil:
+		case fmt.Stringer:
+			// e.g. the local dates of TOML
+			if t, err := cast.StringToDateInDefaultLocation(value.String(), time.Local); err == nil {
+				return t
+			}
+		default:
+			if t, err := cast.ToTimeE(value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func frontMatterBool(frontMatter map[string]any, key string) (bool, bool) {
+	value, ok := frontMatter[key]
+	if !ok {
+		return false, false
+	}
+	b, err := cast.ToBoolE(value)
+	return b, err == nil
+}
+
+// expandPermalink replaces the tokens like :year of the permalink pattern of the static site
+func expandPermalink(pattern string, tokens map[string]string) string {
+	return permalinkTokenPattern.ReplaceAllStringFunc(pattern, func(token string) string {
+		if value, ok := tokens[token[1:]]; ok {
+			return value
+		}
+		return token
+	})
+}
+
+func dateTokens(t time.Time) map[string]string {
+	return map[string]string{
+		"year":    fmt.Sprintf("%04d", t.Year()),
+		"month":   fmt.Sprintf("%02d", t.Month()),
+		"i_month": fmt.Sprintf("%d", t.Month()),
+		"day":     fmt.Sprintf("%02d", t.Day()),
+		"i_day":   fmt.Sprintf("%d", t.Day()),
+		"hour":    fmt.Sprintf("%02d", t.Hour()),
+		"minute":  fmt.Sprintf("%02d", t.Minute()),
+		"second":  fmt.Sprintf("%02d", t.Second()),
+	}
+}
@@ -0,0 +1,537 @@
+package impl
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// the WordPress eXtended RSS exported by WordPress, the elements are matched by the local names
+// since the namespaces differ between the WXR versions
+type wxrRSS struct {
+	Channel wxrChannel `xml:"channel"`
+}
+
+type wxrChannel struct {
+	Link        string        `xml:"link"`
+	WXRVersion  string        `xml:"wxr_version"`
+	BaseSiteURL string        `xml:"base_site_url"`
+	BaseBlogURL string        `xml:"base_blog_url"`
+	Categories  []wxrCategory `xml:"category"`
+	Tags        []wxrTag      `xml:"tag"`
+	Items       []wxrItem     `xml:"item"`
+}
+
+type wxrCategory struct {
+	TermID      int    `xml:"term_id"`
+	Nicename    string `xml:"category_nicename"`
+	Parent      string `xml:"category_parent"`
+	Name        string `xml:"cat_name"`
+	Description string `xml:"category_description"`
+}
+
+type wxrTag struct {
+	TermID int    `xml:"term_id"`
+	Slug   string `xml:"tag_slug"`
+	Name   string `xml:"tag_name"`
+}
+
+type wxrItem struct {
+	Title           string       `xml:"title"`
+	Encoded         []wxrEncoded `xml:"encoded"`
+	PostID          int          `xml:"post_id"`
+	PostDate        string       `xml:"post_date"`
+	PostDateGMT     string       `xml:"post_date_gmt"`
+	PostModified    string       `xml:"post_modified"`
+	PostModifiedGMT string       `xml:"post_modified_gmt"`
+	CommentStatus   string       `xml:"comment_status"`
+	PostName        string       `xml:"post_name"`
+	Status          string       `xml:"status"`
+	PostType        string       `xml:"post_type"`
+	PostPassword    string       `xml:"post_password"`
+	IsSticky        int          `xml:"is_sticky"`
+	AttachmentURL   string       `xml:"attachment_url"`
+	Terms           []wxrTerm    `xml:"category"`
+	Metas           []wxrMeta    `xml:"postmeta"`
+	Comments        []wxrComment `xml:"comment"`
+}
+
+// wxrEncoded is either content:encoded or excerpt:encoded
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrTerm struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+type wxrMeta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+type wxrComment struct {
+	ID          int    `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	AuthorURL   string `xml:"comment_author_url"`
+	AuthorIP    string `xml:"comment_author_IP"`
+	Date        string `xml:"comment_date"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	Approved    string `xml:"comment_approved"`
+	Type        string `xml:"comment_type"`
+	Parent      int    `xml:"comment_parent"`
+}
+
+const wxrTimeLayout = "2006-01-02 15:04:05"
+
+var (
+	wordPressMediaPattern = regexp.MustCompile(`(?:https?:)?//[^\s"'<>()\[\]]+/wp-content/uploads/[^\s"'<>()\[\]?#]+`)
+	wordPressPrePattern   = regexp.MustCompile(`(?is)<pre[\s>].*?</pre>`)
+	wordPressParaPattern  = regexp.MustCompile(`\n\s*\n`)
+	wordPressBlockPattern = regexp.MustCompile(`(?i)^<(?:p|div|h[1-6]|ul|ol|li|dl|table|blockquote|pre|figure|hr|section|article|aside|form|address|iframe|!--)[\s>/]`)
+)
+
+// wordPressImport keeps the terms and the attachments imported from the WXR file
+type wordPressImport struct {
+	*contentImport
+	// hosts are the hosts of the WordPress site, only the media on them are imported
+	hosts       map[string]struct{}
+	categories  map[string]int32
+	tags        map[string]int32
+	attachments map[int]string
+}
+
+func (e *exportImport) ImportWordPress(ctx context.Context, reader io.Reader, uploads fs.FS) (*dto.ImportReport, error) {
+	ctx = context.WithValue(ctx, consts.ContentImport, true)
+	var rss wxrRSS
+	decoder := xml.NewDecoder(reader)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	if err := decoder.Decode(&rss); err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("parse WXR file failed")
+	}
+	channel := rss.Channel
+	if channel.WXRVersion == "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("not a WordPress export file")
+	}
+
+	w := &wordPressImport{
+		contentImport: newContentImport(uploads, "uploads", "wp-content/uploads"),
+		hosts:         make(map[string]struct{}),
+		categories:    make(map[string]int32),
+		tags:          make(map[string]int32),
+		attachments:   make(map[int]string),
+	}
+	for _, siteURL := range []string{channel.Link, channel.BaseSiteURL, channel.BaseBlogURL} {
+		w.addHost(siteURL)
+	}
+
+	if err := e.importWXRCategories(ctx, w, channel.Categories); err != nil {
+		return nil, err
+	}
+	for _, tag := range channel.Tags {
+		if _, err := e.wxrTagID(ctx, w, tag.Slug, tag.Name, "tag-"+strconv.Itoa(tag.TermID)); err != nil {
+			return nil, err
+		}
+	}
+
+	// the attachments are imported first so that the thumbnails and the content refer to them
+	for _, item := range channel.Items {
+		if item.PostType != "attachment" || item.AttachmentURL == "" {
+			continue
+		}
+		w.addHost(item.AttachmentURL)
+		relativePath := item.meta("_wp_attached_file")
+		if relativePath == "" {
+			relativePath = wordPressUploadPath(item.AttachmentURL)
+		}
+		if attachmentPath, ok := e.importMedia(ctx, w.contentImport, item.AttachmentURL, relativePath); ok {
+			w.attachments[item.PostID] = attachmentPath
+		}
+	}
+
+	for _, item := range channel.Items {
+		switch item.PostType {
+		case "attachment":
+		case "post", "page":
+			if err := e.importWXRItem(ctx, w, &item); err != nil {
+				return nil, err
+			}
+		default:
+			w.skip(item.PostType, item.Title, item.PostName, "unsupported post type")
+		}
+	}
+	return w.report, nil
+}
+
+// importWXRCategories creates the categories after their parents, the categories of the same slug or name are reused
+func (e *exportImport) importWXRCategories(ctx context.Context, w *wordPressImport, categories []wxrCategory) error {
+	pending := categories
+	for len(pending) > 0 {
+		next := make([]wxrCategory, 0)
+		for _, category := range pending {
+			parentID, ok := w.categories[category.Parent]
+			if category.Parent != "" && !ok {
+				next = append(next, category)
+				continue
+			}
+			categoryID, err := e.wxrCategoryID(ctx, w, category.Nicename, category.Name, category.Description, parentID, "category-"+strconv.Itoa(category.TermID))
+			if err != nil {
+				return err
+			}
+			w.categories[category.Nicename] = categoryID
+		}
+		if len(next) == len(pending) {
+			// the parents are missing in the file, the categories are created at the top level
+			for i := range next {
+				next[i].Parent = ""
+			}
+		}
+		pending = next
+	}
+	return nil
+}
+
+func (e *exportImport) wxrCategoryID(ctx context.Context, w *wordPressImport, nicename, name, description string, parentID int32, fallbackSlug string) (int32, error) {
+	if categoryID, ok := w.categories[nicename]; ok {
+		return categoryID, nil
+	}
+	name = truncateRunes(strings.TrimSpace(html.UnescapeString(name)), 255)
+	slug := importSlug(nicename, importSlug(name, fallbackSlug))
+	category, err := e.CategoryService.GetBySlug(ctx, slug)
+	if xerr.GetType(err) == xerr.NoRecord {
+		category, err = e.CategoryService.GetByName(ctx, name)
+	}
+	switch {
+	case err == nil:
+	case xerr.GetType(err) == xerr.NoRecord:
+		category, err = e.CategoryService.Create(ctx, &param.Category{
+			Name:        name,
+			Slug:        slug,
+			Description: truncateRunes(description, 100),
+			ParentID:    parentID,
+		})
+		if err != nil {
+			return 0, err
+		}
+		w.report.Categories++
+	default:
+		return 0, err
+	}
+	w.categories[nicename] = category.ID
+	return category.ID, nil
+}
+
+func (e *exportImport) wxrTagID(ctx context.Context, w *wordPressImport, nicename, name, fallbackSlug string) (int32, error) {
+	if tagID, ok := w.tags[nicename]; ok {
+		return tagID, nil
+	}
+	name = truncateRunes(strings.TrimSpace(html.UnescapeString(name)), 255)
+	slug := importSlug(nicename, importSlug(name, fallbackSlug))
+	tag, err := e.TagService.GetBySlug(ctx, slug)
+	if xerr.GetType(err) == xerr.NoRecord {
+		tag, err = e.TagService.GetByName(ctx, name)
+	}
+	switch {
+	case err == nil:
+	case xerr.GetType(err) == xerr.NoRecord:
+		tag, err = e.TagService.Create(ctx, &param.Tag{
+			Name: name,
+			Slug: slug,
+		})
+		if err != nil {
+			return 0, err
+		}
+		w.report.Tags++
+	default:
+		return 0, err
+	}
+	w.tags[nicename] = tag.ID
+	return tag.ID, nil
+}
+
+// importWXRItem imports the post or the page as a sheet with its comments,
+// the items whose slugs are used are skipped so that importing the same file again does not duplicate them
+func (e *exportImport) importWXRItem(ctx context.Context, w *wordPressImport, item *wxrItem) error {
+	itemType := util.IfElse(item.PostType == "page", "sheet", "post").(string)
+	title := truncateRunes(strings.TrimSpace(html.UnescapeString(item.Title)), 100)
+	if title == "" {
+		title = "Untitled"
+	}
+	var status consts.PostStatus
+	switch item.Status {
+	case "publish":
+		status = consts.PostStatusPublished
+	case "private":
+		status = consts.PostStatusIntimate
+	case "draft", "pending", "future":
+		status = consts.PostStatusDraft
+	default:
+		w.skip(itemType, title, item.PostName, "unsupported status "+item.Status)
+		return nil
+	}
+	if item.PostPassword != "" && status != consts.PostStatusDraft {
+		status = consts.PostStatusIntimate
+	}
+
+	slug := importSlug(item.PostName, importSlug(title, item.PostType+"-"+strconv.Itoa(item.PostID)))
+	existing, err := e.PostService.GetBySlug(ctx, slug)
+	if err == nil {
+		w.conflict(itemType, title, slug, "the slug is used by "+existing.Title)
+		return nil
+	}
+	if xerr.GetType(err) != xerr.NoRecord {
+		return err
+	}
+
+	var content, excerpt string
+	for _, encoded := range item.Encoded {
+		if strings.Contains(encoded.XMLName.Space, "excerpt") {
+			excerpt = encoded.Value
+		} else {
+			content = encoded.Value
+		}
+	}
+	content = e.rewriteWordPressMedia(ctx, w, wordPressAutoP(content))
+
+	post := &entity.Post{
+		Type:            util.IfElse(item.PostType == "page", consts.PostTypeSheet, consts.PostTypePost).(consts.PostType),
+		Title:           title,
+		Slug:            slug,
+		Status:          status,
+		EditorType:      consts.EditorTypeRichText,
+		OriginalContent: content,
+		FormatContent:   content,
+		Summary:         strings.TrimSpace(html.UnescapeString(util.CleanHTMLTag(excerpt))),
+		Password:        truncateRunes(item.PostPassword, 255),
+		DisallowComment: item.CommentStatus == "closed",
+		TopPriority:     int32(item.IsSticky),
+		WordCount:       util.HTMLFormatWordCount(content),
+		CreateTime:      wxrTime(item.PostDateGMT, item.PostDate),
+	}
+	if post.CreateTime.IsZero() {
+		post.CreateTime = time.Now()
+	}
+	if modified := wxrTime(item.PostModifiedGMT, item.PostModified); !modified.IsZero() {
+		post.EditTime = util.TimePtr(modified)
+		post.UpdateTime = util.TimePtr(modified)
+	}
+	if thumbnailID, err := strconv.Atoi(item.meta("_thumbnail_id")); err == nil {
+		post.Thumbnail = w.attachments[thumbnailID]
+	}
+
+	// the services are not asked to create the post, so that importing does not publish the update events
+	// which notify the search engines, send the mentions and deliver the posts to the followers
+	if item.PostType == "page" {
+		post, err = e.SheetService.CreateOrUpdate(ctx, post, nil, nil, nil)
+	} else {
+		var categoryIDs, tagIDs []int32
+		categoryIDs, tagIDs, err = e.wxrTermIDs(ctx, w, item.Terms)
+		if err != nil {
+			return err
+		}
+		post, err = e.PostService.CreateOrUpdate(ctx, post, categoryIDs, tagIDs, nil)
+	}
+	if err != nil {
+		w.skip(itemType, title, slug, err.Error())
+		return nil
+	}
+	if item.PostType == "page" {
+		w.report.Sheets++
+	} else {
+		w.report.Posts++
+	}
+	return e.importWXRComments(ctx, w, post, item.Comments)
+}
+
+func (e *exportImport) wxrTermIDs(ctx context.Context, w *wordPressImport, terms []wxrTerm) (categoryIDs, tagIDs []int32, err error) {
+	for _, term := range terms {
+		switch term.Domain {
+		case "category":
+			categoryID, err := e.wxrCategoryID(ctx, w, term.Nicename, term.Name, "", 0, "category-"+util.Md5Hex(term.Name)[:8])
+			if err != nil {
+				return nil, nil, err
+			}
+			categoryIDs = append(categoryIDs, categoryID)
+		case "post_tag":
+			tagID, err := e.wxrTagID(ctx, w, term.Nicename, term.Name, "tag-"+util.Md5Hex(term.Name)[:8])
+			if err != nil {
+				return nil, nil, err
+			}
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+	return categoryIDs, tagIDs, nil
+}
+
+// importWXRComments creates the comments with their original authors, dates and statuses,
+// the replies are attached to the top level when their parents are not imported
+func (e *exportImport) importWXRComments(ctx context.Context, w *wordPressImport, post *entity.Post, comments []wxrComment) error {
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].ID < comments[j].ID
+	})
+	commentDAL := dal.GetQueryByCtx(ctx).Comment
+	imported := make(map[int]int32, len(comments))
+	for _, source := range comments {
+		var status consts.CommentStatus
+		switch source.Approved {
+		case "1":
+			status = consts.CommentStatusPublished
+		case "0":
+			status = consts.CommentStatusAuditing
+		default:
+			w.skip("comment", source.Author, post.Slug, "the comment is "+source.Approved)
+			continue
+		}
+		if source.Type == "pingback" || source.Type == "trackback" {
+			w.skip("comment", source.AuthorURL, post.Slug, "unsupported comment type "+source.Type)
+			continue
+		}
+		content := strings.TrimSpace(html.UnescapeString(util.CleanHTMLTag(source.Content)))
+		if content == "" {
+			w.skip("comment", source.Author, post.Slug, "empty content")
+			continue
+		}
+		comment := &entity.Comment{
+			Type:              util.IfElse(post.Type == consts.PostTypeSheet, consts.CommentTypeSheet, consts.CommentTypePost).(consts.CommentType),
+			CreateTime:        wxrTime(source.DateGMT, source.Date),
+			AllowNotification: true,
+			Author:            truncateRunes(util.IfElse(source.Author != "", source.Author, "Anonymous").(string), 50),
+			AuthorURL:         truncateRunes(source.AuthorURL, 511),
+			Content:           truncateRunes(content, 1023),
+			Email:             truncateRunes(source.AuthorEmail, 255),
+			GravatarMd5:       util.Md5Hex(source.AuthorEmail),
+			IPAddress:         truncateRunes(source.AuthorIP, 127),
+			ParentID:          imported[source.Parent],
+			PostID:            post.ID,
+			Status:            status,
+		}
+		err := commentDAL.WithContext(ctx).Select(field.Star).Omit(commentDAL.UpdateTime).Create(comment)
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		imported[source.ID] = comment.ID
+		w.report.Comments++
+	}
+	return nil
+}
+
+// rewriteWordPressMedia imports the uploaded media of the WordPress site referenced by the content
+// and replaces the urls with the paths of the attachments
+func (e *exportImport) rewriteWordPressMedia(ctx context.Context, w *wordPressImport, content string) string {
+	return wordPressMediaPattern.ReplaceAllStringFunc(content, func(match string) string {
+		source := match
+		if strings.HasPrefix(source, "//") {
+			source = "https:" + source
+		}
+		sourceURL, err := url.Parse(source)
+		if err != nil {
+			return match
+		}
+		if _, ok := w.hosts[sourceURL.Hostname()]; !ok {
+			return match
+		}
+		attachmentPath, ok := e.importMedia(ctx, w.contentImport, source, wordPressUploadPath(source))
+		if !ok {
+			return match
+		}
+		return attachmentPath
+	})
+}
+
+func (w *wordPressImport) addHost(siteURL string) {
+	if u, err := url.Parse(strings.TrimSpace(siteURL)); err == nil && u.Hostname() != "" {
+		w.hosts[u.Hostname()] = struct{}{}
+	}
+}
+
+func (i *wxrItem) meta(key string) string {
+	for _, meta := range i.Metas {
+		if meta.Key == key {
+			return strings.TrimSpace(meta.Value)
+		}
+	}
+	return ""
+}
+
+// wordPressUploadPath returns the path relative to the uploads directory of the media url
+func wordPressUploadPath(mediaURL string) string {
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return ""
+	}
+	_, relativePath, ok := strings.Cut(u.Path, "/wp-content/uploads/")
+	if !ok {
+		return ""
+	}
+	return relativePath
+}
+
+// wxrTime prefers the time in GMT, WordPress leaves it zero for the drafts
+func wxrTime(gmt, local string) time.Time {
+	if t, err := time.ParseInLocation(wxrTimeLayout, strings.TrimSpace(gmt), time.UTC); err == nil && t.Year() > 1 {
+		return t.Local()
+	}
+	if t, err := time.ParseInLocation(wxrTimeLayout, strings.TrimSpace(local), time.Local); err == nil && t.Year() > 1 {
+		return t
+	}
+	return time.Time{}
+}
+
+// wordPressAutoP wraps the paragraphs of the classic editor content separated by the blank lines,
+// like wpautop of WordPress does when rendering, the block editor content has the paragraphs already
+func wordPressAutoP(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if strings.Contains(content, "<!-- wp:") || !strings.Contains(content, "\n") {
+		return content
+	}
+	// the preformatted blocks may contain the blank lines
+	pres := make([]string, 0)
+	content = wordPressPrePattern.ReplaceAllStringFunc(content, func(pre string) string {
+		pres = append(pres, pre)
+		return fmt.Sprintf("<pre>%d</pre>", len(pres)-1)
+	})
+	paragraphs := wordPressParaPattern.Split(strings.TrimSpace(content), -1)
+	var result strings.Builder
+	for _, paragraph := range paragraphs {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if wordPressBlockPattern.MatchString(paragraph) {
+			result.WriteString(paragraph)
+		} else {
+			result.WriteString("<p>" + strings.ReplaceAll(paragraph, "\n", "<br />\n") + "</p>")
+		}
+		result.WriteString("\n")
+	}
+	content = result.String()
+	for i, pre := range pres {
+		content = strings.Replace(content, fmt.Sprintf("<pre>%d</pre>", i), pre, 1)
+	}
+	return content
+}
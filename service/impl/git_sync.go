@@ -0,0 +1,842 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/pageparser"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type gitSyncServiceImpl struct {
+	Config              *config.Config
+	OptionService       service.OptionService
+	UserService         service.UserService
+	PostService         service.PostService
+	MetaService         service.MetaService
+	ExportImportService service.ExportImport
+	// mutex serializes the operations on the working copy
+	mutex sync.Mutex
+	// stateMutex guards the state reported by Status
+	stateMutex   sync.Mutex
+	lastPullTime time.Time
+	lastPushTime time.Time
+	lastError    string
+}
+
+func NewGitSyncService(
+	config *config.Config,
+	optionService service.OptionService,
+	userService service.UserService,
+	postService service.PostService,
+	metaService service.MetaService,
+	exportImportService service.ExportImport,
+	lifecycle fx.Lifecycle,
+) service.GitSyncService {
+	g := &gitSyncServiceImpl{
+		Config:              config,
+		OptionService:       optionService,
+		UserService:         userService,
+		PostService:         postService,
+		MetaService:         metaService,
+		ExportImportService: exportImportService,
+	}
+	stop := make(chan struct{})
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go g.schedule(stop)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			return nil
+		},
+	})
+	return g
+}
+
+// gitSyncSettings is the snapshot of the git sync options
+type gitSyncSettings struct {
+	repository string
+	branch     string
+	directory  string
+	auth       transport.AuthMethod
+}
+
+// gitSyncRepo is the working copy checked out at the branch of the remote
+type gitSyncRepo struct {
+	settings *gitSyncSettings
+	repo     *git.Repository
+	worktree *git.Worktree
+	dir      string
+}
+
+// schedule pulls the repository by the interval option, the option is checked every minute so that changing it needs no restart
+func (g *gitSyncServiceImpl) schedule(stop chan struct{}) {
+	ticker := time.NewTicker(consts.GitSyncScheduleTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		ctx := context.Background()
+		if !g.OptionService.GetOrByDefault(ctx, property.GitSyncEnabled).(bool) {
+			continue
+		}
+		interval := g.OptionService.GetOrByDefault(ctx, property.GitSyncInterval).(int)
+		g.stateMutex.Lock()
+		lastPullTime := g.lastPullTime
+		g.stateMutex.Unlock()
+		if interval <= 0 || time.Since(lastPullTime) < time.Duration(interval)*time.Minute {
+			continue
+		}
+		if _, err := g.Pull(ctx); err != nil {
+			log.CtxWarn(ctx, "scheduled git sync pull err", zap.Error(err))
+		}
+	}
+}
+
+func (g *gitSyncServiceImpl) settings(ctx context.Context) (*gitSyncSettings, error) {
+	if !g.OptionService.GetOrByDefault(ctx, property.GitSyncEnabled).(bool) {
+		return nil, xerr.Forbidden.New("git sync disabled").WithStatus(xerr.StatusForbidden).WithMsg("Git sync is disabled")
+	}
+	s := &gitSyncSettings{
+		repository: strings.TrimSpace(g.OptionService.GetOrByDefault(ctx, property.GitSyncRepository).(string)),
+		branch:     strings.TrimSpace(g.OptionService.GetOrByDefault(ctx, property.GitSyncBranch).(string)),
+		directory:  strings.Trim(path.Clean("/"+g.OptionService.GetOrByDefault(ctx, property.GitSyncDirectory).(string)), "/"),
+	}
+	if s.repository == "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("The repository of git sync is not configured")
+	}
+	if s.branch == "" {
+		s.branch = property.GitSyncBranch.DefaultValue.(string)
+	}
+	username := g.OptionService.GetOrByDefault(ctx, property.GitSyncUsername).(string)
+	password := g.OptionService.GetOrByDefault(ctx, property.GitSyncPassword).(string)
+	if username != "" || password != "" {
+		if username == "" {
+			// the access tokens of GitHub and Gitea accept any username
+			username = "sonic"
+		}
+		s.auth = &githttp.BasicAuth{Username: username, Password: password}
+	}
+	return s, nil
+}
+
+// open opens the working copy and resets it to the branch of the remote, the local commits not pushed are discarded
+// since the posts they were made from are still in the database
+func (g *gitSyncServiceImpl) open(ctx context.Context, s *gitSyncSettings) (*gitSyncRepo, error) {
+	dir := filepath.Join(g.Config.Sonic.WorkDir, consts.GitSyncDirName)
+	repo, err := git.PlainOpen(dir)
+	if err == nil {
+		// the working copy of another repository is cloned again
+		if remote, err := repo.Remote("origin"); err != nil || len(remote.Config().URLs) == 0 || remote.Config().URLs[0] != s.repository {
+			repo = nil
+		}
+	}
+	if repo == nil {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, xerr.NoType.Wrap(err).WithMsg("remove the git sync directory err")
+		}
+		if repo, err = git.PlainInit(dir, false); err != nil {
+			return nil, xerr.NoType.Wrap(err).WithMsg("init the git sync repository err")
+		}
+		if _, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{s.repository}}); err != nil {
+			return nil, xerr.NoType.Wrap(err).WithMsg("add the remote err")
+		}
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(s.branch)
+	remoteRef := plumbing.NewRemoteReferenceName("origin", s.branch)
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec("+" + branchRef + ":" + remoteRef)},
+		Auth:       s.auth,
+		Force:      true,
+	})
+	// the empty repository and the new branch are pushed to by the first commit
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) && !errors.Is(err, transport.ErrEmptyRemoteRepository) &&
+		!errors.Is(err, git.NoMatchingRefSpecError{}) {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("fetch the repository err: " + err.Error())
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branchRef)); err != nil {
+		return nil, xerr.NoType.Wrap(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err)
+	}
+	if remote, err := repo.Reference(remoteRef, true); err == nil {
+		if err := worktree.Reset(&git.ResetOptions{Commit: remote.Hash(), Mode: git.HardReset}); err != nil {
+			return nil, xerr.NoType.Wrap(err).WithMsg("reset the working copy err")
+		}
+	}
+	return &gitSyncRepo{settings: s, repo: repo, worktree: worktree, dir: dir}, nil
+}
+
+// path returns the path of the file in the working copy, the content of the repository is not trusted,
+// so a file other than a regular one or a path resolved out of the working copy by the symbolic links is refused
+func (r *gitSyncRepo) path(name string) (string, error) {
+	fullName := filepath.Join(r.dir, filepath.FromSlash(name))
+	root, err := filepath.EvalSymlinks(r.dir)
+	if err != nil {
+		return "", err
+	}
+	// the directories not created yet are checked by the nearest existing one
+	dir := filepath.Dir(fullName)
+	for {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			dir = resolved
+			break
+		}
+		parent := filepath.Dir(dir)
+		if !errors.Is(err, fs.ErrNotExist) || parent == dir {
+			return "", err
+		}
+		dir = parent
+	}
+	if relativeDir, err := filepath.Rel(root, dir); err != nil || relativeDir == ".." || strings.HasPrefix(relativeDir, ".."+string(filepath.Separator)) {
+		return "", xerr.BadParam.New("the path is out of the repository name=%s", name)
+	}
+	if info, err := os.Lstat(fullName); err == nil && !info.Mode().IsRegular() {
+		return "", xerr.BadParam.New("not a regular file name=%s", name)
+	}
+	return fullName, nil
+}
+
+// readFile returns the content of the file in the working copy, nil if it does not exist
+func (r *gitSyncRepo) readFile(name string) ([]byte, error) {
+	fullName, err := r.path(name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(fullName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func (g *gitSyncServiceImpl) commit(ctx context.Context, r *gitSyncRepo, name, content, message string) error {
+	fullName, err := r.path(name)
+	if err != nil {
+		return xerr.NoType.Wrap(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(fullName), os.ModePerm); err != nil {
+		return xerr.NoType.Wrap(err)
+	}
+	if err := os.WriteFile(fullName, []byte(content), 0o644); err != nil {
+		return xerr.NoType.Wrap(err)
+	}
+	if _, err := r.worktree.Add(name); err != nil {
+		return xerr.NoType.Wrap(err)
+	}
+	_, err = r.worktree.Commit(message, &git.CommitOptions{Author: g.signature(ctx)})
+	if err != nil {
+		return xerr.NoType.Wrap(err).WithMsg("commit err")
+	}
+	return nil
+}
+
+func (g *gitSyncServiceImpl) push(ctx context.Context, r *gitSyncRepo) error {
+	branchRef := plumbing.NewBranchReferenceName(r.settings.branch)
+	err := r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(branchRef + ":" + branchRef)},
+		Auth:       r.settings.auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("push the repository err: " + err.Error())
+	}
+	return nil
+}
+
+func (g *gitSyncServiceImpl) signature(ctx context.Context) *object.Signature {
+	signature := &object.Signature{
+		Name: g.OptionService.GetOrByDefault(ctx, property.BlogTitle).(string),
+		When: time.Now(),
+	}
+	if signature.Name == "" {
+		signature.Name = "sonic"
+	}
+	if users, err := g.UserService.GetAllUser(ctx); err == nil && len(users) > 0 {
+		signature.Email = users[0].Email
+	}
+	return signature
+}
+
+func (g *gitSyncServiceImpl) Push(ctx context.Context) (*dto.GitSyncReport, error) {
+	s, err := g.settings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := g.PostService.GetByStatus(ctx, []consts.PostStatus{consts.PostStatusPublished}, consts.PostTypePost, nil)
+	if err != nil {
+		return nil, err
+	}
+	return g.pushPosts(ctx, s, posts)
+}
+
+func (g *gitSyncServiceImpl) PushPost(ctx context.Context, postID int32) error {
+	if !g.OptionService.GetOrByDefault(ctx, property.GitSyncEnabled).(bool) {
+		return nil
+	}
+	s, err := g.settings(ctx)
+	if err != nil {
+		return err
+	}
+	post, err := g.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.Type != consts.PostTypePost || post.Status != consts.PostStatusPublished {
+		return nil
+	}
+	_, err = g.pushPosts(ctx, s, []*entity.Post{post})
+	return err
+}
+
+// pushPosts commits the posts changed since the last sync and pushes them at once,
+// it is retried once if the remote is changed while committing
+func (g *gitSyncServiceImpl) pushPosts(ctx context.Context, s *gitSyncSettings, posts []*entity.Post) (report *dto.GitSyncReport, err error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	defer func() {
+		if err != nil || report.Pushed > 0 {
+			g.setState(false, err)
+		}
+	}()
+	for attempt := 0; ; attempt++ {
+		report = &dto.GitSyncReport{Conflicts: make([]*dto.GitSyncConflict, 0)}
+		r, err := g.open(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		pending := make([]*entity.GitSyncFile, 0)
+		for _, post := range posts {
+			file, err := g.commitPost(ctx, r, post, report)
+			if err != nil {
+				return nil, err
+			}
+			if file != nil {
+				pending = append(pending, file)
+			}
+		}
+		if len(pending) == 0 {
+			return report, nil
+		}
+		err = g.push(ctx, r)
+		if err != nil && attempt == 0 {
+			log.CtxWarn(ctx, "git sync push err, retry", zap.Error(err))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range pending {
+			if err := g.saveFile(ctx, file); err != nil {
+				return nil, err
+			}
+		}
+		report.Pushed = len(pending)
+		return report, nil
+	}
+}
+
+// commitPost commits the post if it is changed since the last sync, the record of the sync is returned to be saved after pushing.
+// The file changed in the repository since the last sync is a conflict and is not overwritten
+func (g *gitSyncServiceImpl) commitPost(ctx context.Context, r *gitSyncRepo, post *entity.Post, report *dto.GitSyncReport) (*entity.GitSyncFile, error) {
+	file, err := g.getFileByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if file != nil && file.Conflict != "" {
+		report.Conflicts = append(report.Conflicts, g.convertToConflict(file, post))
+		return nil, nil
+	}
+	if file == nil {
+		file = &entity.GitSyncFile{
+			PostID: post.ID,
+			Path:   path.Join(r.settings.directory, strings.ReplaceAll(post.Slug, "/", "-")+".md"),
+		}
+	}
+	content, err := g.ExportImportService.RenderMarkdown(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	contentHash := gitSyncHash([]byte(content))
+	existing, err := r.readFile(file.Path)
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err)
+	}
+	fileHash := ""
+	if existing != nil {
+		fileHash = gitSyncHash(existing)
+	}
+
+	switch {
+	case file.ID != 0 && fileHash != file.FileHash:
+		reason := "the file is changed in the repository since the last sync"
+		if existing == nil {
+			reason = "the file is deleted in the repository since the last sync"
+		}
+		return nil, g.conflict(ctx, file, post, reason, report)
+	case file.ID != 0 && contentHash == file.ContentHash:
+		return nil, nil
+	case file.ID == 0 && existing != nil && fileHash != contentHash:
+		return nil, g.conflict(ctx, file, post, "the file exists in the repository but has never been synced", report)
+	}
+	if fileHash != contentHash {
+		if err := g.commit(ctx, r, file.Path, content, "Update "+post.Title); err != nil {
+			return nil, err
+		}
+	}
+	file.FileHash = contentHash
+	file.ContentHash = contentHash
+	return file, nil
+}
+
+func (g *gitSyncServiceImpl) Pull(ctx context.Context) (report *dto.GitSyncReport, err error) {
+	s, err := g.settings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	defer func() {
+		g.setState(true, err)
+	}()
+	r, err := g.open(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	report = &dto.GitSyncReport{Conflicts: make([]*dto.GitSyncConflict, 0)}
+
+	names := make([]string, 0)
+	root := filepath.Join(r.dir, filepath.FromSlash(s.directory))
+	err = filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		// the symbolic links are skipped, they could point to the files out of the repository
+		if d.Type().IsRegular() && isMarkdownFile(name) {
+			relativeName, err := filepath.Rel(r.dir, name)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(relativeName))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err)
+	}
+	for _, name := range names {
+		if err := g.pullFile(ctx, r, name, report); err != nil {
+			return nil, err
+		}
+	}
+
+	// the posts are kept when their files are deleted in the repository, it is reported as a conflict
+	fileDAL := dal.GetQueryByCtx(ctx).GitSyncFile
+	files, err := fileDAL.WithContext(ctx).Where(fileDAL.Conflict.Eq("")).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	for _, file := range files {
+		content, err := r.readFile(file.Path)
+		if err != nil {
+			return nil, xerr.NoType.Wrap(err)
+		}
+		if content != nil || !strings.HasPrefix(file.Path, strings.TrimPrefix(s.directory+"/", "/")) {
+			continue
+		}
+		post, err := g.PostService.GetByPostID(ctx, file.PostID)
+		if xerr.GetType(err) == xerr.NoRecord {
+			if _, err := fileDAL.WithContext(ctx).Where(fileDAL.ID.Eq(file.ID)).Delete(); err != nil {
+				return nil, WrapDBErr(err)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := g.conflict(ctx, file, post, "the file is deleted in the repository since the last sync", report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// pullFile creates or updates the post by the file changed since the last sync,
+// the post also changed since the last sync is a conflict and is not overwritten
+func (g *gitSyncServiceImpl) pullFile(ctx context.Context, r *gitSyncRepo, name string, report *dto.GitSyncReport) error {
+	content, err := r.readFile(name)
+	if err != nil {
+		return xerr.NoType.Wrap(err)
+	}
+	fileHash := gitSyncHash(content)
+	fileDAL := dal.GetQueryByCtx(ctx).GitSyncFile
+	file, err := fileDAL.WithContext(ctx).Where(fileDAL.Path.Eq(name)).Take()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return WrapDBErr(err)
+	}
+	if file != nil && file.FileHash == fileHash {
+		return nil
+	}
+
+	var post *entity.Post
+	if file != nil {
+		post, err = g.PostService.GetByPostID(ctx, file.PostID)
+	} else {
+		post, err = g.PostService.GetBySlug(ctx, gitSyncSlug(name, content))
+	}
+	if err != nil && xerr.GetType(err) != xerr.NoRecord {
+		return err
+	}
+	if post != nil && post.Type != consts.PostTypePost {
+		report.Conflicts = append(report.Conflicts, &dto.GitSyncConflict{
+			Title: post.Title, Slug: post.Slug, Path: name, Reason: "the slug is used by a sheet",
+		})
+		return nil
+	}
+	if post != nil && file == nil {
+		// the file is renamed in the repository or the post has never been synced
+		if file, err = g.getFileByPostID(ctx, post.ID); err != nil {
+			return err
+		}
+		if file == nil {
+			file = &entity.GitSyncFile{PostID: post.ID, Path: name}
+		}
+		if file.Path != name {
+			file.Path = name
+		}
+	}
+	if post != nil {
+		if file.Conflict != "" {
+			report.Conflicts = append(report.Conflicts, g.convertToConflict(file, post))
+			return nil
+		}
+		current, err := g.ExportImportService.RenderMarkdown(ctx, post)
+		if err != nil {
+			return err
+		}
+		currentHash := gitSyncHash([]byte(current))
+		switch {
+		case currentHash == fileHash:
+			// the post is the same as the file, e.g. the repository is populated by the export
+			file.FileHash, file.ContentHash = fileHash, currentHash
+			return g.saveFile(ctx, file)
+		case file.ID == 0:
+			return g.conflict(ctx, file, post, "the post of the same slug exists but has never been synced", report)
+		case currentHash != file.ContentHash:
+			return g.conflict(ctx, file, post, "both the post and the file are changed since the last sync", report)
+		}
+	}
+
+	post, err = g.applyFile(ctx, post, name, content)
+	if err != nil {
+		if xerr.GetHTTPStatus(err) == xerr.StatusBadRequest {
+			report.Conflicts = append(report.Conflicts, &dto.GitSyncConflict{Path: name, Reason: xerr.GetMessage(err)})
+			return nil
+		}
+		return err
+	}
+	if file == nil {
+		report.Created++
+		file = &entity.GitSyncFile{PostID: post.ID, Path: name}
+	} else {
+		report.Updated++
+	}
+	current, err := g.ExportImportService.RenderMarkdown(ctx, post)
+	if err != nil {
+		return err
+	}
+	file.FileHash, file.ContentHash = fileHash, gitSyncHash([]byte(current))
+	return g.saveFile(ctx, file)
+}
+
+// applyFile creates the post by the file, or updates the post by it with the fields not in the front matter kept
+func (g *gitSyncServiceImpl) applyFile(ctx context.Context, post *entity.Post, name string, content []byte) (*entity.Post, error) {
+	ctx = context.WithValue(ctx, consts.ContentImport, true)
+	postParam, err := g.ExportImportService.ParseMarkdown(ctx, path.Base(name), bytes.NewReader(content))
+	if err != nil {
+		// the invalid file is reported instead of failing the others
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest)
+	}
+	if postParam.Slug == "" {
+		postParam.Slug = gitSyncSlug(name, content)
+	}
+	if postParam.Title == "" {
+		postParam.Title = postParam.Slug
+	}
+	if post == nil {
+		return g.PostService.Create(ctx, postParam)
+	}
+
+	postParam.Thumbnail = post.Thumbnail
+	postParam.Password = post.Password
+	postParam.Template = post.Template
+	postParam.TopPriority = post.TopPriority
+	postParam.MetaDescription = post.MetaDescription
+	if postParam.MetaKeywords == "" {
+		postParam.MetaKeywords = post.MetaKeywords
+	}
+	if post.Status == consts.PostStatusIntimate && postParam.Status == consts.PostStatusPublished {
+		postParam.Status = consts.PostStatusIntimate
+	}
+	metas, err := g.MetaService.GetPostMeta(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, meta := range metas {
+		postParam.MetaParam = append(postParam.MetaParam, param.Meta{Key: meta.MetaKey, Value: meta.MetaValue})
+	}
+	return g.PostService.Update(ctx, post.ID, postParam)
+}
+
+func (g *gitSyncServiceImpl) Resolve(ctx context.Context, postID int32, keepPost bool) (err error) {
+	s, err := g.settings(ctx)
+	if err != nil {
+		return err
+	}
+	file, err := g.getFileByPostID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if file == nil || file.Conflict == "" {
+		return xerr.BadParam.New("no conflict postID=%d", postID).WithStatus(xerr.StatusBadRequest).WithMsg("The post has no conflict")
+	}
+	post, err := g.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	defer func() {
+		g.setState(!keepPost, err)
+	}()
+	r, err := g.open(ctx, s)
+	if err != nil {
+		return err
+	}
+	fileDAL := dal.GetQueryByCtx(ctx).GitSyncFile
+	if keepPost {
+		content, err := g.ExportImportService.RenderMarkdown(ctx, post)
+		if err != nil {
+			return err
+		}
+		if err := g.commit(ctx, r, file.Path, content, "Update "+post.Title); err != nil {
+			return err
+		}
+		if err := g.push(ctx, r); err != nil {
+			return err
+		}
+		file.FileHash = gitSyncHash([]byte(content))
+		file.ContentHash = file.FileHash
+		file.Conflict = ""
+		return g.saveFile(ctx, file)
+	}
+
+	content, err := r.readFile(file.Path)
+	if err != nil {
+		return xerr.NoType.Wrap(err)
+	}
+	if content == nil {
+		// the post deleted in the repository is moved to the recycle bin
+		if _, err := g.PostService.UpdateStatus(ctx, postID, consts.PostStatusRecycle); err != nil {
+			return err
+		}
+		_, err = fileDAL.WithContext(ctx).Where(fileDAL.ID.Eq(file.ID)).Delete()
+		return WrapDBErr(err)
+	}
+	post, err = g.applyFile(ctx, post, file.Path, content)
+	if err != nil {
+		return err
+	}
+	current, err := g.ExportImportService.RenderMarkdown(ctx, post)
+	if err != nil {
+		return err
+	}
+	file.FileHash = gitSyncHash(content)
+	file.ContentHash = gitSyncHash([]byte(current))
+	file.Conflict = ""
+	return g.saveFile(ctx, file)
+}
+
+func (g *gitSyncServiceImpl) Status(ctx context.Context) (*dto.GitSyncStatus, error) {
+	g.stateMutex.Lock()
+	status := &dto.GitSyncStatus{
+		Enabled:    g.OptionService.GetOrByDefault(ctx, property.GitSyncEnabled).(bool),
+		Repository: g.OptionService.GetOrByDefault(ctx, property.GitSyncRepository).(string),
+		Branch:     g.OptionService.GetOrByDefault(ctx, property.GitSyncBranch).(string),
+		LastError:  g.lastError,
+		Conflicts:  make([]*dto.GitSyncConflict, 0),
+	}
+	if !g.lastPullTime.IsZero() {
+		status.LastPullTime = g.lastPullTime.UnixMilli()
+	}
+	if !g.lastPushTime.IsZero() {
+		status.LastPushTime = g.lastPushTime.UnixMilli()
+	}
+	g.stateMutex.Unlock()
+
+	fileDAL := dal.GetQueryByCtx(ctx).GitSyncFile
+	files, err := fileDAL.WithContext(ctx).Where(fileDAL.Conflict.Neq("")).Order(fileDAL.ID).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	postIDs := make([]int32, 0, len(files))
+	for _, file := range files {
+		postIDs = append(postIDs, file.PostID)
+	}
+	posts, err := g.PostService.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if post, ok := posts[file.PostID]; ok {
+			status.Conflicts = append(status.Conflicts, g.convertToConflict(file, post))
+		}
+	}
+	return status, nil
+}
+
+func (g *gitSyncServiceImpl) VerifyWebhook(ctx context.Context, header http.Header, body []byte) error {
+	secret := g.OptionService.GetOrByDefault(ctx, property.GitSyncWebhookSecret).(string)
+	if secret == "" || !g.OptionService.GetOrByDefault(ctx, property.GitSyncEnabled).(bool) {
+		return xerr.NoRecord.New("git sync webhook disabled").WithStatus(xerr.StatusNotFound).WithMsg("The webhook is disabled")
+	}
+	// GitLab sends the secret as it is, GitHub and Gitea sign the body with it
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			return nil
+		}
+	} else {
+		signature := strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if signature == "" {
+			signature = header.Get("X-Gitea-Signature")
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if expected, err := hex.DecodeString(signature); err == nil && hmac.Equal(expected, mac.Sum(nil)) {
+			return nil
+		}
+	}
+	return xerr.Forbidden.New("invalid webhook signature").WithStatus(xerr.StatusForbidden).WithMsg("Invalid signature")
+}
+
+func (g *gitSyncServiceImpl) setState(pull bool, err error) {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+	if pull {
+		g.lastPullTime = time.Now()
+	} else if err == nil {
+		g.lastPushTime = time.Now()
+	}
+	g.lastError = ""
+	if err != nil {
+		g.lastError = err.Error()
+	}
+}
+
+func (g *gitSyncServiceImpl) getFileByPostID(ctx context.Context, postID int32) (*entity.GitSyncFile, error) {
+	fileDAL := dal.GetQueryByCtx(ctx).GitSyncFile
+	file, err := fileDAL.WithContext(ctx).Where(fileDAL.PostID.Eq(postID)).Take()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return file, WrapDBErr(err)
+}
+
+func (g *gitSyncServiceImpl) saveFile(ctx context.Context, file *entity.GitSyncFile) error {
+	fileDAL := dal.GetQueryByCtx(ctx).GitSyncFile
+	if file.ID == 0 {
+		return WrapDBErr(fileDAL.WithContext(ctx).Create(file))
+	}
+	_, err := fileDAL.WithContext(ctx).Where(fileDAL.ID.Eq(file.ID)).Select(fileDAL.Path, fileDAL.FileHash, fileDAL.ContentHash, fileDAL.Conflict).Updates(file)
+	return WrapDBErr(err)
+}
+
+// conflict saves the conflict of the post so that it is not synced until the conflict is resolved
+func (g *gitSyncServiceImpl) conflict(ctx context.Context, file *entity.GitSyncFile, post *entity.Post, reason string, report *dto.GitSyncReport) error {
+	file.Conflict = reason
+	if err := g.saveFile(ctx, file); err != nil {
+		return err
+	}
+	log.CtxWarn(ctx, "git sync conflict", zap.Int32("postID", post.ID), zap.String("path", file.Path), zap.String("reason", reason))
+	report.Conflicts = append(report.Conflicts, g.convertToConflict(file, post))
+	return nil
+}
+
+func (g *gitSyncServiceImpl) convertToConflict(file *entity.GitSyncFile, post *entity.Post) *dto.GitSyncConflict {
+	return &dto.GitSyncConflict{
+		PostID: post.ID,
+		Title:  post.Title,
+		Slug:   post.Slug,
+		Path:   file.Path,
+		Reason: file.Conflict,
+	}
+}
+
+// gitSyncSlug returns the slug of the file by its front matter or its name
+func gitSyncSlug(name string, content []byte) string {
+	if contentFrontMatter, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(content)); err == nil {
+		for _, key := range []string{"slug", "permalink"} {
+			if slug, ok := contentFrontMatter.FrontMatter[key].(string); ok && slug != "" {
+				return slug
+			}
+		}
+	}
+	if _, postName, err := parseJekyllFilename(path.Base(name)); err == nil {
+		return postName
+	}
+	return strings.TrimSuffix(path.Base(name), path.Ext(name))
+}
+
+func gitSyncHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
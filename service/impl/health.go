@@ -0,0 +1,91 @@
+package impl
+
+import (
+	"context"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type healthServiceImpl struct {
+	Config   *config.Config
+	Template *template.Template
+}
+
+func NewHealthService(config *config.Config, template *template.Template) service.HealthService {
+	return &healthServiceImpl{
+		Config:   config,
+		Template: template,
+	}
+}
+
+func (h *healthServiceImpl) Liveness(ctx context.Context) *dto.Health {
+	return &dto.Health{Status: consts.HealthStatusUp}
+}
+
+func (h *healthServiceImpl) Readiness(ctx context.Context) *dto.Health {
+	ctx, cancel := context.WithTimeout(ctx, consts.HealthCheckTimeout)
+	defer cancel()
+
+	checks := map[string]error{
+		"database":  h.checkDB(ctx),
+		"uploadDir": checkWritable(h.Config.Sonic.UploadDir),
+		"logDir":    checkWritable(h.Config.Sonic.LogDir),
+		"template":  h.checkTemplate(),
+	}
+	health := &dto.Health{
+		Status: consts.HealthStatusUp,
+		Checks: make(map[string]*dto.HealthCheck, len(checks)),
+	}
+	for name, err := range checks {
+		if err != nil {
+			// the details are only logged since the endpoint is not authenticated
+			log.CtxWarn(ctx, "health check failed", zap.String("check", name), zap.Error(err))
+			health.Status = consts.HealthStatusDown
+			health.Checks[name] = &dto.HealthCheck{Status: consts.HealthStatusDown}
+			continue
+		}
+		health.Checks[name] = &dto.HealthCheck{Status: consts.HealthStatusUp}
+	}
+	return health
+}
+
+func (h *healthServiceImpl) checkDB(ctx context.Context) error {
+	if dal.DB == nil {
+		return xerr.NoType.New("database is not initialized")
+	}
+	sqlDB, err := dal.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (h *healthServiceImpl) checkTemplate() error {
+	if !h.Template.Loaded() {
+		return xerr.NoType.New("templates are not loaded")
+	}
+	return nil
+}
+
+// checkWritable creates and removes a temporary file, a stat would miss read-only mounts
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".sonic-health-*")
+	if err != nil {
+		return err
+	}
+	name := file.Name()
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
@@ -9,6 +9,7 @@ func init() {
 	injection.Provide(
 		NewAdminService,
 		NewAttachmentService,
+		NewAuditLogService,
 		NewAuthenticateService,
 		NewBackUpService,
 		NewBaseCommentService,
@@ -16,12 +17,16 @@ func init() {
 		NewCategoryService,
 		NewEmailService,
 		NewInstallService,
+		NewHealthService,
 		NewJournalService,
 		NewLinkService,
 		NewJournalCommentService,
 		NewLogService,
+		NewLoginProtectService,
 		NewMenuService,
+		NewMetricsService,
 		NewMetaService,
+		NewOIDCService,
 		NewBaseMFAService,
 		NewTwoFactorTOTPMFAService,
 		NewOneTimeTokenService,
@@ -32,11 +37,25 @@ func init() {
 		NewPostCategoryService,
 		NewPostCommentService,
 		NewPostTagService,
+		NewRedirectService,
 		NewSheetService,
 		NewSheetCommentService,
+		NewShortcodeService,
+		NewSitemapService,
+		NewMentionService,
+		NewActivityPubService,
+		NewGitSyncService,
+		NewMetaWeblogService,
+		NewMicropubService,
+		NewSeriesService,
+		NewCustomFieldService,
+		NewRelatedPostService,
+		NewTranslationService,
 		NewStatisticService,
 		NewTagService,
 		NewThemeService,
+		NewThemePreviewService,
+		NewThemeSettingPresetService,
 		NewUserService,
 		NewExportImport,
 		storage.NewFileStorageComposite,
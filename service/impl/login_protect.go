@@ -0,0 +1,159 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type loginFailure struct {
+	Count       int
+	LastFailed  time.Time
+	LockedUntil time.Time
+}
+
+type loginProtectServiceImpl struct {
+	Cache         cache.Cache
+	OptionService service.OptionService
+	mu            sync.Mutex
+}
+
+func NewLoginProtectService(cache cache.Cache, optionService service.OptionService) service.LoginProtectService {
+	return &loginProtectServiceImpl{
+		Cache:         cache,
+		OptionService: optionService,
+	}
+}
+
+func (l *loginProtectServiceImpl) CheckAllowed(ctx context.Context, loginParam param.LoginParam) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	maxCount := 0
+	for _, key := range l.failureKeys(ctx, loginParam.Username) {
+		failure := l.getFailure(key)
+		if failure == nil {
+			continue
+		}
+		if now.Before(failure.LockedUntil) {
+			minutes := int(failure.LockedUntil.Sub(now).Minutes()) + 1
+			return xerr.Forbidden.New("login locked key=%s", key).WithStatus(xerr.StatusTooManyRequests).
+				WithMsg(fmt.Sprintf("登录失败次数过多，请在 %d 分钟后重试", minutes))
+		}
+		if wait := failure.LastFailed.Add(loginDelay(failure.Count)).Sub(now); wait > 0 {
+			seconds := int(wait.Seconds()) + 1
+			return xerr.Forbidden.New("login delayed key=%s", key).WithStatus(xerr.StatusTooManyRequests).
+				WithMsg(fmt.Sprintf("登录过于频繁，请在 %d 秒后重试", seconds))
+		}
+		if failure.Count > maxCount {
+			maxCount = failure.Count
+		}
+	}
+
+	captchaEnabled := l.OptionService.GetOrByDefault(ctx, property.LoginCaptchaEnabled).(bool)
+	if !captchaEnabled || maxCount < consts.LoginCaptchaThreshold {
+		return nil
+	}
+	if loginParam.CaptchaID == "" || loginParam.CaptchaCode == "" {
+		return xerr.BadParam.New("captcha required").WithStatus(xerr.StatusBadRequest).WithMsg("请输入验证码")
+	}
+	code, ok := l.Cache.Get(cache.BuildLoginCaptchaKey(loginParam.CaptchaID))
+	if !ok {
+		return xerr.BadParam.New("captcha not exist").WithStatus(xerr.StatusBadRequest).WithMsg("验证码已过期，请刷新")
+	}
+	if !strings.EqualFold(code.(string), strings.TrimSpace(loginParam.CaptchaCode)) {
+		l.Cache.Delete(cache.BuildLoginCaptchaKey(loginParam.CaptchaID))
+		return xerr.BadParam.New("captcha mismatch").WithStatus(xerr.StatusBadRequest).WithMsg("验证码错误")
+	}
+	return nil
+}
+
+func (l *loginProtectServiceImpl) RecordFailure(ctx context.Context, loginParam param.LoginParam) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	maxAttempts := l.OptionService.GetOrByDefault(ctx, property.LoginMaxFailedAttempts).(int)
+	lockDuration := time.Duration(l.OptionService.GetOrByDefault(ctx, property.LoginLockMinutes).(int)) * time.Minute
+
+	now := time.Now()
+	for _, key := range l.failureKeys(ctx, loginParam.Username) {
+		failure := l.getFailure(key)
+		// start counting again once a lock has expired
+		if failure == nil || (!failure.LockedUntil.IsZero() && now.After(failure.LockedUntil)) {
+			failure = &loginFailure{}
+		}
+		failure.Count++
+		failure.LastFailed = now
+		if maxAttempts > 0 && failure.Count >= maxAttempts {
+			failure.LockedUntil = now.Add(lockDuration)
+		}
+		l.Cache.Set(key, failure, lockDuration)
+	}
+	// a captcha can only be used for one failed attempt
+	if loginParam.CaptchaID != "" {
+		l.Cache.Delete(cache.BuildLoginCaptchaKey(loginParam.CaptchaID))
+	}
+}
+
+func (l *loginProtectServiceImpl) RecordSuccess(ctx context.Context, username string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Cache.BatchDelete(l.failureKeys(ctx, username))
+}
+
+func (l *loginProtectServiceImpl) GenerateCaptcha(ctx context.Context) (*dto.Captcha, error) {
+	code := util.GenCaptchaCode(4)
+	image, err := util.GenCaptchaImage(code)
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError).WithMsg("generate captcha error")
+	}
+	captchaID := util.GenUUIDWithOutDash()
+	l.Cache.Set(cache.BuildLoginCaptchaKey(captchaID), code, consts.LoginCaptchaValidDuration)
+	return &dto.Captcha{
+		ID:    captchaID,
+		Image: image,
+	}, nil
+}
+
+func (l *loginProtectServiceImpl) failureKeys(ctx context.Context, username string) []string {
+	keys := make([]string, 0, 2)
+	if ip := util.GetClientIP(ctx); ip != "" {
+		keys = append(keys, cache.BuildLoginFailedIPKey(ip))
+	}
+	if username != "" {
+		keys = append(keys, cache.BuildLoginFailedUserKey(username))
+	}
+	return keys
+}
+
+func (l *loginProtectServiceImpl) getFailure(key string) *loginFailure {
+	value, ok := l.Cache.Get(key)
+	if !ok {
+		return nil
+	}
+	return value.(*loginFailure)
+}
+
+// loginDelay doubles the waiting time for every failed attempt after consts.LoginDelayThreshold
+func loginDelay(count int) time.Duration {
+	if count < consts.LoginDelayThreshold {
+		return 0
+	}
+	delay := time.Second << (count - consts.LoginDelayThreshold)
+	if delay > consts.LoginMaxDelay || delay <= 0 {
+		return consts.LoginMaxDelay
+	}
+	return delay
+}
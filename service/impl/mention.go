@@ -0,0 +1,575 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/microformats"
+	"github.com/go-sonic/sonic/util/xerr"
+	"github.com/go-sonic/sonic/util/xmlrpc"
+)
+
+type mentionServiceImpl struct {
+	OptionService service.OptionService
+	PostService   service.PostService
+	client        *http.Client
+	// queue holds the received mentions until a worker verifies them
+	queue chan *receivedMention
+}
+
+type receivedMention struct {
+	protocol  consts.MentionProtocol
+	source    string
+	target    string
+	ipAddress string
+}
+
+func NewMentionService(optionService service.OptionService, postService service.PostService, lifecycle fx.Lifecycle) service.MentionService {
+	m := &mentionServiceImpl{
+		OptionService: optionService,
+		PostService:   postService,
+		client:        newPublicHTTPClient(consts.MentionTimeout),
+		queue:         make(chan *receivedMention, consts.MentionQueueSize),
+	}
+	stop := make(chan struct{})
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			for i := 0; i < consts.MentionWorkers; i++ {
+				go m.work(stop)
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			return nil
+		},
+	})
+	return m
+}
+
+func (m *mentionServiceImpl) work(stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case mention := <-m.queue:
+			ctx, cancel := context.WithTimeout(context.Background(), consts.MentionTimeout*2)
+			if err := m.Verify(ctx, mention.protocol, mention.source, mention.target, mention.ipAddress); err != nil {
+				log.CtxWarn(ctx, "verify mention err", zap.String("source", mention.source), zap.String("target", mention.target), zap.Error(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// newPublicHTTPClient creates the client for the urls submitted by visitors, it only connects to the public addresses
+func newPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: util.PublicAddressControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return &http.Client{Timeout: timeout, Transport: tracing.NewTransport(transport)}
+}
+
+func (m *mentionServiceImpl) Receive(ctx context.Context, protocol consts.MentionProtocol, source, target, ipAddress string) error {
+	if !m.isEnabled(ctx, protocol) {
+		return xerr.Forbidden.New("mention disabled protocol=%d", protocol).WithStatus(xerr.StatusForbidden).WithMsg("Mention is not enabled")
+	}
+	sourceURL, err := parseMentionURL(source)
+	if err != nil {
+		return err
+	}
+	targetURL, err := parseMentionURL(target)
+	if err != nil {
+		return err
+	}
+	if normalizeMentionURL(sourceURL) == normalizeMentionURL(targetURL) {
+		return xerr.BadParam.New("source equals target").WithStatus(xerr.StatusBadRequest).WithMsg("The source must not be the target")
+	}
+	if _, err := m.findTargetPost(ctx, targetURL); err != nil {
+		return err
+	}
+	// the mentions are verified asynchronously by a bounded number of workers, the senders retry when the queue is full
+	select {
+	case m.queue <- &receivedMention{protocol: protocol, source: sourceURL.String(), target: targetURL.String(), ipAddress: ipAddress}:
+		return nil
+	default:
+		return xerr.NoType.New("mention queue is full").WithStatus(xerr.StatusTooManyRequests).WithMsg("Too many mentions, please retry later")
+	}
+}
+
+func (m *mentionServiceImpl) Verify(ctx context.Context, protocol consts.MentionProtocol, source, target, ipAddress string) error {
+	sourceURL, err := parseMentionURL(source)
+	if err != nil {
+		return err
+	}
+	targetURL, err := parseMentionURL(target)
+	if err != nil {
+		return err
+	}
+	post, err := m.findTargetPost(ctx, targetURL)
+	if err != nil {
+		return err
+	}
+	resp, err := m.get(ctx, sourceURL.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusNotFound {
+		return m.deleteBySource(ctx, source, target)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return xerr.NoType.New("fetch source %s failed status=%d", source, resp.StatusCode).WithStatus(xerr.StatusBadRequest)
+	}
+	page, err := microformats.Parse(io.LimitReader(resp.Body, consts.MentionFetchLimit), resp.Request.URL)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Invalid source")
+	}
+	normalizedTarget := normalizeMentionURL(targetURL)
+	if !containsMentionURL(page.Links, normalizedTarget) {
+		return m.deleteBySource(ctx, source, target)
+	}
+
+	mention := &entity.Mention{
+		PostID:    post.ID,
+		Source:    source,
+		Target:    target,
+		Protocol:  protocol,
+		Type:      consts.MentionTypeMention,
+		Title:     truncateRunes(page.Title, 1023),
+		IPAddress: ipAddress,
+		Author:    sourceURL.Host,
+		AuthorURL: sourceURL.Scheme + "://" + sourceURL.Host,
+	}
+	if entry := page.Entry; entry != nil {
+		switch {
+		case containsMentionURL(entry.InReplyTo, normalizedTarget):
+			mention.Type = consts.MentionTypeReply
+		case containsMentionURL(entry.LikeOf, normalizedTarget):
+			mention.Type = consts.MentionTypeLike
+		case containsMentionURL(entry.RepostOf, normalizedTarget):
+			mention.Type = consts.MentionTypeRepost
+		case containsMentionURL(entry.BookmarkOf, normalizedTarget):
+			mention.Type = consts.MentionTypeBookmark
+		}
+		// the name of a note is its content
+		if entry.Name != "" && entry.Name != entry.Content {
+			mention.Title = truncateRunes(entry.Name, 1023)
+		}
+		mention.Content = truncateRunes(entry.Content, consts.MentionContentLimit)
+		if author := entry.Author; author != nil {
+			if author.Name != "" {
+				mention.Author = truncateRunes(author.Name, 255)
+			}
+			if author.URL != "" {
+				mention.AuthorURL = truncateRunes(author.URL, 1023)
+			}
+			mention.AuthorPhoto = truncateRunes(author.Photo, 1023)
+		}
+		if !entry.Published.IsZero() {
+			mention.PublishTime = &entry.Published
+		}
+	}
+
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	existing, err := mentionDAL.WithContext(ctx).Where(mentionDAL.Source.Eq(source), mentionDAL.Target.Eq(target)).Find()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if len(existing) > 0 {
+		mention.ID = existing[0].ID
+		mention.Status = existing[0].Status
+		_, err = mentionDAL.WithContext(ctx).Where(mentionDAL.ID.Eq(mention.ID)).Select(field.Star).Omit(mentionDAL.CreateTime).Updates(mention)
+		return WrapDBErr(err)
+	}
+	mention.Status = consts.CommentStatusPublished
+	if m.OptionService.GetOrByDefault(ctx, property.MentionNewNeedCheck).(bool) {
+		mention.Status = consts.CommentStatusAuditing
+	}
+	return WrapDBErr(mentionDAL.WithContext(ctx).Create(mention))
+}
+
+func (m *mentionServiceImpl) Send(ctx context.Context, post *entity.Post) error {
+	webmentionEnabled := m.OptionService.GetOrByDefault(ctx, property.WebmentionEnabled).(bool)
+	pingbackEnabled := m.OptionService.GetOrByDefault(ctx, property.PingbackEnabled).(bool)
+	if (!webmentionEnabled && !pingbackEnabled) || post.Status != consts.PostStatusPublished {
+		return nil
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return err
+	}
+	fullPath, err := m.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return err
+	}
+	source := absoluteURL(blogURL, fullPath)
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Invalid blog url")
+	}
+	page, err := microformats.Parse(strings.NewReader(post.FormatContent), sourceURL)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	sent := make(map[string]struct{})
+	var sendErr error
+	for _, target := range page.Links {
+		targetURL, err := url.Parse(target)
+		if err != nil || targetURL.Host == sourceURL.Host {
+			continue
+		}
+		targetURL.Fragment = ""
+		if _, ok := sent[targetURL.String()]; ok {
+			continue
+		}
+		if len(sent) >= consts.MentionSendLimit {
+			break
+		}
+		sent[targetURL.String()] = struct{}{}
+		if err := m.sendTo(ctx, source, targetURL.String(), webmentionEnabled, pingbackEnabled); err != nil {
+			log.CtxWarn(ctx, "send mention err", zap.String("source", source), zap.String("target", targetURL.String()), zap.Error(err))
+			sendErr = err
+		}
+	}
+	return sendErr
+}
+
+// sendTo discovers the webmention endpoint of the target, and falls back to pingback
+func (m *mentionServiceImpl) sendTo(ctx context.Context, source, target string, webmentionEnabled, pingbackEnabled bool) error {
+	resp, err := m.get(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var page *microformats.Page
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		page, err = microformats.Parse(io.LimitReader(resp.Body, consts.MentionFetchLimit), resp.Request.URL)
+		if err != nil {
+			return xerr.WithStatus(err, xerr.StatusBadRequest)
+		}
+	}
+	if webmentionEnabled {
+		endpoint := linkHeader(resp.Header.Values("Link"), resp.Request.URL, "webmention")
+		if endpoint == "" && page != nil && len(page.Rels["webmention"]) > 0 {
+			endpoint = page.Rels["webmention"][0]
+		}
+		if endpoint != "" {
+			form := url.Values{"source": {source}, "target": {target}}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+			if err != nil {
+				return xerr.WithStatus(err, xerr.StatusBadRequest)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return m.do(req)
+		}
+	}
+	if pingbackEnabled {
+		endpoint := resp.Header.Get("X-Pingback")
+		if endpoint == "" && page != nil && len(page.Rels["pingback"]) > 0 {
+			endpoint = page.Rels["pingback"][0]
+		}
+		if endpoint != "" {
+			body := &bytes.Buffer{}
+			if err := xmlrpc.EncodeMethodCall(body, "pingback.ping", source, target); err != nil {
+				return xerr.WithStatus(err, xerr.StatusInternalServerError)
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+			if err != nil {
+				return xerr.WithStatus(err, xerr.StatusBadRequest)
+			}
+			req.Header.Set("Content-Type", "text/xml")
+			resp, err := m.client.Do(req)
+			if err != nil {
+				return xerr.WithStatus(err, xerr.StatusInternalServerError)
+			}
+			defer resp.Body.Close()
+			_, err = xmlrpc.DecodeResponse(io.LimitReader(resp.Body, consts.MentionFetchLimit))
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mentionServiceImpl) Page(ctx context.Context, mentionQuery param.MentionQuery) ([]*entity.Mention, int64, error) {
+	if mentionQuery.PageNum < 0 || mentionQuery.PageSize <= 0 || mentionQuery.PageSize > 100 {
+		return nil, 0, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("Paging parameter error")
+	}
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	mentionDO := mentionDAL.WithContext(ctx).Where(m.buildConditions(ctx, mentionQuery)...)
+	err := BuildSort(mentionQuery.Sort, &mentionDAL, &mentionDO)
+	if err != nil {
+		return nil, 0, err
+	}
+	mentions, totalCount, err := mentionDO.FindByPage(mentionQuery.PageNum*mentionQuery.PageSize, mentionQuery.PageSize)
+	if err != nil {
+		return nil, 0, WrapDBErr(err)
+	}
+	return mentions, totalCount, nil
+}
+
+func (m *mentionServiceImpl) buildConditions(ctx context.Context, mentionQuery param.MentionQuery) []gen.Condition {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	conditions := make([]gen.Condition, 0)
+	if mentionQuery.PostID != nil {
+		conditions = append(conditions, mentionDAL.PostID.Eq(*mentionQuery.PostID))
+	}
+	if mentionQuery.MentionType != nil {
+		conditions = append(conditions, mentionDAL.Type.Eq(*mentionQuery.MentionType))
+	}
+	if mentionQuery.MentionStatus != nil {
+		conditions = append(conditions, mentionDAL.Status.Eq(*mentionQuery.MentionStatus))
+	}
+	if mentionQuery.Keyword != nil && *mentionQuery.Keyword != "" {
+		keyword := "%" + *mentionQuery.Keyword + "%"
+		conditions = append(conditions, field.Or(mentionDAL.Source.Like(keyword), mentionDAL.Author.Like(keyword),
+			mentionDAL.Title.Like(keyword), mentionDAL.Content.Like(keyword)))
+	}
+	return conditions
+}
+
+func (m *mentionServiceImpl) ListByPostID(ctx context.Context, postID int32, status consts.CommentStatus) ([]*entity.Mention, error) {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	mentions, err := mentionDAL.WithContext(ctx).Where(mentionDAL.PostID.Eq(postID), mentionDAL.Status.Eq(status)).Order(mentionDAL.CreateTime).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return mentions, nil
+}
+
+func (m *mentionServiceImpl) UpdateStatus(ctx context.Context, mentionID int32, status consts.CommentStatus) (*entity.Mention, error) {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	updateResult, err := mentionDAL.WithContext(ctx).Where(mentionDAL.ID.Eq(mentionID)).UpdateSimple(mentionDAL.Status.Value(status))
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	if updateResult.RowsAffected != 1 {
+		return nil, xerr.NoRecord.New("mention not exist id=%d", mentionID).WithStatus(xerr.StatusNotFound).WithMsg("Mention not found")
+	}
+	mention, err := mentionDAL.WithContext(ctx).Where(mentionDAL.ID.Eq(mentionID)).First()
+	return mention, WrapDBErr(err)
+}
+
+func (m *mentionServiceImpl) UpdateStatusBatch(ctx context.Context, mentionIDs []int32, status consts.CommentStatus) ([]*entity.Mention, error) {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	_, err := mentionDAL.WithContext(ctx).Where(mentionDAL.ID.In(mentionIDs...)).UpdateSimple(mentionDAL.Status.Value(status))
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	mentions, err := mentionDAL.WithContext(ctx).Where(mentionDAL.ID.In(mentionIDs...)).Find()
+	return mentions, WrapDBErr(err)
+}
+
+func (m *mentionServiceImpl) Delete(ctx context.Context, mentionID int32) error {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	deleteResult, err := mentionDAL.WithContext(ctx).Where(mentionDAL.ID.Eq(mentionID)).Delete()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if deleteResult.RowsAffected != 1 {
+		return xerr.NoRecord.New("mention not exist id=%d", mentionID).WithStatus(xerr.StatusNotFound).WithMsg("Mention not found")
+	}
+	return nil
+}
+
+func (m *mentionServiceImpl) DeleteBatch(ctx context.Context, mentionIDs []int32) error {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	_, err := mentionDAL.WithContext(ctx).Where(mentionDAL.ID.In(mentionIDs...)).Delete()
+	return WrapDBErr(err)
+}
+
+func (m *mentionServiceImpl) ConvertToDTO(mention *entity.Mention) *dto.Mention {
+	mentionDTO := &dto.Mention{
+		ID:          mention.ID,
+		PostID:      mention.PostID,
+		Source:      mention.Source,
+		Target:      mention.Target,
+		Protocol:    mention.Protocol,
+		Type:        mention.Type,
+		Status:      mention.Status,
+		Author:      mention.Author,
+		AuthorURL:   mention.AuthorURL,
+		AuthorPhoto: mention.AuthorPhoto,
+		Title:       mention.Title,
+		Content:     mention.Content,
+		IPAddress:   mention.IPAddress,
+		CreateTime:  mention.CreateTime.UnixMilli(),
+	}
+	if mention.PublishTime != nil {
+		mentionDTO.PublishTime = mention.PublishTime.UnixMilli()
+	}
+	return mentionDTO
+}
+
+func (m *mentionServiceImpl) ConvertToDTOs(mentions []*entity.Mention) []*dto.Mention {
+	mentionDTOs := make([]*dto.Mention, 0, len(mentions))
+	for _, mention := range mentions {
+		mentionDTOs = append(mentionDTOs, m.ConvertToDTO(mention))
+	}
+	return mentionDTOs
+}
+
+func (m *mentionServiceImpl) isEnabled(ctx context.Context, protocol consts.MentionProtocol) bool {
+	if protocol == consts.MentionProtocolPingback {
+		return m.OptionService.GetOrByDefault(ctx, property.PingbackEnabled).(bool)
+	}
+	return m.OptionService.GetOrByDefault(ctx, property.WebmentionEnabled).(bool)
+}
+
+// findTargetPost finds the published post or sheet whose url is the target
+func (m *mentionServiceImpl) findTargetPost(ctx context.Context, targetURL *url.URL) (*entity.Post, error) {
+	notFound := xerr.NoRecord.New("mention target not exist target=%s", targetURL.String()).WithStatus(xerr.StatusNotFound).WithMsg("The target does not exist")
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u, err := url.Parse(blogURL); err != nil || !strings.EqualFold(u.Host, targetURL.Host) {
+		return nil, xerr.BadParam.New("mention target not on this site target=%s", targetURL.String()).WithStatus(xerr.StatusBadRequest).WithMsg("The target is not on this site")
+	}
+	pathSuffix, err := m.OptionService.GetPathSuffix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(strings.Trim(targetURL.Path, "/"), "/")
+	lastSegment := strings.TrimSuffix(segments[len(segments)-1], pathSuffix)
+
+	var post *entity.Post
+	if p := targetURL.Query().Get("p"); p != "" {
+		if postID, err := strconv.ParseInt(p, 10, 32); err == nil {
+			post, _ = m.PostService.GetByPostID(ctx, int32(postID))
+		}
+	}
+	if post == nil && lastSegment != "" {
+		post, _ = m.PostService.GetBySlug(ctx, lastSegment)
+	}
+	if post == nil {
+		if postID, err := strconv.ParseInt(lastSegment, 10, 32); err == nil {
+			post, _ = m.PostService.GetByPostID(ctx, int32(postID))
+		}
+	}
+	if post == nil || post.Status != consts.PostStatusPublished {
+		return nil, notFound
+	}
+	fullPath, err := m.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	postURL, err := url.Parse(absoluteURL(blogURL, fullPath))
+	if err != nil || normalizeMentionURL(postURL) != normalizeMentionURL(targetURL) {
+		return nil, notFound
+	}
+	return post, nil
+}
+
+func (m *mentionServiceImpl) deleteBySource(ctx context.Context, source, target string) error {
+	mentionDAL := dal.GetQueryByCtx(ctx).Mention
+	_, err := mentionDAL.WithContext(ctx).Where(mentionDAL.Source.Eq(source), mentionDAL.Target.Eq(target)).Delete()
+	return WrapDBErr(err)
+}
+
+func (m *mentionServiceImpl) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Failed to fetch " + rawURL)
+	}
+	return resp, nil
+}
+
+func (m *mentionServiceImpl) do(req *http.Request) error {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return xerr.NoType.New("request %s failed status=%d", req.URL.String(), resp.StatusCode).WithStatus(xerr.StatusInternalServerError)
+	}
+	return nil
+}
+
+func parseMentionURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, xerr.BadParam.New("invalid url %s", rawURL).WithStatus(xerr.StatusBadRequest).WithMsg("Invalid url " + rawURL)
+	}
+	return u, nil
+}
+
+// normalizeMentionURL ignores the scheme, the fragment and the trailing slash when comparing urls
+func normalizeMentionURL(u *url.URL) string {
+	normalized := strings.ToLower(u.Host) + strings.TrimSuffix(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		normalized += "?" + u.RawQuery
+	}
+	return normalized
+}
+
+func containsMentionURL(urls []string, normalizedURL string) bool {
+	for _, rawURL := range urls {
+		if u, err := url.Parse(rawURL); err == nil && normalizeMentionURL(u) == normalizedURL {
+			return true
+		}
+	}
+	return false
+}
+
+// linkHeader returns the first url of the rel in the Link headers, like <https://example.com/webmention>; rel="webmention"
+func linkHeader(values []string, base *url.URL, rel string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			ref := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, part := range parts[1:] {
+				key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+				if !ok || !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(r, rel) {
+						if u, err := base.Parse(ref); err == nil {
+							return u.String()
+						}
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
@@ -3,6 +3,7 @@ package impl
 import (
 	"context"
 
+	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/dal"
 	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/entity"
@@ -24,7 +25,7 @@ func (m *menuServiceImpl) DeleteBatch(ctx context.Context, ids []int32) error {
 	if err != nil {
 		return WrapDBErr(err)
 	}
-	return nil
+	return deleteTranslations(ctx, consts.TranslationTypeMenu, ids...)
 }
 
 func (m *menuServiceImpl) UpdateBatch(ctx context.Context, menuParams []*param.Menu) ([]*entity.Menu, error) {
@@ -191,7 +192,7 @@ func (m *menuServiceImpl) Delete(ctx context.Context, id int32) error {
 	if deleteResult.RowsAffected != 1 {
 		return xerr.DB.New("delete menu failed id=%d", id).WithStatus(xerr.StatusInternalServerError)
 	}
-	return nil
+	return deleteTranslations(ctx, consts.TranslationTypeMenu, id)
 }
 
 func (m *menuServiceImpl) ConvertToDTO(ctx context.Context, menu *entity.Menu) *dto.Menu {
@@ -0,0 +1,542 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"strings"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type metaWeblogServiceImpl struct {
+	OptionService       service.OptionService
+	UserService         service.UserService
+	LoginProtect        service.LoginProtectService
+	TwoFactorTOTPMFA    service.TwoFactorTOTPMFAService
+	PostService         service.PostService
+	PostCategoryService service.PostCategoryService
+	PostTagService      service.PostTagService
+	CategoryService     service.CategoryService
+	TagService          service.TagService
+	MetaService         service.MetaService
+	AttachmentService   service.AttachmentService
+	Event               event.Bus
+}
+
+func NewMetaWeblogService(
+	optionService service.OptionService,
+	userService service.UserService,
+	loginProtect service.LoginProtectService,
+	twoFactorMFA service.TwoFactorTOTPMFAService,
+	postService service.PostService,
+	postCategoryService service.PostCategoryService,
+	postTagService service.PostTagService,
+	categoryService service.CategoryService,
+	tagService service.TagService,
+	metaService service.MetaService,
+	attachmentService service.AttachmentService,
+	event event.Bus,
+) service.MetaWeblogService {
+	return &metaWeblogServiceImpl{
+		OptionService:       optionService,
+		UserService:         userService,
+		LoginProtect:        loginProtect,
+		TwoFactorTOTPMFA:    twoFactorMFA,
+		PostService:         postService,
+		PostCategoryService: postCategoryService,
+		PostTagService:      postTagService,
+		CategoryService:     categoryService,
+		TagService:          tagService,
+		MetaService:         metaService,
+		AttachmentService:   attachmentService,
+		Event:               event,
+	}
+}
+
+func (m *metaWeblogServiceImpl) Authenticate(ctx context.Context, username, password string) (*entity.User, error) {
+	missMatchTip := "用户名或密码不正确"
+	loginParam := param.LoginParam{Username: username, Password: password}
+
+	if err := m.LoginProtect.CheckAllowed(ctx, loginParam); err != nil {
+		return nil, err
+	}
+
+	var user *entity.User
+	err := util.Validate.Var(username, "email")
+	if err != nil {
+		user, err = m.UserService.GetByUsername(ctx, username)
+	} else {
+		user, err = m.UserService.GetByEmail(ctx, username)
+	}
+	if xerr.GetType(err) == xerr.NoRecord {
+		m.loginFailed(ctx, loginParam)
+		return nil, xerr.WithMsg(err, missMatchTip).WithStatus(xerr.StatusForbidden)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = m.UserService.MustNotExpire(ctx, user.ExpireTime); err != nil {
+		return nil, err
+	}
+
+	appPassword := m.OptionService.GetOrByDefault(ctx, property.MetaWeblogAppPassword).(string)
+	matched := appPassword != "" && m.UserService.PasswordMatch(ctx, appPassword, password)
+	if !matched && !m.TwoFactorTOTPMFA.UseMFA(user.MfaType) {
+		// the code of the two-factor authentication can not be sent by the editors, the app password is required then
+		matched = m.UserService.PasswordMatch(ctx, user.Password, password)
+	}
+	if !matched {
+		m.loginFailed(ctx, loginParam)
+		return nil, xerr.Forbidden.New("").WithMsg(missMatchTip).WithStatus(xerr.StatusForbidden)
+	}
+	m.LoginProtect.RecordSuccess(ctx, username)
+	return user, nil
+}
+
+func (m *metaWeblogServiceImpl) loginFailed(ctx context.Context, loginParam param.LoginParam) {
+	m.LoginProtect.RecordFailure(ctx, loginParam)
+	m.Event.Publish(ctx, &event.LogEvent{
+		LogKey:    loginParam.Username,
+		LogType:   consts.LogTypeLoginFailed,
+		Content:   loginParam.Username,
+		IPAddress: util.GetClientIP(ctx),
+	})
+}
+
+func (m *metaWeblogServiceImpl) GetBlog(ctx context.Context) (*dto.MetaWeblogBlog, error) {
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MetaWeblogBlog{
+		Name: m.OptionService.GetOrByDefault(ctx, property.BlogTitle).(string),
+		URL:  blogURL,
+	}, nil
+}
+
+func (m *metaWeblogServiceImpl) NewPost(ctx context.Context, postParam *param.MetaWeblogPost) (int32, error) {
+	post, err := m.convertParam(ctx, nil, postParam)
+	if err != nil {
+		return 0, err
+	}
+	created, err := m.PostService.Create(ctx, post)
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (m *metaWeblogServiceImpl) EditPost(ctx context.Context, postID int32, postParam *param.MetaWeblogPost) error {
+	post, err := m.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	postToUpdate, err := m.convertParam(ctx, post, postParam)
+	if err != nil {
+		return err
+	}
+	_, err = m.PostService.Update(ctx, post.ID, postToUpdate)
+	return err
+}
+
+// convertParam converts the post sent by the editors, the fields the editors know nothing about are kept from the post being edited
+func (m *metaWeblogServiceImpl) convertParam(ctx context.Context, post *entity.Post, postParam *param.MetaWeblogPost) (*param.Post, error) {
+	title := strings.TrimSpace(postParam.Title)
+	if title == "" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("title is required")
+	}
+	result := &param.Post{
+		Title:           title,
+		Slug:            postParam.Slug,
+		OriginalContent: postParam.Content,
+		Summary:         postParam.Excerpt,
+		Status:          consts.PostStatusDraft,
+	}
+	if postParam.Published {
+		result.Status = consts.PostStatusPublished
+	}
+	if postParam.CreateTime != nil && !postParam.CreateTime.IsZero() {
+		result.CreateTime = util.Int64Ptr(postParam.CreateTime.UnixMilli())
+	}
+
+	editorType := consts.EditorTypeRichText
+	if post != nil {
+		editorType = post.EditorType
+		result.Thumbnail = post.Thumbnail
+		result.Password = post.Password
+		result.Template = post.Template
+		result.TopPriority = post.TopPriority
+		result.MetaKeywords = post.MetaKeywords
+		result.MetaDescription = post.MetaDescription
+		result.DisallowComment = post.DisallowComment
+		if result.Slug == "" {
+			result.Slug = post.Slug
+		}
+		if result.CreateTime == nil {
+			result.CreateTime = util.Int64Ptr(post.CreateTime.UnixMilli())
+		}
+		if post.Status == consts.PostStatusIntimate && result.Status == consts.PostStatusPublished {
+			result.Status = consts.PostStatusIntimate
+		}
+		metas, err := m.MetaService.GetPostMeta(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, meta := range metas {
+			result.MetaParam = append(result.MetaParam, param.Meta{Key: meta.MetaKey, Value: meta.MetaValue})
+		}
+	}
+	if postParam.TextFilter != nil {
+		if *postParam.TextFilter == consts.MetaWeblogTextFilterMarkdown {
+			editorType = consts.EditorTypeMarkdown
+		} else {
+			editorType = consts.EditorTypeRichText
+		}
+	}
+	result.EditorType = editorType.Ptr()
+	if editorType == consts.EditorTypeMarkdown {
+		var buf bytes.Buffer
+		if err := importMarkdown.Convert([]byte(postParam.Content), &buf); err != nil {
+			return nil, xerr.BadParam.Wrapf(err, "convert markdown err").WithStatus(xerr.StatusBadRequest)
+		}
+		result.Content = buf.String()
+	} else {
+		result.Content = postParam.Content
+	}
+	if postParam.DisallowComment != nil {
+		result.DisallowComment = *postParam.DisallowComment
+	}
+
+	var err error
+	switch {
+	case postParam.CategoryIDs != nil:
+		result.CategoryIDs = postParam.CategoryIDs
+	case postParam.Categories != nil:
+		result.CategoryIDs, err = m.resolveCategories(ctx, postParam.Categories)
+	case post != nil:
+		result.CategoryIDs, err = m.listCategoryIDs(ctx, post.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case postParam.Tags != nil:
+		result.TagIDs, err = resolveTagIDs(ctx, m.TagService, postParam.Tags)
+	case post != nil:
+		result.TagIDs, err = m.listTagIDs(ctx, post.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m *metaWeblogServiceImpl) GetPost(ctx context.Context, postID int32) (*dto.MetaWeblogPost, error) {
+	post, err := m.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := m.PostCategoryService.ListCategoryByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := m.PostTagService.ListTagByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	return m.convertPost(ctx, blogURL, post, categories, tags)
+}
+
+func (m *metaWeblogServiceImpl) GetRecentPosts(ctx context.Context, size int) ([]*dto.MetaWeblogPost, error) {
+	if size <= 0 || size > consts.MetaWeblogRecentPostsLimit {
+		size = consts.MetaWeblogRecentPostsLimit
+	}
+	posts, _, err := m.PostService.Page(ctx, param.PostQuery{
+		Page: param.Page{
+			PageNum:  0,
+			PageSize: size,
+		},
+		Sort:     &param.Sort{Fields: []string{"createTime,desc"}},
+		Statuses: []*consts.PostStatus{consts.PostStatusDraft.Ptr(), consts.PostStatusIntimate.Ptr(), consts.PostStatusPublished.Ptr()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	postIDs := make([]int32, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	categoryMap, err := m.PostCategoryService.ListCategoryMapByPostID(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	tagMap, err := m.PostTagService.ListTagMapByPostID(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.MetaWeblogPost, 0, len(posts))
+	for _, post := range posts {
+		metaWeblogPost, err := m.convertPost(ctx, blogURL, post, categoryMap[post.ID], tagMap[post.ID])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, metaWeblogPost)
+	}
+	return result, nil
+}
+
+func (m *metaWeblogServiceImpl) convertPost(ctx context.Context, blogURL string, post *entity.Post, categories []*entity.Category, tags []*entity.Tag) (*dto.MetaWeblogPost, error) {
+	fullPath, err := m.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.MetaWeblogPost{
+		PostID:          post.ID,
+		Title:           post.Title,
+		Content:         post.OriginalContent,
+		Excerpt:         post.Summary,
+		Slug:            post.Slug,
+		Link:            absoluteURL(blogURL, fullPath),
+		Categories:      make([]string, 0, len(categories)),
+		Tags:            make([]string, 0, len(tags)),
+		CreateTime:      post.CreateTime,
+		Published:       post.Status == consts.PostStatusPublished || post.Status == consts.PostStatusIntimate,
+		DisallowComment: post.DisallowComment,
+	}
+	if post.EditorType == consts.EditorTypeMarkdown {
+		result.TextFilter = consts.MetaWeblogTextFilterMarkdown
+	}
+	for _, category := range categories {
+		result.Categories = append(result.Categories, category.Name)
+	}
+	for _, tag := range tags {
+		result.Tags = append(result.Tags, tag.Name)
+	}
+	return result, nil
+}
+
+func (m *metaWeblogServiceImpl) DeletePost(ctx context.Context, postID int32) error {
+	post, err := m.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	// the post is moved to the recycle bin instead of being deleted, as the admin does at first
+	_, err = m.PostService.UpdateStatus(ctx, post.ID, consts.PostStatusRecycle)
+	return err
+}
+
+// getPost gets the post which is not recycled, the sheets can not be edited by the editors
+func (m *metaWeblogServiceImpl) getPost(ctx context.Context, postID int32) (*entity.Post, error) {
+	post, err := m.PostService.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Type != consts.PostTypePost || post.Status == consts.PostStatusRecycle {
+		return nil, xerr.NoRecord.New("post not exist id=%d", postID).WithStatus(xerr.StatusNotFound).WithMsg("文章不存在")
+	}
+	return post, nil
+}
+
+func (m *metaWeblogServiceImpl) NewMediaObject(ctx context.Context, name string, content []byte) (*dto.AttachmentDTO, error) {
+	// the editors send the name with the directories, e.g. "Open Live Writer/image.png"
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" || name == "." || name == "/" {
+		return nil, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("name is required")
+	}
+	fileHeader, err := newFileHeader(name, content)
+	if err != nil {
+		return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid media")
+	}
+	attachment, err := m.AttachmentService.Upload(ctx, fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attachment.Path = absoluteURL(blogURL, attachment.Path)
+	return attachment, nil
+}
+
+func (m *metaWeblogServiceImpl) GetCategories(ctx context.Context) ([]*dto.MetaWeblogCategory, error) {
+	categories, err := m.CategoryService.ListAll(ctx, &param.Sort{Fields: []string{"priority,asc"}})
+	if err != nil {
+		return nil, err
+	}
+	categoryDTOs, err := m.CategoryService.ConvertToCategoryDTOs(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.MetaWeblogCategory, 0, len(categoryDTOs))
+	for _, category := range categoryDTOs {
+		result = append(result, &dto.MetaWeblogCategory{
+			ID:          category.ID,
+			Name:        category.Name,
+			Slug:        category.Slug,
+			Description: category.Description,
+			ParentID:    category.ParentID,
+			URL:         absoluteURL(blogURL, category.FullPath),
+			RSSURL:      absoluteURL(blogURL, "/feed/categories/"+category.Slug),
+		})
+	}
+	return result, nil
+}
+
+func (m *metaWeblogServiceImpl) NewCategory(ctx context.Context, name, slug, description string, parentID int32) (int32, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("name is required")
+	}
+	if slug == "" {
+		slug = util.Slug(name)
+	}
+	category, err := m.CategoryService.Create(ctx, &param.Category{
+		Name:        name,
+		Slug:        slug,
+		Description: description,
+		ParentID:    parentID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return category.ID, nil
+}
+
+func (m *metaWeblogServiceImpl) GetTags(ctx context.Context) ([]*dto.MetaWeblogTag, error) {
+	tags, err := m.PostTagService.ListAllTagWithPostCount(ctx, &param.Sort{Fields: []string{"name,asc"}})
+	if err != nil {
+		return nil, err
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.MetaWeblogTag, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, &dto.MetaWeblogTag{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			Slug:      tag.Slug,
+			URL:       absoluteURL(blogURL, tag.FullPath),
+			PostCount: tag.PostCount,
+		})
+	}
+	return result, nil
+}
+
+func (m *metaWeblogServiceImpl) SetPostCategories(ctx context.Context, postID int32, categoryIDs []int32) error {
+	if len(categoryIDs) > 0 {
+		categories, err := m.CategoryService.ListByIDs(ctx, categoryIDs)
+		if err != nil {
+			return err
+		}
+		if len(categories) != len(categoryIDs) {
+			return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("category not exist")
+		}
+	} else {
+		// the empty categories clear the categories of the post instead of keeping them
+		categoryIDs = []int32{}
+	}
+	return m.editPostFields(ctx, postID, func(postParam *param.MetaWeblogPost) {
+		postParam.CategoryIDs = categoryIDs
+	})
+}
+
+// editPostFields edits the post with the fields changed by fn, the others are kept
+func (m *metaWeblogServiceImpl) editPostFields(ctx context.Context, postID int32, fn func(postParam *param.MetaWeblogPost)) error {
+	current, err := m.GetPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	postParam := &param.MetaWeblogPost{
+		Title:      current.Title,
+		Content:    current.Content,
+		Excerpt:    current.Excerpt,
+		Slug:       current.Slug,
+		CreateTime: &current.CreateTime,
+		Published:  current.Published,
+	}
+	fn(postParam)
+	return m.EditPost(ctx, postID, postParam)
+}
+
+func (m *metaWeblogServiceImpl) GenerateAppPassword(ctx context.Context) (*dto.MetaWeblogAppPassword, error) {
+	password := util.GenUUIDWithOutDash()
+	err := m.OptionService.Save(ctx, map[string]string{
+		property.MetaWeblogAppPassword.KeyValue: m.UserService.EncryptPassword(ctx, password),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MetaWeblogAppPassword{Password: password}, nil
+}
+
+func (m *metaWeblogServiceImpl) RevokeAppPassword(ctx context.Context) error {
+	return m.OptionService.Save(ctx, map[string]string{property.MetaWeblogAppPassword.KeyValue: ""})
+}
+
+// resolveCategories gets the categories by the names, the missing ones are created
+func (m *metaWeblogServiceImpl) resolveCategories(ctx context.Context, names []string) ([]int32, error) {
+	categoryIDs := make([]int32, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		category, err := m.CategoryService.GetByName(ctx, name)
+		if xerr.GetType(err) == xerr.NoRecord {
+			category, err = m.CategoryService.Create(ctx, &param.Category{Name: name, Slug: util.Slug(name)})
+		}
+		if err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	return categoryIDs, nil
+}
+
+func (m *metaWeblogServiceImpl) listCategoryIDs(ctx context.Context, postID int32) ([]int32, error) {
+	categories, err := m.PostCategoryService.ListCategoryByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs := make([]int32, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	return categoryIDs, nil
+}
+
+func (m *metaWeblogServiceImpl) listTagIDs(ctx context.Context, postID int32) ([]int32, error) {
+	tags, err := m.PostTagService.ListTagByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs := make([]int32, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return tagIDs, nil
+}
@@ -0,0 +1,114 @@
+package impl
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/metrics"
+	"github.com/go-sonic/sonic/service"
+)
+
+const businessMetricsTimeout = 5 * time.Second
+
+var (
+	postsDesc = prometheus.NewDesc("sonic_posts", "Number of posts by status.",
+		[]string{"status"}, nil)
+	commentsAuditingDesc = prometheus.NewDesc("sonic_comments_auditing", "Number of comments awaiting audit by type.",
+		[]string{"type"}, nil)
+	attachmentsDesc = prometheus.NewDesc("sonic_attachments", "Number of attachments.",
+		nil, nil)
+	attachmentBytesDesc = prometheus.NewDesc("sonic_attachment_storage_bytes", "Total size of all attachments in bytes.",
+		nil, nil)
+)
+
+type metricsServiceImpl struct {
+	logger                *zap.Logger
+	PostService           service.PostService
+	PostCommentService    service.PostCommentService
+	SheetCommentService   service.SheetCommentService
+	JournalCommentService service.JournalCommentService
+}
+
+func NewMetricsService(logger *zap.Logger,
+	postService service.PostService,
+	postCommentService service.PostCommentService,
+	sheetCommentService service.SheetCommentService,
+	journalCommentService service.JournalCommentService,
+) service.MetricsService {
+	m := &metricsServiceImpl{
+		logger:                logger,
+		PostService:           postService,
+		PostCommentService:    postCommentService,
+		SheetCommentService:   sheetCommentService,
+		JournalCommentService: journalCommentService,
+	}
+	metrics.Registry.MustRegister(m)
+	return m
+}
+
+func (m *metricsServiceImpl) Handler() http.Handler {
+	return metrics.Handler()
+}
+
+func (m *metricsServiceImpl) Describe(ch chan<- *prometheus.Desc) {
+	ch <- postsDesc
+	ch <- commentsAuditingDesc
+	ch <- attachmentsDesc
+	ch <- attachmentBytesDesc
+}
+
+// Collect queries the business figures on every scrape, a failed query only drops its own metric
+func (m *metricsServiceImpl) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), businessMetricsTimeout)
+	defer cancel()
+
+	postStatuses := map[string]consts.PostStatus{
+		"published": consts.PostStatusPublished,
+		"draft":     consts.PostStatusDraft,
+		"recycle":   consts.PostStatusRecycle,
+		"intimate":  consts.PostStatusIntimate,
+	}
+	for label, status := range postStatuses {
+		count, err := m.PostService.CountByStatus(ctx, status)
+		if err != nil {
+			m.logger.Error("collect post metrics err", zap.Error(err))
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(postsDesc, prometheus.GaugeValue, float64(count), label)
+	}
+
+	commentCounters := map[string]func(context.Context, consts.CommentStatus) (int64, error){
+		"post":    m.PostCommentService.CountByStatus,
+		"sheet":   m.SheetCommentService.CountByStatus,
+		"journal": m.JournalCommentService.CountByStatus,
+	}
+	for label, countByStatus := range commentCounters {
+		count, err := countByStatus(ctx, consts.CommentStatusAuditing)
+		if err != nil {
+			m.logger.Error("collect comment metrics err", zap.Error(err))
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(commentsAuditingDesc, prometheus.GaugeValue, float64(count), label)
+	}
+
+	var attachmentStat struct {
+		Count int64
+		// SUM of an empty table is NULL
+		Size sql.NullInt64
+	}
+	attachmentDAL := dal.GetQueryByCtx(ctx).Attachment
+	err := attachmentDAL.WithContext(ctx).Select(attachmentDAL.ID.Count().As("count"), attachmentDAL.Size.Sum().As("size")).Scan(&attachmentStat)
+	if err != nil {
+		m.logger.Error("collect attachment metrics err", zap.Error(err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(attachmentsDesc, prometheus.GaugeValue, float64(attachmentStat.Count))
+	ch <- prometheus.MustNewConstMetric(attachmentBytesDesc, prometheus.GaugeValue, float64(attachmentStat.Size.Int64))
+}
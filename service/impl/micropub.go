@@ -0,0 +1,476 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// the fragments of the urls of the journals and the photos, they are shown in the list pages only
+const (
+	micropubJournalFragment = "journal-"
+	micropubPhotoFragment   = "photo-"
+	// micropubTextLimit is the max length of the name and the description of the photos
+	micropubTextLimit = 255
+)
+
+type micropubServiceImpl struct {
+	OptionService     service.OptionService
+	PostService       service.PostService
+	PostTagService    service.PostTagService
+	TagService        service.TagService
+	JournalService    service.JournalService
+	PhotoService      service.PhotoService
+	AttachmentService service.AttachmentService
+	Cache             cache.Cache
+	client            *http.Client
+}
+
+func NewMicropubService(
+	optionService service.OptionService,
+	postService service.PostService,
+	postTagService service.PostTagService,
+	tagService service.TagService,
+	journalService service.JournalService,
+	photoService service.PhotoService,
+	attachmentService service.AttachmentService,
+	cache cache.Cache,
+) service.MicropubService {
+	return &micropubServiceImpl{
+		OptionService:     optionService,
+		PostService:       postService,
+		PostTagService:    postTagService,
+		TagService:        tagService,
+		JournalService:    journalService,
+		PhotoService:      photoService,
+		AttachmentService: attachmentService,
+		Cache:             cache,
+		client:            &http.Client{Timeout: consts.MicropubTimeout, Transport: tracing.NewTransport(nil)},
+	}
+}
+
+func (m *micropubServiceImpl) VerifyToken(ctx context.Context, token string) (*dto.MicropubToken, error) {
+	if token == "" {
+		return nil, xerr.WithStatus(nil, xerr.StatusUnauthorized).WithMsg("access token is required")
+	}
+	if value, ok := m.Cache.Get(cache.BuildMicropubTokenKey(token)); ok {
+		return value.(*dto.MicropubToken), nil
+	}
+	tokenEndpoint := m.OptionService.GetOrByDefault(ctx, property.MicropubTokenEndpoint).(string)
+	if tokenEndpoint == "" {
+		return nil, xerr.Forbidden.New("token endpoint not set").WithStatus(xerr.StatusForbidden).WithMsg("token endpoint is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenEndpoint, nil)
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError).WithMsg("invalid token endpoint")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError).WithMsg("verify token failed")
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, consts.MentionFetchLimit))
+	if err != nil {
+		return nil, xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError).WithMsg("verify token failed")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerr.Forbidden.New("token endpoint status=%d", resp.StatusCode).WithStatus(xerr.StatusForbidden).WithMsg("invalid access token")
+	}
+
+	var result struct {
+		Me       string `json:"me"`
+		ClientID string `json:"client_id"`
+		Scope    string `json:"scope"`
+	}
+	// the legacy token endpoints respond form encoded even if json is accepted
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, xerr.Forbidden.Wrap(err).WithStatus(xerr.StatusForbidden).WithMsg("invalid access token")
+		}
+		result.Me, result.ClientID, result.Scope = values.Get("me"), values.Get("client_id"), values.Get("scope")
+	} else if err = json.Unmarshal(body, &result); err != nil {
+		return nil, xerr.Forbidden.Wrap(err).WithStatus(xerr.StatusForbidden).WithMsg("invalid access token")
+	}
+
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !sameProfileURL(result.Me, blogURL) {
+		return nil, xerr.Forbidden.New("token me=%s", result.Me).WithStatus(xerr.StatusForbidden).WithMsg("the access token is not issued for this blog")
+	}
+	verified := &dto.MicropubToken{
+		Me:       result.Me,
+		ClientID: result.ClientID,
+		Scopes:   strings.Fields(result.Scope),
+	}
+	m.Cache.Set(cache.BuildMicropubTokenKey(token), verified, consts.MicropubTokenValidDuration)
+	return verified, nil
+}
+
+// sameProfileURL compares the profile urls ignoring the scheme and the trailing slash, as IndieAuth normalizes them
+func sameProfileURL(me, blogURL string) bool {
+	meURL, err := url.Parse(me)
+	if err != nil || meURL.Host == "" {
+		return false
+	}
+	u, err := url.Parse(blogURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(meURL.Host, u.Host) && strings.TrimSuffix(meURL.Path, "/") == strings.TrimSuffix(u.Path, "/")
+}
+
+func (m *micropubServiceImpl) Create(ctx context.Context, entry *param.MicropubEntry) (string, error) {
+	switch {
+	case strings.TrimSpace(entry.Name) != "":
+		return m.createPost(ctx, entry)
+	case len(entry.Photos) > 0:
+		return m.createPhotos(ctx, entry)
+	default:
+		return m.createJournal(ctx, entry)
+	}
+}
+
+// createPost creates the article, the content is Markdown unless it is HTML
+func (m *micropubServiceImpl) createPost(ctx context.Context, entry *param.MicropubEntry) (string, error) {
+	content := entry.Content
+	for _, photo := range entry.Photos {
+		if entry.ContentHTML {
+			content += `<p><img src="` + html.EscapeString(photo.URL) + `" alt="` + html.EscapeString(photo.Alt) + `"></p>`
+		} else {
+			content += "\n\n![" + photo.Alt + "](" + photo.URL + ")"
+		}
+	}
+	postParam := &param.Post{
+		Title:           strings.TrimSpace(entry.Name),
+		Slug:            entry.Slug,
+		OriginalContent: content,
+		Summary:         entry.Summary,
+		Status:          consts.PostStatusPublished,
+	}
+	if entry.Draft {
+		postParam.Status = consts.PostStatusDraft
+	}
+	if entry.Published != nil {
+		postParam.CreateTime = util.Int64Ptr(entry.Published.UnixMilli())
+	}
+	if entry.ContentHTML {
+		postParam.EditorType = consts.EditorTypeRichText.Ptr()
+		postParam.Content = content
+	} else {
+		renderedContent, err := renderMicropubMarkdown(content)
+		if err != nil {
+			return "", err
+		}
+		postParam.EditorType = consts.EditorTypeMarkdown.Ptr()
+		postParam.Content = renderedContent
+	}
+	tagIDs, err := resolveTagIDs(ctx, m.TagService, entry.Categories)
+	if err != nil {
+		return "", err
+	}
+	postParam.TagIDs = tagIDs
+
+	post, err := m.PostService.Create(ctx, postParam)
+	if err != nil {
+		return "", err
+	}
+	return m.postURL(ctx, post)
+}
+
+// createJournal creates the note, the private note is an intimate journal
+func (m *micropubServiceImpl) createJournal(ctx context.Context, entry *param.MicropubEntry) (string, error) {
+	if strings.TrimSpace(entry.Content) == "" {
+		return "", xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("content is required")
+	}
+	journalParam := &param.Journal{
+		SourceContent: entry.Content,
+		Content:       entry.Content,
+		Type:          consts.JournalTypePublic,
+	}
+	if !entry.ContentHTML {
+		renderedContent, err := renderMicropubMarkdown(entry.Content)
+		if err != nil {
+			return "", err
+		}
+		journalParam.Content = renderedContent
+	}
+	if entry.Private {
+		journalParam.Type = consts.JournalTypeIntimate
+	}
+	journal, err := m.JournalService.Create(ctx, journalParam)
+	if err != nil {
+		return "", err
+	}
+	return m.fragmentURL(ctx, m.OptionService.GetJournalPrefix, micropubJournalFragment, journal.ID)
+}
+
+// createPhotos creates a photo for each of the photos, the first category is the team of the photos
+func (m *micropubServiceImpl) createPhotos(ctx context.Context, entry *param.MicropubEntry) (string, error) {
+	description := entry.Content
+	if entry.ContentHTML {
+		description = util.CleanHTMLTag(description)
+	}
+	var team string
+	if len(entry.Categories) > 0 {
+		team = entry.Categories[0]
+	}
+	var firstPhotoID int32
+	for i, photo := range entry.Photos {
+		name := photo.Alt
+		if name == "" {
+			name = entry.Summary
+		}
+		if name == "" {
+			name = firstLine(description)
+		}
+		if name == "" {
+			name = lastPathSegment(photo.URL)
+		}
+		photoParam := &param.Photo{
+			Name:        truncateRunes(name, micropubTextLimit),
+			Thumbnail:   photo.URL,
+			URL:         photo.URL,
+			Team:        truncateRunes(team, micropubTextLimit),
+			Location:    truncateRunes(entry.Location, micropubTextLimit),
+			Description: truncateRunes(description, micropubTextLimit),
+		}
+		if entry.Published != nil {
+			photoParam.TakeTime = util.Int64Ptr(entry.Published.UnixMilli())
+		}
+		created, err := m.PhotoService.Create(ctx, photoParam)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			firstPhotoID = created.ID
+		}
+	}
+	return m.fragmentURL(ctx, m.OptionService.GetPhotoPrefix, micropubPhotoFragment, firstPhotoID)
+}
+
+func (m *micropubServiceImpl) postURL(ctx context.Context, post *entity.Post) (string, error) {
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	fullPath, err := m.PostService.BuildFullPath(ctx, post)
+	if err != nil {
+		return "", err
+	}
+	return absoluteURL(blogURL, fullPath), nil
+}
+
+func (m *micropubServiceImpl) fragmentURL(ctx context.Context, getPrefix func(ctx context.Context) (string, error), fragment string, id int32) (string, error) {
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	prefix, err := getPrefix(ctx)
+	if err != nil {
+		return "", err
+	}
+	return absoluteURL(blogURL, prefix) + "#" + fragment + strconv.Itoa(int(id)), nil
+}
+
+func (m *micropubServiceImpl) Source(ctx context.Context, sourceURL string) (*dto.MicropubEntry, error) {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid url")
+	}
+	switch {
+	case strings.HasPrefix(u.Fragment, micropubJournalFragment):
+		return m.journalSource(ctx, sourceURL, strings.TrimPrefix(u.Fragment, micropubJournalFragment))
+	case strings.HasPrefix(u.Fragment, micropubPhotoFragment):
+		return m.photoSource(ctx, sourceURL, strings.TrimPrefix(u.Fragment, micropubPhotoFragment))
+	default:
+		return m.postSource(ctx, u)
+	}
+}
+
+// postSource finds the post by the id or the slug in the url, the url is checked against the full path of the post
+func (m *micropubServiceImpl) postSource(ctx context.Context, u *url.URL) (*dto.MicropubEntry, error) {
+	candidates := make([]*entity.Post, 0, 2)
+	lastSegment := strings.TrimSuffix(lastPathSegment(u.Path), ".html")
+	for _, id := range []string{u.Query().Get("p"), lastSegment} {
+		if postID, err := strconv.ParseInt(id, 10, 32); err == nil {
+			if post, err := m.PostService.GetByPostID(ctx, int32(postID)); err == nil {
+				candidates = append(candidates, post)
+			}
+		}
+	}
+	if post, err := m.PostService.GetBySlug(ctx, lastSegment); err == nil {
+		candidates = append(candidates, post)
+	}
+	for _, post := range candidates {
+		if post.Type != consts.PostTypePost || post.Status == consts.PostStatusRecycle {
+			continue
+		}
+		postURL, err := m.postURL(ctx, post)
+		if err != nil {
+			return nil, err
+		}
+		if parsed, err := url.Parse(postURL); err != nil || strings.TrimSuffix(parsed.RequestURI(), "/") != strings.TrimSuffix(u.RequestURI(), "/") {
+			continue
+		}
+		tags, err := m.PostTagService.ListTagByPostID(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		entry := &dto.MicropubEntry{
+			URL:         postURL,
+			Name:        post.Title,
+			Content:     post.OriginalContent,
+			ContentHTML: post.FormatContent,
+			Summary:     post.Summary,
+			Categories:  make([]string, 0, len(tags)),
+			Published:   post.CreateTime,
+			Slug:        post.Slug,
+			Draft:       post.Status == consts.PostStatusDraft,
+		}
+		for _, tag := range tags {
+			entry.Categories = append(entry.Categories, tag.Name)
+		}
+		return entry, nil
+	}
+	return nil, xerr.NoRecord.New("post not exist url=%s", u.String()).WithStatus(xerr.StatusNotFound).WithMsg("post not exist")
+}
+
+func (m *micropubServiceImpl) journalSource(ctx context.Context, sourceURL, id string) (*dto.MicropubEntry, error) {
+	journalID, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid url")
+	}
+	journals, err := m.JournalService.GetByJournalIDs(ctx, []int32{int32(journalID)})
+	if err != nil {
+		return nil, err
+	}
+	journal, ok := journals[int32(journalID)]
+	if !ok {
+		return nil, xerr.NoRecord.New("journal not exist id=%d", journalID).WithStatus(xerr.StatusNotFound).WithMsg("journal not exist")
+	}
+	return &dto.MicropubEntry{
+		URL:         sourceURL,
+		Content:     journal.SourceContent,
+		ContentHTML: journal.Content,
+		Published:   journal.CreateTime,
+	}, nil
+}
+
+func (m *micropubServiceImpl) photoSource(ctx context.Context, sourceURL, id string) (*dto.MicropubEntry, error) {
+	photoID, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return nil, xerr.BadParam.Wrap(err).WithStatus(xerr.StatusBadRequest).WithMsg("invalid url")
+	}
+	photo, err := m.PhotoService.GetByID(ctx, int32(photoID))
+	if err != nil {
+		return nil, err
+	}
+	entry := &dto.MicropubEntry{
+		URL:       sourceURL,
+		Summary:   photo.Name,
+		Content:   photo.Description,
+		Photos:    []string{photo.URL},
+		Published: photo.CreateTime,
+		Location:  photo.Location,
+	}
+	if photo.TakeTime != nil {
+		entry.Published = *photo.TakeTime
+	}
+	if photo.Team != "" {
+		entry.Categories = []string{photo.Team}
+	}
+	return entry, nil
+}
+
+func (m *micropubServiceImpl) Config(ctx context.Context) (*dto.MicropubConfig, error) {
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MicropubConfig{
+		MediaEndpoint: blogURL + consts.MicropubMediaPath,
+		SyndicateTo:   []any{},
+		PostTypes: []*dto.MicropubPostType{
+			{Type: "note", Name: "Journal"},
+			{Type: "article", Name: "Post"},
+			{Type: "photo", Name: "Photo"},
+		},
+		Q: []string{"config", "source", "syndicate-to", "category"},
+	}, nil
+}
+
+func (m *micropubServiceImpl) ListCategories(ctx context.Context) ([]string, error) {
+	tags, err := m.TagService.ListAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names, nil
+}
+
+func (m *micropubServiceImpl) UploadMedia(ctx context.Context, fileHeader *multipart.FileHeader) (string, error) {
+	attachment, err := m.AttachmentService.Upload(ctx, fileHeader)
+	if err != nil {
+		return "", err
+	}
+	blogURL, err := m.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	return absoluteURL(blogURL, attachment.Path), nil
+}
+
+func renderMicropubMarkdown(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := importMarkdown.Convert([]byte(content), &buf); err != nil {
+		return "", xerr.BadParam.Wrapf(err, "convert markdown err").WithStatus(xerr.StatusBadRequest)
+	}
+	return buf.String(), nil
+}
+
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+// lastPathSegment returns the last segment of the url or the path
+func lastPathSegment(p string) string {
+	p = strings.TrimSuffix(p, "/")
+	if i := strings.LastIndexByte(p, '/'); i >= 0 {
+		p = p[i+1:]
+	}
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	return p
+}
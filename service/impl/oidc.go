@@ -0,0 +1,469 @@
+package impl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+type oidcDiscovery struct {
+	Issuer                 string   `json:"issuer"`
+	AuthorizationEndpoint  string   `json:"authorization_endpoint"`
+	TokenEndpoint          string   `json:"token_endpoint"`
+	JwksURI                string   `json:"jwks_uri"`
+	TokenEndpointAuthMeths []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+type oidcJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type oidcState struct {
+	Provider string
+	Nonce    string
+}
+
+type oidcServiceImpl struct {
+	Config      *config.Config
+	Cache       cache.Cache
+	UserService service.UserService
+	client      *http.Client
+
+	mu          sync.RWMutex
+	discoveries map[string]*oidcDiscovery
+	keys        map[string]map[string]interface{}
+}
+
+func NewOIDCService(config *config.Config, cache cache.Cache, userService service.UserService) service.OIDCService {
+	return &oidcServiceImpl{
+		Config:      config,
+		Cache:       cache,
+		UserService: userService,
+		client:      &http.Client{Timeout: time.Second * 10, Transport: tracing.NewTransport(nil)},
+		discoveries: make(map[string]*oidcDiscovery),
+		keys:        make(map[string]map[string]interface{}),
+	}
+}
+
+func (o *oidcServiceImpl) ListProviders(ctx context.Context) []*dto.OIDCProvider {
+	providers := make([]*dto.OIDCProvider, 0, len(o.Config.OIDC.Providers))
+	for _, provider := range o.Config.OIDC.Providers {
+		providers = append(providers, &dto.OIDCProvider{
+			Name:        provider.Name,
+			DisplayName: util.IfElse(provider.DisplayName == "", provider.Name, provider.DisplayName).(string),
+		})
+	}
+	return providers
+}
+
+func (o *oidcServiceImpl) Authorize(ctx context.Context, providerName string) (*dto.OIDCAuthorization, error) {
+	provider, err := o.getProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	discovery, err := o.discover(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	state := util.GenUUIDWithOutDash()
+	nonce := util.GenUUIDWithOutDash()
+	o.Cache.Set(cache.BuildOIDCStateKey(state), &oidcState{Provider: provider.Name, Nonce: nonce}, consts.OIDCStateValidDuration)
+
+	scopes := provider.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.ClientID)
+	query.Set("redirect_uri", provider.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+
+	authorizeURL := discovery.AuthorizationEndpoint
+	if strings.Contains(authorizeURL, "?") {
+		authorizeURL += "&" + query.Encode()
+	} else {
+		authorizeURL += "?" + query.Encode()
+	}
+	return &dto.OIDCAuthorization{
+		AuthorizeURL: authorizeURL,
+		State:        state,
+	}, nil
+}
+
+func (o *oidcServiceImpl) Authenticate(ctx context.Context, providerName, code, state string) (*entity.User, error) {
+	provider, err := o.getProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	stateKey := cache.BuildOIDCStateKey(state)
+	value, ok := o.Cache.Get(stateKey)
+	if !ok {
+		return nil, xerr.BadParam.New("oidc state not exist").WithStatus(xerr.StatusBadRequest).WithMsg("登录状态已失效，请重新登录")
+	}
+	o.Cache.Delete(stateKey)
+	loginState := value.(*oidcState)
+	if loginState.Provider != provider.Name {
+		return nil, xerr.BadParam.New("oidc state provider mismatch").WithStatus(xerr.StatusBadRequest).WithMsg("登录状态不匹配")
+	}
+
+	discovery, err := o.discover(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := o.exchangeCode(ctx, provider, discovery, code)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := o.verifyIDToken(ctx, provider, discovery, idToken, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err = o.checkClaims(provider, claims); err != nil {
+		return nil, err
+	}
+	return o.mapUser(ctx, provider, claims)
+}
+
+func (o *oidcServiceImpl) SkipMFA(providerName string) bool {
+	provider, err := o.getProvider(providerName)
+	return err == nil && provider.SkipMFA
+}
+
+func (o *oidcServiceImpl) getProvider(name string) (*config.OIDCProvider, error) {
+	for i := range o.Config.OIDC.Providers {
+		if o.Config.OIDC.Providers[i].Name == name {
+			return &o.Config.OIDC.Providers[i], nil
+		}
+	}
+	return nil, xerr.NoRecord.New("oidc provider not exist name=%s", name).WithStatus(xerr.StatusNotFound).WithMsg("登录方式不存在")
+}
+
+func (o *oidcServiceImpl) discover(ctx context.Context, provider *config.OIDCProvider) (*oidcDiscovery, error) {
+	o.mu.RLock()
+	discovery, ok := o.discoveries[provider.Name]
+	o.mu.RUnlock()
+	if ok {
+		return discovery, nil
+	}
+
+	discovery = &oidcDiscovery{}
+	issuer := strings.TrimSuffix(provider.Issuer, "/")
+	if err := o.getJSON(ctx, issuer+oidcDiscoveryPath, discovery); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, xerr.NoType.New("oidc issuer mismatch expected=%s actual=%s", issuer, discovery.Issuer).
+			WithStatus(xerr.StatusInternalServerError).WithMsg("身份提供方配置错误")
+	}
+	o.mu.Lock()
+	o.discoveries[provider.Name] = discovery
+	o.mu.Unlock()
+	return discovery, nil
+}
+
+func (o *oidcServiceImpl) exchangeCode(ctx context.Context, provider *config.OIDCProvider, discovery *oidcDiscovery, code string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.RedirectURL)
+
+	// client_secret_basic is the default authentication method of the token endpoint
+	useBasic := len(discovery.TokenEndpointAuthMeths) == 0 || slices.Contains(discovery.TokenEndpointAuthMeths, "client_secret_basic")
+	if !useBasic {
+		form.Set("client_id", provider.ClientID)
+		form.Set("client_secret", provider.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if useBasic {
+		req.SetBasicAuth(url.QueryEscape(provider.ClientID), url.QueryEscape(provider.ClientSecret))
+	}
+
+	tokenResp := struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	if err = o.doJSON(req, &tokenResp); err != nil && tokenResp.Error == "" {
+		return "", err
+	}
+	if tokenResp.Error != "" {
+		return "", xerr.BadParam.New("oidc token error=%s description=%s", tokenResp.Error, tokenResp.ErrorDescription).
+			WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	if tokenResp.IDToken == "" {
+		return "", xerr.BadParam.New("oidc token response without id_token").WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	return tokenResp.IDToken, nil
+}
+
+func (o *oidcServiceImpl) verifyIDToken(ctx context.Context, provider *config.OIDCProvider, discovery *oidcDiscovery, idToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return o.getKey(ctx, provider, discovery, kid)
+	})
+	if err != nil {
+		return nil, xerr.BadParam.Wrapf(err, "verify id token").WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, xerr.BadParam.New("id token issuer mismatch").WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	if !claims.VerifyAudience(provider.ClientID, true) {
+		return nil, xerr.BadParam.New("id token audience mismatch").WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, xerr.BadParam.New("id token nonce mismatch").WithStatus(xerr.StatusBadRequest).WithMsg("身份验证失败")
+	}
+	return claims, nil
+}
+
+func (o *oidcServiceImpl) getKey(ctx context.Context, provider *config.OIDCProvider, discovery *oidcDiscovery, kid string) (interface{}, error) {
+	lookup := func() (interface{}, bool) {
+		o.mu.RLock()
+		defer o.mu.RUnlock()
+		keys := o.keys[provider.Name]
+		if key, ok := keys[kid]; ok {
+			return key, true
+		}
+		// a key set with a single key does not need a kid
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, true
+			}
+		}
+		return nil, false
+	}
+	if key, ok := lookup(); ok {
+		return key, nil
+	}
+
+	// the provider may have rotated its keys, so fetch the key set again
+	jwks := struct {
+		Keys []oidcJWK `json:"keys"`
+	}{}
+	if err := o.getJSON(ctx, discovery.JwksURI, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	o.mu.Lock()
+	o.keys[provider.Name] = keys
+	o.mu.Unlock()
+
+	if key, ok := lookup(); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("signing key not found kid=%s", kid)
+}
+
+func (o *oidcServiceImpl) checkClaims(provider *config.OIDCProvider, claims jwt.MapClaims) error {
+	for name, expected := range provider.AllowedClaims {
+		value, ok := claims[name]
+		if !ok || !claimContains(value, expected) {
+			return xerr.Forbidden.New("claim %s not allowed", name).WithStatus(xerr.StatusForbidden).WithMsg("该账号不允许登录")
+		}
+	}
+	if len(provider.AllowedDomains) == 0 {
+		return nil
+	}
+	email, _ := claims["email"].(string)
+	if err := checkEmailVerified(claims); err != nil {
+		return err
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return xerr.Forbidden.New("email claim missing").WithStatus(xerr.StatusForbidden).WithMsg("该账号不允许登录")
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range provider.AllowedDomains {
+		if strings.ToLower(allowed) == domain {
+			return nil
+		}
+	}
+	return xerr.Forbidden.New("email domain not allowed domain=%s", domain).WithStatus(xerr.StatusForbidden).WithMsg("该账号不允许登录")
+}
+
+func (o *oidcServiceImpl) mapUser(ctx context.Context, provider *config.OIDCProvider, claims jwt.MapClaims) (*entity.User, error) {
+	var user *entity.User
+	var err error
+	identity, _ := claims["sub"].(string)
+	username := ""
+	for _, subject := range provider.Subjects {
+		if identity != "" && subject.Subject == identity {
+			username = subject.Username
+			break
+		}
+	}
+	if username != "" {
+		user, err = o.UserService.GetByUsername(ctx, username)
+	} else {
+		// the users are linked by email, an unverified email could be set to the email of any user
+		if err = checkEmailVerified(claims); err != nil {
+			return nil, err
+		}
+		identity, _ = claims["email"].(string)
+		if util.Validate.Var(identity, "required,email") != nil {
+			return nil, xerr.Forbidden.New("email claim missing").WithStatus(xerr.StatusForbidden).WithMsg("该账号不允许登录")
+		}
+		user, err = o.UserService.GetByEmail(ctx, identity)
+	}
+	if xerr.GetType(err) == xerr.NoRecord {
+		return nil, xerr.Forbidden.Wrapf(err, "no user for identity=%s", identity).WithStatus(xerr.StatusForbidden).WithMsg("没有与该账号关联的用户")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = o.UserService.MustNotExpire(ctx, user.ExpireTime); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (o *oidcServiceImpl) getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError)
+	}
+	req.Header.Set("Accept", "application/json")
+	return o.doJSON(req, v)
+}
+
+func (o *oidcServiceImpl) doJSON(req *http.Request, v interface{}) error {
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return xerr.NoType.Wrapf(err, "request %s", req.URL.String()).WithStatus(xerr.StatusInternalServerError).WithMsg("无法连接身份提供方")
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return xerr.NoType.Wrap(err).WithStatus(xerr.StatusInternalServerError).WithMsg("无法连接身份提供方")
+	}
+	// decode error responses too, so that the caller can read the error fields
+	decodeErr := json.Unmarshal(body, v)
+	if resp.StatusCode != http.StatusOK {
+		return xerr.NoType.New("request %s status=%d", req.URL.String(), resp.StatusCode).WithStatus(xerr.StatusInternalServerError).WithMsg("身份提供方返回错误")
+	}
+	if decodeErr != nil {
+		return xerr.NoType.Wrapf(decodeErr, "decode %s", req.URL.String()).WithStatus(xerr.StatusInternalServerError).WithMsg("身份提供方返回错误")
+	}
+	return nil
+}
+
+func (j *oidcJWK) publicKey() (interface{}, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+	switch j.Kty {
+	case "RSA":
+		n, err := decode(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", j.Crv)
+		}
+		x, err := decode(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", j.Kty)
+}
+
+// checkEmailVerified requires the email_verified claim to be true, a missing claim is not verified
+func checkEmailVerified(claims jwt.MapClaims) error {
+	if verified, _ := claims["email_verified"].(bool); !verified {
+		return xerr.Forbidden.New("email not verified").WithStatus(xerr.StatusForbidden).WithMsg("邮箱未验证")
+	}
+	return nil
+}
+
+func claimContains(value interface{}, expected string) bool {
+	if values, ok := value.([]interface{}); ok {
+		for _, v := range values {
+			if fmt.Sprint(v) == expected {
+				return true
+			}
+		}
+		return false
+	}
+	return fmt.Sprint(value) == expected
+}
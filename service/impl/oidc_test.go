@@ -0,0 +1,273 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+// mockIdP is a local OpenID provider, the authorization code it accepts is the nonce of the login
+type mockIdP struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	issuer string
+	// claims customizes the claims of the id token
+	claims func(claims jwt.MapClaims)
+	// signKey signs the id token instead of the published key when it is not nil
+	signKey       *rsa.PrivateKey
+	tokenRequests int
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]interface{}{
+			"issuer":                 idp.issuer,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		idp.tokenRequests++
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "sonic" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeTestJSON(w, map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != "https://blog.example.com/callback" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":            idp.issuer,
+			"sub":            "1",
+			"aud":            "sonic",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          r.PostFormValue("code"),
+			"email":          "admin@example.com",
+			"email_verified": true,
+		}
+		if idp.claims != nil {
+			idp.claims(claims)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test"
+		signKey := idp.key
+		if idp.signKey != nil {
+			signKey = idp.signKey
+		}
+		idToken, err := token.SignedString(signKey)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeTestJSON(w, map[string]string{"access_token": "access", "token_type": "Bearer", "id_token": idToken})
+	})
+	idp.Server = httptest.NewServer(mux)
+	idp.issuer = idp.URL
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type stubUserService struct {
+	service.UserService
+	users []*entity.User
+}
+
+func (s *stubUserService) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, user := range s.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, xerr.NoRecord.New("user not exist")
+}
+
+func (s *stubUserService) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, xerr.NoRecord.New("user not exist")
+}
+
+func (s *stubUserService) MustNotExpire(ctx context.Context, expireTime *time.Time) error {
+	return nil
+}
+
+func newTestOIDCService(idp *mockIdP, provider config.OIDCProvider) service.OIDCService {
+	provider.Name = "mock"
+	provider.Issuer = idp.URL
+	provider.ClientID = "sonic"
+	provider.ClientSecret = "secret"
+	provider.RedirectURL = "https://blog.example.com/callback"
+	conf := &config.Config{OIDC: config.OIDC{Providers: []config.OIDCProvider{provider}}}
+	userService := &stubUserService{users: []*entity.User{
+		{ID: 1, Username: "admin", Email: "admin@example.com"},
+	}}
+	return NewOIDCService(conf, cache.NewCache(), userService)
+}
+
+// startOIDCLogin starts the authorization and returns the state and the code the mock provider accepts
+func startOIDCLogin(t *testing.T, oidcService service.OIDCService) (state, code string) {
+	authorization, err := oidcService.Authorize(context.Background(), "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizeURL, err := url.Parse(authorization.AuthorizeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := authorizeURL.Query()
+	if query.Get("client_id") != "sonic" || query.Get("response_type") != "code" || query.Get("state") != authorization.State {
+		t.Fatalf("unexpected authorize url %s", authorization.AuthorizeURL)
+	}
+	return authorization.State, query.Get("nonce")
+}
+
+func TestOIDCAuthenticate(t *testing.T) {
+	idp := newMockIdP(t)
+	oidcService := newTestOIDCService(idp, config.OIDCProvider{AllowedDomains: []string{"example.com"}})
+
+	state, code := startOIDCLogin(t, oidcService)
+	user, err := oidcService.Authenticate(context.Background(), "mock", code, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 1 {
+		t.Fatalf("expected user 1, got %d", user.ID)
+	}
+
+	// the state can be used only once
+	if _, err = oidcService.Authenticate(context.Background(), "mock", code, state); err == nil {
+		t.Fatal("expected the used state to be rejected")
+	}
+}
+
+func TestOIDCAuthenticateBySubject(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = func(claims jwt.MapClaims) {
+		claims["email"] = "other@example.com"
+		delete(claims, "email_verified")
+	}
+	oidcService := newTestOIDCService(idp, config.OIDCProvider{Subjects: []config.OIDCSubject{{Subject: "1", Username: "admin"}}})
+
+	state, code := startOIDCLogin(t, oidcService)
+	user, err := oidcService.Authenticate(context.Background(), "mock", code, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "admin" {
+		t.Fatalf("expected user admin, got %s", user.Username)
+	}
+}
+
+func TestOIDCDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.issuer = "https://other.example.com"
+	oidcService := newTestOIDCService(idp, config.OIDCProvider{})
+
+	if _, err := oidcService.Authorize(context.Background(), "mock"); err == nil {
+		t.Fatal("expected the discovery with another issuer to be rejected")
+	}
+}
+
+func TestOIDCRejectState(t *testing.T) {
+	idp := newMockIdP(t)
+	oidcService := newTestOIDCService(idp, config.OIDCProvider{})
+
+	_, code := startOIDCLogin(t, oidcService)
+	if _, err := oidcService.Authenticate(context.Background(), "mock", code, "unknown"); err == nil {
+		t.Fatal("expected the unknown state to be rejected")
+	}
+	if idp.tokenRequests != 0 {
+		t.Fatal("expected no token exchange without a valid state")
+	}
+}
+
+func TestOIDCRejectIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		provider config.OIDCProvider
+		claims   func(claims jwt.MapClaims)
+		signKey  *rsa.PrivateKey
+		code     func(code string) string
+	}{
+		{name: "nonce", code: func(code string) string { return "other" }},
+		{name: "audience", claims: func(claims jwt.MapClaims) { claims["aud"] = "other" }},
+		{name: "issuer", claims: func(claims jwt.MapClaims) { claims["iss"] = "https://other.example.com" }},
+		{name: "expired", claims: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "signature", signKey: otherKey},
+		{name: "unverified email", claims: func(claims jwt.MapClaims) { claims["email_verified"] = false }},
+		{name: "missing email verification", claims: func(claims jwt.MapClaims) { delete(claims, "email_verified") }},
+		{
+			name:     "unknown subject",
+			provider: config.OIDCProvider{Subjects: []config.OIDCSubject{{Subject: "2", Username: "admin"}}},
+			claims:   func(claims jwt.MapClaims) { delete(claims, "email_verified") },
+		},
+		{name: "domain", provider: config.OIDCProvider{AllowedDomains: []string{"example.org"}}},
+		{name: "claim", provider: config.OIDCProvider{AllowedClaims: map[string]string{"groups": "admin"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idp := newMockIdP(t)
+			idp.claims = test.claims
+			idp.signKey = test.signKey
+			oidcService := newTestOIDCService(idp, test.provider)
+
+			state, code := startOIDCLogin(t, oidcService)
+			if test.code != nil {
+				code = test.code(code)
+			}
+			if _, err := oidcService.Authenticate(context.Background(), "mock", code, state); err == nil {
+				t.Fatal("expected the id token to be rejected")
+			}
+		})
+	}
+}
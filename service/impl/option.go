@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -436,6 +438,38 @@ func (o *optionServiceImpl) GetSheetPrefix(ctx context.Context) (string, error)
 	return value.(string), nil
 }
 
+func (o *optionServiceImpl) GetSeriesPrefix(ctx context.Context) (string, error) {
+	p := property.SeriesPrefix
+	value, err := o.getFromCacheMissFromDB(ctx, p)
+	if xerr.GetType(err) == xerr.NoRecord {
+		o.Cache.SetDefault(p.KeyValue, p.DefaultValue)
+		return p.DefaultValue.(string), nil
+	} else if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+func (o *optionServiceImpl) GetBlogLanguages(ctx context.Context) ([]string, error) {
+	locale, err := o.GetOrByDefaultWithErr(ctx, property.BlogLocale, property.BlogLocale.DefaultValue)
+	if err != nil {
+		return nil, err
+	}
+	others, err := o.GetOrByDefaultWithErr(ctx, property.BlogLanguages, property.BlogLanguages.DefaultValue)
+	if err != nil {
+		return nil, err
+	}
+	languages := []string{locale.(string)}
+	for _, language := range strings.Split(others.(string), ",") {
+		language = strings.TrimSpace(language)
+		if language == "" || slices.Contains(languages, language) {
+			continue
+		}
+		languages = append(languages, language)
+	}
+	return languages, nil
+}
+
 func (o *optionServiceImpl) GetAttachmentType(ctx context.Context) consts.AttachmentType {
 	p := property.AttachmentType
 	value, err := o.getFromCacheMissFromDB(ctx, p)
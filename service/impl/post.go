@@ -25,24 +25,33 @@ import (
 
 type postServiceImpl struct {
 	service.BasePostService
-	CategoryService service.CategoryService
-	OptionService   service.OptionService
-	Event           event.Bus
-	Cache           cache.Cache
+	CategoryService    service.CategoryService
+	OptionService      service.OptionService
+	SeriesService      service.SeriesService
+	CustomFieldService service.CustomFieldService
+	TranslationService service.TranslationService
+	Event              event.Bus
+	Cache              cache.Cache
 }
 
 func NewPostService(basePostService service.BasePostService,
 	categoryService service.CategoryService,
 	optionService service.OptionService,
+	seriesService service.SeriesService,
+	customFieldService service.CustomFieldService,
+	translationService service.TranslationService,
 	event event.Bus,
 	cache cache.Cache,
 ) service.PostService {
 	return &postServiceImpl{
-		BasePostService: basePostService,
-		CategoryService: categoryService,
-		OptionService:   optionService,
-		Event:           event,
-		Cache:           cache,
+		BasePostService:    basePostService,
+		CategoryService:    categoryService,
+		OptionService:      optionService,
+		SeriesService:      seriesService,
+		CustomFieldService: customFieldService,
+		TranslationService: translationService,
+		Event:              event,
+		Cache:              cache,
 	}
 }
 
@@ -75,6 +84,27 @@ func (p postServiceImpl) Page(ctx context.Context, postQuery param.PostQuery) ([
 	if postQuery.CategoryID != nil {
 		postDo.Join(&entity.PostCategory{}, postDAL.ID.EqCol(postCategoryDAL.PostID)).Where(postCategoryDAL.CategoryID.Eq(*postQuery.CategoryID))
 	}
+	if len(postQuery.Metas) > 0 {
+		postIDs, err := p.CustomFieldService.ListPostIDsByFilters(ctx, consts.PostTypePost, postQuery.Metas)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(postIDs) == 0 {
+			return []*entity.Post{}, 0, nil
+		}
+		postDo = postDo.Where(postDAL.ID.In(postIDs...))
+	}
+	language := requestLanguage(ctx)
+	if postQuery.Language != nil {
+		language = *postQuery.Language
+	}
+	filter, err := newLanguageFilter(ctx, p.OptionService, consts.TranslationTypePost, language)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter != nil {
+		postDo = postDo.Where(filter.condition(postDAL.ID))
+	}
 
 	posts, totalCount, err := postDo.FindByPage(postQuery.PageNum*postQuery.PageSize, postQuery.PageSize)
 	if err != nil {
@@ -112,7 +142,18 @@ func (p postServiceImpl) Create(ctx context.Context, postParam *param.Post) (*en
 	if err != nil {
 		return nil, err
 	}
+	if postParam.SeriesID != nil {
+		if err := p.SeriesService.AssignPost(ctx, post.ID, *postParam.SeriesID); err != nil {
+			return nil, err
+		}
+	}
+	if err := linkPostTranslation(ctx, p.TranslationService, post.ID, postParam.Language, postParam.TranslationOf); err != nil {
+		return nil, err
+	}
 	// Todo delete authorization
+	p.Event.Publish(ctx, &event.PostUpdateEvent{
+		PostID: post.ID,
+	})
 	p.Event.Publish(ctx, &event.LogEvent{
 		LogKey:    strconv.Itoa(int(post.ID)),
 		LogType:   consts.LogTypePostPublished,
@@ -140,6 +181,14 @@ func (p postServiceImpl) Update(ctx context.Context, postID int32, postParam *pa
 	if err != nil {
 		return nil, err
 	}
+	if postParam.SeriesID != nil {
+		if err := p.SeriesService.AssignPost(ctx, post.ID, *postParam.SeriesID); err != nil {
+			return nil, err
+		}
+	}
+	if err := linkPostTranslation(ctx, p.TranslationService, post.ID, postParam.Language, postParam.TranslationOf); err != nil {
+		return nil, err
+	}
 	// TODO should use transcation
 	p.Event.Publish(ctx, &event.PostUpdateEvent{
 		PostID: post.ID,
@@ -153,6 +202,46 @@ func (p postServiceImpl) Update(ctx context.Context, postID int32, postParam *pa
 	return post, nil
 }
 
+func (p postServiceImpl) UpdateStatus(ctx context.Context, postID int32, status consts.PostStatus) (*entity.Post, error) {
+	post, err := p.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus := post.Status
+	post, err = p.BasePostService.UpdateStatus(ctx, postID, status)
+	if err != nil {
+		return nil, err
+	}
+	p.publishStatusUpdate(ctx, post.ID, previousStatus, status)
+	return post, nil
+}
+
+func (p postServiceImpl) UpdateStatusBatch(ctx context.Context, status consts.PostStatus, postIDs []int32) ([]*entity.Post, error) {
+	previousPosts, err := p.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := p.BasePostService.UpdateStatusBatch(ctx, status, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		if previousPost, ok := previousPosts[post.ID]; ok {
+			p.publishStatusUpdate(ctx, post.ID, previousPost.Status, status)
+		}
+	}
+	return posts, nil
+}
+
+// publishStatusUpdate publishes the PostUpdateEvent when the post is published or withdrawn by changing its status
+func (p postServiceImpl) publishStatusUpdate(ctx context.Context, postID int32, previousStatus, status consts.PostStatus) {
+	if previousStatus != status && (previousStatus == consts.PostStatusPublished || status == consts.PostStatusPublished) {
+		p.Event.Publish(ctx, &event.PostUpdateEvent{
+			PostID: postID,
+		})
+	}
+}
+
 func (p postServiceImpl) ConvertParam(ctx context.Context, postParam *param.Post) (*entity.Post, error) {
 	post := &entity.Post{
 		Type:            consts.PostTypePost,
@@ -14,7 +14,8 @@ import (
 )
 
 type postTagServiceImpl struct {
-	TagService service.TagService
+	TagService    service.TagService
+	OptionService service.OptionService
 }
 
 func (p *postTagServiceImpl) PagePost(ctx context.Context, postQuery param.PostQuery) ([]*entity.Post, int64, error) {
@@ -43,6 +44,17 @@ func (p *postTagServiceImpl) PagePost(ctx context.Context, postQuery param.PostQ
 	if postQuery.TagID != nil {
 		postDo.Join(&entity.PostTag{}, postDAL.ID.EqCol(postTagDAL.PostID)).Where(postTagDAL.TagID.Eq(*postQuery.TagID))
 	}
+	language := requestLanguage(ctx)
+	if postQuery.Language != nil {
+		language = *postQuery.Language
+	}
+	filter, err := newLanguageFilter(ctx, p.OptionService, consts.TranslationTypePost, language)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter != nil {
+		postDo = postDo.Where(filter.condition(postDAL.ID))
+	}
 	posts, totalCount, err := postDo.FindByPage(postQuery.PageNum*postQuery.PageSize, postQuery.PageSize)
 	if err != nil {
 		return nil, 0, WrapDBErr(err)
@@ -50,9 +62,10 @@ func (p *postTagServiceImpl) PagePost(ctx context.Context, postQuery param.PostQ
 	return posts, totalCount, nil
 }
 
-func NewPostTagService(tagService service.TagService) service.PostTagService {
+func NewPostTagService(tagService service.TagService, optionService service.OptionService) service.PostTagService {
 	return &postTagServiceImpl{
-		TagService: tagService,
+		TagService:    tagService,
+		OptionService: optionService,
 	}
 }
 
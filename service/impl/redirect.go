@@ -0,0 +1,148 @@
+package impl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type redirectServiceImpl struct{}
+
+func NewRedirectService() service.RedirectService {
+	return &redirectServiceImpl{}
+}
+
+func (r *redirectServiceImpl) List(ctx context.Context, sort *param.Sort) ([]*entity.Redirect, error) {
+	redirectDAL := dal.GetQueryByCtx(ctx).Redirect
+	redirectDO := redirectDAL.WithContext(ctx)
+	err := BuildSort(sort, &redirectDAL, &redirectDO)
+	if err != nil {
+		return nil, err
+	}
+	redirects, err := redirectDO.Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return redirects, nil
+}
+
+func (r *redirectServiceImpl) Match(ctx context.Context, path string) (*entity.Redirect, error) {
+	redirectDAL := dal.GetQueryByCtx(ctx).Redirect
+	redirect, err := redirectDAL.WithContext(ctx).Where(redirectDAL.Source.Eq(normalizeRedirectSource(path))).Take()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return redirect, nil
+}
+
+func (r *redirectServiceImpl) Create(ctx context.Context, redirectParam *param.Redirect) (*entity.Redirect, error) {
+	redirect, err := r.convertParam(redirectParam)
+	if err != nil {
+		return nil, err
+	}
+	redirectDAL := dal.GetQueryByCtx(ctx).Redirect
+	count, err := redirectDAL.WithContext(ctx).Where(redirectDAL.Source.Eq(redirect.Source)).Count()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	if count > 0 {
+		return nil, xerr.BadParam.New("source=%s", redirect.Source).WithStatus(xerr.StatusBadRequest).WithMsg("The redirect of the source exists already")
+	}
+	err = redirectDAL.WithContext(ctx).Create(redirect)
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return redirect, nil
+}
+
+func (r *redirectServiceImpl) Update(ctx context.Context, id int32, redirectParam *param.Redirect) (*entity.Redirect, error) {
+	redirect, err := r.convertParam(redirectParam)
+	if err != nil {
+		return nil, err
+	}
+	redirectDAL := dal.GetQueryByCtx(ctx).Redirect
+	count, err := redirectDAL.WithContext(ctx).Where(redirectDAL.Source.Eq(redirect.Source), redirectDAL.ID.Neq(id)).Count()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	if count > 0 {
+		return nil, xerr.BadParam.New("source=%s", redirect.Source).WithStatus(xerr.StatusBadRequest).WithMsg("The redirect of the source exists already")
+	}
+	_, err = redirectDAL.WithContext(ctx).Where(redirectDAL.ID.Eq(id)).UpdateSimple(
+		redirectDAL.Source.Value(redirect.Source),
+		redirectDAL.Target.Value(redirect.Target),
+		redirectDAL.Permanent.Value(redirect.Permanent),
+	)
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	redirect, err = redirectDAL.WithContext(ctx).Where(redirectDAL.ID.Eq(id)).First()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return redirect, nil
+}
+
+func (r *redirectServiceImpl) Delete(ctx context.Context, id int32) error {
+	redirectDAL := dal.GetQueryByCtx(ctx).Redirect
+	deleteResult, err := redirectDAL.WithContext(ctx).Where(redirectDAL.ID.Eq(id)).Delete()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if deleteResult.RowsAffected != 1 {
+		return xerr.NoRecord.New("redirect not found id=%d", id).WithStatus(xerr.StatusNotFound).WithMsg("The redirect was not found")
+	}
+	return nil
+}
+
+func (r *redirectServiceImpl) ConvertToDTO(redirect *entity.Redirect) *dto.Redirect {
+	return &dto.Redirect{
+		ID:         redirect.ID,
+		Source:     redirect.Source,
+		Target:     redirect.Target,
+		Permanent:  redirect.Permanent,
+		CreateTime: redirect.CreateTime.UnixMilli(),
+	}
+}
+
+func (r *redirectServiceImpl) ConvertToDTOs(redirects []*entity.Redirect) []*dto.Redirect {
+	result := make([]*dto.Redirect, 0, len(redirects))
+	for _, redirect := range redirects {
+		result = append(result, r.ConvertToDTO(redirect))
+	}
+	return result
+}
+
+func (r *redirectServiceImpl) convertParam(redirectParam *param.Redirect) (*entity.Redirect, error) {
+	source := normalizeRedirectSource(redirectParam.Source)
+	target := strings.TrimSpace(redirectParam.Target)
+	targetURL, err := url.Parse(target)
+	if err != nil || (!strings.HasPrefix(target, "/") && targetURL.Scheme != "http" && targetURL.Scheme != "https") {
+		return nil, xerr.BadParam.New("target=%s", target).WithStatus(xerr.StatusBadRequest).WithMsg("The target must be a path or a http url")
+	}
+	if source == "/" || source == normalizeRedirectSource(target) {
+		return nil, xerr.BadParam.New("source=%s", source).WithStatus(xerr.StatusBadRequest).WithMsg("The source can not be redirected")
+	}
+	return &entity.Redirect{
+		Source:    source,
+		Target:    target,
+		Permanent: redirectParam.Permanent,
+	}, nil
+}
+
+// normalizeRedirectSource keeps the unescaped path of the source without the query and the trailing slash
+func normalizeRedirectSource(source string) string {
+	source = strings.TrimSpace(source)
+	if sourceURL, err := url.Parse(source); err == nil {
+		source = sourceURL.Path
+	}
+	source = "/" + strings.Trim(source, "/")
+	return source
+}
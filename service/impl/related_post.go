@@ -0,0 +1,341 @@
+package impl
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+)
+
+const (
+	relatedPostTagWeight      = 2.0
+	relatedPostCategoryWeight = 1.0
+	// relatedPostTextWeight is the score of two posts with the same terms, the cosine similarity of their terms is in [0, 1]
+	relatedPostTextWeight = 4.0
+	// relatedPostTitleWeight is how many times a term in the title counts as much as one in the content
+	relatedPostTitleWeight = 3
+	// relatedPostTermLimit is the number of the heaviest terms of a post compared with the other posts
+	relatedPostTermLimit = 64
+)
+
+type relatedPostServiceImpl struct {
+	Cache cache.Cache
+	// mutex keeps the related posts from being computed more than once at a time
+	mutex sync.Mutex
+	// generation is increased by Invalidate, the related posts computed from the posts before it are not cached
+	generation atomic.Uint64
+	// previous is the last computed related posts, they are served while the new ones are computed in the background
+	previous atomic.Pointer[map[int32][]int32]
+	// refreshTimer debounces computing the related posts in the background after the changes
+	timerMutex     sync.Mutex
+	refreshTimer   *time.Timer
+	refreshPending bool
+}
+
+func NewRelatedPostService(cache cache.Cache) service.RelatedPostService {
+	return &relatedPostServiceImpl{
+		Cache: cache,
+	}
+}
+
+func (r *relatedPostServiceImpl) ListRelatedPosts(ctx context.Context, postID int32, top int) ([]*entity.Post, error) {
+	if top > consts.RelatedPostsMaxCount {
+		top = consts.RelatedPostsMaxCount
+	}
+	if top <= 0 {
+		return []*entity.Post{}, nil
+	}
+	relatedMap, err := r.getRelatedMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	relatedIDs := relatedMap[postID]
+	if len(relatedIDs) == 0 {
+		return []*entity.Post{}, nil
+	}
+
+	// the posts may have been changed since the related posts were computed
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	posts, err := postDAL.WithContext(ctx).Where(postDAL.ID.In(relatedIDs...), postDAL.Status.Eq(consts.PostStatusPublished)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	postMap := make(map[int32]*entity.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	result := make([]*entity.Post, 0, top)
+	for _, relatedID := range relatedIDs {
+		if post, ok := postMap[relatedID]; ok && len(result) < top {
+			result = append(result, post)
+		}
+	}
+	return result, nil
+}
+
+func (r *relatedPostServiceImpl) Invalidate(ctx context.Context) {
+	r.generation.Add(1)
+	r.Cache.Delete(consts.RelatedPostsCacheKey)
+	r.scheduleRefresh(true)
+}
+
+// scheduleRefresh computes the related posts in the background after consts.RelatedPostsRefreshDelay,
+// a pending refresh is delayed again only by the changes, not by the reads
+func (r *relatedPostServiceImpl) scheduleRefresh(changed bool) {
+	r.timerMutex.Lock()
+	defer r.timerMutex.Unlock()
+	if r.refreshPending && !changed {
+		return
+	}
+	r.refreshPending = true
+	if r.refreshTimer == nil {
+		r.refreshTimer = time.AfterFunc(consts.RelatedPostsRefreshDelay, r.refresh)
+		return
+	}
+	r.refreshTimer.Reset(consts.RelatedPostsRefreshDelay)
+}
+
+func (r *relatedPostServiceImpl) refresh() {
+	r.timerMutex.Lock()
+	r.refreshPending = false
+	r.timerMutex.Unlock()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.Cache.Get(consts.RelatedPostsCacheKey); ok {
+		return
+	}
+	if _, err := r.compute(context.Background()); err != nil {
+		log.Errorf("compute related posts err=%+v", err)
+	}
+}
+
+func (r *relatedPostServiceImpl) getRelatedMap(ctx context.Context) (map[int32][]int32, error) {
+	if value, ok := r.Cache.Get(consts.RelatedPostsCacheKey); ok {
+		return value.(map[int32][]int32), nil
+	}
+	if previous := r.previous.Load(); previous != nil {
+		// stale or expired, the previous related posts are still served until the new ones are computed
+		r.scheduleRefresh(false)
+		return *previous, nil
+	}
+	// nothing has been computed yet
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// computed by another request or in the background while waiting for the lock
+	if previous := r.previous.Load(); previous != nil {
+		return *previous, nil
+	}
+	return r.compute(ctx)
+}
+
+// compute scores every pair of published posts sharing a tag, a category or a term and caches the ids of the
+// consts.RelatedPostsMaxCount posts with the highest scores for each post
+func (r *relatedPostServiceImpl) compute(ctx context.Context) (map[int32][]int32, error) {
+	generation := r.generation.Load()
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	posts, err := postDAL.WithContext(ctx).
+		Select(postDAL.ID, postDAL.Title, postDAL.FormatContent).
+		Where(postDAL.Type.Eq(consts.PostTypePost), postDAL.Status.Eq(consts.PostStatusPublished)).
+		Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	published := make(map[int32]struct{}, len(posts))
+	for _, post := range posts {
+		published[post.ID] = struct{}{}
+	}
+
+	postTagDAL := dal.GetQueryByCtx(ctx).PostTag
+	postTags, err := postTagDAL.WithContext(ctx).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	postTagIDs := make(map[int32][]int32)
+	tagPostIDs := make(map[int32][]int32)
+	for _, postTag := range postTags {
+		if _, ok := published[postTag.PostID]; ok {
+			postTagIDs[postTag.PostID] = append(postTagIDs[postTag.PostID], postTag.TagID)
+			tagPostIDs[postTag.TagID] = append(tagPostIDs[postTag.TagID], postTag.PostID)
+		}
+	}
+	postCategoryDAL := dal.GetQueryByCtx(ctx).PostCategory
+	postCategories, err := postCategoryDAL.WithContext(ctx).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	postCategoryIDs := make(map[int32][]int32)
+	categoryPostIDs := make(map[int32][]int32)
+	for _, postCategory := range postCategories {
+		if _, ok := published[postCategory.PostID]; ok {
+			postCategoryIDs[postCategory.PostID] = append(postCategoryIDs[postCategory.PostID], postCategory.CategoryID)
+			categoryPostIDs[postCategory.CategoryID] = append(categoryPostIDs[postCategory.CategoryID], postCategory.PostID)
+		}
+	}
+
+	termVectors := buildRelatedPostTermVectors(posts)
+	termPostWeights := make(map[string]map[int32]float64)
+	for postID, vector := range termVectors {
+		for term, weight := range vector {
+			if termPostWeights[term] == nil {
+				termPostWeights[term] = make(map[int32]float64)
+			}
+			termPostWeights[term][postID] = weight
+		}
+	}
+
+	relatedMap := make(map[int32][]int32, len(posts))
+	for _, post := range posts {
+		scores := make(map[int32]float64)
+		for _, tagID := range postTagIDs[post.ID] {
+			for _, otherID := range tagPostIDs[tagID] {
+				scores[otherID] += relatedPostTagWeight
+			}
+		}
+		for _, categoryID := range postCategoryIDs[post.ID] {
+			for _, otherID := range categoryPostIDs[categoryID] {
+				scores[otherID] += relatedPostCategoryWeight
+			}
+		}
+		for term, weight := range termVectors[post.ID] {
+			for otherID, otherWeight := range termPostWeights[term] {
+				scores[otherID] += relatedPostTextWeight * weight * otherWeight
+			}
+		}
+		delete(scores, post.ID)
+
+		relatedIDs := make([]int32, 0, len(scores))
+		for otherID := range scores {
+			relatedIDs = append(relatedIDs, otherID)
+		}
+		// the newer post comes first between the posts of the same score
+		sort.Slice(relatedIDs, func(i, j int) bool {
+			if scores[relatedIDs[i]] != scores[relatedIDs[j]] {
+				return scores[relatedIDs[i]] > scores[relatedIDs[j]]
+			}
+			return relatedIDs[i] > relatedIDs[j]
+		})
+		if len(relatedIDs) > consts.RelatedPostsMaxCount {
+			relatedIDs = relatedIDs[:consts.RelatedPostsMaxCount]
+		}
+		relatedMap[post.ID] = relatedIDs
+	}
+	r.previous.Store(&relatedMap)
+	if r.generation.Load() == generation {
+		r.Cache.Set(consts.RelatedPostsCacheKey, relatedMap, consts.RelatedPostsValidDuration)
+	}
+	return relatedMap, nil
+}
+
+// buildRelatedPostTermVectors weights the terms of the posts by tf-idf and keeps the heaviest ones normalized,
+// so that the dot product of two vectors is the cosine similarity of the posts
+func buildRelatedPostTermVectors(posts []*entity.Post) map[int32]map[string]float64 {
+	termCounts := make(map[int32]map[string]int, len(posts))
+	documentFrequencies := make(map[string]int)
+	for _, post := range posts {
+		counts := make(map[string]int)
+		for _, term := range splitRelatedPostTerms(post.Title) {
+			counts[term] += relatedPostTitleWeight
+		}
+		for _, term := range splitRelatedPostTerms(util.CleanHTMLTag(post.FormatContent)) {
+			counts[term]++
+		}
+		for term := range counts {
+			documentFrequencies[term]++
+		}
+		termCounts[post.ID] = counts
+	}
+
+	type termWeight struct {
+		term   string
+		weight float64
+	}
+	vectors := make(map[int32]map[string]float64, len(posts))
+	for postID, counts := range termCounts {
+		weights := make([]termWeight, 0, len(counts))
+		for term, count := range counts {
+			documentFrequency := documentFrequencies[term]
+			// a term of a single post relates it to none of the others and a term of every post to all of them
+			if documentFrequency < 2 || documentFrequency == len(posts) {
+				continue
+			}
+			idf := math.Log(float64(len(posts)) / float64(documentFrequency))
+			weights = append(weights, termWeight{term: term, weight: (1 + math.Log(float64(count))) * idf})
+		}
+		sort.Slice(weights, func(i, j int) bool {
+			if weights[i].weight != weights[j].weight {
+				return weights[i].weight > weights[j].weight
+			}
+			return weights[i].term < weights[j].term
+		})
+		if len(weights) > relatedPostTermLimit {
+			weights = weights[:relatedPostTermLimit]
+		}
+		norm := 0.0
+		for _, w := range weights {
+			norm += w.weight * w.weight
+		}
+		if norm == 0 {
+			continue
+		}
+		norm = math.Sqrt(norm)
+		vector := make(map[string]float64, len(weights))
+		for _, w := range weights {
+			vector[w.term] = w.weight / norm
+		}
+		vectors[postID] = vector
+	}
+	return vectors
+}
+
+// splitRelatedPostTerms splits the text into lower case words, the Han characters having no spaces between words
+// are split into overlapping pairs
+func splitRelatedPostTerms(text string) []string {
+	terms := make([]string, 0)
+	var word strings.Builder
+	han := make([]rune, 0)
+	flushWord := func() {
+		if utf8.RuneCountInString(word.String()) > 1 {
+			terms = append(terms, word.String())
+		}
+		word.Reset()
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			terms = append(terms, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			terms = append(terms, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+	for _, char := range text {
+		switch {
+		case unicode.Is(unicode.Han, char):
+			flushWord()
+			han = append(han, char)
+		case unicode.IsLetter(char) || unicode.IsDigit(char):
+			flushHan()
+			word.WriteRune(unicode.ToLower(char))
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return terms
+}
@@ -0,0 +1,380 @@
+package impl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type seriesServiceImpl struct {
+	OptionService service.OptionService
+}
+
+func NewSeriesService(optionService service.OptionService) service.SeriesService {
+	return &seriesServiceImpl{
+		OptionService: optionService,
+	}
+}
+
+func (s *seriesServiceImpl) ListAll(ctx context.Context) ([]*entity.Series, error) {
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	series, err := seriesDAL.WithContext(ctx).Order(seriesDAL.CreateTime.Desc(), seriesDAL.ID.Desc()).Find()
+	return series, WrapDBErr(err)
+}
+
+func (s *seriesServiceImpl) GetByID(ctx context.Context, id int32) (*entity.Series, error) {
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	series, err := seriesDAL.WithContext(ctx).Where(seriesDAL.ID.Eq(id)).First()
+	return series, WrapDBErr(err)
+}
+
+func (s *seriesServiceImpl) GetBySlug(ctx context.Context, slug string) (*entity.Series, error) {
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	series, err := seriesDAL.WithContext(ctx).Where(seriesDAL.Slug.Eq(slug)).First()
+	return series, WrapDBErr(err)
+}
+
+func (s *seriesServiceImpl) Create(ctx context.Context, seriesParam *param.Series) (*entity.Series, error) {
+	series := &entity.Series{
+		Name:        seriesParam.Name,
+		Slug:        seriesSlug(seriesParam),
+		Description: seriesParam.Description,
+		Thumbnail:   seriesParam.Thumbnail,
+	}
+	if err := s.checkSlug(ctx, 0, series.Slug); err != nil {
+		return nil, err
+	}
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	if err := seriesDAL.WithContext(ctx).Create(series); err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return series, nil
+}
+
+func (s *seriesServiceImpl) Update(ctx context.Context, id int32, seriesParam *param.Series) (*entity.Series, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	slug := seriesSlug(seriesParam)
+	if err := s.checkSlug(ctx, id, slug); err != nil {
+		return nil, err
+	}
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	_, err := seriesDAL.WithContext(ctx).Where(seriesDAL.ID.Eq(id)).UpdateSimple(
+		seriesDAL.Name.Value(seriesParam.Name),
+		seriesDAL.Slug.Value(slug),
+		seriesDAL.Description.Value(seriesParam.Description),
+		seriesDAL.Thumbnail.Value(seriesParam.Thumbnail),
+	)
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return s.GetByID(ctx, id)
+}
+
+func seriesSlug(seriesParam *param.Series) string {
+	if seriesParam.Slug == "" {
+		return util.Slug(seriesParam.Name)
+	}
+	return util.Slug(seriesParam.Slug)
+}
+
+func (s *seriesServiceImpl) checkSlug(ctx context.Context, id int32, slug string) error {
+	if slug == "" {
+		return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("series slug is empty")
+	}
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	count, err := seriesDAL.WithContext(ctx).Where(seriesDAL.Slug.Eq(slug), seriesDAL.ID.Neq(id)).Count()
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if count > 0 {
+		return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("series slug already exists")
+	}
+	return nil
+}
+
+func (s *seriesServiceImpl) Delete(ctx context.Context, id int32) error {
+	return dal.Transaction(ctx, func(txCtx context.Context) error {
+		seriesDAL := dal.GetQueryByCtx(txCtx).Series
+		deleteResult, err := seriesDAL.WithContext(txCtx).Where(seriesDAL.ID.Eq(id)).Delete()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		if deleteResult.RowsAffected != 1 {
+			return xerr.NoRecord.New("series not found id=%v", id).WithStatus(xerr.StatusNotFound).WithMsg("series not found")
+		}
+		seriesPostDAL := dal.GetQueryByCtx(txCtx).SeriesPost
+		_, err = seriesPostDAL.WithContext(txCtx).Where(seriesPostDAL.SeriesID.Eq(id)).Delete()
+		return WrapDBErr(err)
+	})
+}
+
+func (s *seriesServiceImpl) ListPosts(ctx context.Context, seriesID int32, status *consts.PostStatus) ([]*entity.Post, error) {
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	seriesPostDAL := dal.GetQueryByCtx(ctx).SeriesPost
+	postDo := postDAL.WithContext(ctx).Join(seriesPostDAL, postDAL.ID.EqCol(seriesPostDAL.PostID)).
+		Where(seriesPostDAL.SeriesID.Eq(seriesID), postDAL.Type.Eq(consts.PostTypePost))
+	if status != nil {
+		postDo = postDo.Where(postDAL.Status.Eq(*status))
+	}
+	posts, err := postDo.Order(seriesPostDAL.Priority, seriesPostDAL.ID).Find()
+	return posts, WrapDBErr(err)
+}
+
+func (s *seriesServiceImpl) UpdatePosts(ctx context.Context, seriesID int32, postIDs []int32) error {
+	if _, err := s.GetByID(ctx, seriesID); err != nil {
+		return err
+	}
+	postIDs = distinctIDs(postIDs)
+	if len(postIDs) > 0 {
+		postDAL := dal.GetQueryByCtx(ctx).Post
+		count, err := postDAL.WithContext(ctx).Where(postDAL.ID.In(postIDs...), postDAL.Type.Eq(consts.PostTypePost)).Count()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		if int(count) != len(postIDs) {
+			return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("post not exist")
+		}
+	}
+	return dal.Transaction(ctx, func(txCtx context.Context) error {
+		seriesPostDAL := dal.GetQueryByCtx(txCtx).SeriesPost
+		_, err := seriesPostDAL.WithContext(txCtx).Where(seriesPostDAL.SeriesID.Eq(seriesID)).Or(seriesPostDAL.PostID.In(postIDs...)).Delete()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		if len(postIDs) == 0 {
+			return nil
+		}
+		seriesPosts := make([]*entity.SeriesPost, 0, len(postIDs))
+		for i, postID := range postIDs {
+			seriesPosts = append(seriesPosts, &entity.SeriesPost{
+				SeriesID: seriesID,
+				PostID:   postID,
+				Priority: int32(i),
+			})
+		}
+		return WrapDBErr(seriesPostDAL.WithContext(txCtx).Create(seriesPosts...))
+	})
+}
+
+func (s *seriesServiceImpl) AssignPost(ctx context.Context, postID int32, seriesID int32) error {
+	if seriesID != 0 {
+		if _, err := s.GetByID(ctx, seriesID); err != nil {
+			return err
+		}
+	}
+	return dal.Transaction(ctx, func(txCtx context.Context) error {
+		seriesPostDAL := dal.GetQueryByCtx(txCtx).SeriesPost
+		seriesPost, err := seriesPostDAL.WithContext(txCtx).Where(seriesPostDAL.PostID.Eq(postID)).Take()
+		if err != nil && xerr.GetType(WrapDBErr(err)) != xerr.NoRecord {
+			return WrapDBErr(err)
+		}
+		if seriesPost != nil {
+			if seriesPost.SeriesID == seriesID {
+				return nil
+			}
+			if _, err := seriesPostDAL.WithContext(txCtx).Where(seriesPostDAL.ID.Eq(seriesPost.ID)).Delete(); err != nil {
+				return WrapDBErr(err)
+			}
+		}
+		if seriesID == 0 {
+			return nil
+		}
+		var last struct {
+			Priority *int32
+		}
+		err = seriesPostDAL.WithContext(txCtx).Select(seriesPostDAL.Priority.Max().As("priority")).
+			Where(seriesPostDAL.SeriesID.Eq(seriesID)).Scan(&last)
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		priority := int32(0)
+		if last.Priority != nil {
+			priority = *last.Priority + 1
+		}
+		return WrapDBErr(seriesPostDAL.WithContext(txCtx).Create(&entity.SeriesPost{
+			SeriesID: seriesID,
+			PostID:   postID,
+			Priority: priority,
+		}))
+	})
+}
+
+func (s *seriesServiceImpl) GetByPostID(ctx context.Context, postID int32) (*entity.Series, error) {
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	seriesPostDAL := dal.GetQueryByCtx(ctx).SeriesPost
+	series, err := seriesDAL.WithContext(ctx).Join(seriesPostDAL, seriesDAL.ID.EqCol(seriesPostDAL.SeriesID)).
+		Where(seriesPostDAL.PostID.Eq(postID)).First()
+	return series, WrapDBErr(err)
+}
+
+func (s *seriesServiceImpl) ListSeriesMapByPostID(ctx context.Context, postIDs []int32) (map[int32]*entity.Series, error) {
+	result := make(map[int32]*entity.Series)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+	seriesPostDAL := dal.GetQueryByCtx(ctx).SeriesPost
+	seriesPosts, err := seriesPostDAL.WithContext(ctx).Where(seriesPostDAL.PostID.In(postIDs...)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	if len(seriesPosts) == 0 {
+		return result, nil
+	}
+	seriesIDs := make([]int32, 0, len(seriesPosts))
+	for _, seriesPost := range seriesPosts {
+		seriesIDs = append(seriesIDs, seriesPost.SeriesID)
+	}
+	seriesDAL := dal.GetQueryByCtx(ctx).Series
+	series, err := seriesDAL.WithContext(ctx).Where(seriesDAL.ID.In(distinctIDs(seriesIDs)...)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	seriesMap := make(map[int32]*entity.Series, len(series))
+	for _, one := range series {
+		seriesMap[one.ID] = one
+	}
+	for _, seriesPost := range seriesPosts {
+		if one, ok := seriesMap[seriesPost.SeriesID]; ok {
+			result[seriesPost.PostID] = one
+		}
+	}
+	return result, nil
+}
+
+func (s *seriesServiceImpl) GetAdjacentPosts(ctx context.Context, postID int32) (*entity.Post, *entity.Post, error) {
+	series, err := s.GetByPostID(ctx, postID)
+	if xerr.GetType(err) == xerr.NoRecord {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	posts, err := s.ListPosts(ctx, series.ID, consts.PostStatusPublished.Ptr())
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, post := range posts {
+		if post.ID != postID {
+			continue
+		}
+		var prev, next *entity.Post
+		if i > 0 {
+			prev = posts[i-1]
+		}
+		if i < len(posts)-1 {
+			next = posts[i+1]
+		}
+		return prev, next, nil
+	}
+	return nil, nil, nil
+}
+
+func (s *seriesServiceImpl) ConvertToDTO(ctx context.Context, series *entity.Series) (*dto.Series, error) {
+	seriesDTOs, err := s.ConvertToDTOs(ctx, []*entity.Series{series})
+	if err != nil {
+		return nil, err
+	}
+	return seriesDTOs[0], nil
+}
+
+func (s *seriesServiceImpl) ConvertToDTOs(ctx context.Context, series []*entity.Series) ([]*dto.Series, error) {
+	isEnabled, err := s.OptionService.IsEnabledAbsolutePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var blogBaseURL string
+	if isEnabled {
+		blogBaseURL, err = s.OptionService.GetBlogBaseURL(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	seriesPrefix, err := s.OptionService.GetSeriesPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pathSuffix, err := s.OptionService.GetPathSuffix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	postCounts, err := s.countPublishedPosts(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	seriesDTOs := make([]*dto.Series, 0, len(series))
+	for _, one := range series {
+		fullPath := strings.Builder{}
+		if isEnabled {
+			fullPath.WriteString(blogBaseURL)
+		}
+		fullPath.WriteString("/")
+		fullPath.WriteString(seriesPrefix)
+		fullPath.WriteString("/")
+		fullPath.WriteString(one.Slug)
+		fullPath.WriteString(pathSuffix)
+		seriesDTOs = append(seriesDTOs, &dto.Series{
+			ID:          one.ID,
+			Name:        one.Name,
+			Slug:        one.Slug,
+			Description: one.Description,
+			Thumbnail:   one.Thumbnail,
+			CreateTime:  one.CreateTime.UnixMilli(),
+			FullPath:    fullPath.String(),
+			PostCount:   postCounts[one.ID],
+		})
+	}
+	return seriesDTOs, nil
+}
+
+func (s *seriesServiceImpl) countPublishedPosts(ctx context.Context, series []*entity.Series) (map[int32]int64, error) {
+	counts := make(map[int32]int64, len(series))
+	if len(series) == 0 {
+		return counts, nil
+	}
+	seriesIDs := make([]int32, 0, len(series))
+	for _, one := range series {
+		seriesIDs = append(seriesIDs, one.ID)
+	}
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	seriesPostDAL := dal.GetQueryByCtx(ctx).SeriesPost
+	rows := make([]*struct {
+		SeriesID  int32
+		PostCount int64
+	}, 0)
+	err := seriesPostDAL.WithContext(ctx).Select(seriesPostDAL.SeriesID, seriesPostDAL.PostID.Count().As("post_count")).
+		Join(postDAL, postDAL.ID.EqCol(seriesPostDAL.PostID)).
+		Where(seriesPostDAL.SeriesID.In(seriesIDs...), postDAL.Status.Eq(consts.PostStatusPublished)).
+		Group(seriesPostDAL.SeriesID).Scan(&rows)
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	for _, row := range rows {
+		counts[row.SeriesID] = row.PostCount
+	}
+	return counts, nil
+}
+
+// distinctIDs removes the repeated ids and keeps the order of the first ones
+func distinctIDs(ids []int32) []int32 {
+	seen := make(map[int32]struct{}, len(ids))
+	result := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
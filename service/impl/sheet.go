@@ -26,6 +26,7 @@ type sheetServiceImpl struct {
 	Event               event.Bus
 	Cache               cache.Cache
 	ThemeService        service.ThemeService
+	TranslationService  service.TranslationService
 }
 
 func NewSheetService(basePostService service.BasePostService,
@@ -35,6 +36,7 @@ func NewSheetService(basePostService service.BasePostService,
 	event event.Bus,
 	cache cache.Cache,
 	themeService service.ThemeService,
+	translationService service.TranslationService,
 ) service.SheetService {
 	return &sheetServiceImpl{
 		BasePostService:     basePostService,
@@ -44,6 +46,7 @@ func NewSheetService(basePostService service.BasePostService,
 		Event:               event,
 		Cache:               cache,
 		ThemeService:        themeService,
+		TranslationService:  translationService,
 	}
 }
 
@@ -70,6 +73,9 @@ func (s sheetServiceImpl) Create(ctx context.Context, sheetParam *param.Sheet) (
 	if err != nil {
 		return nil, err
 	}
+	if err := linkPostTranslation(ctx, s.TranslationService, sheet.ID, sheetParam.Language, sheetParam.TranslationOf); err != nil {
+		return nil, err
+	}
 	return sheet, nil
 }
 
@@ -131,6 +137,9 @@ func (s sheetServiceImpl) Update(ctx context.Context, sheetID int32, sheetParam
 	if err != nil {
 		return nil, err
 	}
+	if err := linkPostTranslation(ctx, s.TranslationService, sheet.ID, sheetParam.Language, sheetParam.TranslationOf); err != nil {
+		return nil, err
+	}
 	s.Event.Publish(ctx, &event.LogEvent{
 		LogKey:    strconv.Itoa(int(sheet.ID)),
 		LogType:   consts.LogTypeSheetEdited,
@@ -0,0 +1,434 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	htmlTemplate "html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util/pageparser"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+var (
+	// the markdown editor escapes the delimiters and the quoted params of a shortcode
+	escapedShortcodeRegexp = regexp.MustCompile(`\{\{(&lt;|<|%)(.*?)(&gt;|>|%)\}\}`)
+	// a shortcode written in a line of its own is wrapped in a paragraph by the markdown editor
+	paragraphShortcodeRegexp = regexp.MustCompile(`<p>\s*(\{\{[<%][^{}]*?[>%]\}\})\s*</p>`)
+	// the shortcodes in the code blocks are shown as they are written
+	codeBlockRegexp            = regexp.MustCompile(`(?is)<pre\b.*?</pre>|<code\b.*?</code>`)
+	codeBlockPlaceholderRegexp = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+type shortcodeFunc func(ctx context.Context, sc *shortcode) (string, error)
+
+type shortcode struct {
+	Name        string
+	Params      map[string]any
+	Args        []any
+	Inner       string
+	children    []*shortcodeNode
+	openSource  string
+	closeSource string
+}
+
+// Get returns the named param, or the positional one when the params are positional
+func (s *shortcode) Get(name string, position int) string {
+	if value, ok := s.Params[name]; ok {
+		return fmt.Sprint(value)
+	}
+	if position >= 0 && position < len(s.Args) {
+		return fmt.Sprint(s.Args[position])
+	}
+	return ""
+}
+
+func (s *shortcode) source() string {
+	return s.openSource + s.Inner + s.closeSource
+}
+
+type shortcodeNode struct {
+	Text      string
+	Shortcode *shortcode
+}
+
+type shortcodeServiceImpl struct {
+	ThemeService      service.ThemeService
+	PhotoService      service.PhotoService
+	AttachmentService service.AttachmentService
+	Template          *template.Template
+	builtins          map[string]shortcodeFunc
+}
+
+func NewShortcodeService(themeService service.ThemeService, photoService service.PhotoService, attachmentService service.AttachmentService, template *template.Template) service.ShortcodeService {
+	s := &shortcodeServiceImpl{
+		ThemeService:      themeService,
+		PhotoService:      photoService,
+		AttachmentService: attachmentService,
+		Template:          template,
+	}
+	s.builtins = map[string]shortcodeFunc{
+		"attachment": s.renderAttachment,
+		"gallery":    s.renderGallery,
+		"notice":     s.renderNotice,
+		"photo":      s.renderPhoto,
+		"youtube":    s.renderYoutube,
+	}
+	return s
+}
+
+func (s *shortcodeServiceImpl) Render(ctx context.Context, content string) (string, error) {
+	if !strings.Contains(content, "{{") {
+		return content, nil
+	}
+	normalized, restore := protectCodeBlocks(content)
+	normalized = escapedShortcodeRegexp.ReplaceAllStringFunc(normalized, func(match string) string {
+		parts := escapedShortcodeRegexp.FindStringSubmatch(match)
+		return "{{" + html.UnescapeString(parts[1]) + html.UnescapeString(parts[2]) + html.UnescapeString(parts[3]) + "}}"
+	})
+	normalized = paragraphShortcodeRegexp.ReplaceAllString(normalized, "$1")
+
+	nodes, err := parseShortcodes(normalized)
+	if err != nil {
+		log.CtxWarn(ctx, "parse shortcodes err", zap.Error(err))
+		return content, nil
+	}
+	rendered, err := s.renderNodes(ctx, nodes)
+	if err != nil {
+		return "", err
+	}
+	return restore(rendered), nil
+}
+
+// protectCodeBlocks replaces the code blocks containing shortcodes with placeholders, restore puts them back
+func protectCodeBlocks(content string) (protected string, restore func(string) string) {
+	blocks := make([]string, 0)
+	protected = codeBlockRegexp.ReplaceAllStringFunc(content, func(block string) string {
+		if !strings.Contains(block, "{{") {
+			return block
+		}
+		blocks = append(blocks, block)
+		return "\x00" + strconv.Itoa(len(blocks)-1) + "\x00"
+	})
+	restore = func(s string) string {
+		if len(blocks) == 0 {
+			return s
+		}
+		return codeBlockPlaceholderRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+			index, _ := strconv.Atoi(strings.Trim(placeholder, "\x00"))
+			if index < len(blocks) {
+				return blocks[index]
+			}
+			return placeholder
+		})
+	}
+	return protected, restore
+}
+
+func (s *shortcodeServiceImpl) renderNodes(ctx context.Context, nodes []*shortcodeNode) (string, error) {
+	buf := strings.Builder{}
+	for _, node := range nodes {
+		if node.Shortcode == nil {
+			buf.WriteString(node.Text)
+			continue
+		}
+		sc := node.Shortcode
+		inner, err := s.renderNodes(ctx, sc.children)
+		if err != nil {
+			return "", err
+		}
+		sc.Inner = inner
+		rendered, ok, err := s.renderShortcode(ctx, sc)
+		if err != nil {
+			log.CtxWarn(ctx, "render shortcode err", zap.String("name", sc.Name), zap.Error(err))
+			ok = false
+		}
+		if !ok {
+			rendered = sc.source()
+		}
+		buf.WriteString(rendered)
+	}
+	return buf.String(), nil
+}
+
+func (s *shortcodeServiceImpl) renderShortcode(ctx context.Context, sc *shortcode) (string, bool, error) {
+	templateName, err := s.ThemeService.Render(ctx, "shortcodes/"+sc.Name)
+	if err != nil {
+		return "", false, err
+	}
+	tmpl := s.getTemplate(ctx)
+	if tmpl.Lookup(templateName) {
+		model := template.Model{
+			"name":   sc.Name,
+			"params": sc.Params,
+			"args":   sc.Args,
+			"inner":  htmlTemplate.HTML(sc.Inner),
+		}
+		buf := bytes.Buffer{}
+		if err := tmpl.ExecuteTemplate(ctx, &buf, templateName, model); err != nil {
+			return "", false, err
+		}
+		return buf.String(), true, nil
+	}
+	builtin, ok := s.builtins[sc.Name]
+	if !ok {
+		return "", false, nil
+	}
+	rendered, err := builtin(ctx, sc)
+	return rendered, err == nil, err
+}
+
+func (s *shortcodeServiceImpl) getTemplate(ctx context.Context) *template.Template {
+	if previewTemplate, ok := ctx.Value(consts.ThemePreviewTemplate).(*template.Template); ok {
+		return previewTemplate
+	}
+	return s.Template
+}
+
+func (s *shortcodeServiceImpl) renderYoutube(ctx context.Context, sc *shortcode) (string, error) {
+	id := sc.Get("id", 0)
+	if id == "" {
+		return "", xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("youtube id is required")
+	}
+	title := sc.Get("title", 1)
+	if title == "" {
+		title = "YouTube Video"
+	}
+	return fmt.Sprintf(`<div class="shortcode-youtube"><iframe src="https://www.youtube-nocookie.com/embed/%s" title="%s" `+
+		`frameborder="0" loading="lazy" allow="accelerometer; autoplay; clipboard-write; encrypted-media; gyroscope; picture-in-picture" allowfullscreen></iframe></div>`,
+		html.EscapeString(id), html.EscapeString(title)), nil
+}
+
+func (s *shortcodeServiceImpl) renderNotice(ctx context.Context, sc *shortcode) (string, error) {
+	noticeType := sc.Get("type", 0)
+	if noticeType == "" {
+		noticeType = "info"
+	}
+	buf := strings.Builder{}
+	buf.WriteString(`<div class="shortcode-notice notice-` + html.EscapeString(noticeType) + `">`)
+	if title := sc.Get("title", 1); title != "" {
+		buf.WriteString(`<p class="notice-title">` + html.EscapeString(title) + `</p>`)
+	}
+	buf.WriteString(sc.Inner)
+	buf.WriteString(`</div>`)
+	return buf.String(), nil
+}
+
+func (s *shortcodeServiceImpl) renderGallery(ctx context.Context, sc *shortcode) (string, error) {
+	sort := &param.Sort{Fields: []string{"takeTime,desc", "id,desc"}}
+	team := sc.Get("team", 0)
+
+	var (
+		entities []*entity.Photo
+		err      error
+	)
+	if team != "" {
+		entities, err = s.PhotoService.ListByTeam(ctx, team, sort)
+	} else {
+		entities, err = s.PhotoService.List(ctx, sort)
+	}
+	if err != nil {
+		return "", err
+	}
+	photos := make([]*photoFigure, 0, len(entities))
+	for _, photo := range s.PhotoService.ConvertToDTOs(ctx, entities) {
+		photos = append(photos, &photoFigure{URL: photo.URL, Thumbnail: photo.Thumbnail, Name: photo.Name, Description: photo.Description})
+	}
+	if limit, err := strconv.Atoi(sc.Get("limit", 1)); err == nil && limit > 0 && limit < len(photos) {
+		photos = photos[:limit]
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString(`<div class="shortcode-gallery">`)
+	for _, photo := range photos {
+		buf.WriteString(photo.html())
+	}
+	buf.WriteString(sc.Inner)
+	buf.WriteString(`</div>`)
+	return buf.String(), nil
+}
+
+func (s *shortcodeServiceImpl) renderPhoto(ctx context.Context, sc *shortcode) (string, error) {
+	id, err := strconv.ParseInt(sc.Get("id", 0), 10, 32)
+	if err != nil {
+		return "", xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("photo id is invalid")
+	}
+	photo, err := s.PhotoService.GetByID(ctx, int32(id))
+	if err != nil {
+		return "", err
+	}
+	photoDTO := s.PhotoService.ConvertToDTO(ctx, photo)
+	figure := &photoFigure{URL: photoDTO.URL, Thumbnail: photoDTO.Thumbnail, Name: photoDTO.Name, Description: photoDTO.Description}
+	return figure.html(), nil
+}
+
+func (s *shortcodeServiceImpl) renderAttachment(ctx context.Context, sc *shortcode) (string, error) {
+	id, err := strconv.ParseInt(sc.Get("id", 0), 10, 32)
+	if err != nil {
+		return "", xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("attachment id is invalid")
+	}
+	attachment, err := s.AttachmentService.GetAttachment(ctx, int32(id))
+	if err != nil {
+		return "", err
+	}
+	attachmentDTO, err := s.AttachmentService.ConvertToDTO(ctx, attachment)
+	if err != nil {
+		return "", err
+	}
+	name := sc.Get("title", 1)
+	if name == "" {
+		name = attachmentDTO.Name
+	}
+	path := html.EscapeString(attachmentDTO.Path)
+	switch {
+	case strings.HasPrefix(attachmentDTO.MediaType, "image/"):
+		figure := &photoFigure{URL: attachmentDTO.Path, Thumbnail: attachmentDTO.ThumbPath, Name: name}
+		return figure.html(), nil
+	case strings.HasPrefix(attachmentDTO.MediaType, "video/"):
+		return fmt.Sprintf(`<video class="shortcode-attachment" src="%s" controls preload="metadata"></video>`, path), nil
+	case strings.HasPrefix(attachmentDTO.MediaType, "audio/"):
+		return fmt.Sprintf(`<audio class="shortcode-attachment" src="%s" controls preload="metadata"></audio>`, path), nil
+	default:
+		return fmt.Sprintf(`<a class="shortcode-attachment" href="%s" download>%s</a>`, path, html.EscapeString(name)), nil
+	}
+}
+
+type photoFigure struct {
+	URL         string
+	Thumbnail   string
+	Name        string
+	Description string
+}
+
+func (p *photoFigure) html() string {
+	thumbnail := p.Thumbnail
+	if thumbnail == "" {
+		thumbnail = p.URL
+	}
+	caption := p.Description
+	if caption == "" {
+		caption = p.Name
+	}
+	buf := strings.Builder{}
+	buf.WriteString(`<figure class="shortcode-photo"><a href="` + html.EscapeString(p.URL) + `" target="_blank">`)
+	buf.WriteString(`<img src="` + html.EscapeString(thumbnail) + `" alt="` + html.EscapeString(p.Name) + `" loading="lazy"></a>`)
+	if caption != "" {
+		buf.WriteString(`<figcaption>` + html.EscapeString(caption) + `</figcaption>`)
+	}
+	buf.WriteString(`</figure>`)
+	return buf.String()
+}
+
+// parseShortcodes splits the content into text and shortcodes, a shortcode owns the nodes up to its closing tag.
+// A shortcode whose closing tag is missing is kept standalone and the nodes after it are moved to its parent.
+func parseShortcodes(content string) ([]*shortcodeNode, error) {
+	result, err := pageparser.ParseMain(strings.NewReader(content), pageparser.Config{})
+	if err != nil {
+		return nil, err
+	}
+	source := result.Input()
+	root := &shortcode{}
+	stack := []*shortcode{root}
+
+	appendNode := func(node *shortcodeNode) {
+		top := stack[len(stack)-1]
+		top.children = append(top.children, node)
+	}
+	// unwind pops the shortcodes above the given depth, they are not paired
+	unwind := func(depth int) {
+		for len(stack) > depth {
+			sc := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			children := sc.children
+			sc.children = nil
+			appendNode(&shortcodeNode{Shortcode: sc})
+			for _, child := range children {
+				appendNode(child)
+			}
+		}
+	}
+
+	iter := result.Iterator()
+	for {
+		item := iter.Next()
+		switch {
+		case item.IsEOF():
+			unwind(1)
+			return root.children, nil
+		case item.IsError():
+			return nil, item.Err
+		case item.IsLeftShortcodeDelim():
+			start := item.Pos()
+			sc := &shortcode{Params: map[string]any{}}
+			isClosing, isSelfClosing := false, false
+			for {
+				item = iter.Next()
+				switch {
+				case item.IsError():
+					return nil, item.Err
+				case item.IsEOF():
+					return nil, fmt.Errorf("unclosed shortcode %s", sc.Name)
+				case item.IsShortcodeClose():
+					if sc.Name == "" {
+						isClosing = true
+					} else {
+						isSelfClosing = true
+					}
+				case item.IsShortcodeName(), item.IsInlineShortcodeName():
+					sc.Name = item.ValStr(source)
+				case item.IsShortcodeParam():
+					if next := iter.Peek(); next.IsShortcodeParamVal() {
+						iter.Next()
+						sc.Params[item.ValStr(source)] = next.ValTyped(source)
+					} else {
+						sc.Args = append(sc.Args, item.ValTyped(source))
+					}
+				}
+				if item.IsRightShortcodeDelim() {
+					break
+				}
+			}
+			tag := string(source[start : item.Pos()+len(item.Val(source))])
+
+			if isClosing {
+				depth := -1
+				for i := len(stack) - 1; i > 0; i-- {
+					if stack[i].Name == sc.Name {
+						depth = i
+						break
+					}
+				}
+				if depth < 0 {
+					appendNode(&shortcodeNode{Text: tag})
+					continue
+				}
+				unwind(depth + 1)
+				opened := stack[depth]
+				stack = stack[:depth]
+				opened.closeSource = tag
+				appendNode(&shortcodeNode{Shortcode: opened})
+				continue
+			}
+			sc.openSource = tag
+			if isSelfClosing {
+				appendNode(&shortcodeNode{Shortcode: sc})
+				continue
+			}
+			stack = append(stack, sc)
+		default:
+			appendNode(&shortcodeNode{Text: item.ValStr(source)})
+		}
+	}
+}
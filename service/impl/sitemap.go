@@ -0,0 +1,702 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/log"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+var imgSrcRegexp = regexp.MustCompile(`<img\s[^>]*?src\s*=\s*["']([^"']+)["']`)
+
+type sitemapServiceImpl struct {
+	OptionService   service.OptionService
+	PostService     service.PostService
+	SheetService    service.SheetService
+	CategoryService service.CategoryService
+	TagService      service.TagService
+	client          *http.Client
+}
+
+func NewSitemapService(
+	optionService service.OptionService,
+	postService service.PostService,
+	sheetService service.SheetService,
+	categoryService service.CategoryService,
+	tagService service.TagService,
+) service.SitemapService {
+	return &sitemapServiceImpl{
+		OptionService:   optionService,
+		PostService:     postService,
+		SheetService:    sheetService,
+		CategoryService: categoryService,
+		TagService:      tagService,
+		client:          &http.Client{Timeout: consts.IndexNowTimeout, Transport: tracing.NewTransport(nil)},
+	}
+}
+
+func (s *sitemapServiceImpl) ListSitemaps(ctx context.Context) ([]*dto.Sitemap, error) {
+	blogURL, err := s.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, pathPrefix, err := s.sitemapLanguage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sitemaps := make([]*dto.Sitemap, 0)
+	for _, sitemapType := range consts.SitemapTypes {
+		count, err := s.countURLs(ctx, sitemapType)
+		if err != nil {
+			return nil, err
+		}
+		pages := (count + consts.SitemapPageSize - 1) / consts.SitemapPageSize
+		for page := 1; page <= int(pages); page++ {
+			sitemap := &dto.Sitemap{Loc: blogURL + pathPrefix + sitemapPath(sitemapType, page)}
+			// urls are sorted by create time desc, so only the first page knows the last modification
+			if page == 1 {
+				sitemap.LastMod, err = s.lastModOf(ctx, sitemapType)
+				if err != nil {
+					return nil, err
+				}
+			}
+			sitemaps = append(sitemaps, sitemap)
+		}
+	}
+	return sitemaps, nil
+}
+
+func (s *sitemapServiceImpl) ListURLs(ctx context.Context, sitemapType consts.SitemapType, page int) ([]*dto.SitemapURL, error) {
+	count, err := s.countURLs(ctx, sitemapType)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 || int64((page-1)*consts.SitemapPageSize) >= count {
+		return nil, xerr.NoRecord.New("sitemap not exist type=%s page=%d", sitemapType, page).WithStatus(xerr.StatusNotFound).WithMsg("Sitemap not found")
+	}
+	blogURL, err := s.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	offset := (page - 1) * consts.SitemapPageSize
+
+	var urls []*dto.SitemapURL
+	switch sitemapType {
+	case consts.SitemapTypePages:
+		urls, err = s.listPageURLs(ctx)
+	case consts.SitemapTypePosts:
+		urls, err = s.listPostURLs(ctx, consts.PostTypePost, offset)
+	case consts.SitemapTypeSheets:
+		urls, err = s.listPostURLs(ctx, consts.PostTypeSheet, offset)
+	case consts.SitemapTypeCategories:
+		urls, err = s.listCategoryURLs(ctx, offset)
+	case consts.SitemapTypeTags:
+		urls, err = s.listTagURLs(ctx, offset)
+	case consts.SitemapTypeJournals:
+		urls, err = s.listJournalURLs(ctx, offset)
+	case consts.SitemapTypePhotos:
+		urls, err = s.listPhotoURLs(ctx, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, sitemapURL := range urls {
+		sitemapURL.Loc = absoluteURL(blogURL, sitemapURL.Loc)
+		for i, image := range sitemapURL.Images {
+			sitemapURL.Images[i] = absoluteURL(blogURL, image)
+		}
+		for _, alternate := range sitemapURL.Alternates {
+			alternate.Href = absoluteURL(blogURL, alternate.Href)
+		}
+	}
+	return urls, nil
+}
+
+func (s *sitemapServiceImpl) Notify(ctx context.Context, urls []string) error {
+	blogURL, err := s.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return err
+	}
+	var notifyErr error
+	if s.OptionService.GetOrByDefault(ctx, property.SeoIndexNowEnabled).(bool) && len(urls) > 0 {
+		if err := s.submitIndexNow(ctx, blogURL, urls); err != nil {
+			log.CtxWarn(ctx, "submit urls to IndexNow err", zap.Error(err))
+			notifyErr = err
+		}
+	}
+	pingURLs := s.OptionService.GetOrByDefault(ctx, property.SeoSitemapPingURLs).(string)
+	for _, pingURL := range strings.Split(pingURLs, "\n") {
+		pingURL = strings.TrimSpace(pingURL)
+		if pingURL == "" {
+			continue
+		}
+		pingURL = strings.ReplaceAll(pingURL, consts.SitemapPingURLHolder, url.QueryEscape(blogURL+"/sitemap.xml"))
+		if err := s.ping(ctx, pingURL); err != nil {
+			log.CtxWarn(ctx, "ping sitemap err", zap.String("url", pingURL), zap.Error(err))
+			notifyErr = err
+		}
+	}
+	return notifyErr
+}
+
+func (s *sitemapServiceImpl) submitIndexNow(ctx context.Context, blogURL string, urls []string) error {
+	key := s.OptionService.GetOrByDefault(ctx, property.SeoIndexNowKey).(string)
+	if key == "" {
+		return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("IndexNow key is empty")
+	}
+	endpoint := s.OptionService.GetOrByDefault(ctx, property.SeoIndexNowEndpoint).(string)
+	u, err := url.Parse(blogURL)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Invalid blog url")
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"host":        u.Host,
+		"key":         key,
+		"keyLocation": blogURL + consts.IndexNowKeyPath,
+		"urlList":     urls,
+	})
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Invalid IndexNow endpoint")
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return s.do(req)
+}
+
+func (s *sitemapServiceImpl) ping(ctx context.Context, pingURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pingURL, nil)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("Invalid ping url")
+	}
+	return s.do(req)
+}
+
+func (s *sitemapServiceImpl) do(req *http.Request) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return xerr.WithStatus(err, xerr.StatusInternalServerError)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return xerr.NoType.New("request %s failed status=%d", req.URL.String(), resp.StatusCode).WithStatus(xerr.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (s *sitemapServiceImpl) countURLs(ctx context.Context, sitemapType consts.SitemapType) (int64, error) {
+	var (
+		count int64
+		conds []gen.Condition
+		err   error
+	)
+	_, pathPrefix, err := s.sitemapLanguage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// tags, journals and photos are not translated, they are only in the sitemaps of the default language
+	if pathPrefix != "" && (sitemapType == consts.SitemapTypeTags || sitemapType == consts.SitemapTypeJournals || sitemapType == consts.SitemapTypePhotos) {
+		return 0, nil
+	}
+	switch sitemapType {
+	case consts.SitemapTypePages:
+		return 1, nil
+	case consts.SitemapTypePosts, consts.SitemapTypeSheets:
+		postDAL := dal.GetQueryByCtx(ctx).Post
+		conds, err = s.languageConditions(ctx, consts.TranslationTypePost, postDAL.ID)
+		if err != nil {
+			return 0, err
+		}
+		conds = append(conds, postDAL.Type.Eq(postTypeOf(sitemapType)), postDAL.Status.Eq(consts.PostStatusPublished))
+		count, err = postDAL.WithContext(ctx).Where(conds...).Count()
+	case consts.SitemapTypeCategories:
+		categoryDAL := dal.GetQueryByCtx(ctx).Category
+		conds, err = s.languageConditions(ctx, consts.TranslationTypeCategory, categoryDAL.ID)
+		if err != nil {
+			return 0, err
+		}
+		conds = append(conds, categoryDAL.Type.Neq(consts.CategoryTypeIntimate))
+		count, err = categoryDAL.WithContext(ctx).Where(conds...).Count()
+	case consts.SitemapTypeTags:
+		tagDAL := dal.GetQueryByCtx(ctx).Tag
+		count, err = tagDAL.WithContext(ctx).Count()
+	case consts.SitemapTypeJournals:
+		journalDAL := dal.GetQueryByCtx(ctx).Journal
+		count, err = journalDAL.WithContext(ctx).Where(journalDAL.Type.Eq(consts.JournalTypePublic)).Count()
+		count = pageCount(count, s.OptionService.GetOrByDefault(ctx, property.JournalPageSize).(int))
+	case consts.SitemapTypePhotos:
+		photoDAL := dal.GetQueryByCtx(ctx).Photo
+		count, err = photoDAL.WithContext(ctx).Count()
+		count = pageCount(count, s.OptionService.GetOrByDefault(ctx, property.PhotoPageSize).(int))
+	default:
+		return 0, xerr.NoRecord.New("sitemap not exist type=%s", sitemapType).WithStatus(xerr.StatusNotFound).WithMsg("Sitemap not found")
+	}
+	if err != nil {
+		return 0, WrapDBErr(err)
+	}
+	return count, nil
+}
+
+func (s *sitemapServiceImpl) lastModOf(ctx context.Context, sitemapType consts.SitemapType) (*time.Time, error) {
+	switch sitemapType {
+	case consts.SitemapTypePages:
+		return s.lastModOf(ctx, consts.SitemapTypePosts)
+	case consts.SitemapTypePosts, consts.SitemapTypeSheets:
+		postDAL := dal.GetQueryByCtx(ctx).Post
+		conds, err := s.languageConditions(ctx, consts.TranslationTypePost, postDAL.ID)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, postDAL.Type.Eq(postTypeOf(sitemapType)), postDAL.Status.Eq(consts.PostStatusPublished))
+		posts, err := postDAL.WithContext(ctx).Where(conds...).Order(postDAL.EditTime.Desc()).Limit(1).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		if len(posts) == 0 {
+			return nil, nil
+		}
+		lastMod := postLastMod(posts[0])
+		return &lastMod, nil
+	case consts.SitemapTypeJournals:
+		journalDAL := dal.GetQueryByCtx(ctx).Journal
+		journals, err := journalDAL.WithContext(ctx).Where(journalDAL.Type.Eq(consts.JournalTypePublic)).Order(journalDAL.CreateTime.Desc()).Limit(1).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		if len(journals) == 0 {
+			return nil, nil
+		}
+		lastMod := latestOf(journals[0].CreateTime, journals[0].UpdateTime)
+		return &lastMod, nil
+	case consts.SitemapTypePhotos:
+		photoDAL := dal.GetQueryByCtx(ctx).Photo
+		photos, err := photoDAL.WithContext(ctx).Order(photoDAL.CreateTime.Desc()).Limit(1).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		if len(photos) == 0 {
+			return nil, nil
+		}
+		lastMod := latestOf(photos[0].CreateTime, photos[0].UpdateTime)
+		return &lastMod, nil
+	}
+	return nil, nil
+}
+
+func (s *sitemapServiceImpl) listPageURLs(ctx context.Context) ([]*dto.SitemapURL, error) {
+	lastMod, err := s.lastModOf(ctx, consts.SitemapTypePages)
+	if err != nil {
+		return nil, err
+	}
+	prefixGetters := []func(ctx context.Context) (string, error){
+		s.OptionService.GetArchivePrefix,
+		s.OptionService.GetCategoryPrefix,
+		s.OptionService.GetTagPrefix,
+		s.OptionService.GetLinksPrefix,
+		s.OptionService.GetSeriesPrefix,
+	}
+	paths := []string{"/"}
+	for _, getPrefix := range prefixGetters {
+		prefix, err := getPrefix(ctx)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, "/"+prefix)
+	}
+	languages, pathPrefix, err := s.sitemapLanguage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]*dto.SitemapURL, 0, len(paths))
+	for _, path := range paths {
+		sitemapURL := &dto.SitemapURL{Loc: languagePath(pathPrefix, path)}
+		if lastMod != nil {
+			sitemapURL.LastMod = *lastMod
+		}
+		// the pages are in every language
+		if len(languages) > 1 {
+			for i, language := range languages {
+				href := path
+				if i > 0 {
+					href = languagePath("/"+language, path)
+				}
+				sitemapURL.Alternates = append(sitemapURL.Alternates, &dto.SitemapAlternate{Language: language, Href: href})
+			}
+			sitemapURL.Alternates = append(sitemapURL.Alternates, &dto.SitemapAlternate{Language: "x-default", Href: path})
+		}
+		urls = append(urls, sitemapURL)
+	}
+	return urls, nil
+}
+
+func (s *sitemapServiceImpl) listPostURLs(ctx context.Context, postType consts.PostType, offset int) ([]*dto.SitemapURL, error) {
+	postDAL := dal.GetQueryByCtx(ctx).Post
+	conds, err := s.languageConditions(ctx, consts.TranslationTypePost, postDAL.ID)
+	if err != nil {
+		return nil, err
+	}
+	conds = append(conds, postDAL.Type.Eq(postType), postDAL.Status.Eq(consts.PostStatusPublished))
+	posts, err := postDAL.WithContext(ctx).Where(conds...).Order(postDAL.CreateTime.Desc()).Offset(offset).Limit(consts.SitemapPageSize).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	postIDs := make([]int32, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	alternates, err := s.listAlternates(ctx, consts.TranslationTypePost, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	fullPaths, err := s.PostService.BuildFullPaths(ctx, posts)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]*dto.SitemapURL, 0, len(posts))
+	for _, post := range posts {
+		urls = append(urls, &dto.SitemapURL{
+			Loc:        fullPaths[post.ID],
+			LastMod:    postLastMod(post),
+			Images:     postImages(post),
+			Alternates: alternates[post.ID],
+		})
+	}
+	return urls, nil
+}
+
+func (s *sitemapServiceImpl) listCategoryURLs(ctx context.Context, offset int) ([]*dto.SitemapURL, error) {
+	categoryDAL := dal.GetQueryByCtx(ctx).Category
+	conds, err := s.languageConditions(ctx, consts.TranslationTypeCategory, categoryDAL.ID)
+	if err != nil {
+		return nil, err
+	}
+	conds = append(conds, categoryDAL.Type.Neq(consts.CategoryTypeIntimate))
+	categories, err := categoryDAL.WithContext(ctx).Where(conds...).Order(categoryDAL.ID).Offset(offset).Limit(consts.SitemapPageSize).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	categoryDTOs, err := s.CategoryService.ConvertToCategoryDTOs(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs := make([]int32, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	alternates, err := s.listAlternates(ctx, consts.TranslationTypeCategory, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]*dto.SitemapURL, 0, len(categories))
+	for i, category := range categories {
+		sitemapURL := &dto.SitemapURL{
+			Loc:        categoryDTOs[i].FullPath,
+			LastMod:    latestOf(category.CreateTime, category.UpdateTime),
+			Alternates: alternates[category.ID],
+		}
+		if category.Thumbnail != "" {
+			sitemapURL.Images = []string{category.Thumbnail}
+		}
+		urls = append(urls, sitemapURL)
+	}
+	return urls, nil
+}
+
+func (s *sitemapServiceImpl) listTagURLs(ctx context.Context, offset int) ([]*dto.SitemapURL, error) {
+	tagDAL := dal.GetQueryByCtx(ctx).Tag
+	tags, err := tagDAL.WithContext(ctx).Order(tagDAL.ID).Offset(offset).Limit(consts.SitemapPageSize).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	tagDTOs, err := s.TagService.ConvertToDTOs(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]*dto.SitemapURL, 0, len(tags))
+	for i, tag := range tags {
+		sitemapURL := &dto.SitemapURL{
+			Loc:     tagDTOs[i].FullPath,
+			LastMod: latestOf(tag.CreateTime, tag.UpdateTime),
+		}
+		if tag.Thumbnail != "" {
+			sitemapURL.Images = []string{tag.Thumbnail}
+		}
+		urls = append(urls, sitemapURL)
+	}
+	return urls, nil
+}
+
+// listJournalURLs lists the pages of journals starting from the offset-th page
+func (s *sitemapServiceImpl) listJournalURLs(ctx context.Context, offset int) ([]*dto.SitemapURL, error) {
+	journalPrefix, err := s.OptionService.GetJournalPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := s.OptionService.GetOrByDefault(ctx, property.JournalPageSize).(int)
+	journalDAL := dal.GetQueryByCtx(ctx).Journal
+	journals, err := journalDAL.WithContext(ctx).Select(journalDAL.CreateTime, journalDAL.UpdateTime).
+		Where(journalDAL.Type.Eq(consts.JournalTypePublic)).Order(journalDAL.CreateTime.Desc()).
+		Offset(offset * pageSize).Limit(consts.SitemapPageSize * pageSize).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	urls := make([]*dto.SitemapURL, 0)
+	for i, journal := range journals {
+		lastMod := latestOf(journal.CreateTime, journal.UpdateTime)
+		if i%pageSize == 0 {
+			urls = append(urls, &dto.SitemapURL{Loc: pagePath(journalPrefix, offset+i/pageSize+1), LastMod: lastMod})
+		} else if sitemapURL := urls[len(urls)-1]; lastMod.After(sitemapURL.LastMod) {
+			sitemapURL.LastMod = lastMod
+		}
+	}
+	return urls, nil
+}
+
+// listPhotoURLs lists the pages of photos starting from the offset-th page, photos of a page are its images
+func (s *sitemapServiceImpl) listPhotoURLs(ctx context.Context, offset int) ([]*dto.SitemapURL, error) {
+	photoPrefix, err := s.OptionService.GetPhotoPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := s.OptionService.GetOrByDefault(ctx, property.PhotoPageSize).(int)
+	photoDAL := dal.GetQueryByCtx(ctx).Photo
+	photos, err := photoDAL.WithContext(ctx).Select(photoDAL.CreateTime, photoDAL.UpdateTime, photoDAL.URL).
+		Order(photoDAL.CreateTime.Desc()).Offset(offset * pageSize).Limit(consts.SitemapPageSize * pageSize).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	urls := make([]*dto.SitemapURL, 0)
+	for i, photo := range photos {
+		lastMod := latestOf(photo.CreateTime, photo.UpdateTime)
+		if i%pageSize == 0 {
+			urls = append(urls, &dto.SitemapURL{Loc: pagePath(photoPrefix, offset+i/pageSize+1), LastMod: lastMod})
+		}
+		sitemapURL := urls[len(urls)-1]
+		if lastMod.After(sitemapURL.LastMod) {
+			sitemapURL.LastMod = lastMod
+		}
+		if photo.URL != "" && len(sitemapURL.Images) < consts.SitemapImageLimit {
+			sitemapURL.Images = append(sitemapURL.Images, photo.URL)
+		}
+	}
+	return urls, nil
+}
+
+// sitemapLanguage returns the languages of the blog and the path prefix of the language the sitemaps are asked for,
+// which is empty for the default language
+func (s *sitemapServiceImpl) sitemapLanguage(ctx context.Context) ([]string, string, error) {
+	languages, err := s.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	language := requestLanguage(ctx)
+	if language == "" || language == languages[0] {
+		return languages, "", nil
+	}
+	return languages, "/" + language, nil
+}
+
+// languageConditions returns the conditions keeping the contents in the language of the sitemaps
+func (s *sitemapServiceImpl) languageConditions(ctx context.Context, translationType consts.TranslationType, id field.Int32) ([]gen.Condition, error) {
+	filter, err := newLanguageFilter(ctx, s.OptionService, translationType, requestLanguage(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return []gen.Condition{}, nil
+	}
+	return []gen.Condition{filter.condition(id)}, nil
+}
+
+// listAlternates lists the published translations of each content for the hreflang links, contents without translations have none
+func (s *sitemapServiceImpl) listAlternates(ctx context.Context, translationType consts.TranslationType, contentIDs []int32) (map[int32][]*dto.SitemapAlternate, error) {
+	result := make(map[int32][]*dto.SitemapAlternate)
+	languages, err := s.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(languages) <= 1 || len(contentIDs) == 0 {
+		return result, nil
+	}
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	translations, err := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.ContentID.In(contentIDs...)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	if len(translations) == 0 {
+		return result, nil
+	}
+	groupIDs := make([]int32, 0, len(translations))
+	for _, translation := range translations {
+		groupIDs = append(groupIDs, translation.GroupID)
+	}
+	members, err := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.GroupID.In(groupIDs...)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	memberIDs := make([]int32, 0, len(members))
+	groups := make(map[int32][]*entity.Translation)
+	for _, member := range members {
+		if !slices.Contains(languages, member.Language) {
+			continue
+		}
+		memberIDs = append(memberIDs, member.ContentID)
+		groups[member.GroupID] = append(groups[member.GroupID], member)
+	}
+	paths := make(map[int32]string, len(memberIDs))
+	switch translationType {
+	case consts.TranslationTypePost:
+		postDAL := dal.GetQueryByCtx(ctx).Post
+		posts, err := postDAL.WithContext(ctx).Where(postDAL.ID.In(memberIDs...), postDAL.Status.Eq(consts.PostStatusPublished)).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		if paths, err = s.PostService.BuildFullPaths(ctx, posts); err != nil {
+			return nil, err
+		}
+	case consts.TranslationTypeCategory:
+		categoryDAL := dal.GetQueryByCtx(ctx).Category
+		categories, err := categoryDAL.WithContext(ctx).Where(categoryDAL.ID.In(memberIDs...), categoryDAL.Type.Neq(consts.CategoryTypeIntimate)).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		categoryDTOs, err := s.CategoryService.ConvertToCategoryDTOs(ctx, categories)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categoryDTOs {
+			paths[category.ID] = category.FullPath
+		}
+	}
+	for _, translation := range translations {
+		alternates := make([]*dto.SitemapAlternate, 0)
+		var defaultHref string
+		for _, member := range groups[translation.GroupID] {
+			href, ok := paths[member.ContentID]
+			if !ok {
+				continue
+			}
+			alternates = append(alternates, &dto.SitemapAlternate{Language: member.Language, Href: href})
+			if member.Language == languages[0] {
+				defaultHref = href
+			}
+		}
+		if len(alternates) <= 1 {
+			continue
+		}
+		sort.SliceStable(alternates, func(i, j int) bool {
+			return slices.Index(languages, alternates[i].Language) < slices.Index(languages, alternates[j].Language)
+		})
+		if defaultHref != "" {
+			alternates = append(alternates, &dto.SitemapAlternate{Language: "x-default", Href: defaultHref})
+		}
+		result[translation.ContentID] = alternates
+	}
+	return result, nil
+}
+
+func sitemapPath(sitemapType consts.SitemapType, page int) string {
+	return fmt.Sprintf("/sitemap/%s-%d.xml", sitemapType, page)
+}
+
+func pagePath(prefix string, page int) string {
+	if page == 1 {
+		return "/" + prefix
+	}
+	return "/" + prefix + "/page/" + strconv.Itoa(page)
+}
+
+// languagePath puts the path under the prefix of its language, the home page of the language has no trailing slash
+func languagePath(pathPrefix, path string) string {
+	if pathPrefix != "" && path == "/" {
+		return pathPrefix
+	}
+	return pathPrefix + path
+}
+
+func pageCount(count int64, pageSize int) int64 {
+	if pageSize <= 0 {
+		return 0
+	}
+	return (count + int64(pageSize) - 1) / int64(pageSize)
+}
+
+func postTypeOf(sitemapType consts.SitemapType) consts.PostType {
+	if sitemapType == consts.SitemapTypeSheets {
+		return consts.PostTypeSheet
+	}
+	return consts.PostTypePost
+}
+
+func postLastMod(post *entity.Post) time.Time {
+	if post.EditTime != nil {
+		return *post.EditTime
+	}
+	return latestOf(post.CreateTime, post.UpdateTime)
+}
+
+func latestOf(createTime time.Time, updateTime *time.Time) time.Time {
+	if updateTime != nil && updateTime.After(createTime) {
+		return *updateTime
+	}
+	return createTime
+}
+
+// postImages returns the thumbnail and the images of the content of the post
+func postImages(post *entity.Post) []string {
+	images := make([]string, 0)
+	exist := make(map[string]struct{})
+	add := func(image string) {
+		if _, ok := exist[image]; ok || image == "" || strings.HasPrefix(image, "data:") {
+			return
+		}
+		exist[image] = struct{}{}
+		images = append(images, image)
+	}
+	add(post.Thumbnail)
+	for _, match := range imgSrcRegexp.FindAllStringSubmatch(post.FormatContent, -1) {
+		if len(images) >= consts.SitemapImageLimit {
+			break
+		}
+		add(html.UnescapeString(match[1]))
+	}
+	return images
+}
+
+func absoluteURL(blogURL, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if strings.HasPrefix(path, "//") {
+		if u, err := url.Parse(blogURL); err == nil && u.Scheme != "" {
+			return u.Scheme + ":" + path
+		}
+		return "https:" + path
+	}
+	return blogURL + "/" + strings.TrimPrefix(path, "/")
+}
@@ -230,3 +230,27 @@ func (t tagServiceImpl) GetByName(ctx context.Context, name string) (*entity.Tag
 	tag, err := tagDAL.WithContext(ctx).Where(tagDAL.Name.Eq(name)).First()
 	return tag, WrapDBErr(err)
 }
+
+// resolveTagIDs gets the tags by the names, the missing ones are created
+func resolveTagIDs(ctx context.Context, tagService service.TagService, names []string) ([]int32, error) {
+	tagIDs := make([]int32, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tag, err := tagService.GetByName(ctx, name)
+		if xerr.GetType(err) == xerr.NoRecord {
+			// the slug may be taken by a tag named differently, e.g. "Go" and "go"
+			tag, err = tagService.GetBySlug(ctx, util.Slug(name))
+		}
+		if xerr.GetType(err) == xerr.NoRecord {
+			tag, err = tagService.Create(ctx, &param.Tag{Name: name, Slug: util.Slug(name)})
+		}
+		if err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return tagIDs, nil
+}
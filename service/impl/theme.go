@@ -2,16 +2,21 @@ package impl
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"math"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"go.uber.org/fx"
+	"gopkg.in/yaml.v2"
 
 	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/dal"
 	"github.com/go-sonic/sonic/event"
 	"github.com/go-sonic/sonic/model/dto"
@@ -19,6 +24,7 @@ import (
 	"github.com/go-sonic/sonic/model/property"
 	"github.com/go-sonic/sonic/service"
 	"github.com/go-sonic/sonic/service/theme"
+	"github.com/go-sonic/sonic/template"
 	"github.com/go-sonic/sonic/util"
 	"github.com/go-sonic/sonic/util/xerr"
 )
@@ -30,6 +36,8 @@ type themeServiceImpl struct {
 	PropertyScanner theme.PropertyScanner
 	FileScanner     theme.FileScanner
 	ThemeFetchers   themeFetchers
+	ThemeUpdater    theme.ThemeUpdater
+	Template        *template.Template
 }
 
 type themeFetchers struct {
@@ -38,7 +46,7 @@ type themeFetchers struct {
 	GitRepoThemeFetcher      theme.ThemeFetcher `name:"gitRepoThemeFetcher"`
 }
 
-func NewThemeService(optionService service.OptionService, config *config.Config, event event.Bus, propertyScanner theme.PropertyScanner, fileScanner theme.FileScanner, themeFetcher themeFetchers) service.ThemeService {
+func NewThemeService(optionService service.OptionService, config *config.Config, event event.Bus, propertyScanner theme.PropertyScanner, fileScanner theme.FileScanner, themeFetcher themeFetchers, themeUpdater theme.ThemeUpdater, template *template.Template) service.ThemeService {
 	return &themeServiceImpl{
 		OptionService:   optionService,
 		Config:          config,
@@ -46,6 +54,8 @@ func NewThemeService(optionService service.OptionService, config *config.Config,
 		PropertyScanner: propertyScanner,
 		FileScanner:     fileScanner,
 		ThemeFetchers:   themeFetcher,
+		ThemeUpdater:    themeUpdater,
+		Template:        template,
 	}
 }
 
@@ -208,7 +218,15 @@ func (t *themeServiceImpl) ListCustomTemplates(ctx context.Context, themeID, pre
 }
 
 func (t *themeServiceImpl) ActivateTheme(ctx context.Context, themeID string) (*dto.ThemeProperty, error) {
-	err := t.OptionService.Save(ctx, map[string]string{property.Theme.KeyValue: themeID})
+	themeProperty, err := t.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	validation := t.validateTheme(ctx, themeProperty)
+	if !validation.Valid {
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("theme is invalid: " + strings.Join(validation.Errors, "; "))
+	}
+	err = t.OptionService.Save(ctx, map[string]string{property.Theme.KeyValue: themeID})
 	if err != nil {
 		return nil, err
 	}
@@ -313,6 +331,11 @@ func (t *themeServiceImpl) SaveThemeSettings(ctx context.Context, themeID string
 		if !ok {
 			return xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("setting name invalid: " + name)
 		}
+		convertedValue, err := convertThemeSettingValue(item, value)
+		if err != nil {
+			return xerr.WithErrMsgf(err, "value=%v type invalid ,setting name=%s", value, name).WithStatus(xerr.StatusBadRequest)
+		}
+		value = convertedValue
 		if themeSetting, ok := allThemeSettingMap[name]; ok {
 			if value == "" {
 				toDeleteIDs = append(toDeleteIDs, themeSetting.ID)
@@ -470,6 +493,9 @@ func (t *themeServiceImpl) addTheme(ctx context.Context, themeProperty *dto.Them
 }
 
 func (t *themeServiceImpl) Render(ctx context.Context, name string) (string, error) {
+	if preview, ok := ctx.Value(consts.ThemePreview).(*dto.ThemePreview); ok {
+		return preview.ThemeID + "/" + name, nil
+	}
 	activatedThemeID, err := t.OptionService.GetActivatedThemeID(ctx)
 	if err != nil {
 		return "", err
@@ -484,3 +510,234 @@ func (t *themeServiceImpl) Fetch(ctx context.Context, themeURL string) (*dto.The
 	}
 	return t.addTheme(ctx, fetchTheme)
 }
+
+func (t *themeServiceImpl) CheckThemeUpdate(ctx context.Context, themeID string) (*dto.ThemeUpdate, error) {
+	themeProperty, err := t.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	return t.ThemeUpdater.CheckUpdate(ctx, themeProperty)
+}
+
+func (t *themeServiceImpl) UpdateThemeByFetching(ctx context.Context, themeID string) (*dto.ThemeProperty, error) {
+	oldThemeProperty, err := t.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	newThemeProperty, err := t.ThemeUpdater.FetchUpdate(ctx, oldThemeProperty)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(newThemeProperty.ThemePath)
+
+	if newThemeProperty.ID != oldThemeProperty.ID {
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("theme id of the new version is " + newThemeProperty.ID)
+	}
+	return t.replaceTheme(ctx, oldThemeProperty, newThemeProperty.ThemePath)
+}
+
+// replaceTheme updates the files of an installed theme in place. The theme settings are kept because
+// they are stored by theme id. The new version is parsed before the files are replaced, and the previous
+// version is restored if the replaced files fail to parse.
+func (t *themeServiceImpl) replaceTheme(ctx context.Context, oldThemeProperty *dto.ThemeProperty, newThemePath string) (*dto.ThemeProperty, error) {
+	// the watcher reloads the theme as soon as the files change, so a broken version must not be copied in
+	stagedThemeProperty, err := t.PropertyScanner.ReadThemeProperty(ctx, newThemePath)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("new theme version is invalid: " + err.Error())
+	}
+	if validation := t.validateTheme(ctx, stagedThemeProperty); !validation.Valid {
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("new theme version is invalid: " + strings.Join(validation.Errors, "; "))
+	}
+
+	themePath := oldThemeProperty.ThemePath
+	backupDir, err := os.MkdirTemp("", "sonic-theme-backup-*")
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("create theme backup directory err")
+	}
+	defer os.RemoveAll(backupDir)
+	if err = util.CopyDir(themePath, backupDir); err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("backup theme err")
+	}
+
+	rollback := func(cause error) error {
+		if err := os.RemoveAll(themePath); err != nil {
+			return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("rollback theme err")
+		}
+		if err := util.CopyDir(backupDir, themePath); err != nil {
+			return xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("rollback theme err")
+		}
+		return cause
+	}
+
+	if err = os.RemoveAll(themePath); err != nil {
+		return nil, rollback(xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("delete old theme err"))
+	}
+	if err = util.CopyDir(newThemePath, themePath); err != nil {
+		return nil, rollback(xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("copy new theme err"))
+	}
+	newThemeProperty, err := t.PropertyScanner.ReadThemeProperty(ctx, themePath)
+	if err != nil {
+		return nil, rollback(xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("new theme version is invalid, rolled back: " + err.Error()))
+	}
+	if validation := t.validateTheme(ctx, newThemeProperty); !validation.Valid {
+		return nil, rollback(xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("new theme version is invalid, rolled back: " + strings.Join(validation.Errors, "; ")))
+	}
+
+	if oldThemeProperty.Activated {
+		newThemeProperty.Activated = true
+		if err = t.ReloadTheme(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return newThemeProperty, nil
+}
+
+func (t *themeServiceImpl) ValidateTheme(ctx context.Context, themeID string) (*dto.ThemeValidation, error) {
+	themeProperty, err := t.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	return t.validateTheme(ctx, themeProperty), nil
+}
+
+// validateTheme parses the settings and all templates of the theme the same way they are loaded on activation
+func (t *themeServiceImpl) validateTheme(ctx context.Context, themeProperty *dto.ThemeProperty) *dto.ThemeValidation {
+	validation := &dto.ThemeValidation{
+		ThemeID: themeProperty.ID,
+		Errors:  make([]string, 0),
+	}
+	if themeProperty.HasOptions {
+		if _, err := t.PropertyScanner.ReadThemeConfig(ctx, themeProperty.ThemePath); err != nil {
+			validation.Errors = append(validation.Errors, "settings.yaml: "+err.Error())
+		}
+	}
+	htmlTemplate, err := t.Template.Parse(t.GetThemeTemplatePaths(ctx, themeProperty))
+	if err != nil {
+		validation.Errors = append(validation.Errors, err.Error())
+	} else {
+		for _, name := range consts.ThemeRequiredTemplates {
+			if htmlTemplate.Lookup(themeProperty.ID+"/"+name) == nil {
+				validation.Errors = append(validation.Errors, "missing template: "+themeProperty.ID+"/"+name)
+			}
+		}
+	}
+	validation.Valid = len(validation.Errors) == 0
+	return validation
+}
+
+func (t *themeServiceImpl) GetThemeTemplatePaths(ctx context.Context, themeProperty *dto.ThemeProperty) []string {
+	return []string{filepath.Join(t.Config.Sonic.TemplateDir, "common"), themeProperty.ThemePath}
+}
+
+func (t *themeServiceImpl) ExportThemeSettings(ctx context.Context, themeID, format string) ([]byte, error) {
+	themeProperty, err := t.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	export := &dto.ThemeSettingExport{
+		ThemeID:      themeProperty.ID,
+		ThemeVersion: themeProperty.Version,
+		Settings:     map[string]interface{}{},
+	}
+	if themeProperty.HasOptions {
+		export.Settings, err = t.GetThemeSettingMap(ctx, themeID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var content []byte
+	switch format {
+	case "json":
+		content, err = json.MarshalIndent(export, "", "  ")
+	case "", "yaml":
+		content, err = yaml.Marshal(export)
+	default:
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("unsupported format: " + format)
+	}
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("marshal theme settings err")
+	}
+	return content, nil
+}
+
+func (t *themeServiceImpl) ImportThemeSettings(ctx context.Context, themeID string, content []byte) (*dto.ThemeSettingImport, error) {
+	export := &dto.ThemeSettingExport{}
+	var err error
+	if json.Valid(content) {
+		err = json.Unmarshal(content, export)
+	} else {
+		err = yaml.Unmarshal(content, export)
+	}
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("theme settings file is invalid")
+	}
+	settings, skipped, err := t.ConvertThemeSettings(ctx, themeID, export.Settings)
+	if err != nil {
+		return nil, err
+	}
+	err = t.SaveThemeSettings(ctx, themeID, settings)
+	if err != nil {
+		return nil, err
+	}
+	imported := make([]string, 0, len(settings))
+	for name := range settings {
+		imported = append(imported, name)
+	}
+	sort.Strings(imported)
+	return &dto.ThemeSettingImport{Imported: imported, Skipped: skipped}, nil
+}
+
+func (t *themeServiceImpl) ConvertThemeSettings(ctx context.Context, themeID string, settings map[string]interface{}) (map[string]interface{}, []string, error) {
+	itemMap, err := t.getThemeConfigItemMap(ctx, themeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make(map[string]interface{}, len(settings))
+	skipped := make([]string, 0)
+	invalid := make([]string, 0)
+	for name, value := range settings {
+		item, ok := itemMap[name]
+		if !ok {
+			skipped = append(skipped, name)
+			continue
+		}
+		value, err := convertThemeSettingValue(item, value)
+		if err != nil {
+			invalid = append(invalid, name)
+			continue
+		}
+		result[name] = value
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("setting value type invalid: " + strings.Join(invalid, ", "))
+	}
+	sort.Strings(skipped)
+	return result, skipped, nil
+}
+
+// convertThemeSettingValue also accepts values in their string form and numbers decoded from json as float64,
+// which is how they come back from exported files and presets
+func convertThemeSettingValue(item *dto.ThemeConfigItem, value interface{}) (interface{}, error) {
+	switch data := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		if data == "" || item.DataType == consts.ThemeConfigDataTypeString {
+			return data, nil
+		}
+		return item.DataType.Convert(data)
+	case float64:
+		if item.DataType == consts.ThemeConfigDataTypeLong && data == math.Trunc(data) {
+			value = int64(data)
+		}
+	case int:
+		if item.DataType == consts.ThemeConfigDataTypeDouble {
+			value = float64(data)
+		}
+	}
+	if _, err := item.DataType.FormatToStr(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
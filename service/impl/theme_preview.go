@@ -0,0 +1,156 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-sonic/sonic/cache"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type themePreview struct {
+	Preview  *dto.ThemePreview
+	Template *template.Template
+}
+
+type themePreviewServiceImpl struct {
+	ThemeService  service.ThemeService
+	OptionService service.OptionService
+	Template      *template.Template
+	Cache         cache.Cache
+}
+
+func NewThemePreviewService(themeService service.ThemeService, optionService service.OptionService, template *template.Template, cache cache.Cache) service.ThemePreviewService {
+	return &themePreviewServiceImpl{
+		ThemeService:  themeService,
+		OptionService: optionService,
+		Template:      template,
+		Cache:         cache,
+	}
+}
+
+func (t *themePreviewServiceImpl) CreatePreview(ctx context.Context, themeID string, settings map[string]interface{}) (*dto.ThemePreview, error) {
+	user, err := MustGetAuthorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	validation, err := t.ThemeService.ValidateTheme(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	if !validation.Valid {
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("theme is invalid: " + strings.Join(validation.Errors, "; "))
+	}
+	themeProperty, err := t.ThemeService.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	themeProperty.Activated = false
+
+	themeSettings, err := t.mergeDraftSettings(ctx, themeProperty, settings)
+	if err != nil {
+		return nil, err
+	}
+	isEnabledAbsolutePath, err := t.OptionService.IsEnabledAbsolutePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blogBaseURL, err := t.OptionService.GetBlogBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	themeBasePath := "/themes/" + themeProperty.FolderName
+	if isEnabledAbsolutePath {
+		themeBasePath = blogBaseURL + themeBasePath
+	}
+
+	previewTemplate, err := t.Template.Fork(t.ThemeService.GetThemeTemplatePaths(ctx, themeProperty), map[string]any{
+		"theme_base": themeBasePath,
+		"theme":      themeProperty,
+		"settings":   themeSettings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	token := util.GenUUIDWithOutDash()
+	preview := &dto.ThemePreview{
+		Token:      token,
+		ThemeID:    themeProperty.ID,
+		UserID:     user.ID,
+		URL:        blogBaseURL + "/?" + consts.ThemePreviewQueryName + "=" + token,
+		ExpireTime: time.Now().Add(consts.ThemePreviewValidDuration).UnixMilli(),
+		// the secret only grants the preview, the access token of the admin is never put in a cookie
+		AdminSecret: util.GenUUIDWithOutDash(),
+	}
+	t.Cache.Set(cache.BuildThemePreviewKey(token), &themePreview{Preview: preview, Template: previewTemplate}, consts.ThemePreviewValidDuration)
+	t.Cache.Set(cache.BuildThemePreviewAdminKey(preview.AdminSecret), user.ID, consts.ThemePreviewValidDuration)
+	return preview, nil
+}
+
+func (t *themePreviewServiceImpl) GetPreview(ctx context.Context, token string) (*dto.ThemePreview, *template.Template, bool) {
+	value, ok := t.Cache.Get(cache.BuildThemePreviewKey(token))
+	if !ok {
+		return nil, nil, false
+	}
+	preview := value.(*themePreview)
+	return preview.Preview, preview.Template, true
+}
+
+func (t *themePreviewServiceImpl) DeletePreview(ctx context.Context, token string) {
+	t.Cache.Delete(cache.BuildThemePreviewKey(token))
+}
+
+// mergeDraftSettings converts the draft settings the same way SaveThemeSettings does, an empty value falls back to the default
+func (t *themePreviewServiceImpl) mergeDraftSettings(ctx context.Context, themeProperty *dto.ThemeProperty, settings map[string]interface{}) (map[string]interface{}, error) {
+	if !themeProperty.HasOptions {
+		if len(settings) > 0 {
+			return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("theme has no settings")
+		}
+		return map[string]interface{}{}, nil
+	}
+	themeSettings, err := t.ThemeService.GetThemeSettingMap(ctx, themeProperty.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return themeSettings, nil
+	}
+	themeConfig, err := t.ThemeService.GetThemeConfig(ctx, themeProperty.ID)
+	if err != nil {
+		return nil, err
+	}
+	itemMap := make(map[string]*dto.ThemeConfigItem)
+	for _, group := range themeConfig {
+		for _, item := range group.Items {
+			itemMap[item.Name] = item
+		}
+	}
+	for name, value := range settings {
+		item, ok := itemMap[name]
+		if !ok {
+			return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("setting name invalid: " + name)
+		}
+		if value == "" {
+			delete(themeSettings, name)
+			if item.DefaultValue != nil {
+				themeSettings[name] = item.DefaultValue
+			}
+			continue
+		}
+		valueStr, err := item.DataType.FormatToStr(value)
+		if err != nil {
+			return nil, xerr.WithErrMsgf(err, "value=%s type invalid ,setting name=%s", value, name).WithStatus(xerr.StatusBadRequest)
+		}
+		themeSettings[name], err = item.DataType.Convert(valueStr)
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusBadRequest)
+		}
+	}
+	return themeSettings, nil
+}
@@ -0,0 +1,203 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type themeSettingPresetServiceImpl struct {
+	ThemeService service.ThemeService
+}
+
+func NewThemeSettingPresetService(themeService service.ThemeService) service.ThemeSettingPresetService {
+	return &themeSettingPresetServiceImpl{
+		ThemeService: themeService,
+	}
+}
+
+func (t *themeSettingPresetServiceImpl) ListPresets(ctx context.Context, themeID string) ([]*dto.ThemeSettingPreset, error) {
+	presetDAL := dal.GetQueryByCtx(ctx).ThemeSettingPreset
+	presets, err := presetDAL.WithContext(ctx).Where(presetDAL.ThemeID.Eq(themeID)).Order(presetDAL.Name).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	if len(presets) == 0 {
+		return make([]*dto.ThemeSettingPreset, 0), nil
+	}
+	configGroups, err := t.ThemeService.GetThemeConfig(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := t.ThemeService.GetThemeSettingMap(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.ThemeSettingPreset, 0, len(presets))
+	for _, preset := range presets {
+		presetDTO, err := t.convertToDTO(preset)
+		if err != nil {
+			return nil, err
+		}
+		presetDTO.Active = len(diffSettings(configGroups, current, presetDTO.Settings)) == 0
+		result = append(result, presetDTO)
+	}
+	return result, nil
+}
+
+func (t *themeSettingPresetServiceImpl) SavePreset(ctx context.Context, themeID, name string) (*dto.ThemeSettingPreset, error) {
+	settings, err := t.ThemeService.GetThemeSettingMap(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("marshal theme settings err")
+	}
+
+	presetDAL := dal.GetQueryByCtx(ctx).ThemeSettingPreset
+	preset, err := presetDAL.WithContext(ctx).Where(presetDAL.ThemeID.Eq(themeID), presetDAL.Name.Eq(name)).Take()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, WrapDBErr(err)
+	}
+	if preset == nil {
+		preset = &entity.ThemeSettingPreset{
+			ThemeID:  themeID,
+			Name:     name,
+			Settings: string(settingsJSON),
+		}
+		err = presetDAL.WithContext(ctx).Create(preset)
+	} else {
+		preset.Settings = string(settingsJSON)
+		_, err = presetDAL.WithContext(ctx).Where(presetDAL.ID.Eq(preset.ID)).Select(field.Star).Omit(presetDAL.CreateTime).Updates(preset)
+	}
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	presetDTO, err := t.convertToDTO(preset)
+	if err != nil {
+		return nil, err
+	}
+	presetDTO.Active = true
+	return presetDTO, nil
+}
+
+func (t *themeSettingPresetServiceImpl) ApplyPreset(ctx context.Context, themeID string, presetID int32) error {
+	preset, err := t.getPreset(ctx, themeID, presetID)
+	if err != nil {
+		return err
+	}
+	settings, _, err := t.ThemeService.ConvertThemeSettings(ctx, themeID, preset.Settings)
+	if err != nil {
+		return err
+	}
+	configGroups, err := t.ThemeService.GetThemeConfig(ctx, themeID)
+	if err != nil {
+		return err
+	}
+	for _, group := range configGroups {
+		for _, item := range group.Items {
+			if _, ok := settings[item.Name]; !ok {
+				// an empty value removes the saved setting, so the default is used
+				settings[item.Name] = ""
+			}
+		}
+	}
+	return t.ThemeService.SaveThemeSettings(ctx, themeID, settings)
+}
+
+func (t *themeSettingPresetServiceImpl) DeletePreset(ctx context.Context, themeID string, presetID int32) error {
+	presetDAL := dal.GetQueryByCtx(ctx).ThemeSettingPreset
+	_, err := presetDAL.WithContext(ctx).Where(presetDAL.ID.Eq(presetID), presetDAL.ThemeID.Eq(themeID)).Delete()
+	return WrapDBErr(err)
+}
+
+func (t *themeSettingPresetServiceImpl) DiffPreset(ctx context.Context, themeID string, presetID int32) ([]*dto.ThemeSettingDiff, error) {
+	preset, err := t.getPreset(ctx, themeID, presetID)
+	if err != nil {
+		return nil, err
+	}
+	configGroups, err := t.ThemeService.GetThemeConfig(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := t.ThemeService.GetThemeSettingMap(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	return diffSettings(configGroups, current, preset.Settings), nil
+}
+
+func (t *themeSettingPresetServiceImpl) getPreset(ctx context.Context, themeID string, presetID int32) (*dto.ThemeSettingPreset, error) {
+	presetDAL := dal.GetQueryByCtx(ctx).ThemeSettingPreset
+	preset, err := presetDAL.WithContext(ctx).Where(presetDAL.ID.Eq(presetID), presetDAL.ThemeID.Eq(themeID)).Take()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return t.convertToDTO(preset)
+}
+
+func (t *themeSettingPresetServiceImpl) convertToDTO(preset *entity.ThemeSettingPreset) (*dto.ThemeSettingPreset, error) {
+	settings := make(map[string]interface{})
+	err := json.Unmarshal([]byte(preset.Settings), &settings)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("unmarshal theme setting preset err")
+	}
+	presetDTO := &dto.ThemeSettingPreset{
+		ID:         preset.ID,
+		ThemeID:    preset.ThemeID,
+		Name:       preset.Name,
+		Settings:   settings,
+		CreateTime: preset.CreateTime.UnixMilli(),
+	}
+	if preset.UpdateTime != nil {
+		presetDTO.UpdateTime = preset.UpdateTime.UnixMilli()
+	}
+	return presetDTO, nil
+}
+
+// diffSettings lists the config items whose current value differs from the value the preset would set
+func diffSettings(configGroups []*dto.ThemeConfigGroup, current, preset map[string]interface{}) []*dto.ThemeSettingDiff {
+	groups := append([]*dto.ThemeConfigGroup(nil), configGroups...)
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	diffs := make([]*dto.ThemeSettingDiff, 0)
+	for _, group := range groups {
+		items := append([]*dto.ThemeConfigItem(nil), group.Items...)
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Name < items[j].Name
+		})
+		for _, item := range items {
+			presetValue, ok := preset[item.Name]
+			if !ok {
+				presetValue = item.DefaultValue
+			}
+			if convertedValue, err := convertThemeSettingValue(item, presetValue); err == nil {
+				presetValue = convertedValue
+			}
+			if fmt.Sprint(current[item.Name]) == fmt.Sprint(presetValue) {
+				continue
+			}
+			diffs = append(diffs, &dto.ThemeSettingDiff{
+				Group:   group.Name,
+				Name:    item.Name,
+				Label:   item.Label,
+				Current: current[item.Name],
+				Preset:  presetValue,
+			})
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,506 @@
+package impl
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"gorm.io/gen/field"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/dal"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type translationServiceImpl struct {
+	OptionService   service.OptionService
+	BasePostService service.BasePostService
+	CategoryService service.CategoryService
+}
+
+func NewTranslationService(optionService service.OptionService, basePostService service.BasePostService, categoryService service.CategoryService) service.TranslationService {
+	return &translationServiceImpl{
+		OptionService:   optionService,
+		BasePostService: basePostService,
+		CategoryService: categoryService,
+	}
+}
+
+func (t *translationServiceImpl) ListLanguages(ctx context.Context) ([]*dto.Language, error) {
+	languages, err := t.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	basePath := ""
+	isEnabled, err := t.OptionService.IsEnabledAbsolutePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isEnabled {
+		basePath, err = t.OptionService.GetBlogBaseURL(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := make([]*dto.Language, 0, len(languages))
+	for i, language := range languages {
+		fullPath := basePath + "/"
+		if i > 0 {
+			fullPath += language
+		}
+		result = append(result, &dto.Language{
+			Language: language,
+			Default:  i == 0,
+			FullPath: fullPath,
+		})
+	}
+	return result, nil
+}
+
+func (t *translationServiceImpl) GetLanguage(ctx context.Context, translationType consts.TranslationType, contentID int32) (string, error) {
+	languages, err := t.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return "", err
+	}
+	translation, err := getTranslation(ctx, translationType, contentID)
+	if err != nil {
+		return "", err
+	}
+	if translation == nil {
+		return languages[0], nil
+	}
+	return translation.Language, nil
+}
+
+func (t *translationServiceImpl) Link(ctx context.Context, translationType consts.TranslationType, contentID int32, language string, translationOfPtr *int32) error {
+	languages, err := t.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return err
+	}
+	if language == "" {
+		language = languages[0]
+	}
+	if !slices.Contains(languages, language) {
+		return xerr.BadParam.New("language=%s", language).WithStatus(xerr.StatusBadRequest).WithMsg("The language is not one of the blog languages")
+	}
+	var translationOf int32
+	if translationOfPtr != nil {
+		translationOf = *translationOfPtr
+	}
+	if translationOf == contentID {
+		return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("The content can not be the translation of itself")
+	}
+	if err := t.checkContent(ctx, translationType, contentID, translationOf); err != nil {
+		return err
+	}
+	return dal.Transaction(ctx, func(txCtx context.Context) error {
+		translationDAL := dal.GetQueryByCtx(txCtx).Translation
+		translation, err := getTranslation(txCtx, translationType, contentID)
+		if err != nil {
+			return err
+		}
+		if translationOfPtr == nil && translation != nil {
+			// stays with its translations, one of them is taken as the content it translates
+			other, err := translationDAL.WithContext(txCtx).Where(translationDAL.Type.Eq(translationType), translationDAL.GroupID.Eq(translation.GroupID),
+				translationDAL.ContentID.Neq(contentID)).Take()
+			if err != nil && xerr.GetType(WrapDBErr(err)) != xerr.NoRecord {
+				return WrapDBErr(err)
+			}
+			if other != nil {
+				translationOf = other.ContentID
+			}
+		}
+		if translationOf == 0 && language == languages[0] {
+			if translation == nil {
+				return nil
+			}
+			_, err := translationDAL.WithContext(txCtx).Where(translationDAL.ID.Eq(translation.ID)).Delete()
+			return WrapDBErr(err)
+		}
+
+		var groupID int32
+		if translationOf != 0 {
+			target, err := getTranslation(txCtx, translationType, translationOf)
+			if err != nil {
+				return err
+			}
+			if target == nil {
+				if groupID, err = nextTranslationGroupID(txCtx); err != nil {
+					return err
+				}
+				target = &entity.Translation{
+					Type:      translationType,
+					ContentID: translationOf,
+					Language:  languages[0],
+					GroupID:   groupID,
+				}
+				if err := translationDAL.WithContext(txCtx).Create(target); err != nil {
+					return WrapDBErr(err)
+				}
+			}
+			groupID = target.GroupID
+			count, err := translationDAL.WithContext(txCtx).Where(translationDAL.Type.Eq(translationType), translationDAL.GroupID.Eq(groupID),
+				translationDAL.Language.Eq(language), translationDAL.ContentID.Neq(contentID)).Count()
+			if err != nil {
+				return WrapDBErr(err)
+			}
+			if count > 0 {
+				return xerr.BadParam.New("language=%s", language).WithStatus(xerr.StatusBadRequest).WithMsg("The translation in the language already exists")
+			}
+		} else if translation != nil {
+			count, err := translationDAL.WithContext(txCtx).Where(translationDAL.Type.Eq(translationType), translationDAL.GroupID.Eq(translation.GroupID)).Count()
+			if err != nil {
+				return WrapDBErr(err)
+			}
+			// a content alone keeps its group, otherwise it leaves the group of its translations
+			if count == 1 {
+				groupID = translation.GroupID
+			}
+		}
+		if groupID == 0 {
+			if groupID, err = nextTranslationGroupID(txCtx); err != nil {
+				return err
+			}
+		}
+
+		if translation == nil {
+			return WrapDBErr(translationDAL.WithContext(txCtx).Create(&entity.Translation{
+				Type:      translationType,
+				ContentID: contentID,
+				Language:  language,
+				GroupID:   groupID,
+			}))
+		}
+		_, err = translationDAL.WithContext(txCtx).Where(translationDAL.ID.Eq(translation.ID)).UpdateSimple(
+			translationDAL.Language.Value(language),
+			translationDAL.GroupID.Value(groupID),
+		)
+		return WrapDBErr(err)
+	})
+}
+
+func (t *translationServiceImpl) ListTranslations(ctx context.Context, translationType consts.TranslationType, contentID int32, onlyPublished bool) ([]*dto.Translation, error) {
+	languages, err := t.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	translation, err := getTranslation(ctx, translationType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	var translations []*entity.Translation
+	if translation == nil {
+		translations = []*entity.Translation{{Type: translationType, ContentID: contentID, Language: languages[0]}}
+	} else {
+		translationDAL := dal.GetQueryByCtx(ctx).Translation
+		translations, err = translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.GroupID.Eq(translation.GroupID)).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+	}
+	// the versions are in the order of the blog languages, the ones in other languages come last
+	languageIndex := func(language string) int {
+		if i := slices.Index(languages, language); i >= 0 {
+			return i
+		}
+		return len(languages)
+	}
+	sort.SliceStable(translations, func(i, j int) bool {
+		return languageIndex(translations[i].Language) < languageIndex(translations[j].Language)
+	})
+
+	contentIDs := make([]int32, 0, len(translations))
+	for _, translation := range translations {
+		contentIDs = append(contentIDs, translation.ContentID)
+	}
+	result := make([]*dto.Translation, 0, len(translations))
+	add := func(id int32, title, fullPath string) {
+		for _, translation := range translations {
+			if translation.ContentID == id {
+				result = append(result, &dto.Translation{
+					Type:     translationType,
+					ID:       id,
+					Language: translation.Language,
+					Title:    title,
+					FullPath: fullPath,
+					Current:  id == contentID,
+				})
+				return
+			}
+		}
+	}
+	switch translationType {
+	case consts.TranslationTypePost:
+		posts, err := t.BasePostService.GetByPostIDs(ctx, contentIDs)
+		if err != nil {
+			return nil, err
+		}
+		postList := make([]*entity.Post, 0, len(posts))
+		for _, post := range posts {
+			postList = append(postList, post)
+		}
+		fullPaths, err := t.BasePostService.BuildFullPaths(ctx, postList)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range contentIDs {
+			post, ok := posts[id]
+			if !ok || (onlyPublished && id != contentID && post.Status != consts.PostStatusPublished) {
+				continue
+			}
+			add(id, post.Title, fullPaths[id])
+		}
+	case consts.TranslationTypeCategory:
+		categories, err := t.CategoryService.ListByIDs(ctx, contentIDs)
+		if err != nil {
+			return nil, err
+		}
+		categoryDTOs, err := t.CategoryService.ConvertToCategoryDTOs(ctx, categories)
+		if err != nil {
+			return nil, err
+		}
+		categoryDTOMap := make(map[int32]*dto.CategoryDTO, len(categoryDTOs))
+		for i, category := range categories {
+			if onlyPublished && category.ID != contentID && category.Type == consts.CategoryTypeIntimate {
+				continue
+			}
+			categoryDTOMap[category.ID] = categoryDTOs[i]
+		}
+		for _, id := range contentIDs {
+			if category, ok := categoryDTOMap[id]; ok {
+				add(id, category.Name, category.FullPath)
+			}
+		}
+	case consts.TranslationTypeMenu:
+		menuDAL := dal.GetQueryByCtx(ctx).Menu
+		menus, err := menuDAL.WithContext(ctx).Where(menuDAL.ID.In(contentIDs...)).Find()
+		if err != nil {
+			return nil, WrapDBErr(err)
+		}
+		menuMap := make(map[int32]*entity.Menu, len(menus))
+		for _, menu := range menus {
+			menuMap[menu.ID] = menu
+		}
+		for _, id := range contentIDs {
+			if menu, ok := menuMap[id]; ok {
+				add(id, menu.Name, menu.URL)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (t *translationServiceImpl) FilterByLanguage(ctx context.Context, translationType consts.TranslationType, contentIDs []int32, language string) ([]int32, error) {
+	if language == "" || len(contentIDs) == 0 {
+		return contentIDs, nil
+	}
+	languages, err := t.OptionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	translations, err := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.ContentID.In(contentIDs...)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	contentLanguages := make(map[int32]string, len(translations))
+	for _, translation := range translations {
+		contentLanguages[translation.ContentID] = translation.Language
+	}
+	result := make([]int32, 0, len(contentIDs))
+	for _, id := range contentIDs {
+		contentLanguage, ok := contentLanguages[id]
+		if !ok {
+			contentLanguage = languages[0]
+		}
+		if contentLanguage == language {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+func (t *translationServiceImpl) MatchRequestLanguage(ctx context.Context, translationType consts.TranslationType, contentID int32) (bool, error) {
+	languagePrefix, err := languagePathPrefix(ctx, t.OptionService, translationType, contentID)
+	if err != nil {
+		return false, err
+	}
+	requestPathPrefix, _ := ctx.Value(consts.RequestPathPrefix).(string)
+	return languagePrefix == requestPathPrefix, nil
+}
+
+func (t *translationServiceImpl) checkContent(ctx context.Context, translationType consts.TranslationType, contentIDs ...int32) error {
+	ids := make([]int32, 0, len(contentIDs))
+	for _, id := range contentIDs {
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	var (
+		count int64
+		err   error
+	)
+	switch translationType {
+	case consts.TranslationTypePost:
+		postDAL := dal.GetQueryByCtx(ctx).Post
+		posts, err := postDAL.WithContext(ctx).Select(postDAL.ID, postDAL.Type).Where(postDAL.ID.In(ids...)).Find()
+		if err != nil {
+			return WrapDBErr(err)
+		}
+		for _, post := range posts {
+			if post.Type != posts[0].Type {
+				return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("A post can not be the translation of a sheet")
+			}
+		}
+		count = int64(len(posts))
+	case consts.TranslationTypeCategory:
+		categoryDAL := dal.GetQueryByCtx(ctx).Category
+		count, err = categoryDAL.WithContext(ctx).Where(categoryDAL.ID.In(ids...)).Count()
+	case consts.TranslationTypeMenu:
+		menuDAL := dal.GetQueryByCtx(ctx).Menu
+		count, err = menuDAL.WithContext(ctx).Where(menuDAL.ID.In(ids...)).Count()
+	default:
+		return xerr.BadParam.New("").WithStatus(xerr.StatusBadRequest).WithMsg("unknown TranslationType")
+	}
+	if err != nil {
+		return WrapDBErr(err)
+	}
+	if count != int64(len(ids)) {
+		return xerr.NoRecord.New("type=%s ids=%v", translationType, ids).WithStatus(xerr.StatusNotFound).WithMsg("The content does not exist")
+	}
+	return nil
+}
+
+// linkPostTranslation links the post or sheet to its translations as the language and translationOf of its param tell
+func linkPostTranslation(ctx context.Context, translationService service.TranslationService, postID int32, language *string, translationOf *int32) error {
+	if language == nil && translationOf == nil {
+		return nil
+	}
+	var postLanguage string
+	if language != nil {
+		postLanguage = *language
+	} else {
+		var err error
+		postLanguage, err = translationService.GetLanguage(ctx, consts.TranslationTypePost, postID)
+		if err != nil {
+			return err
+		}
+	}
+	return translationService.Link(ctx, consts.TranslationTypePost, postID, postLanguage, translationOf)
+}
+
+func getTranslation(ctx context.Context, translationType consts.TranslationType, contentID int32) (*entity.Translation, error) {
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	translation, err := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.ContentID.Eq(contentID)).Take()
+	if err != nil {
+		if xerr.GetType(WrapDBErr(err)) == xerr.NoRecord {
+			return nil, nil
+		}
+		return nil, WrapDBErr(err)
+	}
+	return translation, nil
+}
+
+func nextTranslationGroupID(ctx context.Context) (int32, error) {
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	var last struct {
+		GroupID *int32
+	}
+	err := translationDAL.WithContext(ctx).Select(translationDAL.GroupID.Max().As("group_id")).Scan(&last)
+	if err != nil {
+		return 0, WrapDBErr(err)
+	}
+	if last.GroupID == nil {
+		return 1, nil
+	}
+	return *last.GroupID + 1, nil
+}
+
+func deleteTranslations(ctx context.Context, translationType consts.TranslationType, contentIDs ...int32) error {
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	_, err := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.ContentID.In(contentIDs...)).Delete()
+	return WrapDBErr(err)
+}
+
+// requestLanguage returns the language of the content the request asks for,
+// it is empty when the blog publishes in a single language or the request is not from the content router
+func requestLanguage(ctx context.Context) string {
+	language, _ := ctx.Value(consts.RequestLanguage).(string)
+	return language
+}
+
+// languagePathPrefixes returns the path prefix of the contents in a language other than the default one, e.g. /en
+func languagePathPrefixes(ctx context.Context, optionService service.OptionService, translationType consts.TranslationType, contentIDs ...int32) (map[int32]string, error) {
+	result := make(map[int32]string)
+	languages, err := optionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(languages) <= 1 || len(contentIDs) == 0 {
+		return result, nil
+	}
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	translations, err := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType), translationDAL.ContentID.In(contentIDs...)).Find()
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	for _, translation := range translations {
+		if translation.Language != languages[0] && slices.Contains(languages, translation.Language) {
+			result[translation.ContentID] = "/" + translation.Language
+		}
+	}
+	return result, nil
+}
+
+func languagePathPrefix(ctx context.Context, optionService service.OptionService, translationType consts.TranslationType, contentID int32) (string, error) {
+	prefixes, err := languagePathPrefixes(ctx, optionService, translationType, contentID)
+	if err != nil {
+		return "", err
+	}
+	return prefixes[contentID], nil
+}
+
+// languageFilter filters the contents listed to the ones in the language of the request
+type languageFilter struct {
+	ids []int32
+	// exclude tells the ids are the contents in the other languages, which is the case of the default language
+	exclude bool
+}
+
+// newLanguageFilter returns nil when the contents are not to be filtered
+func newLanguageFilter(ctx context.Context, optionService service.OptionService, translationType consts.TranslationType, language string) (*languageFilter, error) {
+	if language == "" {
+		return nil, nil
+	}
+	languages, err := optionService.GetBlogLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(languages) <= 1 {
+		return nil, nil
+	}
+	translationDAL := dal.GetQueryByCtx(ctx).Translation
+	translationDO := translationDAL.WithContext(ctx).Where(translationDAL.Type.Eq(translationType))
+	filter := &languageFilter{}
+	if language == languages[0] {
+		filter.exclude = true
+		translationDO = translationDO.Where(translationDAL.Language.In(languages[1:]...))
+	} else {
+		translationDO = translationDO.Where(translationDAL.Language.Eq(language))
+	}
+	err = translationDO.Pluck(translationDAL.ContentID, &filter.ids)
+	if err != nil {
+		return nil, WrapDBErr(err)
+	}
+	return filter, nil
+}
+
+func (f *languageFilter) condition(id field.Int32) field.Expr {
+	if f.exclude {
+		return id.NotIn(f.ids...)
+	}
+	return id.In(f.ids...)
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type LoginProtectService interface {
+	// CheckAllowed rejects the attempt while the client ip or the username is locked, or when the captcha is required but wrong
+	CheckAllowed(ctx context.Context, loginParam param.LoginParam) error
+	RecordFailure(ctx context.Context, loginParam param.LoginParam)
+	RecordSuccess(ctx context.Context, username string)
+	GenerateCaptcha(ctx context.Context) (*dto.Captcha, error)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type MentionService interface {
+	// Receive checks the mention of the target from the source and verifies the source asynchronously
+	Receive(ctx context.Context, protocol consts.MentionProtocol, source, target, ipAddress string) error
+	// Verify fetches the source and saves the mention, the mention is deleted when the source no longer links to the target
+	Verify(ctx context.Context, protocol consts.MentionProtocol, source, target, ipAddress string) error
+	// Send sends webmentions, or pingbacks when the webmention endpoint is not found, to the links of the post
+	Send(ctx context.Context, post *entity.Post) error
+	Page(ctx context.Context, mentionQuery param.MentionQuery) ([]*entity.Mention, int64, error)
+	ListByPostID(ctx context.Context, postID int32, status consts.CommentStatus) ([]*entity.Mention, error)
+	UpdateStatus(ctx context.Context, mentionID int32, status consts.CommentStatus) (*entity.Mention, error)
+	UpdateStatusBatch(ctx context.Context, mentionIDs []int32, status consts.CommentStatus) ([]*entity.Mention, error)
+	Delete(ctx context.Context, mentionID int32) error
+	DeleteBatch(ctx context.Context, mentionIDs []int32) error
+	ConvertToDTO(mention *entity.Mention) *dto.Mention
+	ConvertToDTOs(mentions []*entity.Mention) []*dto.Mention
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type MetaWeblogService interface {
+	// Authenticate checks the password of the XML-RPC call, the app password is always accepted
+	// while the account password is accepted only when the two-factor authentication is off
+	Authenticate(ctx context.Context, username, password string) (*entity.User, error)
+	GetBlog(ctx context.Context) (*dto.MetaWeblogBlog, error)
+	NewPost(ctx context.Context, postParam *param.MetaWeblogPost) (int32, error)
+	EditPost(ctx context.Context, postID int32, postParam *param.MetaWeblogPost) error
+	GetPost(ctx context.Context, postID int32) (*dto.MetaWeblogPost, error)
+	GetRecentPosts(ctx context.Context, size int) ([]*dto.MetaWeblogPost, error)
+	DeletePost(ctx context.Context, postID int32) error
+	NewMediaObject(ctx context.Context, name string, content []byte) (*dto.AttachmentDTO, error)
+	GetCategories(ctx context.Context) ([]*dto.MetaWeblogCategory, error)
+	NewCategory(ctx context.Context, name, slug, description string, parentID int32) (int32, error)
+	GetTags(ctx context.Context) ([]*dto.MetaWeblogTag, error)
+	SetPostCategories(ctx context.Context, postID int32, categoryIDs []int32) error
+	// GenerateAppPassword replaces the app password with a random one, it is returned only once
+	GenerateAppPassword(ctx context.Context) (*dto.MetaWeblogAppPassword, error)
+	RevokeAppPassword(ctx context.Context) error
+}
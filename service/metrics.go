@@ -0,0 +1,8 @@
+package service
+
+import "net/http"
+
+type MetricsService interface {
+	// Handler serves the runtime and business metrics in the prometheus text format
+	Handler() http.Handler
+}
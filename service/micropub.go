@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"mime/multipart"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type MicropubService interface {
+	// VerifyToken verifies the bearer token by the token endpoint, the token must be issued for the blog url
+	VerifyToken(ctx context.Context, token string) (*dto.MicropubToken, error)
+	// Create creates a post when the entry has a name, photos when it has photos but no name, otherwise a journal.
+	// The url of the created one is returned
+	Create(ctx context.Context, entry *param.MicropubEntry) (string, error)
+	// Source gets the post, the journal or the photo by its url returned by Create
+	Source(ctx context.Context, sourceURL string) (*dto.MicropubEntry, error)
+	Config(ctx context.Context) (*dto.MicropubConfig, error)
+	ListCategories(ctx context.Context) ([]string, error)
+	// UploadMedia uploads the file to the configured file storage and returns its url
+	UploadMedia(ctx context.Context, fileHeader *multipart.FileHeader) (string, error)
+}
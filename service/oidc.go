@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+)
+
+type OIDCService interface {
+	ListProviders(ctx context.Context) []*dto.OIDCProvider
+	Authorize(ctx context.Context, providerName string) (*dto.OIDCAuthorization, error)
+	// Authenticate exchanges the authorization code and maps the verified identity to a sonic user
+	Authenticate(ctx context.Context, providerName, code, state string) (*entity.User, error)
+	// SkipMFA reports whether the provider is trusted to check the second factor of the users
+	SkipMFA(providerName string) bool
+}
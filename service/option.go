@@ -31,6 +31,9 @@ type OptionService interface {
 	GetTagPrefix(ctx context.Context) (string, error)
 	GetLinkPrefix(ctx context.Context) (string, error)
 	GetSheetPrefix(ctx context.Context) (string, error)
+	GetSeriesPrefix(ctx context.Context) (string, error)
+	// GetBlogLanguages returns the languages the blog publishes in, the default language BlogLocale comes first
+	GetBlogLanguages(ctx context.Context) ([]string, error)
 	GetAttachmentType(ctx context.Context) consts.AttachmentType
 	GetAdminURLPath(ctx context.Context) (string, error)
 }
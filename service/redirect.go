@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type RedirectService interface {
+	List(ctx context.Context, sort *param.Sort) ([]*entity.Redirect, error)
+	// Match returns the redirect of the request path, the trailing slash of the path is ignored
+	Match(ctx context.Context, path string) (*entity.Redirect, error)
+	Create(ctx context.Context, redirectParam *param.Redirect) (*entity.Redirect, error)
+	Update(ctx context.Context, id int32, redirectParam *param.Redirect) (*entity.Redirect, error)
+	Delete(ctx context.Context, id int32) error
+	ConvertToDTO(redirect *entity.Redirect) *dto.Redirect
+	ConvertToDTOs(redirects []*entity.Redirect) []*dto.Redirect
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/entity"
+)
+
+type RelatedPostService interface {
+	// ListRelatedPosts lists the published posts most related to the post by their shared tags and categories
+	// and the similarity of their titles and contents, top is capped by consts.RelatedPostsMaxCount
+	ListRelatedPosts(ctx context.Context, postID int32, top int) ([]*entity.Post, error)
+	// Invalidate marks the related posts stale, they are computed again in the background once the changes settle
+	// and the stale ones are served meanwhile
+	Invalidate(ctx context.Context)
+}
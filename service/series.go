@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/param"
+)
+
+type SeriesService interface {
+	ListAll(ctx context.Context) ([]*entity.Series, error)
+	GetByID(ctx context.Context, id int32) (*entity.Series, error)
+	GetBySlug(ctx context.Context, slug string) (*entity.Series, error)
+	Create(ctx context.Context, seriesParam *param.Series) (*entity.Series, error)
+	Update(ctx context.Context, id int32, seriesParam *param.Series) (*entity.Series, error)
+	Delete(ctx context.Context, id int32) error
+	// ListPosts lists the posts of the series in order, all the posts are listed when status is nil
+	ListPosts(ctx context.Context, seriesID int32, status *consts.PostStatus) ([]*entity.Post, error)
+	// UpdatePosts replaces the posts of the series with the ones in the order given,
+	// the posts in other series are moved into this one
+	UpdatePosts(ctx context.Context, seriesID int32, postIDs []int32) error
+	// AssignPost puts the post at the end of the series, the post is taken out of its series when seriesID is 0
+	AssignPost(ctx context.Context, postID int32, seriesID int32) error
+	GetByPostID(ctx context.Context, postID int32) (*entity.Series, error)
+	ListSeriesMapByPostID(ctx context.Context, postIDs []int32) (map[int32]*entity.Series, error)
+	// GetAdjacentPosts gets the published posts before and after the post in its series
+	GetAdjacentPosts(ctx context.Context, postID int32) (prev *entity.Post, next *entity.Post, err error)
+	ConvertToDTO(ctx context.Context, series *entity.Series) (*dto.Series, error)
+	ConvertToDTOs(ctx context.Context, series []*entity.Series) ([]*dto.Series, error)
+}
@@ -0,0 +1,9 @@
+package service
+
+import "context"
+
+type ShortcodeService interface {
+	// Render replaces the shortcodes in the html content of a post or sheet, a shortcode is rendered by the
+	// template shortcodes/<name> of the theme if it exists, otherwise by the built-in one
+	Render(ctx context.Context, content string) (string, error)
+}
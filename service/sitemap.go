@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+type SitemapService interface {
+	// ListSitemaps returns the child sitemaps linked by the sitemap index
+	ListSitemaps(ctx context.Context) ([]*dto.Sitemap, error)
+	// ListURLs returns the urls of a child sitemap, the page starts from 1
+	ListURLs(ctx context.Context, sitemapType consts.SitemapType, page int) ([]*dto.SitemapURL, error)
+	// Notify submits the changed urls to IndexNow and pings the search engines with the sitemap index
+	Notify(ctx context.Context, urls []string) error
+}
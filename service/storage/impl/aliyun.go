@@ -8,11 +8,13 @@ import (
 	"net/url"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/property"
 	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
@@ -62,7 +64,10 @@ func (a *Aliyun) Upload(ctx context.Context, fileHeader *multipart.FileHeader) (
 		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("open upload file error")
 	}
 	defer file.Close()
+	_, span := tracing.Start(ctx, "aliyun_oss.PutObject",
+		attribute.String("storage.bucket", aliyunClientInstance.BucketName), attribute.String("storage.key", fd.getRelativePath()))
 	err = aliyunClientInstance.Bucket.PutObject(fd.getRelativePath(), file)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, xerr.WithMsg(err, "upload to aliyun oss error: "+err.Error()).WithStatus(xerr.StatusInternalServerError)
 	}
@@ -98,7 +103,10 @@ func (a *Aliyun) Delete(ctx context.Context, fileKey string) error {
 	if err != nil {
 		return err
 	}
+	_, span := tracing.Start(ctx, "aliyun_oss.DeleteObject",
+		attribute.String("storage.bucket", aliyunClientInstance.BucketName), attribute.String("storage.key", fileKey))
 	err = aliyunClientInstance.Bucket.DeleteObject(fileKey)
+	tracing.End(span, err)
 	if err != nil {
 		return xerr.WithMsg(err, "delete file err from aliyun oss").WithStatus(xerr.StatusInternalServerError)
 	}
@@ -9,11 +9,13 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/model/dto"
 	"github.com/go-sonic/sonic/model/property"
 	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/tracing"
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
@@ -56,7 +58,10 @@ func (m *MinIO) Upload(ctx context.Context, fileHeader *multipart.FileHeader) (*
 		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("open upload file error")
 	}
 	defer file.Close()
-	_, err = minioClientInstance.PutObject(ctx, minioClientInstance.BucketName, fd.getRelativePath(), file, fileHeader.Size, minio.PutObjectOptions{})
+	spanCtx, span := tracing.Start(ctx, "minio.PutObject",
+		attribute.String("storage.bucket", minioClientInstance.BucketName), attribute.String("storage.key", fd.getRelativePath()))
+	_, err = minioClientInstance.PutObject(spanCtx, minioClientInstance.BucketName, fd.getRelativePath(), file, fileHeader.Size, minio.PutObjectOptions{})
+	tracing.End(span, err)
 	if err != nil {
 		return nil, xerr.WithMsg(err, "upload to minio error").WithStatus(xerr.StatusInternalServerError).WithErrMsgf("err=%v", err)
 	}
@@ -89,7 +94,10 @@ func (m *MinIO) Delete(ctx context.Context, fileKey string) error {
 	if err != nil {
 		return err
 	}
-	err = minioClientInstance.RemoveObject(ctx, minioClientInstance.BucketName, fileKey, minio.RemoveObjectOptions{})
+	spanCtx, span := tracing.Start(ctx, "minio.RemoveObject",
+		attribute.String("storage.bucket", minioClientInstance.BucketName), attribute.String("storage.key", fileKey))
+	err = minioClientInstance.RemoveObject(spanCtx, minioClientInstance.BucketName, fileKey, minio.RemoveObjectOptions{})
+	tracing.End(span, err)
 	if err != nil {
 		return xerr.WithStatus(err, xerr.StatusInternalServerError).WithErrMsgf("err=%v", err)
 	}
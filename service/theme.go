@@ -28,4 +28,13 @@ type ThemeService interface {
 	TemplateExist(ctx context.Context, template string) (bool, error)
 	Render(ctx context.Context, name string) (string, error)
 	Fetch(ctx context.Context, themeURL string) (*dto.ThemeProperty, error)
+	CheckThemeUpdate(ctx context.Context, themeID string) (*dto.ThemeUpdate, error)
+	UpdateThemeByFetching(ctx context.Context, themeID string) (*dto.ThemeProperty, error)
+	ValidateTheme(ctx context.Context, themeID string) (*dto.ThemeValidation, error)
+	GetThemeTemplatePaths(ctx context.Context, themeProperty *dto.ThemeProperty) []string
+	ExportThemeSettings(ctx context.Context, themeID, format string) ([]byte, error)
+	ImportThemeSettings(ctx context.Context, themeID string, content []byte) (*dto.ThemeSettingImport, error)
+	// ConvertThemeSettings checks the values against the data types of the theme config items, the names
+	// which are not defined by the theme are returned as skipped
+	ConvertThemeSettings(ctx context.Context, themeID string, settings map[string]interface{}) (map[string]interface{}, []string, error)
 }
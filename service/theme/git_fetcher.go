@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/fx"
 
 	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/tracing"
 	"github.com/go-sonic/sonic/util"
 	"github.com/go-sonic/sonic/util/xerr"
 )
@@ -33,9 +35,11 @@ func (g gitThemeFetcherImpl) FetchTheme(ctx context.Context, file interface{}) (
 			return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg("delete tmp theme directory err")
 		}
 	}
-	_, err := git.PlainClone(filepath.Join(tempDir, themeDirName), false, &git.CloneOptions{
+	spanCtx, span := tracing.Start(ctx, "git.Clone", attribute.String("git.url", gitURL))
+	_, err := git.PlainCloneContext(spanCtx, filepath.Join(tempDir, themeDirName), false, &git.CloneOptions{
 		URL: gitURL,
 	})
+	tracing.End(span, err)
 	if err != nil {
 		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg(err.Error())
 	}
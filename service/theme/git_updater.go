@@ -0,0 +1,276 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/tracing"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type ThemeUpdater interface {
+	// CheckUpdate compares the installed theme with its repo according to the update strategy of the theme
+	CheckUpdate(ctx context.Context, themeProperty *dto.ThemeProperty) (*dto.ThemeUpdate, error)
+	// FetchUpdate checks out the latest version of the theme into a temporary directory,
+	// the caller is responsible for removing ThemePath of the returned property
+	FetchUpdate(ctx context.Context, themeProperty *dto.ThemeProperty) (*dto.ThemeProperty, error)
+}
+
+type gitThemeUpdaterImpl struct {
+	PropertyScanner PropertyScanner
+}
+
+func NewGitThemeUpdater(propertyScanner PropertyScanner) ThemeUpdater {
+	return &gitThemeUpdaterImpl{
+		PropertyScanner: propertyScanner,
+	}
+}
+
+type themeTag struct {
+	name    string
+	version *semver.Version
+	hash    plumbing.Hash
+}
+
+func (g *gitThemeUpdaterImpl) CheckUpdate(ctx context.Context, themeProperty *dto.ThemeProperty) (*dto.ThemeUpdate, error) {
+	tmpDir, err := os.MkdirTemp("", "sonic-theme-update-*")
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("create tmp theme directory err")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repo, err := g.clone(ctx, themeProperty, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	themeUpdate, _, err := g.compare(themeProperty, repo)
+	return themeUpdate, err
+}
+
+func (g *gitThemeUpdaterImpl) FetchUpdate(ctx context.Context, themeProperty *dto.ThemeProperty) (*dto.ThemeProperty, error) {
+	tmpDir, err := os.MkdirTemp("", "sonic-theme-update-*")
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("create tmp theme directory err")
+	}
+	newThemeProperty, err := g.fetchUpdate(ctx, themeProperty, tmpDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+	return newThemeProperty, nil
+}
+
+func (g *gitThemeUpdaterImpl) fetchUpdate(ctx context.Context, themeProperty *dto.ThemeProperty, tmpDir string) (*dto.ThemeProperty, error) {
+	repo, err := g.clone(ctx, themeProperty, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	themeUpdate, target, err := g.compare(themeProperty, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !themeUpdate.HasUpdate && !themeUpdate.VersionUnknown {
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("theme is already up to date")
+	}
+	if themeProperty.UpdateStrategy == consts.ThemeUpdateStrategyRelease {
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("open theme worktree err")
+		}
+		err = worktree.Checkout(&git.CheckoutOptions{Hash: target, Force: true})
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("checkout " + themeUpdate.LatestVersion + " err")
+		}
+	}
+	return g.PropertyScanner.ReadThemeProperty(ctx, tmpDir)
+}
+
+func (g *gitThemeUpdaterImpl) clone(ctx context.Context, themeProperty *dto.ThemeProperty, dir string) (*git.Repository, error) {
+	if themeProperty.Repo == "" {
+		return nil, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("theme repo is not specified")
+	}
+	cloneOptions := &git.CloneOptions{
+		URL:  themeProperty.Repo,
+		Tags: git.AllTags,
+	}
+	if themeProperty.Branch != "" {
+		cloneOptions.ReferenceName = plumbing.NewBranchReferenceName(themeProperty.Branch)
+		cloneOptions.SingleBranch = true
+	}
+	spanCtx, span := tracing.Start(ctx, "git.Clone", attribute.String("git.url", themeProperty.Repo))
+	repo, err := git.PlainCloneContext(spanCtx, dir, false, cloneOptions)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusBadRequest).WithMsg(err.Error())
+	}
+	return repo, nil
+}
+
+// compare returns the update information and the commit the theme should be updated to
+func (g *gitThemeUpdaterImpl) compare(themeProperty *dto.ThemeProperty, repo *git.Repository) (*dto.ThemeUpdate, plumbing.Hash, error) {
+	if themeProperty.UpdateStrategy == consts.ThemeUpdateStrategyRelease {
+		return g.compareRelease(themeProperty, repo)
+	}
+	return g.compareBranch(themeProperty, repo)
+}
+
+func (g *gitThemeUpdaterImpl) compareBranch(themeProperty *dto.ThemeProperty, repo *git.Repository) (*dto.ThemeUpdate, plumbing.Hash, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, plumbing.ZeroHash, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read theme repo head err")
+	}
+	// themes installed from git keep their .git directory, which tells the installed commit
+	installed := plumbing.ZeroHash
+	if installedRepo, err := git.PlainOpen(themeProperty.ThemePath); err == nil {
+		if installedHead, err := installedRepo.Head(); err == nil {
+			installed = installedHead.Hash()
+		}
+	}
+
+	themeUpdate := &dto.ThemeUpdate{
+		ThemeID:        themeProperty.ID,
+		UpdateStrategy: themeProperty.UpdateStrategy,
+		LatestVersion:  shortHash(head.Hash()),
+		HasUpdate:      !installed.IsZero() && installed != head.Hash(),
+		VersionUnknown: installed.IsZero(),
+		Changelog:      make([]*dto.ThemeChangelog, 0),
+	}
+	if !installed.IsZero() {
+		themeUpdate.CurrentVersion = shortHash(installed)
+	}
+	// without the installed commit there is nothing to list the changes since
+	if !themeUpdate.HasUpdate {
+		return themeUpdate, head.Hash(), nil
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, plumbing.ZeroHash, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read theme repo log err")
+	}
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if commit.Hash == installed || len(themeUpdate.Changelog) >= consts.ThemeChangelogLimit {
+			return storer.ErrStop
+		}
+		themeUpdate.Changelog = append(themeUpdate.Changelog, &dto.ThemeChangelog{
+			Version:    shortHash(commit.Hash),
+			Message:    strings.TrimSpace(commit.Message),
+			Author:     commit.Author.Name,
+			CreateTime: commit.Author.When.UnixMilli(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, plumbing.ZeroHash, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read theme repo log err")
+	}
+	return themeUpdate, head.Hash(), nil
+}
+
+func (g *gitThemeUpdaterImpl) compareRelease(themeProperty *dto.ThemeProperty, repo *git.Repository) (*dto.ThemeUpdate, plumbing.Hash, error) {
+	tags, err := g.listTags(repo)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	if len(tags) == 0 {
+		return nil, plumbing.ZeroHash, xerr.WithStatus(nil, xerr.StatusBadRequest).WithMsg("no release found in theme repo")
+	}
+	// a version that can not be parsed can not be compared with the releases
+	installed, _ := semver.NewVersion(themeProperty.Version)
+	latest := tags[0]
+
+	themeUpdate := &dto.ThemeUpdate{
+		ThemeID:        themeProperty.ID,
+		UpdateStrategy: themeProperty.UpdateStrategy,
+		CurrentVersion: themeProperty.Version,
+		LatestVersion:  latest.name,
+		HasUpdate:      installed != nil && latest.version.GreaterThan(installed),
+		VersionUnknown: installed == nil,
+		Changelog:      make([]*dto.ThemeChangelog, 0),
+	}
+
+	target := plumbing.ZeroHash
+	for _, tag := range tags {
+		if installed != nil && !tag.version.GreaterThan(installed) {
+			break
+		}
+		if len(themeUpdate.Changelog) >= consts.ThemeChangelogLimit {
+			break
+		}
+		changelog := &dto.ThemeChangelog{Version: tag.name}
+		commit, err := g.tagCommit(repo, tag.hash, changelog)
+		if err != nil {
+			return nil, plumbing.ZeroHash, err
+		}
+		if target.IsZero() {
+			target = commit.Hash
+		}
+		themeUpdate.Changelog = append(themeUpdate.Changelog, changelog)
+	}
+	return themeUpdate, target, nil
+}
+
+// listTags returns the semver tags of the repo, the latest first
+func (g *gitThemeUpdaterImpl) listTags(repo *git.Repository) ([]*themeTag, error) {
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read theme repo tags err")
+	}
+	tags := make([]*themeTag, 0)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		version, err := semver.NewVersion(ref.Name().Short())
+		if err != nil {
+			return nil
+		}
+		tags = append(tags, &themeTag{name: ref.Name().Short(), version: version, hash: ref.Hash()})
+		return nil
+	})
+	if err != nil {
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read theme repo tags err")
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].version.GreaterThan(tags[j].version)
+	})
+	return tags, nil
+}
+
+// tagCommit resolves both annotated and lightweight tags and fills the changelog from the tag message or the commit
+func (g *gitThemeUpdaterImpl) tagCommit(repo *git.Repository, hash plumbing.Hash, changelog *dto.ThemeChangelog) (*object.Commit, error) {
+	tagObject, err := repo.TagObject(hash)
+	switch {
+	case err == nil:
+		commit, err := tagObject.Commit()
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read tag " + changelog.Version + " err")
+		}
+		changelog.Message = strings.TrimSpace(tagObject.Message)
+		changelog.Author = tagObject.Tagger.Name
+		changelog.CreateTime = tagObject.Tagger.When.UnixMilli()
+		return commit, nil
+	case errors.Is(err, plumbing.ErrObjectNotFound):
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read tag " + changelog.Version + " err")
+		}
+		changelog.Message = strings.TrimSpace(commit.Message)
+		changelog.Author = commit.Author.Name
+		changelog.CreateTime = commit.Author.When.UnixMilli()
+		return commit, nil
+	default:
+		return nil, xerr.WithStatus(err, xerr.StatusInternalServerError).WithMsg("read tag " + changelog.Version + " err")
+	}
+}
+
+func shortHash(hash plumbing.Hash) string {
+	return hash.String()[:7]
+}
@@ -10,6 +10,7 @@ func init() {
 	injection.Provide(
 		NewFileScanner,
 		NewPropertyScanner,
+		NewGitThemeUpdater,
 		fx.Annotated{Target: NewMultipartZipThemeFetcher, Name: "multipartZipThemeFetcher"},
 		fx.Annotated{Target: NewGitThemeFetcher, Name: "gitRepoThemeFetcher"},
 	)
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/template"
+)
+
+type ThemePreviewService interface {
+	// CreatePreview renders the site with a theme which is not activated and the draft settings merged over the saved ones
+	CreatePreview(ctx context.Context, themeID string, settings map[string]interface{}) (*dto.ThemePreview, error)
+	GetPreview(ctx context.Context, token string) (*dto.ThemePreview, *template.Template, bool)
+	DeletePreview(ctx context.Context, token string)
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+type ThemeSettingPresetService interface {
+	ListPresets(ctx context.Context, themeID string) ([]*dto.ThemeSettingPreset, error)
+	// SavePreset stores the current settings of the theme under the name, an existing preset with the same name is overwritten
+	SavePreset(ctx context.Context, themeID, name string) (*dto.ThemeSettingPreset, error)
+	// ApplyPreset replaces the settings of the theme with the preset, the settings missing in the preset fall back to the defaults
+	ApplyPreset(ctx context.Context, themeID string, presetID int32) error
+	DeletePreset(ctx context.Context, themeID string, presetID int32) error
+	DiffPreset(ctx context.Context, themeID string, presetID int32) ([]*dto.ThemeSettingDiff, error)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+)
+
+type TranslationService interface {
+	// ListLanguages lists the languages of the blog, the default one comes first
+	ListLanguages(ctx context.Context) ([]*dto.Language, error)
+	// GetLanguage gets the language of the content, which is the default language when it has never been set
+	GetLanguage(ctx context.Context, translationType consts.TranslationType, contentID int32) (string, error)
+	// Link sets the language of the content and makes it a translation of translationOf, the content is taken out
+	// of its translations when translationOf is 0 and stays with them when it is nil, an empty language is the default one
+	Link(ctx context.Context, translationType consts.TranslationType, contentID int32, language string, translationOf *int32) error
+	// ListTranslations lists the versions of the content in each language including itself,
+	// posts and sheets not published are left out when onlyPublished is true
+	ListTranslations(ctx context.Context, translationType consts.TranslationType, contentID int32, onlyPublished bool) ([]*dto.Translation, error)
+	// FilterByLanguage keeps the ids of the contents in the language
+	FilterByLanguage(ctx context.Context, translationType consts.TranslationType, contentIDs []int32, language string) ([]int32, error)
+	// MatchRequestLanguage reports whether the content is requested under the path prefix of its language,
+	// the pages of the contents are only served under their own prefix
+	MatchRequestLanguage(ctx context.Context, translationType consts.TranslationType, contentID int32) (bool, error)
+}
@@ -0,0 +1,50 @@
+package extension
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/template"
+)
+
+type mentionExtension struct {
+	MentionService service.MentionService
+	Template       *template.Template
+}
+
+func RegisterMentionFunc(template *template.Template, mentionService service.MentionService) {
+	m := &mentionExtension{
+		MentionService: mentionService,
+		Template:       template,
+	}
+	m.addGetPostMentions()
+}
+
+// addGetPostMentions 获取文章已发布的 Webmention 与 Pingback，按回复、喜欢、转发、收藏分组
+func (m *mentionExtension) addGetPostMentions() {
+	getPostMentions := func(postID int32) (*dto.PostMentions, error) {
+		mentions, err := m.MentionService.ListByPostID(context.Background(), postID, consts.CommentStatusPublished)
+		if err != nil {
+			return nil, err
+		}
+		postMentions := &dto.PostMentions{Total: len(mentions)}
+		for _, mentionDTO := range m.MentionService.ConvertToDTOs(mentions) {
+			switch mentionDTO.Type {
+			case consts.MentionTypeReply:
+				postMentions.Replies = append(postMentions.Replies, mentionDTO)
+			case consts.MentionTypeLike:
+				postMentions.Likes = append(postMentions.Likes, mentionDTO)
+			case consts.MentionTypeRepost:
+				postMentions.Reposts = append(postMentions.Reposts, mentionDTO)
+			case consts.MentionTypeBookmark:
+				postMentions.Bookmarks = append(postMentions.Bookmarks, mentionDTO)
+			default:
+				postMentions.Mentions = append(postMentions.Mentions, mentionDTO)
+			}
+		}
+		return postMentions, nil
+	}
+	m.Template.AddFunc("getPostMentions", getPostMentions)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/go-sonic/sonic/service"
 	"github.com/go-sonic/sonic/service/assembler"
 	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util/xerr"
 )
 
 type postExtension struct {
@@ -18,10 +19,11 @@ type postExtension struct {
 	PostCategoryService service.PostCategoryService
 	CategoryService     service.CategoryService
 	TagService          service.TagService
+	RelatedPostService  service.RelatedPostService
 	PostAssembler       assembler.PostAssembler
 }
 
-func RegisterPostFunc(template *template.Template, postService service.PostService, postTagService service.PostTagService, postCategoryService service.PostCategoryService, categoryService service.CategoryService, postAssembler assembler.PostAssembler, tagService service.TagService) {
+func RegisterPostFunc(template *template.Template, postService service.PostService, postTagService service.PostTagService, postCategoryService service.PostCategoryService, categoryService service.CategoryService, postAssembler assembler.PostAssembler, tagService service.TagService, relatedPostService service.RelatedPostService) {
 	p := &postExtension{
 		Template:            template,
 		PostService:         postService,
@@ -30,6 +32,7 @@ func RegisterPostFunc(template *template.Template, postService service.PostServi
 		CategoryService:     categoryService,
 		PostAssembler:       postAssembler,
 		TagService:          tagService,
+		RelatedPostService:  relatedPostService,
 	}
 	p.addListLatestPost()
 	p.addGetPostCount()
@@ -40,6 +43,8 @@ func RegisterPostFunc(template *template.Template, postService service.PostServi
 	p.addListPostByTagID()
 	p.addListPostByTagSlug()
 	p.addListMostPopularPost()
+	p.addListPostByFields()
+	p.addListRelatedPosts()
 }
 
 func (p *postExtension) addListLatestPost() {
@@ -171,3 +176,46 @@ func (p *postExtension) addListPostByTagSlug() {
 	}
 	p.Template.AddFunc("listPostByTagSlug", listPostByTagSlug)
 }
+
+// addListPostByFields lists the latest posts by the filters on their custom fields given as key value pairs,
+// e.g. listPostByFields 10 "price" "lte:100" "color" "red"
+func (p *postExtension) addListPostByFields() {
+	listPostByFields := func(top int, filters ...string) ([]*vo.Post, error) {
+		if len(filters)%2 != 0 {
+			return nil, xerr.BadParam.New("").WithMsg("listPostByFields filters should be key value pairs")
+		}
+		metas := make(map[string]string, len(filters)/2)
+		for i := 0; i < len(filters); i += 2 {
+			metas[filters[i]] = filters[i+1]
+		}
+		ctx := context.Background()
+		posts, _, err := p.PostService.Page(ctx, param.PostQuery{
+			Page: param.Page{
+				PageNum:  0,
+				PageSize: top,
+			},
+			Sort: &param.Sort{
+				Fields: []string{"createTime,desc"},
+			},
+			Statuses: []*consts.PostStatus{consts.PostStatusPublished.Ptr()},
+			Metas:    metas,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return p.PostAssembler.ConvertToListVO(ctx, posts)
+	}
+	p.Template.AddFunc("listPostByFields", listPostByFields)
+}
+
+func (p *postExtension) addListRelatedPosts() {
+	listRelatedPosts := func(postID int32, top int) ([]*vo.Post, error) {
+		ctx := context.Background()
+		posts, err := p.RelatedPostService.ListRelatedPosts(ctx, postID, top)
+		if err != nil {
+			return nil, err
+		}
+		return p.PostAssembler.ConvertToListVO(ctx, posts)
+	}
+	p.Template.AddFunc("listRelatedPosts", listRelatedPosts)
+}
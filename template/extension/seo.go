@@ -0,0 +1,368 @@
+package extension
+
+import (
+	"encoding/json"
+	htmlTemplate "html/template"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/model/vo"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util"
+)
+
+var pagePathRegexp = regexp.MustCompile(`/page/\d+$`)
+
+type seoExtension struct {
+	Template *template.Template
+}
+
+// seoPage is the seo metadata of the page collected from the page model
+type seoPage struct {
+	Title         string
+	Description   string
+	Keywords      string
+	Image         string
+	URL           string
+	Type          string
+	Robots        string
+	PrevURL       string
+	NextURL       string
+	PublishedTime time.Time
+	ModifiedTime  time.Time
+	Section       string
+	Tags          []string
+	Breadcrumbs   []*seoBreadcrumb
+}
+
+type seoBreadcrumb struct {
+	Name string
+	URL  string
+}
+
+func RegisterSeoFunc(template *template.Template) {
+	s := &seoExtension{
+		Template: template,
+	}
+	s.addSeoHead()
+	s.addSeoRobots()
+	s.addCanonicalURL()
+}
+
+// addSeoHead 生成页面的 SEO 元信息：描述、Open Graph、Twitter Card、JSON-LD、canonical 与分页链接
+func (s *seoExtension) addSeoHead() {
+	seoHead := func(model map[string]any) htmlTemplate.HTML {
+		return htmlTemplate.HTML(buildSeoHead(model, newSeoPage(model)))
+	}
+	s.Template.AddFunc("seoHead", seoHead)
+}
+
+func (s *seoExtension) addSeoRobots() {
+	seoRobots := func(model map[string]any) string {
+		return newSeoPage(model).Robots
+	}
+	s.Template.AddFunc("seoRobots", seoRobots)
+}
+
+func (s *seoExtension) addCanonicalURL() {
+	canonicalURL := func(model map[string]any) string {
+		return newSeoPage(model).URL
+	}
+	s.Template.AddFunc("canonicalURL", canonicalURL)
+}
+
+func newSeoPage(model map[string]any) *seoPage {
+	blogURL := modelString(model, "blog_url")
+	page := &seoPage{
+		Title:       modelString(model, "blog_title"),
+		Description: modelString(model, "meta_description"),
+		Keywords:    modelString(model, "meta_keywords"),
+		Image:       modelString(model, "blog_logo"),
+		Type:        "website",
+	}
+	requestPath := modelString(model, "request_path")
+	if requestPath != "" {
+		page.URL = seoAbsoluteURL(blogURL, requestPath)
+	}
+	page.Breadcrumbs = []*seoBreadcrumb{{Name: page.Title, URL: seoAbsoluteURL(blogURL, "/")}}
+
+	switch {
+	case modelBool(model, "is_post") || modelBool(model, "is_sheet"):
+		post := modelPost(model)
+		if post == nil {
+			break
+		}
+		page.Title = post.Title
+		page.Type = "article"
+		page.URL = seoAbsoluteURL(blogURL, post.FullPath)
+		if post.Thumbnail != "" {
+			page.Image = post.Thumbnail
+		}
+		page.PublishedTime = time.UnixMilli(post.CreateTime)
+		page.ModifiedTime = page.PublishedTime
+		if post.EditTime > 0 {
+			page.ModifiedTime = time.UnixMilli(post.EditTime)
+		}
+		if post.Status == consts.PostStatusIntimate || post.Password != "" {
+			page.Robots = "noindex,nofollow"
+		}
+		if categories, ok := model["categories"].([]*dto.CategoryDTO); ok && len(categories) > 0 {
+			page.Section = categories[0].Name
+			page.Breadcrumbs = append(page.Breadcrumbs, &seoBreadcrumb{Name: categories[0].Name, URL: seoAbsoluteURL(blogURL, categories[0].FullPath)})
+		}
+		if tags, ok := model["tags"].([]*dto.Tag); ok {
+			for _, tag := range tags {
+				page.Tags = append(page.Tags, tag.Name)
+			}
+		}
+		page.Breadcrumbs = append(page.Breadcrumbs, &seoBreadcrumb{Name: page.Title, URL: page.URL})
+		applySeoMetas(page, model, blogURL)
+	case modelBool(model, "is_category"):
+		if category, ok := model["category"].(*dto.CategoryDTO); ok {
+			page.Title = category.Name
+			if category.Thumbnail != "" {
+				page.Image = category.Thumbnail
+			}
+			page.Breadcrumbs = append(page.Breadcrumbs,
+				&seoBreadcrumb{Name: "分类", URL: seoAbsoluteURL(blogURL, modelString(model, "categories_url"))},
+				&seoBreadcrumb{Name: category.Name, URL: seoAbsoluteURL(blogURL, category.FullPath)})
+		}
+	case modelBool(model, "is_tag"):
+		if tag, ok := model["tag"].(*dto.Tag); ok {
+			page.Title = tag.Name
+			if tag.Thumbnail != "" {
+				page.Image = tag.Thumbnail
+			}
+			page.Breadcrumbs = append(page.Breadcrumbs,
+				&seoBreadcrumb{Name: "标签", URL: seoAbsoluteURL(blogURL, modelString(model, "tags_url"))},
+				&seoBreadcrumb{Name: tag.Name, URL: seoAbsoluteURL(blogURL, tag.FullPath)})
+		}
+	case modelBool(model, "is_search"):
+		page.Robots = "noindex,follow"
+	}
+	if page.Description == "" {
+		page.Description = modelString(model, "seo_description")
+	}
+	page.Image = seoAbsoluteURL(blogURL, page.Image)
+	if options, ok := model["options"].(map[string]any); ok {
+		if disabled, ok := options[property.SeoSpiderDisabled.KeyValue].(bool); ok && disabled {
+			page.Robots = "noindex,nofollow"
+		}
+	}
+	if !modelBool(model, "is_search") && requestPath != "" {
+		page.PrevURL, page.NextURL = seoPageLinks(model, blogURL, requestPath)
+	}
+	return page
+}
+
+// applySeoMetas applies the post metas overriding the seo metadata of the post
+func applySeoMetas(page *seoPage, model map[string]any, blogURL string) {
+	metas, _ := model["metas"].([]*dto.Meta)
+	for _, meta := range metas {
+		value := strings.TrimSpace(meta.Value)
+		if value == "" {
+			continue
+		}
+		switch meta.Key {
+		case consts.SeoMetaTitle:
+			page.Title = value
+		case consts.SeoMetaDescription:
+			page.Description = value
+		case consts.SeoMetaImage:
+			page.Image = value
+		case consts.SeoMetaCanonical:
+			page.URL = seoAbsoluteURL(blogURL, value)
+		case consts.SeoMetaRobots:
+			page.Robots = value
+		}
+	}
+}
+
+// seoPageLinks returns the urls of the previous and next page of the paginated page
+func seoPageLinks(model map[string]any, blogURL, requestPath string) (string, string) {
+	var page *dto.Page
+	for _, key := range []string{"posts", "journals", "photos"} {
+		if p, ok := model[key].(*dto.Page); ok {
+			page = p
+			break
+		}
+	}
+	if page == nil {
+		return "", ""
+	}
+	basePath := strings.TrimSuffix(pagePathRegexp.ReplaceAllString(requestPath, ""), "/")
+	pageURL := func(pageNum int) string {
+		if pageNum <= 1 {
+			return seoAbsoluteURL(blogURL, util.IfElse(basePath == "", "/", basePath).(string))
+		}
+		return seoAbsoluteURL(blogURL, basePath+"/page/"+strconv.Itoa(pageNum))
+	}
+	var prevURL, nextURL string
+	if page.HasPrevious {
+		prevURL = pageURL(page.PageNum)
+	}
+	if page.HasNext {
+		nextURL = pageURL(page.PageNum + 2)
+	}
+	return prevURL, nextURL
+}
+
+func buildSeoHead(model map[string]any, page *seoPage) string {
+	head := strings.Builder{}
+	writeMeta := func(attr, key, value string) {
+		if value == "" {
+			return
+		}
+		head.WriteString(`<meta ` + attr + `="` + htmlTemplate.HTMLEscapeString(key) + `" content="` + htmlTemplate.HTMLEscapeString(value) + "\">\n")
+	}
+	writeLink := func(rel, href string) {
+		if href == "" {
+			return
+		}
+		head.WriteString(`<link rel="` + rel + `" href="` + htmlTemplate.HTMLEscapeString(href) + "\">\n")
+	}
+	writeMeta("name", "description", page.Description)
+	writeMeta("name", "keywords", page.Keywords)
+	writeMeta("name", "robots", page.Robots)
+	writeLink("canonical", page.URL)
+	writeLink("prev", page.PrevURL)
+	writeLink("next", page.NextURL)
+
+	writeMeta("property", "og:site_name", modelString(model, "blog_title"))
+	writeMeta("property", "og:type", page.Type)
+	writeMeta("property", "og:title", page.Title)
+	writeMeta("property", "og:description", page.Description)
+	writeMeta("property", "og:url", page.URL)
+	writeMeta("property", "og:image", page.Image)
+	if page.Type == "article" {
+		writeMeta("property", "article:published_time", page.PublishedTime.Format(time.RFC3339))
+		writeMeta("property", "article:modified_time", page.ModifiedTime.Format(time.RFC3339))
+		writeMeta("property", "article:section", page.Section)
+		for _, tag := range page.Tags {
+			writeMeta("property", "article:tag", tag)
+		}
+	}
+	writeMeta("name", "twitter:card", util.IfElse(page.Image != "", "summary_large_image", "summary").(string))
+	writeMeta("name", "twitter:title", page.Title)
+	writeMeta("name", "twitter:description", page.Description)
+	writeMeta("name", "twitter:image", page.Image)
+
+	if jsonLD := buildJSONLD(model, page); jsonLD != "" {
+		head.WriteString(`<script type="application/ld+json">` + jsonLD + "</script>\n")
+	}
+	return head.String()
+}
+
+// buildJSONLD returns the structured data of the page, see https://schema.org
+func buildJSONLD(model map[string]any, page *seoPage) string {
+	blogURL := modelString(model, "blog_url")
+	graph := make([]map[string]any, 0)
+
+	var person map[string]any
+	if user, ok := model["user"].(*entity.User); ok && user != nil {
+		person = map[string]any{
+			"@type": "Person",
+			"@id":   seoAbsoluteURL(blogURL, "/") + "#author",
+			"name":  user.Nickname,
+			"url":   seoAbsoluteURL(blogURL, "/"),
+		}
+		if user.Avatar != "" {
+			person["image"] = seoAbsoluteURL(blogURL, user.Avatar)
+		}
+		if user.Description != "" {
+			person["description"] = user.Description
+		}
+		graph = append(graph, person)
+	}
+	if page.Type == "article" {
+		posting := map[string]any{
+			"@type":            "BlogPosting",
+			"headline":         page.Title,
+			"url":              page.URL,
+			"mainEntityOfPage": page.URL,
+			"datePublished":    page.PublishedTime.Format(time.RFC3339),
+			"dateModified":     page.ModifiedTime.Format(time.RFC3339),
+		}
+		if page.Description != "" {
+			posting["description"] = page.Description
+		}
+		if page.Image != "" {
+			posting["image"] = page.Image
+		}
+		if len(page.Tags) > 0 {
+			posting["keywords"] = strings.Join(page.Tags, ",")
+		}
+		if page.Section != "" {
+			posting["articleSection"] = page.Section
+		}
+		if person != nil {
+			posting["author"] = map[string]any{"@id": person["@id"]}
+		}
+		graph = append(graph, posting)
+	}
+	if len(page.Breadcrumbs) > 1 {
+		items := make([]map[string]any, 0, len(page.Breadcrumbs))
+		for i, breadcrumb := range page.Breadcrumbs {
+			items = append(items, map[string]any{
+				"@type":    "ListItem",
+				"position": i + 1,
+				"name":     breadcrumb.Name,
+				"item":     breadcrumb.URL,
+			})
+		}
+		graph = append(graph, map[string]any{
+			"@type":           "BreadcrumbList",
+			"itemListElement": items,
+		})
+	}
+	if len(graph) == 0 {
+		return ""
+	}
+	// json.Marshal escapes <, > and &, so the script can not be closed by the content
+	data, err := json.Marshal(map[string]any{
+		"@context": "https://schema.org",
+		"@graph":   graph,
+	})
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func modelPost(model map[string]any) *dto.PostDetail {
+	switch post := model["post"].(type) {
+	case *vo.PostDetailVO:
+		return &post.PostDetail
+	case *vo.SheetDetail:
+		return &post.PostDetail
+	case *dto.PostDetail:
+		return post
+	}
+	return nil
+}
+
+func modelString(model map[string]any, key string) string {
+	value, _ := model[key].(string)
+	return value
+}
+
+func modelBool(model map[string]any, key string) bool {
+	value, _ := model[key].(bool)
+	return value
+}
+
+func seoAbsoluteURL(blogURL, path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if strings.HasPrefix(path, "//") {
+		return util.IfElse(strings.HasPrefix(blogURL, "http://"), "http:", "https:").(string) + path
+	}
+	return strings.TrimSuffix(blogURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
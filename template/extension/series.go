@@ -0,0 +1,107 @@
+package extension
+
+import (
+	"context"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/entity"
+	"github.com/go-sonic/sonic/model/vo"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/service/assembler"
+	"github.com/go-sonic/sonic/template"
+	"github.com/go-sonic/sonic/util/xerr"
+)
+
+type seriesExtension struct {
+	Template      *template.Template
+	SeriesService service.SeriesService
+	PostAssembler assembler.PostAssembler
+}
+
+func RegisterSeriesFunc(template *template.Template, seriesService service.SeriesService, postAssembler assembler.PostAssembler) {
+	s := &seriesExtension{
+		Template:      template,
+		SeriesService: seriesService,
+		PostAssembler: postAssembler,
+	}
+	s.addListSeries()
+	s.addGetSeriesByPostID()
+	s.addListSeriesPosts()
+	s.addGetSeriesPrevPost()
+	s.addGetSeriesNextPost()
+}
+
+func (s *seriesExtension) addListSeries() {
+	listSeries := func() ([]*dto.Series, error) {
+		ctx := context.Background()
+		series, err := s.SeriesService.ListAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return s.SeriesService.ConvertToDTOs(ctx, series)
+	}
+	s.Template.AddFunc("listSeries", listSeries)
+}
+
+func (s *seriesExtension) addGetSeriesByPostID() {
+	getSeriesByPostID := func(postID int32) (*dto.Series, error) {
+		ctx := context.Background()
+		series, err := s.SeriesService.GetByPostID(ctx, postID)
+		if xerr.GetType(err) == xerr.NoRecord {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return s.SeriesService.ConvertToDTO(ctx, series)
+	}
+	s.Template.AddFunc("getSeriesByPostID", getSeriesByPostID)
+}
+
+func (s *seriesExtension) addListSeriesPosts() {
+	listSeriesPosts := func(seriesID int32) ([]*vo.Post, error) {
+		ctx := context.Background()
+		posts, err := s.SeriesService.ListPosts(ctx, seriesID, consts.PostStatusPublished.Ptr())
+		if err != nil {
+			return nil, err
+		}
+		return s.PostAssembler.ConvertToListVO(ctx, posts)
+	}
+	s.Template.AddFunc("listSeriesPosts", listSeriesPosts)
+}
+
+func (s *seriesExtension) addGetSeriesPrevPost() {
+	getSeriesPrevPost := func(postID int32) (*vo.Post, error) {
+		ctx := context.Background()
+		prevPost, _, err := s.SeriesService.GetAdjacentPosts(ctx, postID)
+		if err != nil {
+			return nil, err
+		}
+		return s.convertToListVO(ctx, prevPost)
+	}
+	s.Template.AddFunc("getSeriesPrevPost", getSeriesPrevPost)
+}
+
+func (s *seriesExtension) addGetSeriesNextPost() {
+	getSeriesNextPost := func(postID int32) (*vo.Post, error) {
+		ctx := context.Background()
+		_, nextPost, err := s.SeriesService.GetAdjacentPosts(ctx, postID)
+		if err != nil {
+			return nil, err
+		}
+		return s.convertToListVO(ctx, nextPost)
+	}
+	s.Template.AddFunc("getSeriesNextPost", getSeriesNextPost)
+}
+
+func (s *seriesExtension) convertToListVO(ctx context.Context, post *entity.Post) (*vo.Post, error) {
+	if post == nil {
+		return nil, nil
+	}
+	postVOs, err := s.PostAssembler.ConvertToListVO(ctx, []*entity.Post{post})
+	if err != nil {
+		return nil, err
+	}
+	return postVOs[0], nil
+}
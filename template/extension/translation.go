@@ -0,0 +1,184 @@
+package extension
+
+import (
+	"context"
+	htmlTemplate "html/template"
+	"slices"
+	"strings"
+
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/model/dto"
+	"github.com/go-sonic/sonic/model/param"
+	"github.com/go-sonic/sonic/model/property"
+	"github.com/go-sonic/sonic/model/vo"
+	"github.com/go-sonic/sonic/service"
+	"github.com/go-sonic/sonic/template"
+)
+
+type translationExtension struct {
+	Template           *template.Template
+	TranslationService service.TranslationService
+	MenuService        service.MenuService
+	OptionService      service.OptionService
+}
+
+func RegisterTranslationFunc(template *template.Template, translationService service.TranslationService, menuService service.MenuService, optionService service.OptionService) {
+	t := &translationExtension{
+		Template:           template,
+		TranslationService: translationService,
+		MenuService:        menuService,
+		OptionService:      optionService,
+	}
+	t.addListLanguages()
+	t.addListTranslations()
+	t.addTranslationLinks()
+	t.addListMenuByLanguage()
+	t.addListMenuAsTreeByLanguage()
+}
+
+func (t *translationExtension) addListLanguages() {
+	listLanguages := func() ([]*dto.Language, error) {
+		return t.TranslationService.ListLanguages(context.Background())
+	}
+	t.Template.AddFunc("listLanguages", listLanguages)
+}
+
+// addListTranslations lists the versions of the current page in each language,
+// they are the linked translations on the pages of posts, sheets and categories
+func (t *translationExtension) addListTranslations() {
+	listTranslations := func(model map[string]any) ([]*dto.Translation, error) {
+		return t.listTranslations(context.Background(), model)
+	}
+	t.Template.AddFunc("listTranslations", listTranslations)
+}
+
+// addTranslationLinks generates the hreflang links of the current page, nothing is generated without translations
+func (t *translationExtension) addTranslationLinks() {
+	translationLinks := func(model map[string]any) (htmlTemplate.HTML, error) {
+		translations, err := t.listTranslations(context.Background(), model)
+		if err != nil || len(translations) <= 1 {
+			return "", err
+		}
+		blogURL := modelString(model, "blog_url")
+		links := strings.Builder{}
+		for i, translation := range translations {
+			href := htmlTemplate.HTMLEscapeString(seoAbsoluteURL(blogURL, translation.FullPath))
+			links.WriteString(`<link rel="alternate" hreflang="` + htmlTemplate.HTMLEscapeString(translation.Language) + `" href="` + href + "\">\n")
+			if i == 0 {
+				links.WriteString(`<link rel="alternate" hreflang="x-default" href="` + href + "\">\n")
+			}
+		}
+		return htmlTemplate.HTML(links.String()), nil
+	}
+	t.Template.AddFunc("translationLinks", translationLinks)
+}
+
+func (t *translationExtension) addListMenuByLanguage() {
+	listMenuByLanguage := func(language string) ([]*dto.Menu, error) {
+		ctx := context.Background()
+		listTeam := t.OptionService.GetOrByDefault(ctx, property.DefaultMenuTeam)
+		menus, err := t.MenuService.ListByTeam(ctx, listTeam.(string), &param.Sort{
+			Fields: []string{"priority,asc"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		menuIDs := make([]int32, 0, len(menus))
+		for _, menu := range menus {
+			menuIDs = append(menuIDs, menu.ID)
+		}
+		menuIDs, err = t.TranslationService.FilterByLanguage(ctx, consts.TranslationTypeMenu, menuIDs, language)
+		if err != nil {
+			return nil, err
+		}
+		menuDTOs := make([]*dto.Menu, 0, len(menuIDs))
+		for _, menuDTO := range t.MenuService.ConvertToDTOs(ctx, menus) {
+			if slices.Contains(menuIDs, menuDTO.ID) {
+				menuDTOs = append(menuDTOs, menuDTO)
+			}
+		}
+		return menuDTOs, nil
+	}
+	t.Template.AddFunc("listMenuByLanguage", listMenuByLanguage)
+}
+
+func (t *translationExtension) addListMenuAsTreeByLanguage() {
+	listMenuAsTreeByLanguage := func(language string) ([]*vo.Menu, error) {
+		ctx := context.Background()
+		listTeam := t.OptionService.GetOrByDefault(ctx, property.DefaultMenuTeam)
+		menus, err := t.MenuService.ListAsTreeByTeam(ctx, listTeam.(string), &param.Sort{Fields: []string{"priority,asc"}})
+		if err != nil {
+			return nil, err
+		}
+		menuIDs := make([]int32, 0)
+		var collect func(menus []*vo.Menu)
+		collect = func(menus []*vo.Menu) {
+			for _, menu := range menus {
+				menuIDs = append(menuIDs, menu.ID)
+				collect(menu.Children)
+			}
+		}
+		collect(menus)
+		menuIDs, err = t.TranslationService.FilterByLanguage(ctx, consts.TranslationTypeMenu, menuIDs, language)
+		if err != nil {
+			return nil, err
+		}
+		var filter func(menus []*vo.Menu) []*vo.Menu
+		filter = func(menus []*vo.Menu) []*vo.Menu {
+			result := make([]*vo.Menu, 0, len(menus))
+			for _, menu := range menus {
+				if slices.Contains(menuIDs, menu.ID) {
+					menu.Children = filter(menu.Children)
+					result = append(result, menu)
+				}
+			}
+			return result
+		}
+		return filter(menus), nil
+	}
+	t.Template.AddFunc("listMenuAsTreeByLanguage", listMenuAsTreeByLanguage)
+}
+
+func (t *translationExtension) listTranslations(ctx context.Context, model map[string]any) ([]*dto.Translation, error) {
+	languages, err := t.TranslationService.ListLanguages(ctx)
+	if err != nil || len(languages) <= 1 {
+		return []*dto.Translation{}, err
+	}
+	switch {
+	case modelBool(model, "is_post") || modelBool(model, "is_sheet"):
+		if post := modelPost(model); post != nil {
+			return t.TranslationService.ListTranslations(ctx, consts.TranslationTypePost, post.ID, true)
+		}
+	case modelBool(model, "is_category"):
+		if category, ok := model["category"].(*dto.CategoryDTO); ok {
+			return t.TranslationService.ListTranslations(ctx, consts.TranslationTypeCategory, category.ID, true)
+		}
+	}
+
+	// the other pages are in every language, the path of the page is the same under the prefix of each language
+	language := modelString(model, "language")
+	path := modelString(model, "request_path")
+	for _, l := range languages {
+		if !l.Default && (path == "/"+l.Language || strings.HasPrefix(path, "/"+l.Language+"/")) {
+			path = strings.TrimPrefix(path, "/"+l.Language)
+			break
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	translations := make([]*dto.Translation, 0, len(languages))
+	for _, l := range languages {
+		fullPath := strings.TrimSuffix(l.FullPath, "/") + path
+		if !l.Default && path == "/" {
+			fullPath = l.FullPath
+		}
+		translations = append(translations, &dto.Translation{
+			Language: l.Language,
+			Title:    modelString(model, "blog_title"),
+			FullPath: fullPath,
+			Current:  l.Language == language,
+		})
+	}
+	return translations, nil
+}
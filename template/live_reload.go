@@ -0,0 +1,70 @@
+package template
+
+import (
+	"sync"
+)
+
+// liveReload tells the open browser tabs to reload the page after the theme files changed in development mode
+type liveReload struct {
+	lock    sync.Mutex
+	clients map[chan struct{}]struct{}
+	closed  bool
+}
+
+func newLiveReload() *liveReload {
+	return &liveReload{
+		clients: make(map[chan struct{}]struct{}),
+	}
+}
+
+func (l *liveReload) subscribe() (<-chan struct{}, func()) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if l.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	l.clients[ch] = struct{}{}
+	return ch, func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		if _, ok := l.clients[ch]; ok {
+			delete(l.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+func (l *liveReload) notify() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for ch := range l.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (l *liveReload) close() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.closed = true
+	for ch := range l.clients {
+		delete(l.clients, ch)
+		close(ch)
+	}
+}
+
+// SubscribeLiveReload returns a channel which receives a value whenever the theme files changed,
+// the channel is closed when the server shuts down
+func (t *Template) SubscribeLiveReload() (<-chan struct{}, func()) {
+	return t.liveReload.subscribe()
+}
+
+// CloseLiveReload closes all live reload subscriptions so that the open event streams do not hold up the shutdown
+func (t *Template) CloseLiveReload() {
+	t.liveReload.close()
+}
@@ -12,9 +12,14 @@ import (
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/fsnotify/fsnotify"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
 	"github.com/go-sonic/sonic/event"
+	"github.com/go-sonic/sonic/metrics"
+	"github.com/go-sonic/sonic/tracing"
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
@@ -28,6 +33,10 @@ type Template struct {
 	paths          []string
 	funcMap        map[string]any
 	bus            event.Bus
+	parent         *Template
+	watchedDirs    map[string]struct{}
+	watchLock      sync.Mutex
+	liveReload     *liveReload
 }
 
 func NewTemplate(logger *zap.Logger, bus event.Bus) *Template {
@@ -36,6 +45,11 @@ func NewTemplate(logger *zap.Logger, bus event.Bus) *Template {
 		logger:         logger,
 		funcMap:        map[string]any{},
 		bus:            bus,
+		watchedDirs:    map[string]struct{}{},
+		liveReload:     newLiveReload(),
+	}
+	if config.IsDev() {
+		t.sharedVariable["live_reload_url"] = consts.LiveReloadPath
 	}
 	t.addUtilFunc()
 	watcher, err := fsnotify.NewWatcher()
@@ -57,34 +71,101 @@ func (t *Template) Load(paths []string) error {
 	defer t.lock.Unlock()
 
 	t.paths = paths
+	filenames, err := templateFilenames(paths)
+	if err != nil {
+		return err
+	}
+	if config.IsDev() {
+		t.watchDirs(paths)
+	} else {
+		for _, filename := range filenames {
+			if err := t.watcher.Add(filename); err != nil {
+				t.logger.Error("template.load.fsnotify.Add", zap.Error(err))
+			}
+		}
+	}
+	ht, tt, err := t.parse(filenames)
+	if err != nil {
+		return err
+	}
+	t.TextTemplate = tt
+	t.HTMLTemplate = ht
+	return nil
+}
+
+// Parse parses the templates under paths with the real funcMap without replacing the loaded templates
+func (t *Template) Parse(paths []string) (*htmlTemplate.Template, error) {
+	filenames, err := templateFilenames(paths)
+	if err != nil {
+		return nil, err
+	}
+	ht, _, err := t.parse(filenames)
+	return ht, err
+}
+
+// Fork returns a template that renders the templates under paths, the variables take precedence over
+// the shared variables of t. It is used to preview a theme which is not activated.
+func (t *Template) Fork(paths []string, variables map[string]any) (*Template, error) {
+	filenames, err := templateFilenames(paths)
+	if err != nil {
+		return nil, err
+	}
+	ht, tt, err := t.parse(filenames)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{
+		HTMLTemplate:   ht,
+		TextTemplate:   tt,
+		sharedVariable: variables,
+		logger:         t.logger,
+		paths:          paths,
+		funcMap:        t.funcMap,
+		bus:            t.bus,
+		parent:         t,
+	}, nil
+}
+
+func (t *Template) parse(filenames []string) (*htmlTemplate.Template, *template.Template, error) {
+	ht, err := htmlTemplate.New("").Funcs(t.funcMap).ParseFiles(filenames...)
+	if err != nil {
+		return nil, nil, xerr.WithMsg(err, "parse template err").WithStatus(xerr.StatusInternalServerError)
+	}
+	tt, err := template.New("").Funcs(t.funcMap).ParseFiles(filenames...)
+	if err != nil {
+		return nil, nil, xerr.WithMsg(err, "parse template err").WithStatus(xerr.StatusInternalServerError)
+	}
+	return ht, tt, nil
+}
+
+func templateFilenames(paths []string) ([]string, error) {
 	filenames := make([]string, 0)
 	for _, templateDir := range paths {
 		err := filepath.Walk(templateDir, func(path string, _ fs.FileInfo, _ error) error {
 			if filepath.Ext(path) == ".tmpl" {
 				filenames = append(filenames, path)
-				if err := t.watcher.Add(path); err != nil {
-					t.logger.Error("template.load.fsnotify.Add", zap.Error(err))
-				}
 			}
 			return nil
 		})
 		if err != nil {
-			return xerr.WithMsg(err, "traverse template dir err").WithStatus(xerr.StatusInternalServerError)
+			return nil, xerr.WithMsg(err, "traverse template dir err").WithStatus(xerr.StatusInternalServerError)
 		}
 	}
+	return filenames, nil
+}
 
-	ht, err := htmlTemplate.New("").Funcs(t.funcMap).ParseFiles(filenames...)
-	if err != nil {
-		return xerr.WithMsg(err, "parse template err").WithStatus(xerr.StatusInternalServerError)
-	}
+// Loaded reports whether the templates of the active theme have been parsed
+func (t *Template) Loaded() bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.HTMLTemplate != nil
+}
 
-	tt, err := template.New("").Funcs(t.funcMap).ParseFiles(filenames...)
-	if err != nil {
-		return xerr.WithMsg(err, "parse template err").WithStatus(xerr.StatusInternalServerError)
-	}
-	t.TextTemplate = tt
-	t.HTMLTemplate = ht
-	return nil
+// Lookup reports whether a html template with the given name has been parsed
+func (t *Template) Lookup(name string) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.HTMLTemplate != nil && t.HTMLTemplate.Lookup(name) != nil
 }
 
 func (t *Template) SetSharedVariable(name string, value interface{}) {
@@ -93,30 +174,50 @@ func (t *Template) SetSharedVariable(name string, value interface{}) {
 	t.sharedVariable[name] = value
 }
 
-func (t *Template) Execute(wr io.Writer, data Model) error {
+func (t *Template) Execute(ctx context.Context, wr io.Writer, data Model) (err error) {
+	spanCtx, span := tracing.Start(ctx, "template.Execute")
+	defer observeRender(spanCtx, span, "", time.Now(), &err)
 	return t.HTMLTemplate.Execute(wr, t.wrapData(data))
 }
 
-func (t *Template) ExecuteTemplate(wr io.Writer, name string, data Model) error {
+func (t *Template) ExecuteTemplate(ctx context.Context, wr io.Writer, name string, data Model) (err error) {
+	spanCtx, span := tracing.Start(ctx, "template.ExecuteTemplate "+name)
+	defer observeRender(spanCtx, span, name, time.Now(), &err)
 	return t.HTMLTemplate.ExecuteTemplate(wr, name, t.wrapData(data))
 }
 
-func (t *Template) ExecuteText(wr io.Writer, data Model) error {
+func (t *Template) ExecuteText(ctx context.Context, wr io.Writer, data Model) (err error) {
+	spanCtx, span := tracing.Start(ctx, "template.ExecuteText")
+	defer observeRender(spanCtx, span, "", time.Now(), &err)
 	return t.TextTemplate.Execute(wr, t.wrapData(data))
 }
 
-func (t *Template) ExecuteTextTemplate(wr io.Writer, name string, data Model) error {
+func (t *Template) ExecuteTextTemplate(ctx context.Context, wr io.Writer, name string, data Model) (err error) {
+	spanCtx, span := tracing.Start(ctx, "template.ExecuteTextTemplate "+name)
+	defer observeRender(spanCtx, span, name, time.Now(), &err)
 	return t.TextTemplate.ExecuteTemplate(wr, name, t.wrapData(data))
 }
 
+// observeRender records the render with the template span as the exemplar, the template functions can not take the ctx
+// of each render because the parsed templates are shared
+func observeRender(ctx context.Context, span trace.Span, name string, start time.Time, err *error) {
+	metrics.ObserveTemplateRender(ctx, name, time.Since(start), *err)
+	tracing.End(span, *err)
+}
+
 func (t *Template) wrapData(data Model) map[string]any {
 	if data == nil {
 		return nil
 	}
 
 	t.lock.RLock()
-	defer t.lock.RUnlock()
 	data.MergeAttributes(t.sharedVariable)
+	t.lock.RUnlock()
+	if t.parent != nil {
+		t.parent.lock.RLock()
+		data.MergeAttributes(t.parent.sharedVariable)
+		t.parent.lock.RUnlock()
+	}
 	data["now"] = time.Now()
 	return data
 }
@@ -1,36 +1,53 @@
 package template
 
 import (
+	"context"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
+	"github.com/go-sonic/sonic/event"
 )
 
+// watchDebounce collects the burst of events caused by one save of an editor or a git checkout into one reload
+const watchDebounce = 300 * time.Millisecond
+
+type watchChanges struct {
+	properties bool
+	templates  bool
+	assets     bool
+}
+
 func (t *Template) Watch() {
+	var (
+		changes watchChanges
+		fire    <-chan time.Time
+	)
 	for {
 		select {
 		case event, ok := <-t.watcher.Events:
 			if !ok {
 				return
 			}
-			if filepath.Ext(event.Name) != ".tmpl" {
-				continue
-			}
-
-			switch {
-			case event.Op&fsnotify.Write == fsnotify.Write:
-				t.logger.Info("Write file:", zap.String("file", event.Name))
-			case event.Op&fsnotify.Create == fsnotify.Create:
-				t.logger.Info("Create file:", zap.String("file", event.Name))
-			default:
+			if !config.IsDev() {
+				t.handleTemplateEvent(event)
 				continue
 			}
-
-			err := t.Reload([]string{event.Name})
-			if err != nil {
-				t.logger.Error("reload template error", zap.Error(err))
+			if t.collectChange(event, &changes) {
+				fire = time.After(watchDebounce)
 			}
+		case <-fire:
+			fire = nil
+			t.applyChanges(changes)
+			changes = watchChanges{}
 		case err, ok := <-t.watcher.Errors:
 			if !ok {
 				return
@@ -39,3 +56,134 @@ func (t *Template) Watch() {
 		}
 	}
 }
+
+func (t *Template) handleTemplateEvent(event fsnotify.Event) {
+	if filepath.Ext(event.Name) != ".tmpl" {
+		return
+	}
+
+	switch {
+	case event.Op&fsnotify.Write == fsnotify.Write:
+		t.logger.Info("Write file:", zap.String("file", event.Name))
+	case event.Op&fsnotify.Create == fsnotify.Create:
+		t.logger.Info("Create file:", zap.String("file", event.Name))
+	default:
+		return
+	}
+
+	err := t.Reload([]string{event.Name})
+	if err != nil {
+		t.logger.Error("reload template error", zap.Error(err))
+	}
+}
+
+// collectChange records what kind of file has changed and reports whether the change needs a reload
+func (t *Template) collectChange(event fsnotify.Event, changes *watchChanges) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Base(event.Name)
+	// skip the swap and backup files of editors
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+		return false
+	}
+	if event.Op&fsnotify.Create == fsnotify.Create {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if skipWatchDir(event.Name) {
+				return false
+			}
+			t.watchDir(event.Name)
+			changes.templates = true
+			return true
+		}
+	}
+	switch {
+	case slices.Contains(consts.ThemePropertyFilenames[:], name), slices.Contains(consts.ThemeSettingFilenames[:], name):
+		changes.properties = true
+	case filepath.Ext(name) == ".tmpl":
+		changes.templates = true
+	default:
+		changes.assets = true
+	}
+	t.logger.Debug("theme file changed", zap.String("file", event.Name), zap.String("op", event.Op.String()))
+	return true
+}
+
+func (t *Template) applyChanges(changes watchChanges) {
+	ctx := context.Background()
+	switch {
+	case changes.properties:
+		t.logger.Info("theme properties changed, reload theme")
+		t.bus.Publish(ctx, &event.ThemeUpdateEvent{})
+	case changes.templates:
+		t.logger.Info("theme templates changed, reload templates")
+		t.bus.Publish(ctx, &event.ThemeFileUpdatedEvent{})
+	}
+	t.liveReload.notify()
+}
+
+// watchDirs watches the directories under paths recursively and stops watching the directories of a previous theme
+func (t *Template) watchDirs(paths []string) {
+	dirs := make(map[string]struct{})
+	for _, path := range paths {
+		root := path
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if path != root && skipWatchDir(path) {
+				return filepath.SkipDir
+			}
+			dirs[path] = struct{}{}
+			return nil
+		})
+	}
+
+	t.watchLock.Lock()
+	defer t.watchLock.Unlock()
+	for dir := range t.watchedDirs {
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+		if err := t.watcher.Remove(dir); err != nil {
+			t.logger.Debug("template.watch.fsnotify.Remove", zap.Error(err))
+		}
+		delete(t.watchedDirs, dir)
+	}
+	for dir := range dirs {
+		t.addWatchedDir(dir)
+	}
+}
+
+func (t *Template) watchDir(root string) {
+	t.watchLock.Lock()
+	defer t.watchLock.Unlock()
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if skipWatchDir(path) {
+			return filepath.SkipDir
+		}
+		t.addWatchedDir(path)
+		return nil
+	})
+}
+
+// skipWatchDir reports whether the directory is not a part of the theme, like .git and node_modules,
+// watching them could exhaust the watches of the system and reload the theme on every git operation
+func skipWatchDir(dir string) bool {
+	name := filepath.Base(dir)
+	return strings.HasPrefix(name, ".") || name == "node_modules"
+}
+
+func (t *Template) addWatchedDir(dir string) {
+	if _, ok := t.watchedDirs[dir]; ok {
+		return
+	}
+	if err := t.watcher.Add(dir); err != nil {
+		t.logger.Error("template.watch.fsnotify.Add", zap.Error(err))
+		return
+	}
+	t.watchedDirs[dir] = struct{}{}
+}
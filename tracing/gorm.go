@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "sonic:tracing_span"
+
+// GormPlugin creates a client span for every statement executed by gorm
+type GormPlugin struct {
+	dbSystem string
+}
+
+var _ gorm.Plugin = &GormPlugin{}
+
+func NewGormPlugin(dbSystem string) *GormPlugin {
+	return &GormPlugin{dbSystem: dbSystem}
+}
+
+func (p *GormPlugin) Name() string {
+	return "sonic:tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	errs := []error{
+		callback.Create().Before("gorm:create").Register("sonic:tracing_before_create", p.before("create")),
+		callback.Create().After("gorm:create").Register("sonic:tracing_after_create", p.after),
+		callback.Query().Before("gorm:query").Register("sonic:tracing_before_query", p.before("query")),
+		callback.Query().After("gorm:query").Register("sonic:tracing_after_query", p.after),
+		callback.Update().Before("gorm:update").Register("sonic:tracing_before_update", p.before("update")),
+		callback.Update().After("gorm:update").Register("sonic:tracing_after_update", p.after),
+		callback.Delete().Before("gorm:delete").Register("sonic:tracing_before_delete", p.before("delete")),
+		callback.Delete().After("gorm:delete").Register("sonic:tracing_after_delete", p.after),
+		callback.Row().Before("gorm:row").Register("sonic:tracing_before_row", p.before("row")),
+		callback.Row().After("gorm:row").Register("sonic:tracing_after_row", p.after),
+		callback.Raw().Before("gorm:raw").Register("sonic:tracing_before_raw", p.before("raw")),
+		callback.Raw().After("gorm:raw").Register("sonic:tracing_after_raw", p.after),
+	}
+	return errors.Join(errs...)
+}
+
+func (p *GormPlugin) before(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		// statements outside of a traced request would only create noise root spans
+		if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+		name := "db." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(p.dbSystem),
+				semconv.DBOperationName(operation),
+				semconv.DBCollectionName(db.Statement.Table),
+			))
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (p *GormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	// the statement is recorded without its vars, they may contain passwords or tokens
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.End()
+		return
+	}
+	End(span, db.Error)
+}
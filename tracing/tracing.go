@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/go-sonic/sonic/config"
+	"github.com/go-sonic/sonic/consts"
+)
+
+const instrumentationName = "github.com/go-sonic/sonic"
+
+// Tracer returns the tracer of sonic, it is a no-op tracer unless tracing is enabled in the config
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the global tracer provider which exports spans over OTLP/HTTP
+func Setup(conf *config.Config, logger *zap.Logger, lifecycle fx.Lifecycle) error {
+	tracingConfig := conf.Tracing
+	if !tracingConfig.Enabled {
+		return nil
+	}
+	options := make([]otlptracehttp.Option, 0)
+	if tracingConfig.Endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(tracingConfig.Endpoint))
+	}
+	if tracingConfig.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	if len(tracingConfig.Headers) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(tracingConfig.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return err
+	}
+
+	serviceName := tracingConfig.ServiceName
+	if serviceName == "" {
+		serviceName = "sonic"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(consts.SonicVersion),
+	))
+	if err != nil {
+		return err
+	}
+
+	sampleRatio := tracingConfig.SampleRatio
+	if sampleRatio <= 0 || sampleRatio > 1 {
+		sampleRatio = 1
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("opentelemetry error", zap.Error(err))
+	}))
+
+	lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return tracerProvider.Shutdown(ctx)
+		},
+	})
+	logger.Info("opentelemetry tracing enabled", zap.String("endpoint", tracingConfig.Endpoint), zap.Float64("sampleRatio", sampleRatio))
+	return nil
+}
+
+// Start starts an internal span, the caller must end it with End
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span if it is not nil and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/rand"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	captchaChars  = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	captchaWidth  = 120
+	captchaHeight = 40
+)
+
+var (
+	captchaFontOnce sync.Once
+	captchaFont     *opentype.Font
+	captchaFontErr  error
+)
+
+func GenCaptchaCode(length int) string {
+	code := make([]byte, length)
+	for i := range code {
+		code[i] = captchaChars[rand.Intn(len(captchaChars))]
+	}
+	return string(code)
+}
+
+// GenCaptchaImage draws the code with some noise and returns it as a png data url
+func GenCaptchaImage(code string) (string, error) {
+	captchaFontOnce.Do(func() {
+		captchaFont, captchaFontErr = opentype.Parse(gomonobold.TTF)
+	})
+	if captchaFontErr != nil {
+		return "", captchaFontErr
+	}
+	face, err := opentype.NewFace(captchaFont, &opentype.FaceOptions{Size: 24, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return "", err
+	}
+	defer face.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, captchaWidth, captchaHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: 245, G: 245, B: 245, A: 255}}, image.Point{}, draw.Src)
+	for i := 0; i < 6; i++ {
+		drawNoiseLine(img)
+	}
+
+	step := captchaWidth / (len(code) + 1)
+	for i, c := range code {
+		drawer := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(randomDarkColor()),
+			Face: face,
+			Dot:  fixed.P(step/2+i*step+rand.Intn(6), 28+rand.Intn(6)-3),
+		}
+		drawer.DrawString(string(c))
+	}
+	for i := 0; i < 120; i++ {
+		img.Set(rand.Intn(captchaWidth), rand.Intn(captchaHeight), randomDarkColor())
+	}
+
+	buf := bytes.Buffer{}
+	if err = png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func drawNoiseLine(img *image.RGBA) {
+	x0, y0 := rand.Intn(captchaWidth), rand.Intn(captchaHeight)
+	x1, y1 := rand.Intn(captchaWidth), rand.Intn(captchaHeight)
+	c := color.RGBA{R: uint8(150 + rand.Intn(80)), G: uint8(150 + rand.Intn(80)), B: uint8(150 + rand.Intn(80)), A: 255}
+	steps := captchaWidth
+	for i := 0; i <= steps; i++ {
+		x := x0 + (x1-x0)*i/steps
+		y := y0 + (y1-y0)*i/steps
+		img.Set(x, y, c)
+	}
+}
+
+func randomDarkColor() color.Color {
+	return color.RGBA{R: uint8(rand.Intn(120)), G: uint8(rand.Intn(120)), B: uint8(rand.Intn(120)), A: 255}
+}
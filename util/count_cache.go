@@ -11,6 +11,8 @@ type CounterCache[K comparable] struct {
 	batchIncr       func(countCache map[K]int64)
 	singleIncr      func(key K, count int64)
 	refreshDuration time.Duration
+	stop            chan struct{}
+	closeOnce       sync.Once
 }
 
 func NewCounterCache[K comparable](refreshDuration time.Duration, batchIncr func(map[K]int64), singleIncr func(K, int64)) *CounterCache[K] {
@@ -19,6 +21,7 @@ func NewCounterCache[K comparable](refreshDuration time.Duration, batchIncr func
 		batchIncr:       batchIncr,
 		singleIncr:      singleIncr,
 		refreshDuration: refreshDuration,
+		stop:            make(chan struct{}),
 	}
 	go c.startFlushTicker()
 	return c
@@ -53,11 +56,24 @@ func (c *CounterCache[K]) get(key K) int64 {
 func (c *CounterCache[K]) startFlushTicker() {
 	ticker := time.NewTicker(c.refreshDuration)
 	defer ticker.Stop()
-	for range ticker.C {
-		c.flush()
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.stop:
+			return
+		}
 	}
 }
 
+// Close stops the flush ticker and writes the counts which have not been flushed yet
+func (c *CounterCache[K]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.flush()
+	})
+}
+
 func (c *CounterCache[K]) flush() {
 	var oldCountCache map[K]int64
 	c.rwMu.Lock()
@@ -10,8 +10,14 @@ import (
 	"github.com/go-sonic/sonic/util/xerr"
 )
 
+// ginContext finds the *gin.Context of the request, ctx may be derived from it, e.g. to carry a span
+func ginContext(ctx context.Context) (*gin.Context, bool) {
+	ginCtx, ok := ctx.Value(gin.ContextKey).(*gin.Context)
+	return ginCtx, ok && ginCtx != nil
+}
+
 func GetClientIP(ctx context.Context) string {
-	ginCtx, ok := ctx.(*gin.Context)
+	ginCtx, ok := ginContext(ctx)
 	if !ok {
 		return ""
 	}
@@ -19,13 +25,18 @@ func GetClientIP(ctx context.Context) string {
 }
 
 func GetUserAgent(ctx context.Context) string {
-	ginCtx, ok := ctx.(*gin.Context)
+	ginCtx, ok := ginContext(ctx)
 	if !ok {
 		return ""
 	}
 	return ginCtx.GetHeader("User-Agent")
 }
 
+// IsHTTPS tells whether the request is made over HTTPS, directly or through a proxy
+func IsHTTPS(ctx *gin.Context) bool {
+	return ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+}
+
 func MustGetQueryString(ctx *gin.Context, key string) (string, error) {
 	str, ok := ctx.GetQuery(key)
 	if !ok || str == "" {
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+var ErrNonPublicAddress = errors.New("dial to a non-public address is not allowed")
+
+// PublicAddressControl is used as net.Dialer.Control to refuse the connections to the loopback, private and
+// link-local addresses, it protects the internal services from the requests to the urls submitted by visitors
+func PublicAddressControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrNonPublicAddress
+	}
+	return nil
+}
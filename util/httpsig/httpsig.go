@@ -0,0 +1,194 @@
+// Package httpsig signs and verifies the HTTP requests with the rsa-sha256 signatures used by ActivityPub servers,
+// see https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures-12
+package httpsig
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Signature is the parsed Signature header
+type Signature struct {
+	KeyID     string
+	Algorithm string
+	Headers   []string
+	Signature []byte
+}
+
+// Sign adds the Date, Digest and Signature headers to the request, the body is nil for the GET requests
+func Sign(req *http.Request, keyID string, key *rsa.PrivateKey, body []byte) error {
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	headers := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		req.Header.Set("Digest", Digest(body))
+		headers = append(headers, "digest")
+	}
+	hashed := sha256.Sum256([]byte(signingString(req, headers)))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Digest returns the value of the Digest header of the body
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Parse parses the Signature header of the request
+func Parse(req *http.Request) (*Signature, error) {
+	header := req.Header.Get("Signature")
+	if header == "" {
+		return nil, ErrMissingSignature
+	}
+	signature := &Signature{Headers: []string{"date"}}
+	for _, param := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch key {
+		case "keyId":
+			signature.KeyID = value
+		case "algorithm":
+			signature.Algorithm = value
+		case "headers":
+			signature.Headers = strings.Fields(strings.ToLower(value))
+		case "signature":
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, ErrInvalidSignature
+			}
+			signature.Signature = decoded
+		}
+	}
+	if signature.KeyID == "" || len(signature.Signature) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	return signature, nil
+}
+
+// Verify verifies the signature of the request, the signed headers must include the request target, the host,
+// the date within maxAge, and the digest of the body for the requests with body
+func (s *Signature) Verify(req *http.Request, body []byte, publicKey *rsa.PublicKey, maxAge time.Duration) error {
+	if s.Algorithm != "" && s.Algorithm != "rsa-sha256" && s.Algorithm != "hs2019" {
+		return fmt.Errorf("unsupported signature algorithm %s", s.Algorithm)
+	}
+	// the target and the host must be signed, or the signature could be replayed against the other paths and hosts
+	for _, header := range []string{"(request-target)", "host", "date"} {
+		if !s.signs(header) {
+			return fmt.Errorf("the %s is not signed", header)
+		}
+	}
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return errors.New("invalid date header")
+	}
+	if age := time.Since(date); age > maxAge || age < -maxAge {
+		return errors.New("the signature is expired")
+	}
+	if body != nil {
+		if !s.signs("digest") {
+			return errors.New("the digest is not signed")
+		}
+		if req.Header.Get("Digest") != Digest(body) {
+			return errors.New("the digest does not match the body")
+		}
+	}
+	hashed := sha256.Sum256([]byte(signingString(req, s.Headers)))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], s.Signature); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *Signature) signs(header string) bool {
+	for _, h := range s.Headers {
+		if h == header {
+			return true
+		}
+	}
+	return false
+}
+
+func signingString(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, header := range headers {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, header+": "+strings.ToLower(req.Method)+" "+req.URL.RequestURI())
+		case "host":
+			lines = append(lines, "host: "+req.Host)
+		default:
+			lines = append(lines, header+": "+strings.Join(req.Header.Values(header), ", "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParsePublicKey parses the PKIX or PKCS #1 public key in PEM
+func ParsePublicKey(publicKeyPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.New("invalid public key pem")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+		return nil, errors.New("not a rsa public key")
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
+
+// ParsePrivateKey parses the PKCS #8 or PKCS #1 private key in PEM
+func ParsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, errors.New("invalid private key pem")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, errors.New("not a rsa private key")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// EncodePrivateKey encodes the private key in PKCS #8 PEM
+func EncodePrivateKey(key *rsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// EncodePublicKey encodes the public key in PKIX PEM
+func EncodePublicKey(key *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
@@ -0,0 +1,275 @@
+// Package microformats parses the h-entry of a page, only the properties used by webmentions are supported,
+// see https://microformats.org/wiki/h-entry
+package microformats
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type Page struct {
+	Title string
+	// Links are the absolute urls of the links and the media of the page
+	Links []string
+	// Rels are the absolute urls of the links grouped by the rel values, like webmention and pingback
+	Rels  map[string][]string
+	Entry *Entry
+}
+
+type Entry struct {
+	Name       string
+	Content    string
+	URL        string
+	Published  time.Time
+	Author     *Card
+	InReplyTo  []string
+	LikeOf     []string
+	RepostOf   []string
+	BookmarkOf []string
+}
+
+type Card struct {
+	Name  string
+	URL   string
+	Photo string
+}
+
+func Parse(r io.Reader, base *url.URL) (*Page, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{base: base}
+	page := &Page{Rels: make(map[string][]string)}
+	walk(doc, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.Title:
+			if page.Title == "" {
+				page.Title = text(n)
+			}
+		case atom.A, atom.Link, atom.Area:
+			href := p.resolve(attr(n, "href"))
+			if href == "" {
+				break
+			}
+			for _, rel := range strings.Fields(strings.ToLower(attr(n, "rel"))) {
+				page.Rels[rel] = append(page.Rels[rel], href)
+			}
+			if n.DataAtom != atom.Link {
+				page.Links = append(page.Links, href)
+			}
+		case atom.Img, atom.Video, atom.Audio, atom.Source:
+			if src := p.resolve(attr(n, "src")); src != "" {
+				page.Links = append(page.Links, src)
+			}
+		}
+		return true
+	})
+	if entry := find(doc, "h-entry"); entry != nil {
+		page.Entry = p.parseEntry(entry)
+		if page.Entry.Author == nil {
+			if card := find(doc, "h-card"); card != nil {
+				page.Entry.Author = p.parseCard(card)
+			}
+		}
+	}
+	return page, nil
+}
+
+type parser struct {
+	base *url.URL
+}
+
+func (p *parser) parseEntry(n *html.Node) *Entry {
+	entry := &Entry{}
+	if name := findProperty(n, "p-name"); name != nil {
+		entry.Name = text(name)
+	}
+	if content := findProperty(n, "e-content"); content != nil {
+		entry.Content = text(content)
+	} else if summary := findProperty(n, "p-summary"); summary != nil {
+		entry.Content = text(summary)
+	}
+	if u := findProperty(n, "u-url"); u != nil {
+		entry.URL = p.urlOf(u)
+	}
+	if published := findProperty(n, "dt-published"); published != nil {
+		value := attr(published, "datetime")
+		if value == "" {
+			value = text(published)
+		}
+		entry.Published, _ = time.Parse(time.RFC3339, value)
+	}
+	if author := findProperty(n, "p-author"); author != nil {
+		if hasClass(author, "h-card") {
+			entry.Author = p.parseCard(author)
+		} else {
+			entry.Author = &Card{Name: text(author), URL: p.resolve(attr(author, "href"))}
+		}
+	} else if author := findProperty(n, "u-author"); author != nil {
+		entry.Author = &Card{URL: p.urlOf(author)}
+	}
+	entry.InReplyTo = p.urlsOf(n, "u-in-reply-to")
+	entry.LikeOf = p.urlsOf(n, "u-like-of")
+	entry.RepostOf = p.urlsOf(n, "u-repost-of")
+	entry.BookmarkOf = p.urlsOf(n, "u-bookmark-of")
+	return entry
+}
+
+func (p *parser) parseCard(n *html.Node) *Card {
+	card := &Card{}
+	if name := findProperty(n, "p-name"); name != nil {
+		card.Name = text(name)
+	} else {
+		card.Name = text(n)
+	}
+	if u := findProperty(n, "u-url"); u != nil {
+		card.URL = p.urlOf(u)
+	} else if n.DataAtom == atom.A {
+		card.URL = p.resolve(attr(n, "href"))
+	}
+	if photo := findProperty(n, "u-photo"); photo != nil {
+		card.Photo = p.urlOf(photo)
+	}
+	return card
+}
+
+func (p *parser) urlsOf(n *html.Node, class string) []string {
+	urls := make([]string, 0)
+	walk(n, func(c *html.Node) bool {
+		if c != n && hasClass(c, class) {
+			if u := p.urlOf(c); u != "" {
+				urls = append(urls, u)
+			}
+			return false
+		}
+		return c == n || !isRoot(c)
+	})
+	return urls
+}
+
+// urlOf returns the url of a u-* property, which is a link, a media or an embedded h-cite
+func (p *parser) urlOf(n *html.Node) string {
+	if isRoot(n) {
+		if u := findProperty(n, "u-url"); u != nil {
+			return p.urlOf(u)
+		}
+	}
+	switch n.DataAtom {
+	case atom.A, atom.Area, atom.Link:
+		return p.resolve(attr(n, "href"))
+	case atom.Img, atom.Audio, atom.Video, atom.Source:
+		return p.resolve(attr(n, "src"))
+	}
+	return p.resolve(text(n))
+}
+
+func (p *parser) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if p.base != nil {
+		u = p.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// findProperty finds the first property of the root without entering the nested roots
+func findProperty(n *html.Node, class string) *html.Node {
+	var result *html.Node
+	walk(n, func(c *html.Node) bool {
+		if result != nil {
+			return false
+		}
+		if c != n && hasClass(c, class) {
+			result = c
+			return false
+		}
+		return c == n || !isRoot(c)
+	})
+	return result
+}
+
+func find(n *html.Node, class string) *html.Node {
+	var result *html.Node
+	walk(n, func(c *html.Node) bool {
+		if result != nil {
+			return false
+		}
+		if hasClass(c, class) {
+			result = c
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// walk visits the nodes in depth-first order, the children are skipped when visit returns false
+func walk(n *html.Node, visit func(n *html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, visit)
+	}
+}
+
+// isRoot reports whether the element is a microformats root like h-entry or h-card
+func isRoot(n *html.Node) bool {
+	for _, class := range strings.Fields(attr(n, "class")) {
+		if strings.HasPrefix(class, "h-") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasClass(n *html.Node, class string) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func text(n *html.Node) string {
+	buf := strings.Builder{}
+	walk(n, func(c *html.Node) bool {
+		if c.DataAtom == atom.Script || c.DataAtom == atom.Style {
+			return false
+		}
+		if c.Type == html.TextNode {
+			buf.WriteString(c.Data)
+			buf.WriteString(" ")
+		}
+		return true
+	})
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
@@ -13,8 +13,10 @@ type Status int
 const (
 	StatusBadRequest          = http.StatusBadRequest
 	StatusInternalServerError = http.StatusInternalServerError
+	StatusUnauthorized        = http.StatusUnauthorized
 	StatusForbidden           = http.StatusForbidden
 	StatusNotFound            = http.StatusNotFound
+	StatusTooManyRequests     = http.StatusTooManyRequests
 )
 
 type ErrorType uint
@@ -0,0 +1,316 @@
+// Package xmlrpc encodes and decodes the XML-RPC messages, see http://xmlrpc.com/spec.md
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const iso8601 = "20060102T15:04:05"
+
+type MethodCall struct {
+	MethodName string
+	Params     []any
+}
+
+// Fault is the error returned by the XML-RPC server
+type Fault struct {
+	Code   int
+	String string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("xmlrpc fault %d: %s", f.Code, f.String)
+}
+
+type rawValue struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+type rawMember struct {
+	Name  string   `xml:"name"`
+	Value rawValue `xml:"value"`
+}
+
+type rawParam struct {
+	Value rawValue `xml:"value"`
+}
+
+type rawMethodCall struct {
+	MethodName string     `xml:"methodName"`
+	Params     []rawParam `xml:"params>param"`
+}
+
+type rawMethodResponse struct {
+	Params []rawParam `xml:"params>param"`
+	Fault  *rawParam  `xml:"fault"`
+}
+
+func DecodeMethodCall(r io.Reader) (*MethodCall, error) {
+	var call rawMethodCall
+	if err := newDecoder(r).Decode(&call); err != nil {
+		return nil, err
+	}
+	methodCall := &MethodCall{MethodName: strings.TrimSpace(call.MethodName), Params: make([]any, 0, len(call.Params))}
+	for _, param := range call.Params {
+		value, err := decodeValue(param.Value.Inner)
+		if err != nil {
+			return nil, err
+		}
+		methodCall.Params = append(methodCall.Params, value)
+	}
+	return methodCall, nil
+}
+
+// DecodeResponse returns the value of the response, or a *Fault when the server responds a fault
+func DecodeResponse(r io.Reader) (any, error) {
+	var response rawMethodResponse
+	if err := newDecoder(r).Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Fault != nil {
+		value, err := decodeValue(response.Fault.Value.Inner)
+		if err != nil {
+			return nil, err
+		}
+		fault := &Fault{}
+		if members, ok := value.(map[string]any); ok {
+			fault.Code, _ = members["faultCode"].(int)
+			fault.String, _ = members["faultString"].(string)
+		}
+		return nil, fault
+	}
+	if len(response.Params) == 0 {
+		return nil, nil
+	}
+	return decodeValue(response.Params[0].Value.Inner)
+}
+
+func EncodeMethodCall(w io.Writer, methodName string, params ...any) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodCall><methodName>")
+	_ = xml.EscapeText(buf, []byte(methodName))
+	buf.WriteString("</methodName><params>")
+	for _, param := range params {
+		buf.WriteString("<param>")
+		if err := encodeValue(buf, param); err != nil {
+			return err
+		}
+		buf.WriteString("</param>")
+	}
+	buf.WriteString("</params></methodCall>")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func EncodeResponse(w io.Writer, value any) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><params><param>")
+	if err := encodeValue(buf, value); err != nil {
+		return err
+	}
+	buf.WriteString("</param></params></methodResponse>")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func EncodeFault(w io.Writer, fault *Fault) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><fault>")
+	err := encodeValue(buf, map[string]any{"faultCode": fault.Code, "faultString": fault.String})
+	if err != nil {
+		return err
+	}
+	buf.WriteString("</fault></methodResponse>")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func newDecoder(r io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
+
+// decodeValue decodes the inner xml of a <value>, a value without type element is a string
+func decodeValue(inner []byte) (any, error) {
+	decoder := newDecoder(bytes.NewReader(inner))
+	text := strings.Builder{}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return text.String(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if data, ok := token.(xml.CharData); ok {
+			text.Write(data)
+			continue
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "array":
+			var array struct {
+				Values []rawValue `xml:"data>value"`
+			}
+			if err := decoder.DecodeElement(&array, &start); err != nil {
+				return nil, err
+			}
+			values := make([]any, 0, len(array.Values))
+			for _, v := range array.Values {
+				value, err := decodeValue(v.Inner)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			return values, nil
+		case "struct":
+			var members struct {
+				Members []rawMember `xml:"member"`
+			}
+			if err := decoder.DecodeElement(&members, &start); err != nil {
+				return nil, err
+			}
+			result := make(map[string]any, len(members.Members))
+			for _, member := range members.Members {
+				value, err := decodeValue(member.Value.Inner)
+				if err != nil {
+					return nil, err
+				}
+				result[strings.TrimSpace(member.Name)] = value
+			}
+			return result, nil
+		}
+		var content string
+		if err := decoder.DecodeElement(&content, &start); err != nil {
+			return nil, err
+		}
+		switch start.Name.Local {
+		case "string":
+			return content, nil
+		case "int", "i4", "i8":
+			return strconv.Atoi(strings.TrimSpace(content))
+		case "boolean":
+			return strings.TrimSpace(content) == "1", nil
+		case "double":
+			return strconv.ParseFloat(strings.TrimSpace(content), 64)
+		case "dateTime.iso8601":
+			return parseTime(strings.TrimSpace(content))
+		case "base64":
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+		case "nil":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unsupported xmlrpc type %s", start.Name.Local)
+	}
+}
+
+func parseTime(text string) (time.Time, error) {
+	for _, layout := range []string{iso8601, "2006-01-02T15:04:05", time.RFC3339, "20060102T15:04:05Z07:00", "20060102T15:04:05Z"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid dateTime.iso8601 %s", text)
+}
+
+func encodeValue(buf *bytes.Buffer, value any) error {
+	buf.WriteString("<value>")
+	defer buf.WriteString("</value>")
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("<nil/>")
+		return nil
+	case string:
+		buf.WriteString("<string>")
+		_ = xml.EscapeText(buf, []byte(v))
+		buf.WriteString("</string>")
+		return nil
+	case bool:
+		buf.WriteString("<boolean>" + map[bool]string{true: "1", false: "0"}[v] + "</boolean>")
+		return nil
+	case time.Time:
+		buf.WriteString("<dateTime.iso8601>" + v.UTC().Format(iso8601) + "</dateTime.iso8601>")
+		return nil
+	case []byte:
+		buf.WriteString("<base64>" + base64.StdEncoding.EncodeToString(v) + "</base64>")
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString("<int>" + strconv.FormatInt(rv.Int(), 10) + "</int>")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf.WriteString("<int>" + strconv.FormatUint(rv.Uint(), 10) + "</int>")
+	case reflect.Float32, reflect.Float64:
+		buf.WriteString("<double>" + strconv.FormatFloat(rv.Float(), 'f', -1, 64) + "</double>")
+	case reflect.String:
+		return encodeValueInner(buf, rv.String())
+	case reflect.Slice, reflect.Array:
+		buf.WriteString("<array><data>")
+		for i := 0; i < rv.Len(); i++ {
+			if err := encodeValue(buf, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</data></array>")
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported xmlrpc struct key %s", rv.Type().Key())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		buf.WriteString("<struct>")
+		for _, key := range keys {
+			buf.WriteString("<member><name>")
+			_ = xml.EscapeText(buf, []byte(key))
+			buf.WriteString("</name>")
+			if err := encodeValue(buf, rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface()); err != nil {
+				return err
+			}
+			buf.WriteString("</member>")
+		}
+		buf.WriteString("</struct>")
+	case reflect.Ptr:
+		if rv.IsNil() {
+			buf.WriteString("<nil/>")
+			return nil
+		}
+		return encodeValueInner(buf, rv.Elem().Interface())
+	default:
+		return fmt.Errorf("unsupported xmlrpc type %T", value)
+	}
+	return nil
+}
+
+// encodeValueInner encodes the value inside the <value> being written
+func encodeValueInner(buf *bytes.Buffer, value any) error {
+	inner := &bytes.Buffer{}
+	if err := encodeValue(inner, value); err != nil {
+		return err
+	}
+	b := inner.Bytes()
+	buf.Write(b[len("<value>") : len(b)-len("</value>")])
+	return nil
+}